@@ -25,6 +25,34 @@ type ImageListItem struct {
 
 	// The list of versions the image is available in.
 	Versions []string `json:"versions"`
+
+	// The details of the image versions, including the digest of the corresponding
+	// manifest and its metadata. Populated only by the registries supporting them.
+	Details []ImageVersionDetails `json:"details,omitempty"`
+}
+
+// ImageVersionDetails describes a single version (i.e., tag) of an image.
+type ImageVersionDetails struct {
+	// The name of the version (i.e., the tag).
+	Version string `json:"version"`
+
+	// The digest of the manifest the version refers to, which can be used to
+	// reference the image immutably (e.g., image@sha256:...).
+	Digest string `json:"digest,omitempty"`
+
+	// The total size of the image (i.e., of its config and layers), in bytes.
+	Size int64 `json:"size,omitempty"`
+
+	// The creation timestamp of the image.
+	Created *metav1.Time `json:"created,omitempty"`
+
+	// The CPU architecture the image is built for. For multi-platform images,
+	// the architecture of the manifest selected for the description is reported.
+	Architecture string `json:"architecture,omitempty"`
+
+	// The annotations of the image manifest (e.g., org.opencontainers.image.description),
+	// merged with the labels of the image configuration.
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // ImageListSpec is the specification of the desired state of the ImageList.
@@ -41,12 +69,74 @@ type ImageListSpec struct {
 
 // ImageListStatus reflects the most recently observed status of the ImageList.
 type ImageListStatus struct {
+	// The usage and deprecation status of the images, computed by the image list updater.
+	Images []ImageStatus `json:"images,omitempty"`
+
+	// The timestamp of the last computation of the usage of the images.
+	LastUsageUpdateTime *metav1.Time `json:"lastUsageUpdateTime,omitempty"`
+}
+
+// ImageStatus describes the usage and deprecation status of a single image.
+type ImageStatus struct {
+	// The name identifying the image.
+	Name string `json:"name"`
+
+	// The InstanceSnapshots producing the image.
+	InstanceSnapshots []GenericRef `json:"instanceSnapshots,omitempty"`
+
+	// The usage and deprecation status of the image versions.
+	Versions []ImageVersionStatus `json:"versions,omitempty"`
+}
+
+// ImageVersionStatus describes the usage and deprecation status of a single version of an image.
+type ImageVersionStatus struct {
+	// The name of the version (i.e., the tag).
+	Version string `json:"version"`
+
+	// The Templates whose environments use the version.
+	Templates []GenericRef `json:"templates,omitempty"`
+
+	// The number of running Instances using the version.
+	RunningInstances int `json:"runningInstances,omitempty"`
+
+	// Whether the version is deprecated according to the deprecation policy of the registry.
+	Deprecated bool `json:"deprecated,omitempty"`
+
+	// The reason why the version is deprecated.
+	DeprecationReason string `json:"deprecationReason,omitempty"`
+
+	// The timestamp the version has been first observed as deprecated.
+	DeprecatedSince *metav1.Time `json:"deprecatedSince,omitempty"`
+}
+
+// ImageDeprecationPolicy defines the criteria to mark the versions of the images as deprecated,
+// and whether the unused deprecated versions shall be deleted from the registry.
+type ImageDeprecationPolicy struct {
+	// The number of most recent versions of each image which are never deprecated because
+	// of their age or number. Versions are sorted by creation time, if known, or by name otherwise.
+	// +kubebuilder:validation:Minimum=0
+	KeepLatestVersions int `json:"keepLatestVersions,omitempty"`
+
+	// Versions older than this duration are deprecated (requires the creation time of the versions).
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
+	// Versions in excess of KeepLatestVersions are deprecated, if enabled.
+	// +kubebuilder:default=false
+	DeprecateOlderVersions bool `json:"deprecateOlderVersions,omitempty"`
+
+	// Regular expressions matching the versions (in the image:version form) to be explicitly deprecated.
+	Patterns []string `json:"patterns,omitempty"`
+
+	// If set, the deprecated versions not used by any Template, running Instance nor InstanceSnapshot
+	// are deleted from the registry once deprecated for at least this duration.
+	DeleteUnusedAfter *metav1.Duration `json:"deleteUnusedAfter,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope="Cluster"
 // +kubebuilder:printcolumn:name="Registry Name",type=string,JSONPath=`.spec.registryName`
+// +kubebuilder:printcolumn:name="Usage Updated",type=date,JSONPath=`.status.lastUsageUpdateTime`
 
 // ImageList describes the available VM images in the CrownLabs registry.
 type ImageList struct {
@@ -0,0 +1,110 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ImageRegistryType is an enumeration of the APIs exposed by the image registries.
+// +kubebuilder:validation:Enum="docker";"harbor";"oci"
+type ImageRegistryType string
+
+const (
+	// ImageRegistryTypeDocker -> the registry exposes the Docker Registry HTTP API V2.
+	ImageRegistryTypeDocker ImageRegistryType = "docker"
+	// ImageRegistryTypeHarbor -> the registry exposes the Harbor REST API v2.
+	ImageRegistryTypeHarbor ImageRegistryType = "harbor"
+	// ImageRegistryTypeOCI -> the registry implements the OCI distribution specification.
+	ImageRegistryTypeOCI ImageRegistryType = "oci"
+)
+
+// ImageRegistrySpec is the specification of the desired state of the ImageRegistry.
+type ImageRegistrySpec struct {
+	// The type of API exposed by the registry.
+	Type ImageRegistryType `json:"type"`
+
+	// The URL used by the operator to contact the registry API.
+	URL string `json:"url"`
+
+	// The host name that can be used to access the registry, reported in the ImageList.
+	RegistryName string `json:"registryName"`
+
+	// The name of the ImageList populated with the images of the registry.
+	ImageListName string `json:"imageListName"`
+
+	// The project the images are retrieved from (required for Harbor registries).
+	Project string `json:"project,omitempty"`
+
+	// The platform described for multi-platform images (only for OCI registries).
+	// +kubebuilder:default="linux/amd64"
+	Platform string `json:"platform,omitempty"`
+
+	// The reference to the Secret containing the credentials to access the registry.
+	// Secrets of type kubernetes.io/dockerconfigjson are supported, as well as generic
+	// Secrets containing either a "token" key (bearer token) or the "username" and
+	// "password" keys. An optional "webhookSecret" key configures the secret expected
+	// from the registry webhooks.
+	CredentialsSecretRef *GenericRef `json:"credentialsSecretRef,omitempty"`
+
+	// The policy marking the versions of the images as deprecated.
+	DeprecationPolicy *ImageDeprecationPolicy `json:"deprecationPolicy,omitempty"`
+}
+
+// ImageRegistryStatus reflects the most recently observed status of the ImageRegistry.
+type ImageRegistryStatus struct {
+	// Whether the images have been successfully retrieved from the registry during the last update.
+	Ready bool `json:"ready,omitempty"`
+
+	// The timestamp of the last update attempt.
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// The number of images retrieved during the last successful update.
+	ImageCount int `json:"imageCount,omitempty"`
+
+	// The error occurred during the last update, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope="Cluster"
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Image List",type=string,JSONPath=`.spec.imageListName`
+// +kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+// +kubebuilder:printcolumn:name="Images",type=integer,JSONPath=`.status.imageCount`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ImageRegistry describes a registry the images of an ImageList are retrieved from.
+type ImageRegistry struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ImageRegistrySpec   `json:"spec,omitempty"`
+	Status ImageRegistryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ImageRegistryList contains a list of ImageRegistry objects.
+type ImageRegistryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageRegistry `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ImageRegistry{}, &ImageRegistryList{})
+}
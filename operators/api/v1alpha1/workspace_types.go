@@ -15,6 +15,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apicommon "github.com/netgroup-polito/CrownLabs/operators/api/common"
@@ -50,6 +51,110 @@ type WorkspaceSpec struct {
 
 	// The amount of resources associated with this workspace, and inherited by enrolled tenants.
 	Quota apicommon.WorkspaceResourceQuota `json:"quota"`
+
+	// The default automation policy applied to the Instances of the Templates belonging to this Workspace.
+	// Each setting can be overridden by the single Templates, and falls back to the global configuration if omitted.
+	AutomationPolicy *WorkspaceAutomationPolicy `json:"automationPolicy,omitempty"`
+
+	// The parent of this Workspace in the hierarchy (e.g., the department a course belongs to).
+	// The quota of the Workspace is sub-allocated from the one of the parent, hence the quotas of all
+	// the children cannot exceed it. The managers of the ancestors are granted the manager permissions
+	// on this Workspace, while its users can view the Templates of the ancestors.
+	Parent *GenericRef `json:"parent,omitempty"`
+
+	// The minimum size of the MyDrive (i.e., the personal storage) of the Tenants enrolled in this
+	// Workspace. Each Tenant is granted the largest size among the ones of its Workspaces, unless
+	// overridden in the Tenant itself.
+	MyDriveSize *resource.Quantity `json:"myDriveSize,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+
+// Weekday identifies a day of the week.
+type Weekday string
+
+const (
+	// Monday -> the first day of the week.
+	Monday Weekday = "Monday"
+	// Tuesday -> the second day of the week.
+	Tuesday Weekday = "Tuesday"
+	// Wednesday -> the third day of the week.
+	Wednesday Weekday = "Wednesday"
+	// Thursday -> the fourth day of the week.
+	Thursday Weekday = "Thursday"
+	// Friday -> the fifth day of the week.
+	Friday Weekday = "Friday"
+	// Saturday -> the sixth day of the week.
+	Saturday Weekday = "Saturday"
+	// Sunday -> the seventh day of the week.
+	Sunday Weekday = "Sunday"
+)
+
+// WorkspaceAutomationPolicy defines the default settings of the instance automation controllers for a Workspace.
+type WorkspaceAutomationPolicy struct {
+	// +kubebuilder:validation:Pattern="^(never|[0-9]+[smhd])$"
+	// The default maximum lifetime of the Instances (see the Template cleanup options).
+	DeleteAfterCreation string `json:"deleteAfterCreation,omitempty"`
+
+	// +kubebuilder:validation:Pattern="^(never|[0-9]+[smhd])$"
+	// The default maximum period of inactivity before the Instances are stopped/deleted (see the Template cleanup options).
+	StopAfterInactivity string `json:"stopAfterInactivity,omitempty"`
+
+	// +kubebuilder:validation:Pattern="^(never|[0-9]+[smhd])$"
+	// The default maximum period a stopped Instance can remain powered off before being deleted (see the Template cleanup options).
+	DeleteAfterInactivity string `json:"deleteAfterInactivity,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// The number of warning notifications sent to the tenant before stopping/deleting an Instance.
+	MaxNumberOfAlerts *int `json:"maxNumberOfAlerts,omitempty"`
+
+	// The interval between two consecutive inactivity warning notifications.
+	InactivityNotificationInterval *metav1.Duration `json:"inactivityNotificationInterval,omitempty"`
+
+	// The interval between two consecutive warning notifications before deleting a powered off Instance.
+	DestructionNotificationInterval *metav1.Duration `json:"destructionNotificationInterval,omitempty"`
+
+	// The interval between the expiration warning notification and the deletion of the Instance.
+	ExpirationNotificationInterval *metav1.Duration `json:"expirationNotificationInterval,omitempty"`
+
+	// The time windows during which no notification is sent to the tenants and no Instance is stopped or deleted,
+	// in addition to the ones configured globally. Notifications and automated actions are postponed to the end of the window.
+	QuietHours []QuietHoursWindow `json:"quietHours,omitempty"`
+
+	// The holiday periods during which no Instance is stopped or deleted, in addition to the ones configured globally.
+	// Automated actions are postponed to the end of the period.
+	Holidays []HolidayPeriod `json:"holidays,omitempty"`
+
+	// The IANA name of the time zone used to evaluate the quiet hours and the holidays (e.g., Europe/Rome). Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// HolidayPeriod defines a range of whole days.
+type HolidayPeriod struct {
+	// A human-readable name of the holiday period (e.g., Christmas break).
+	Name string `json:"name,omitempty"`
+
+	// +kubebuilder:validation:Pattern="^[0-9]{4}-[0-9]{2}-[0-9]{2}$"
+	// The first day of the period, in the YYYY-MM-DD format.
+	Start string `json:"start"`
+
+	// +kubebuilder:validation:Pattern="^[0-9]{4}-[0-9]{2}-[0-9]{2}$"
+	// The last day of the period (included), in the YYYY-MM-DD format. Defaults to the first day if omitted.
+	End string `json:"end,omitempty"`
+}
+
+// QuietHoursWindow defines a daily time window, optionally restricted to a subset of the days of the week.
+type QuietHoursWindow struct {
+	// +kubebuilder:validation:Pattern="^([01][0-9]|2[0-3]):[0-5][0-9]$"
+	// The beginning of the window, in the HH:MM format.
+	Start string `json:"start"`
+
+	// +kubebuilder:validation:Pattern="^([01][0-9]|2[0-3]):[0-5][0-9]$"
+	// The end of the window, in the HH:MM format. If earlier than the start, the window spans midnight.
+	End string `json:"end"`
+
+	// The days of the week the window starts on. If omitted, the window applies to every day.
+	Days []Weekday `json:"days,omitempty"`
 }
 
 // WorkspaceStatus reflects the most recently observed status of the Workspace.
@@ -69,6 +174,43 @@ type WorkspaceStatus struct {
 	// occurred. In case of errors, the other status fields provide additional
 	// information about which problem occurred.
 	Ready bool `json:"ready,omitempty"`
+
+	// The position of the Workspace in the hierarchy, together with the resources
+	// aggregated over its sub-tree. It is omitted for Workspaces with neither a parent nor children.
+	Hierarchy *WorkspaceHierarchyStatus `json:"hierarchy,omitempty"`
+}
+
+// WorkspaceHierarchyStatus reflects the most recently observed position of the Workspace in the hierarchy.
+type WorkspaceHierarchyStatus struct {
+	// The names of the ancestors of the Workspace, starting from the direct parent.
+	Ancestors []string `json:"ancestors,omitempty"`
+
+	// The names of the direct children of the Workspace.
+	Children []string `json:"children,omitempty"`
+
+	// The sum of the quotas sub-allocated to the direct children.
+	AllocatedQuota *WorkspaceResourceUsage `json:"allocatedQuota,omitempty"`
+
+	// Whether the quotas sub-allocated to the direct children exceed the quota of the Workspace.
+	QuotaExceeded bool `json:"quotaExceeded,omitempty"`
+
+	// The resources requested by the running Instances of the Workspace and of all its descendants.
+	Usage *WorkspaceResourceUsage `json:"usage,omitempty"`
+}
+
+// WorkspaceResourceUsage is an aggregated amount of resources.
+type WorkspaceResourceUsage struct {
+	// The number of instances.
+	Instances int64 `json:"instances"`
+
+	// The amount of CPU cores.
+	CPU int64 `json:"cpu"`
+
+	// The amount of RAM memory.
+	Memory resource.Quantity `json:"memory"`
+
+	// The amount of disk space.
+	Disk resource.Quantity `json:"disk,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -76,6 +218,7 @@ type WorkspaceStatus struct {
 // +kubebuilder:resource:scope="Cluster"
 // +kubebuilder:printcolumn:name="Pretty Name",type=string,JSONPath=`.spec.prettyName`
 // +kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=`.status.namespace.name`
+// +kubebuilder:printcolumn:name="Parent",type=string,JSONPath=`.spec.parent.name`,priority=10
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.ready`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
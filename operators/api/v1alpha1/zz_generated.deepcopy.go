@@ -20,6 +20,7 @@ package v1alpha1
 
 import (
 	"github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -38,13 +39,58 @@ func (in *GenericRef) DeepCopy() *GenericRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HolidayPeriod) DeepCopyInto(out *HolidayPeriod) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HolidayPeriod.
+func (in *HolidayPeriod) DeepCopy() *HolidayPeriod {
+	if in == nil {
+		return nil
+	}
+	out := new(HolidayPeriod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageDeprecationPolicy) DeepCopyInto(out *ImageDeprecationPolicy) {
+	*out = *in
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeleteUnusedAfter != nil {
+		in, out := &in.DeleteUnusedAfter, &out.DeleteUnusedAfter
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageDeprecationPolicy.
+func (in *ImageDeprecationPolicy) DeepCopy() *ImageDeprecationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageDeprecationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageList) DeepCopyInto(out *ImageList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageList.
@@ -73,6 +119,13 @@ func (in *ImageListItem) DeepCopyInto(out *ImageListItem) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Details != nil {
+		in, out := &in.Details, &out.Details
+		*out = make([]ImageVersionDetails, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageListItem.
@@ -142,6 +195,17 @@ func (in *ImageListSpec) DeepCopy() *ImageListSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageListStatus) DeepCopyInto(out *ImageListStatus) {
 	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ImageStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUsageUpdateTime != nil {
+		in, out := &in.LastUsageUpdateTime, &out.LastUsageUpdateTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageListStatus.
@@ -154,6 +218,206 @@ func (in *ImageListStatus) DeepCopy() *ImageListStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRegistry) DeepCopyInto(out *ImageRegistry) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRegistry.
+func (in *ImageRegistry) DeepCopy() *ImageRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageRegistry) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRegistryList) DeepCopyInto(out *ImageRegistryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ImageRegistry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRegistryList.
+func (in *ImageRegistryList) DeepCopy() *ImageRegistryList {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRegistryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageRegistryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRegistrySpec) DeepCopyInto(out *ImageRegistrySpec) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(GenericRef)
+		**out = **in
+	}
+	if in.DeprecationPolicy != nil {
+		in, out := &in.DeprecationPolicy, &out.DeprecationPolicy
+		*out = new(ImageDeprecationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRegistrySpec.
+func (in *ImageRegistrySpec) DeepCopy() *ImageRegistrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRegistrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRegistryStatus) DeepCopyInto(out *ImageRegistryStatus) {
+	*out = *in
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRegistryStatus.
+func (in *ImageRegistryStatus) DeepCopy() *ImageRegistryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRegistryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
+	*out = *in
+	if in.InstanceSnapshots != nil {
+		in, out := &in.InstanceSnapshots, &out.InstanceSnapshots
+		*out = make([]GenericRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]ImageVersionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
+func (in *ImageStatus) DeepCopy() *ImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVersionDetails) DeepCopyInto(out *ImageVersionDetails) {
+	*out = *in
+	if in.Created != nil {
+		in, out := &in.Created, &out.Created
+		*out = (*in).DeepCopy()
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVersionDetails.
+func (in *ImageVersionDetails) DeepCopy() *ImageVersionDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVersionDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVersionStatus) DeepCopyInto(out *ImageVersionStatus) {
+	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]GenericRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeprecatedSince != nil {
+		in, out := &in.DeprecatedSince, &out.DeprecatedSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVersionStatus.
+func (in *ImageVersionStatus) DeepCopy() *ImageVersionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVersionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuietHoursWindow) DeepCopyInto(out *QuietHoursWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuietHoursWindow.
+func (in *QuietHoursWindow) DeepCopy() *QuietHoursWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(QuietHoursWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Workspace) DeepCopyInto(out *Workspace) {
 	*out = *in
@@ -181,6 +445,88 @@ func (in *Workspace) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAutomationPolicy) DeepCopyInto(out *WorkspaceAutomationPolicy) {
+	*out = *in
+	if in.MaxNumberOfAlerts != nil {
+		in, out := &in.MaxNumberOfAlerts, &out.MaxNumberOfAlerts
+		*out = new(int)
+		**out = **in
+	}
+	if in.InactivityNotificationInterval != nil {
+		in, out := &in.InactivityNotificationInterval, &out.InactivityNotificationInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DestructionNotificationInterval != nil {
+		in, out := &in.DestructionNotificationInterval, &out.DestructionNotificationInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ExpirationNotificationInterval != nil {
+		in, out := &in.ExpirationNotificationInterval, &out.ExpirationNotificationInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.QuietHours != nil {
+		in, out := &in.QuietHours, &out.QuietHours
+		*out = make([]QuietHoursWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Holidays != nil {
+		in, out := &in.Holidays, &out.Holidays
+		*out = make([]HolidayPeriod, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAutomationPolicy.
+func (in *WorkspaceAutomationPolicy) DeepCopy() *WorkspaceAutomationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceAutomationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceHierarchyStatus) DeepCopyInto(out *WorkspaceHierarchyStatus) {
+	*out = *in
+	if in.Ancestors != nil {
+		in, out := &in.Ancestors, &out.Ancestors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Children != nil {
+		in, out := &in.Children, &out.Children
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllocatedQuota != nil {
+		in, out := &in.AllocatedQuota, &out.AllocatedQuota
+		*out = new(WorkspaceResourceUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(WorkspaceResourceUsage)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceHierarchyStatus.
+func (in *WorkspaceHierarchyStatus) DeepCopy() *WorkspaceHierarchyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceHierarchyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceList) DeepCopyInto(out *WorkspaceList) {
 	*out = *in
@@ -213,10 +559,42 @@ func (in *WorkspaceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceResourceUsage) DeepCopyInto(out *WorkspaceResourceUsage) {
+	*out = *in
+	out.Memory = in.Memory.DeepCopy()
+	out.Disk = in.Disk.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceResourceUsage.
+func (in *WorkspaceResourceUsage) DeepCopy() *WorkspaceResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 	*out = *in
 	in.Quota.DeepCopyInto(&out.Quota)
+	if in.AutomationPolicy != nil {
+		in, out := &in.AutomationPolicy, &out.AutomationPolicy
+		*out = new(WorkspaceAutomationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Parent != nil {
+		in, out := &in.Parent, &out.Parent
+		*out = new(GenericRef)
+		**out = **in
+	}
+	if in.MyDriveSize != nil {
+		in, out := &in.MyDriveSize, &out.MyDriveSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSpec.
@@ -240,6 +618,11 @@ func (in *WorkspaceStatus) DeepCopyInto(out *WorkspaceStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Hierarchy != nil {
+		in, out := &in.Hierarchy, &out.Hierarchy
+		*out = new(WorkspaceHierarchyStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceStatus.
@@ -25,6 +25,34 @@ type GenericRef struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// ContentSource describes the origin of the content a volume is populated with:
+// either an archive or a Git repository.
+// +kubebuilder:validation:XValidation:rule="has(self.archiveURL) != has(self.git)",message="exactly one of archiveURL and git must be specified"
+type ContentSource struct {
+	// The URL of an archive (e.g., zip, tar.gz) to be downloaded and
+	// extracted into the volume.
+	// +optional
+	ArchiveURL string `json:"archiveURL,omitempty"`
+
+	// The Git repository to be checked out into the volume.
+	// +optional
+	Git *GitContentSource `json:"git,omitempty"`
+}
+
+// GitContentSource describes a revision of a Git repository.
+type GitContentSource struct {
+	// +kubebuilder:validation:Pattern="^(https|ssh)://.+"
+
+	// The URL of the Git repository, which shall be publicly accessible
+	// (through either the https or the ssh scheme).
+	Repository string `json:"repository"`
+
+	// +kubebuilder:default="HEAD"
+
+	// The branch, tag or commit to be checked out.
+	Ref string `json:"ref,omitempty"`
+}
+
 // NameCreated contains information about the status of a resource created in
 // the cluster (e.g. a namespace). Specifically, it contains the name of the
 // resource and a flag indicating whether the creation succeeded.
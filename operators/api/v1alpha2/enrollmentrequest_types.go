@@ -0,0 +1,120 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum="";"Approved";"Rejected"
+
+// EnrollmentDecision is an enumeration of the decisions a Workspace manager can take on an EnrollmentRequest.
+type EnrollmentDecision string
+
+const (
+	// EnrollmentApproved -> the Tenant is enrolled in the Workspace with the User role.
+	EnrollmentApproved EnrollmentDecision = "Approved"
+	// EnrollmentRejected -> the Tenant is removed from the Workspace.
+	EnrollmentRejected EnrollmentDecision = "Rejected"
+)
+
+// +kubebuilder:validation:Enum="";"Pending";"Approved";"Rejected";"Expired";"Withdrawn"
+
+// EnrollmentRequestPhase is an enumeration of the different phases of an EnrollmentRequest.
+type EnrollmentRequestPhase string
+
+const (
+	// EnrollmentRequestPending -> the request is waiting for the decision of a Workspace manager.
+	EnrollmentRequestPending EnrollmentRequestPhase = "Pending"
+	// EnrollmentRequestApproved -> the request has been approved, and the Tenant enrolled in the Workspace.
+	EnrollmentRequestApproved EnrollmentRequestPhase = "Approved"
+	// EnrollmentRequestRejected -> the request has been rejected, and the Tenant removed from the Workspace.
+	EnrollmentRequestRejected EnrollmentRequestPhase = "Rejected"
+	// EnrollmentRequestExpired -> no decision has been taken in time, and the Tenant has been removed from the Workspace.
+	EnrollmentRequestExpired EnrollmentRequestPhase = "Expired"
+	// EnrollmentRequestWithdrawn -> the Tenant is no longer a candidate of the Workspace, without a decision on the request.
+	EnrollmentRequestWithdrawn EnrollmentRequestPhase = "Withdrawn"
+)
+
+// EnrollmentRequestSpec is the specification of the desired state of the EnrollmentRequest.
+type EnrollmentRequestSpec struct {
+	// The Tenant asking to be enrolled in the Workspace.
+	TenantRef GenericRef `json:"tenantRef"`
+
+	// The Workspace the Tenant asks to be enrolled in.
+	WorkspaceRef GenericRef `json:"workspaceRef"`
+
+	// +kubebuilder:validation:Optional
+
+	// The decision taken by a manager of the Workspace, which cannot be changed once set.
+	Decision EnrollmentDecision `json:"decision,omitempty"`
+
+	// The reason motivating the decision (mandatory in case of rejection), reported to the Tenant.
+	Reason string `json:"reason,omitempty"`
+}
+
+// EnrollmentRequestStatus reflects the most recently observed status of the EnrollmentRequest.
+type EnrollmentRequestStatus struct {
+	// The current phase of the request.
+	Phase EnrollmentRequestPhase `json:"phase,omitempty"`
+
+	// The timestamp the request has been observed for the first time.
+	RequestTime *metav1.Time `json:"requestTime,omitempty"`
+
+	// The timestamp the request expires, in case no decision is taken.
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+
+	// The timestamp the request has been completed (i.e., approved, rejected, expired or withdrawn).
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Whether the managers of the Workspace have been notified about the request.
+	ManagersNotified bool `json:"managersNotified,omitempty"`
+
+	// The managers of the Workspace already notified about the request, not to notify
+	// them again in case the delivery to the other ones fails and is retried.
+	NotifiedManagers []string `json:"notifiedManagers,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope="Cluster",shortName="enr"
+// +kubebuilder:printcolumn:name="Tenant",type=string,JSONPath=`.spec.tenantRef.name`
+// +kubebuilder:printcolumn:name="Workspace",type=string,JSONPath=`.spec.workspaceRef.name`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Expiration",type=date,JSONPath=`.status.expirationTime`,priority=10
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// EnrollmentRequest describes the request of a Tenant to be enrolled in a Workspace with
+// auto-enrollment with approval, which is approved or rejected by the Workspace managers.
+type EnrollmentRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EnrollmentRequestSpec   `json:"spec,omitempty"`
+	Status EnrollmentRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EnrollmentRequestList contains a list of EnrollmentRequest objects.
+type EnrollmentRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EnrollmentRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EnrollmentRequest{}, &EnrollmentRequestList{})
+}
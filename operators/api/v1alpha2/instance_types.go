@@ -16,6 +16,7 @@ package v1alpha2
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -96,6 +97,46 @@ type InstanceSpec struct {
 	// If set, it will be used to expose the Instance services to the outside world.
 	// LoadBalancer will be created with the specified ports thanks to MetalLB and annotations.
 	PublicExposure *InstancePublicExposure `json:"publicExposure,omitempty"`
+
+	// Optional per-environment override of the size of the persistent disk, keyed by the name of the environment.
+	// The disk is expanded (even while the Instance is running) when the requested size is larger than the
+	// one configured in the Template, while it is never shrunk.
+	DiskSizes map[string]resource.Quantity `json:"diskSizes,omitempty"`
+}
+
+// DiskResizePhase is an enumeration of the different phases of the expansion of a persistent disk.
+// +kubebuilder:validation:Enum="";"Resizing";"FileSystemResizePending";"Completed";"Failed"
+type DiskResizePhase string
+
+const (
+	// DiskResizePhaseUnset -> the disk has never been expanded.
+	DiskResizePhaseUnset DiskResizePhase = ""
+	// DiskResizePhaseResizing -> the expansion of the underlying volume is in progress.
+	DiskResizePhaseResizing DiskResizePhase = "Resizing"
+	// DiskResizePhaseFileSystemResizePending -> the volume has been expanded, and the file system will be expanded when the environment is (re)started.
+	DiskResizePhaseFileSystemResizePending DiskResizePhase = "FileSystemResizePending"
+	// DiskResizePhaseCompleted -> the expansion of the disk has been completed.
+	DiskResizePhaseCompleted DiskResizePhase = "Completed"
+	// DiskResizePhaseFailed -> the expansion of the disk failed (e.g., the storage class does not allow it).
+	DiskResizePhaseFailed DiskResizePhase = "Failed"
+)
+
+// InstanceDiskStatus reflects the state of the persistent disk of an environment.
+type InstanceDiskStatus struct {
+	// The name of the additional disk the status refers to, empty for the root disk.
+	Name string `json:"name,omitempty"`
+
+	// The size of the disk currently requested.
+	Requested resource.Quantity `json:"requested,omitempty"`
+
+	// The actual size of the disk, as reported by the underlying volume.
+	Capacity resource.Quantity `json:"capacity,omitempty"`
+
+	// The phase of the latest expansion of the disk, if any.
+	ResizePhase DiskResizePhase `json:"resizePhase,omitempty"`
+
+	// Additional details about the latest expansion of the disk, in case of failure.
+	Message string `json:"message,omitempty"`
 }
 
 // InstanceAutomationStatus reflects the status of the instance's automation (termination and submission).
@@ -140,6 +181,12 @@ type InstanceStatusEnv struct {
 
 	// Timestamps of the Instance automation phases (check, termination and submission).
 	Automation InstanceAutomationStatus `json:"automation,omitempty"`
+
+	// The status of the persistent disk of the environment, if any.
+	Disk *InstanceDiskStatus `json:"disk,omitempty"`
+
+	// The status of the persistent additional disks of the environment, if any.
+	AdditionalDisks []InstanceDiskStatus `json:"additionalDisks,omitempty"`
 }
 
 // InstanceStatus reflects the most recently observed status of the Instance.
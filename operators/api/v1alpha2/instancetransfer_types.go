@@ -0,0 +1,113 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum="";"Accepted";"Rejected"
+
+// InstanceTransferDecision is an enumeration of the decisions the recipient of an InstanceTransfer can take.
+type InstanceTransferDecision string
+
+const (
+	// InstanceTransferAccepted -> the recipient accepts the transfer, and the Instance is copied into its namespace.
+	InstanceTransferAccepted InstanceTransferDecision = "Accepted"
+	// InstanceTransferRejected -> the recipient refuses the transfer.
+	InstanceTransferRejected InstanceTransferDecision = "Rejected"
+)
+
+// +kubebuilder:validation:Enum="";"Pending";"Copying";"Completed";"Rejected";"Failed"
+
+// InstanceTransferPhase is an enumeration of the different phases of an InstanceTransfer.
+type InstanceTransferPhase string
+
+const (
+	// InstanceTransferPhasePending -> the transfer is waiting for the decision of the recipient, or for the source Instance to be stopped.
+	InstanceTransferPhasePending InstanceTransferPhase = "Pending"
+	// InstanceTransferPhaseCopying -> the persistent volumes of the Instance are being copied into the namespace of the recipient.
+	InstanceTransferPhaseCopying InstanceTransferPhase = "Copying"
+	// InstanceTransferPhaseCompleted -> the volumes have been copied, and the Instance created (stopped) in the namespace of the recipient.
+	InstanceTransferPhaseCompleted InstanceTransferPhase = "Completed"
+	// InstanceTransferPhaseRejected -> the recipient has refused the transfer.
+	InstanceTransferPhaseRejected InstanceTransferPhase = "Rejected"
+	// InstanceTransferPhaseFailed -> the transfer could not be completed.
+	InstanceTransferPhaseFailed InstanceTransferPhase = "Failed"
+)
+
+// InstanceTransferSpec is the specification of the desired state of the InstanceTransfer.
+type InstanceTransferSpec struct {
+	// The reference to the persistent Instance to be transferred, which shall be
+	// stopped (and not restarted) until the copy of its volumes completes.
+	InstanceRef GenericRef `json:"instanceRef"`
+
+	// The reference to the Tenant receiving a copy of the Instance, who shall be
+	// enrolled in the Workspace of the corresponding Template.
+	RecipientRef GenericRef `json:"recipientRef"`
+
+	// +kubebuilder:validation:Optional
+
+	// The decision taken by the recipient, which cannot be changed once set.
+	Decision InstanceTransferDecision `json:"decision,omitempty"`
+}
+
+// InstanceTransferStatus reflects the most recently observed status of the InstanceTransfer.
+type InstanceTransferStatus struct {
+	// The current phase of the transfer.
+	Phase InstanceTransferPhase `json:"phase,omitempty"`
+
+	// A human readable message describing the reason of the current phase.
+	Message string `json:"message,omitempty"`
+
+	// The reference to the Instance created in the namespace of the recipient.
+	TargetInstanceRef *GenericRef `json:"targetInstanceRef,omitempty"`
+
+	// The timestamp the transfer has been completed (i.e., completed, rejected or failed).
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope="Cluster",shortName="itr"
+// +kubebuilder:printcolumn:name="Instance",type=string,JSONPath=`.spec.instanceRef.name`
+// +kubebuilder:printcolumn:name="Source Namespace",type=string,JSONPath=`.spec.instanceRef.namespace`,priority=10
+// +kubebuilder:printcolumn:name="Recipient",type=string,JSONPath=`.spec.recipientRef.name`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`,priority=10
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// InstanceTransfer describes the request to hand a copy of a persistent Instance (e.g., for grading)
+// to another Tenant, which is performed once explicitly accepted by the recipient.
+type InstanceTransfer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InstanceTransferSpec   `json:"spec,omitempty"`
+	Status InstanceTransferStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// InstanceTransferList contains a list of InstanceTransfer objects.
+type InstanceTransferList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InstanceTransfer `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&InstanceTransfer{}, &InstanceTransferList{})
+}
@@ -22,7 +22,7 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
-// +kubebuilder:validation:Enum="";"Pending";"Provisioning";"Ready";"Deleting";"ResourceQuotaExceeded";"Error"
+// +kubebuilder:validation:Enum="";"Pending";"Restoring";"Provisioning";"Ready";"Deleting";"ResourceQuotaExceeded";"Error"
 
 // SharedVolumePhase is an enumeration of the different phases associated with a SharedVolume.
 type SharedVolumePhase string
@@ -32,6 +32,8 @@ const (
 	SharedVolumePhaseUnset SharedVolumePhase = ""
 	// SharedVolumePhasePending -> the shared volume is pending.
 	SharedVolumePhasePending SharedVolumePhase = "Pending"
+	// SharedVolumePhaseRestoring -> the shared volume's PVC is being restored from a snapshot.
+	SharedVolumePhaseRestoring SharedVolumePhase = "Restoring"
 	// SharedVolumePhaseProvisioning -> the shared volume's PVC is under provisioning.
 	SharedVolumePhaseProvisioning SharedVolumePhase = "Provisioning"
 	// SharedVolumePhaseReady -> the shared volume is bound and ready to be accessed.
@@ -51,6 +53,90 @@ type SharedVolumeSpec struct {
 
 	// The size of the volume.
 	Size resource.Quantity `json:"size"`
+
+	// The rules granting access to the Shared Volume. If empty, any Template can
+	// mount the volume, according to the access mode specified in the mount
+	// (read-write for the managers of the workspace). Otherwise, the volume can
+	// be mounted only by the tenants matched by at least one rule, and the most
+	// permissive access among the matching rules is granted.
+	// +optional
+	AccessControl []SharedVolumeAccessRule `json:"accessControl,omitempty"`
+
+	// The policy to periodically snapshot the Shared Volume.
+	// +optional
+	SnapshotPolicy *SharedVolumeSnapshotPolicy `json:"snapshotPolicy,omitempty"`
+
+	// The name of the SharedVolumeSnapshot, in the same namespace, the content
+	// of the Shared Volume is restored from at creation time. It cannot be
+	// changed once the Shared Volume has been created.
+	// +optional
+	RestoreFrom string `json:"restoreFrom,omitempty"`
+
+	// The source the content of the Shared Volume is populated from, once
+	// provisioned. Existing files are preserved, unless modified in the source.
+	// +optional
+	Source *ContentSource `json:"source,omitempty"`
+
+	// An arbitrary value which, when changed (e.g., set to the current time),
+	// triggers a new synchronization of the content from the source,
+	// overwriting the files modified in the volume.
+	// +optional
+	SyncRequest string `json:"syncRequest,omitempty"`
+}
+
+// SharedVolumeSnapshotPolicy describes the schedule and the retention of
+// the snapshots automatically taken for a Shared Volume.
+type SharedVolumeSnapshotPolicy struct {
+	// The interval between two consecutive scheduled snapshots.
+	Schedule metav1.Duration `json:"schedule"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=7
+
+	// The maximum number of ready scheduled snapshots retained: the oldest ones are
+	// deleted when exceeded (snapshots created manually are never deleted).
+	// Failed scheduled snapshots are deleted once a newer one has been taken.
+	Retention int `json:"retention,omitempty"`
+}
+
+// +kubebuilder:validation:Enum="ReadOnly";"ReadWrite"
+
+// SharedVolumeAccessMode is an enumeration of the access modes granted to a SharedVolume.
+type SharedVolumeAccessMode string
+
+const (
+	// SharedVolumeAccessNone -> no access is granted to the shared volume.
+	SharedVolumeAccessNone SharedVolumeAccessMode = ""
+	// SharedVolumeAccessReadOnly -> the shared volume can be mounted in read-only mode.
+	SharedVolumeAccessReadOnly SharedVolumeAccessMode = "ReadOnly"
+	// SharedVolumeAccessReadWrite -> the shared volume can be mounted in read-write mode.
+	SharedVolumeAccessReadWrite SharedVolumeAccessMode = "ReadWrite"
+)
+
+// SharedVolumeAccessRule grants access to the Shared Volume to the tenants
+// matching all the specified criteria (unspecified criteria match any tenant).
+type SharedVolumeAccessRule struct {
+	// The name of the tenant granted access.
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
+
+	// The name of the workspace whose members are granted access.
+	// +optional
+	Workspace string `json:"workspace,omitempty"`
+
+	// The role the tenants shall have in the workspace to be granted access
+	// (it requires the workspace to be specified).
+	// +kubebuilder:validation:Enum="manager";"user"
+	// +optional
+	Role WorkspaceUserRole `json:"role,omitempty"`
+
+	// The Template whose instances are granted access. The namespace defaults
+	// to the one of the Shared Volume.
+	// +optional
+	Template *GenericRef `json:"template,omitempty"`
+
+	// The access granted to the matching tenants.
+	Access SharedVolumeAccessMode `json:"access"`
 }
 
 // SharedVolumeStatus reflects the most recently observed status of the Shared Volume.
@@ -60,6 +146,41 @@ type SharedVolumeStatus struct {
 
 	// The current phase of the lifecycle of the Shared Volume.
 	Phase SharedVolumePhase `json:"phase,omitempty"`
+
+	// The capacity actually provisioned for the Shared Volume, which is lower
+	// than the requested size while an expansion is in progress.
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+
+	// The most recently observed usage of the Shared Volume.
+	Usage *SharedVolumeUsage `json:"usage,omitempty"`
+
+	// The content most recently synchronized from the source.
+	Content *SharedVolumeContentStatus `json:"content,omitempty"`
+}
+
+// SharedVolumeContentStatus reflects the content synchronized from the source of the Shared Volume.
+type SharedVolumeContentStatus struct {
+	// The revision of the content: the commit hash for Git repositories,
+	// and the SHA-256 checksum for archives.
+	Revision string `json:"revision,omitempty"`
+
+	// The sync request the synchronization corresponds to.
+	SyncRequest string `json:"syncRequest,omitempty"`
+
+	// The time the content has been synchronized.
+	LastSyncTime metav1.Time `json:"lastSyncTime"`
+}
+
+// SharedVolumeUsage reflects the space used and available on the Shared Volume.
+type SharedVolumeUsage struct {
+	// The space used on the Shared Volume.
+	Used resource.Quantity `json:"used"`
+
+	// The space still available on the Shared Volume.
+	Available resource.Quantity `json:"available"`
+
+	// The time the usage has been observed.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
 }
 
 // +kubebuilder:object:root=true
@@ -68,6 +189,9 @@ type SharedVolumeStatus struct {
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Pretty Name",type=string,JSONPath=`.spec.prettyName`
 // +kubebuilder:printcolumn:name="Size",type=string,JSONPath=`.spec.size`
+// +kubebuilder:printcolumn:name="Capacity",type=string,JSONPath=`.status.capacity`,priority=10
+// +kubebuilder:printcolumn:name="Used",type=string,JSONPath=`.status.usage.used`,priority=10
+// +kubebuilder:printcolumn:name="Revision",type=string,JSONPath=`.status.content.revision`,priority=10
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
@@ -0,0 +1,95 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum="";"Pending";"Creating";"Ready";"Error"
+
+// SharedVolumeSnapshotPhase is an enumeration of the different phases associated with a SharedVolumeSnapshot.
+type SharedVolumeSnapshotPhase string
+
+const (
+	// SharedVolumeSnapshotPhaseUnset -> the shared volume snapshot phase is unknown.
+	SharedVolumeSnapshotPhaseUnset SharedVolumeSnapshotPhase = ""
+	// SharedVolumeSnapshotPhasePending -> the shared volume snapshot is waiting for the shared volume to be ready.
+	SharedVolumeSnapshotPhasePending SharedVolumeSnapshotPhase = "Pending"
+	// SharedVolumeSnapshotPhaseCreating -> the underlying volume snapshot is being created.
+	SharedVolumeSnapshotPhaseCreating SharedVolumeSnapshotPhase = "Creating"
+	// SharedVolumeSnapshotPhaseReady -> the shared volume snapshot is ready to be restored.
+	SharedVolumeSnapshotPhaseReady SharedVolumeSnapshotPhase = "Ready"
+	// SharedVolumeSnapshotPhaseError -> the shared volume snapshot had an error during reconcile.
+	SharedVolumeSnapshotPhaseError SharedVolumeSnapshotPhase = "Error"
+)
+
+// SharedVolumeSnapshotSpec is the specification of the desired state of the Shared Volume Snapshot.
+type SharedVolumeSnapshotSpec struct {
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="sharedVolumeName is immutable"
+
+	// The name of the Shared Volume to be snapshotted, which shall belong
+	// to the same namespace of the snapshot.
+	SharedVolumeName string `json:"sharedVolumeName"`
+}
+
+// SharedVolumeSnapshotStatus reflects the most recently observed status of the Shared Volume Snapshot.
+type SharedVolumeSnapshotStatus struct {
+	// The current phase of the lifecycle of the Shared Volume Snapshot.
+	Phase SharedVolumeSnapshotPhase `json:"phase,omitempty"`
+
+	// The CSI VolumeSnapshot linked to the Shared Volume Snapshot.
+	VolumeSnapshotName string `json:"volumeSnapshotName,omitempty"`
+
+	// The minimum size of a Shared Volume restored from this snapshot.
+	RestoreSize *resource.Quantity `json:"restoreSize,omitempty"`
+
+	// The time the point-in-time snapshot has been taken by the storage system.
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName="shvolsnap"
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Shared Volume",type=string,JSONPath=`.spec.sharedVolumeName`
+// +kubebuilder:printcolumn:name="Restore Size",type=string,JSONPath=`.status.restoreSize`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SharedVolumeSnapshot describes a point-in-time snapshot of a shared volume in CrownLabs.
+type SharedVolumeSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SharedVolumeSnapshotSpec   `json:"spec,omitempty"`
+	Status SharedVolumeSnapshotStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SharedVolumeSnapshotList contains a list of SharedVolumeSnapshot objects.
+type SharedVolumeSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []SharedVolumeSnapshot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SharedVolumeSnapshot{}, &SharedVolumeSnapshotList{})
+}
@@ -17,6 +17,7 @@ package v1alpha2
 import (
 	"encoding/json"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apicommon "github.com/netgroup-polito/CrownLabs/operators/api/common"
@@ -41,28 +42,41 @@ const (
 	ClassLocalVM EnvironmentType = "LocalVM"
 )
 
+// +kubebuilder:validation:Enum="virtio";"sata";"scsi"
+
+// DiskBus is an enumeration of the buses an additional disk can be attached to.
+type DiskBus string
+
+const (
+	// DiskBusVirtio -> the disk is attached through the paravirtualized virtio bus.
+	DiskBusVirtio DiskBus = "virtio"
+	// DiskBusSata -> the disk is attached through the emulated SATA bus.
+	DiskBusSata DiskBus = "sata"
+	// DiskBusScsi -> the disk is attached through the emulated SCSI bus.
+	DiskBusScsi DiskBus = "scsi"
+)
+
 // CleanupOptions defines the automatic actions to enforce termination policies.
+// Each option, if omitted, is inherited from the automation policy of the Workspace
+// the Template belongs to, if any, and it is considered as "never" otherwise.
 type CleanupOptions struct {
 	// +kubebuilder:validation:Pattern="^(never|[0-9]+[smhd])$"
-	// +kubebuilder:default="never"
 	// The maximum lifetime of an Instance referencing the current Template.
 	// Once this period is expired, the Instance will be automatically deleted
 	// regardless of whether it is still in use or not.
 	// If set to "never", the instance will not be automatically terminated.
-	DeleteAfterCreation string `json:"deleteAfterCreation"`
+	DeleteAfterCreation string `json:"deleteAfterCreation,omitempty"`
 
 	// +kubebuilder:validation:Pattern="^(never|[0-9]+[smhd])$"
-	// +kubebuilder:default="never"
 	// The maximum period of inactivity after which an Instance referencing
 	// the current Template will be automatically stopped, or deleted
 	// (if not persistent) to save resources.
-	StopAfterInactivity string `json:"stopAfterInactivity"`
+	StopAfterInactivity string `json:"stopAfterInactivity,omitempty"`
 
 	// +kubebuilder:validation:Pattern="^(never|[0-9]+[smhd])$"
-	// +kubebuilder:default="never"
 	// The maximum period of time a persistent instance can remain powered off
 	// after being stopped for inactivity, before being completely deleted.
-	DeleteAfterInactivity string `json:"deleteAfterInactivity"`
+	DeleteAfterInactivity string `json:"deleteAfterInactivity,omitempty"`
 }
 
 // TemplateSpec is the specification of the desired state of the Template.
@@ -77,13 +91,16 @@ type TemplateSpec struct {
 	WorkspaceRef GenericRef `json:"workspace.crownlabs.polito.it/WorkspaceRef,omitempty"`
 
 	// The list of environments (i.e. VMs or containers) that compose the Template.
-	// Each environment must have a unique name within the Template.
+	// Each environment must have a unique name within the Template, which cannot match
+	// the one of the volume of an additional disk of another environment (i.e.,
+	// <environment>-<disk>), as they share the same naming scheme.
 	// +kubebuilder:validation:MaxItems:=10
+	// +kubebuilder:validation:XValidation:rule="self.all(e, !has(e.additionalDisks) || e.additionalDisks.all(d, !self.exists(o, o.name == e.name + '-' + d.name)))",message="environment names cannot match <environment>-<disk> for the additional disks of another environment"
 	// +listType=map
 	// +listMapKey=name
 	EnvironmentList []Environment `json:"environmentList"`
 
-	// +kubebuilder:default={deleteAfterCreation: "never", stopAfterInactivity: "never", deleteAfterInactivity: "never"}
+	// +kubebuilder:default={}
 	// Automatic actions to enforce termination policies.
 	Cleanup CleanupOptions `json:"cleanup"`
 
@@ -102,12 +119,14 @@ type TemplateStatus struct {
 }
 
 // Environment defines the characteristics of an environment composing the Template.
+// +kubebuilder:validation:XValidation:rule="!has(self.additionalDisks) || size(self.additionalDisks) == 0 || self.environmentType in ['VirtualMachine', 'CloudVM', 'LocalVM']",message="additionalDisks are supported only by VM-based environments"
 type Environment struct {
 	// The name identifying the specific environment.
 	// The name must be unique within the Template and must follow the Kubernetes
 	// naming conventions, i.e. it must consist of lower case alphanumeric characters,
 	// '-' or '.', must start and end with an alphanumeric character.
 	// +kubebuilder:validation:Pattern="^[a-z\\d][a-z\\d-]{2,10}[a-z\\d]$"
+	// +kubebuilder:validation:MaxLength=12
 	Name string `json:"name"`
 
 	// The VM or container to be started when instantiating the environment.
@@ -152,6 +171,40 @@ type Environment struct {
 
 	// The list of information about Shared Volumes that has to be mounted to the instance.
 	SharedVolumeMounts []SharedVolumeMountInfo `json:"sharedVolumeMounts,omitempty"`
+
+	// The hook archiving the content of the environment before the instance is
+	// automatically stopped or deleted. Supported only by container-based environments.
+	PreTerminationHook *PreTerminationHook `json:"preTerminationHook,omitempty"`
+
+	// The list of data disks attached to the environment in addition to the root one.
+	// Supported only by VM-based environments.
+	// +kubebuilder:validation:MaxItems:=8
+	// +listType=map
+	// +listMapKey=name
+	AdditionalDisks []AdditionalDisk `json:"additionalDisks,omitempty"`
+}
+
+// AdditionalDisk defines a data disk attached to a VM-based environment in addition to the root one.
+type AdditionalDisk struct {
+	// The name identifying the disk, which must be unique within the environment.
+	// +kubebuilder:validation:Pattern="^[a-z\\d][a-z\\d-]{0,10}[a-z\\d]$"
+	// +kubebuilder:validation:MaxLength=12
+	Name string `json:"name"`
+
+	// The size of the disk.
+	Size resource.Quantity `json:"size"`
+
+	// +kubebuilder:default="virtio"
+	// The bus the disk is attached to.
+	Bus DiskBus `json:"bus,omitempty"`
+
+	// The registry image the disk is populated from. If empty, the disk is blank.
+	Image string `json:"image,omitempty"`
+
+	// +kubebuilder:default=false
+	// Whether the content of the disk is preserved across restarts of the environment
+	// (backed by a DataVolume) or not (backed by an ephemeral disk).
+	Persistent bool `json:"persistent,omitempty"`
 }
 
 // EnvironmentResources is the specification of the amount of resources
@@ -205,6 +258,33 @@ type ContainerStartupOpts struct {
 	EnforceWorkdir bool `json:"enforceWorkdir"`
 }
 
+// +kubebuilder:validation:Enum="MyDrive";"ContentDestination"
+
+// PreTerminationHookTarget is an enumeration of the destinations the content of an environment can be archived to.
+type PreTerminationHookTarget string
+
+const (
+	// PreTerminationHookTargetMyDrive -> the content is archived to the MyDrive of the tenant owning the instance.
+	PreTerminationHookTargetMyDrive PreTerminationHookTarget = "MyDrive"
+	// PreTerminationHookTargetContentDestination -> the content is uploaded to the destination URL of the instance content.
+	PreTerminationHookTargetContentDestination PreTerminationHookTarget = "ContentDestination"
+)
+
+// PreTerminationHook specifies how the content of an environment is saved before
+// the corresponding instance is automatically stopped or deleted.
+type PreTerminationHook struct {
+	// +kubebuilder:default="MyDrive"
+	// The destination the content of the environment is archived to, either
+	// the MyDrive of the tenant (which must be mounted in the environment) or
+	// the destination URL configured in the content URLs of the instance.
+	Target PreTerminationHookTarget `json:"target,omitempty"`
+
+	// The maximum time to wait for the archive to be completed, after which
+	// the instance is stopped or deleted anyway. If omitted, the default
+	// timeout configured in the automation controllers is used.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
 // SharedVolumeMountInfo contains mount information for a Shared Volume.
 type SharedVolumeMountInfo struct {
 	// The reference of the Shared Volume this Mount Info is related to.
@@ -15,6 +15,7 @@
 package v1alpha2
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -47,12 +48,25 @@ const (
 
 // TenantWorkspaceEntry contains the information regarding one of the Workspaces
 // the Tenant is subscribed to, including his/her role.
+// +kubebuilder:validation:XValidation:rule="!has(self.validFrom) || !has(self.validUntil) || self.validFrom < self.validUntil",message="validFrom must precede validUntil"
 type TenantWorkspaceEntry struct {
 	// The Workspace the Tenant is subscribed to.
 	Name string `json:"name"`
 
 	// The role of the Tenant in the context of the Workspace.
 	Role WorkspaceUserRole `json:"role"`
+
+	// +kubebuilder:validation:Optional
+
+	// The time from which the membership is valid (immediately, if not specified).
+	ValidFrom *metav1.Time `json:"validFrom,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// The time until which the membership is valid (indefinitely, if not specified).
+	// Outside of the validity period, the Tenant is not granted access to the Workspace,
+	// and the corresponding resources are not accounted in his/her quota.
+	ValidUntil *metav1.Time `json:"validUntil,omitempty"`
 }
 
 // TenantSpec is the specification of the desired state of the Tenant.
@@ -92,6 +106,13 @@ type TenantSpec struct {
 
 	// The amount of resources associated with the Tenant's personal workspace. If defined, the personal workspace is enabled.
 	PersonalWorkspace *apicommon.WorkspaceResourceQuota `json:"personalWorkspace,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// The size of the MyDrive (i.e., the personal storage) of the Tenant, overriding
+	// the one inherited from the Workspaces and the default one. The volume is expanded
+	// when the size grows, while it is never shrunk.
+	MyDriveSize *resource.Quantity `json:"myDriveSize,omitempty"`
 }
 
 // KeycloakStatus defines the status of the authentication flow with Keycloak.
@@ -128,6 +149,14 @@ type TenantStatus struct {
 	// which do not exist.
 	FailingWorkspaces []string `json:"failingWorkspaces,omitempty"`
 
+	// The list of the Workspaces the Tenant is subscribed to, but whose membership
+	// is currently not valid (i.e., not yet started or already expired).
+	InactiveWorkspaces []string `json:"inactiveWorkspaces,omitempty"`
+
+	// The list of the Workspaces whose membership is about to expire, and for
+	// which the Tenant has already been notified.
+	ExpiringWorkspacesNotified []string `json:"expiringWorkspacesNotified,omitempty"`
+
 	// The list of the subscriptions to external services (e.g. Keycloak,
 	// ...), indicating for each one whether it succeeded or an error
 	// occurred.
@@ -144,6 +173,38 @@ type TenantStatus struct {
 
 	// Whether a personal workspace has been created for the tenant.
 	PersonalWorkspaceCreated bool `json:"personalWorkspaceCreated"`
+
+	// The status of the MyDrive (i.e., the personal storage) of the Tenant.
+	MyDrive *MyDriveStatus `json:"myDrive,omitempty"`
+}
+
+// MyDriveStatus reflects the most recently observed status of the MyDrive of the Tenant.
+type MyDriveStatus struct {
+	// The size requested for the MyDrive volume.
+	Size resource.Quantity `json:"size"`
+
+	// The capacity actually provisioned for the MyDrive volume, which is lower
+	// than the requested size while an expansion is in progress.
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+
+	// The most recently observed usage of the MyDrive volume.
+	Usage *MyDriveUsage `json:"usage,omitempty"`
+
+	// The highest usage threshold (in percentage) crossed by the MyDrive volume,
+	// for which the Tenant has already been warned.
+	NotifiedUsageThreshold int `json:"notifiedUsageThreshold,omitempty"`
+}
+
+// MyDriveUsage reflects the space used and available on the MyDrive volume.
+type MyDriveUsage struct {
+	// The space used on the MyDrive volume.
+	Used resource.Quantity `json:"used"`
+
+	// The space still available on the MyDrive volume.
+	Available resource.Quantity `json:"available"`
+
+	// The time the usage has been observed.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
 }
 
 // +kubebuilder:object:root=true
@@ -155,6 +216,8 @@ type TenantStatus struct {
 // +kubebuilder:printcolumn:name="Email",type=string,JSONPath=`.spec.email`,priority=10
 // +kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=`.status.personalNamespace.name`,priority=10
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.ready`
+// +kubebuilder:printcolumn:name="MyDrive Size",type=string,JSONPath=`.status.myDrive.size`,priority=10
+// +kubebuilder:printcolumn:name="MyDrive Used",type=string,JSONPath=`.status.myDrive.usage.used`,priority=10
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Tenant describes a user of CrownLabs.
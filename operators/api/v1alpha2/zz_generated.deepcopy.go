@@ -20,9 +20,27 @@ package v1alpha2
 
 import (
 	"github.com/netgroup-polito/CrownLabs/operators/api/common"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalDisk) DeepCopyInto(out *AdditionalDisk) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalDisk.
+func (in *AdditionalDisk) DeepCopy() *AdditionalDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CleanupOptions) DeepCopyInto(out *CleanupOptions) {
 	*out = *in
@@ -58,6 +76,134 @@ func (in *ContainerStartupOpts) DeepCopy() *ContainerStartupOpts {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContentSource) DeepCopyInto(out *ContentSource) {
+	*out = *in
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitContentSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentSource.
+func (in *ContentSource) DeepCopy() *ContentSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ContentSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnrollmentRequest) DeepCopyInto(out *EnrollmentRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnrollmentRequest.
+func (in *EnrollmentRequest) DeepCopy() *EnrollmentRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(EnrollmentRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnrollmentRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnrollmentRequestList) DeepCopyInto(out *EnrollmentRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EnrollmentRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnrollmentRequestList.
+func (in *EnrollmentRequestList) DeepCopy() *EnrollmentRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(EnrollmentRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnrollmentRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnrollmentRequestSpec) DeepCopyInto(out *EnrollmentRequestSpec) {
+	*out = *in
+	out.TenantRef = in.TenantRef
+	out.WorkspaceRef = in.WorkspaceRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnrollmentRequestSpec.
+func (in *EnrollmentRequestSpec) DeepCopy() *EnrollmentRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnrollmentRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnrollmentRequestStatus) DeepCopyInto(out *EnrollmentRequestStatus) {
+	*out = *in
+	if in.RequestTime != nil {
+		in, out := &in.RequestTime, &out.RequestTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NotifiedManagers != nil {
+		in, out := &in.NotifiedManagers, &out.NotifiedManagers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnrollmentRequestStatus.
+func (in *EnrollmentRequestStatus) DeepCopy() *EnrollmentRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EnrollmentRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Environment) DeepCopyInto(out *Environment) {
 	*out = *in
@@ -72,6 +218,18 @@ func (in *Environment) DeepCopyInto(out *Environment) {
 		*out = make([]SharedVolumeMountInfo, len(*in))
 		copy(*out, *in)
 	}
+	if in.PreTerminationHook != nil {
+		in, out := &in.PreTerminationHook, &out.PreTerminationHook
+		*out = new(PreTerminationHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalDisks != nil {
+		in, out := &in.AdditionalDisks, &out.AdditionalDisks
+		*out = make([]AdditionalDisk, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Environment.
@@ -115,6 +273,21 @@ func (in *GenericRef) DeepCopy() *GenericRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitContentSource) DeepCopyInto(out *GitContentSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitContentSource.
+func (in *GitContentSource) DeepCopy() *GitContentSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GitContentSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Instance) DeepCopyInto(out *Instance) {
 	*out = *in
@@ -175,6 +348,23 @@ func (in *InstanceContentUrls) DeepCopy() *InstanceContentUrls {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceDiskStatus) DeepCopyInto(out *InstanceDiskStatus) {
+	*out = *in
+	out.Requested = in.Requested.DeepCopy()
+	out.Capacity = in.Capacity.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceDiskStatus.
+func (in *InstanceDiskStatus) DeepCopy() *InstanceDiskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceDiskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceList) DeepCopyInto(out *InstanceList) {
 	*out = *in
@@ -362,6 +552,13 @@ func (in *InstanceSpec) DeepCopyInto(out *InstanceSpec) {
 		*out = new(InstancePublicExposure)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DiskSizes != nil {
+		in, out := &in.DiskSizes, &out.DiskSizes
+		*out = make(map[string]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceSpec.
@@ -412,6 +609,18 @@ func (in *InstanceStatus) DeepCopy() *InstanceStatus {
 func (in *InstanceStatusEnv) DeepCopyInto(out *InstanceStatusEnv) {
 	*out = *in
 	in.Automation.DeepCopyInto(&out.Automation)
+	if in.Disk != nil {
+		in, out := &in.Disk, &out.Disk
+		*out = new(InstanceDiskStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalDisks != nil {
+		in, out := &in.AdditionalDisks, &out.AdditionalDisks
+		*out = make([]InstanceDiskStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceStatusEnv.
@@ -424,6 +633,106 @@ func (in *InstanceStatusEnv) DeepCopy() *InstanceStatusEnv {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceTransfer) DeepCopyInto(out *InstanceTransfer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceTransfer.
+func (in *InstanceTransfer) DeepCopy() *InstanceTransfer {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceTransfer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InstanceTransfer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceTransferList) DeepCopyInto(out *InstanceTransferList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InstanceTransfer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceTransferList.
+func (in *InstanceTransferList) DeepCopy() *InstanceTransferList {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceTransferList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InstanceTransferList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceTransferSpec) DeepCopyInto(out *InstanceTransferSpec) {
+	*out = *in
+	out.InstanceRef = in.InstanceRef
+	out.RecipientRef = in.RecipientRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceTransferSpec.
+func (in *InstanceTransferSpec) DeepCopy() *InstanceTransferSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceTransferSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceTransferStatus) DeepCopyInto(out *InstanceTransferStatus) {
+	*out = *in
+	if in.TargetInstanceRef != nil {
+		in, out := &in.TargetInstanceRef, &out.TargetInstanceRef
+		*out = new(GenericRef)
+		**out = **in
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceTransferStatus.
+func (in *InstanceTransferStatus) DeepCopy() *InstanceTransferStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceTransferStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakStatus) DeepCopyInto(out *KeycloakStatus) {
 	*out = *in
@@ -440,6 +749,50 @@ func (in *KeycloakStatus) DeepCopy() *KeycloakStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MyDriveStatus) DeepCopyInto(out *MyDriveStatus) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(MyDriveUsage)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MyDriveStatus.
+func (in *MyDriveStatus) DeepCopy() *MyDriveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MyDriveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MyDriveUsage) DeepCopyInto(out *MyDriveUsage) {
+	*out = *in
+	out.Used = in.Used.DeepCopy()
+	out.Available = in.Available.DeepCopy()
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MyDriveUsage.
+func (in *MyDriveUsage) DeepCopy() *MyDriveUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(MyDriveUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NameCreated) DeepCopyInto(out *NameCreated) {
 	*out = *in
@@ -455,6 +808,26 @@ func (in *NameCreated) DeepCopy() *NameCreated {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreTerminationHook) DeepCopyInto(out *PreTerminationHook) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreTerminationHook.
+func (in *PreTerminationHook) DeepCopy() *PreTerminationHook {
+	if in == nil {
+		return nil
+	}
+	out := new(PreTerminationHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PublicServicePort) DeepCopyInto(out *PublicServicePort) {
 	*out = *in
@@ -476,7 +849,7 @@ func (in *SharedVolume) DeepCopyInto(out *SharedVolume) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolume.
@@ -497,6 +870,42 @@ func (in *SharedVolume) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedVolumeAccessRule) DeepCopyInto(out *SharedVolumeAccessRule) {
+	*out = *in
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(GenericRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolumeAccessRule.
+func (in *SharedVolumeAccessRule) DeepCopy() *SharedVolumeAccessRule {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedVolumeAccessRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedVolumeContentStatus) DeepCopyInto(out *SharedVolumeContentStatus) {
+	*out = *in
+	in.LastSyncTime.DeepCopyInto(&out.LastSyncTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolumeContentStatus.
+func (in *SharedVolumeContentStatus) DeepCopy() *SharedVolumeContentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedVolumeContentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SharedVolumeList) DeepCopyInto(out *SharedVolumeList) {
 	*out = *in
@@ -545,10 +954,141 @@ func (in *SharedVolumeMountInfo) DeepCopy() *SharedVolumeMountInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedVolumeSnapshot) DeepCopyInto(out *SharedVolumeSnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolumeSnapshot.
+func (in *SharedVolumeSnapshot) DeepCopy() *SharedVolumeSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedVolumeSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SharedVolumeSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedVolumeSnapshotList) DeepCopyInto(out *SharedVolumeSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SharedVolumeSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolumeSnapshotList.
+func (in *SharedVolumeSnapshotList) DeepCopy() *SharedVolumeSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedVolumeSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SharedVolumeSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedVolumeSnapshotPolicy) DeepCopyInto(out *SharedVolumeSnapshotPolicy) {
+	*out = *in
+	out.Schedule = in.Schedule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolumeSnapshotPolicy.
+func (in *SharedVolumeSnapshotPolicy) DeepCopy() *SharedVolumeSnapshotPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedVolumeSnapshotPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedVolumeSnapshotSpec) DeepCopyInto(out *SharedVolumeSnapshotSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolumeSnapshotSpec.
+func (in *SharedVolumeSnapshotSpec) DeepCopy() *SharedVolumeSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedVolumeSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedVolumeSnapshotStatus) DeepCopyInto(out *SharedVolumeSnapshotStatus) {
+	*out = *in
+	if in.RestoreSize != nil {
+		in, out := &in.RestoreSize, &out.RestoreSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolumeSnapshotStatus.
+func (in *SharedVolumeSnapshotStatus) DeepCopy() *SharedVolumeSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedVolumeSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SharedVolumeSpec) DeepCopyInto(out *SharedVolumeSpec) {
 	*out = *in
 	out.Size = in.Size.DeepCopy()
+	if in.AccessControl != nil {
+		in, out := &in.AccessControl, &out.AccessControl
+		*out = make([]SharedVolumeAccessRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SnapshotPolicy != nil {
+		in, out := &in.SnapshotPolicy, &out.SnapshotPolicy
+		*out = new(SharedVolumeSnapshotPolicy)
+		**out = **in
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(ContentSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolumeSpec.
@@ -564,6 +1104,21 @@ func (in *SharedVolumeSpec) DeepCopy() *SharedVolumeSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SharedVolumeStatus) DeepCopyInto(out *SharedVolumeStatus) {
 	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(SharedVolumeUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Content != nil {
+		in, out := &in.Content, &out.Content
+		*out = new(SharedVolumeContentStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolumeStatus.
@@ -576,6 +1131,24 @@ func (in *SharedVolumeStatus) DeepCopy() *SharedVolumeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedVolumeUsage) DeepCopyInto(out *SharedVolumeUsage) {
+	*out = *in
+	out.Used = in.Used.DeepCopy()
+	out.Available = in.Available.DeepCopy()
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolumeUsage.
+func (in *SharedVolumeUsage) DeepCopy() *SharedVolumeUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedVolumeUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Template) DeepCopyInto(out *Template) {
 	*out = *in
@@ -754,7 +1327,9 @@ func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
 	if in.Workspaces != nil {
 		in, out := &in.Workspaces, &out.Workspaces
 		*out = make([]TenantWorkspaceEntry, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.PublicKeys != nil {
 		in, out := &in.PublicKeys, &out.PublicKeys
@@ -766,6 +1341,11 @@ func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
 		*out = new(common.WorkspaceResourceQuota)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MyDriveSize != nil {
+		in, out := &in.MyDriveSize, &out.MyDriveSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
@@ -788,6 +1368,16 @@ func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.InactiveWorkspaces != nil {
+		in, out := &in.InactiveWorkspaces, &out.InactiveWorkspaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpiringWorkspacesNotified != nil {
+		in, out := &in.ExpiringWorkspacesNotified, &out.ExpiringWorkspacesNotified
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Subscriptions != nil {
 		in, out := &in.Subscriptions, &out.Subscriptions
 		*out = make(map[string]SubscriptionStatus, len(*in))
@@ -796,6 +1386,11 @@ func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
 		}
 	}
 	out.Keycloak = in.Keycloak
+	if in.MyDrive != nil {
+		in, out := &in.MyDrive, &out.MyDrive
+		*out = new(MyDriveStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantStatus.
@@ -811,6 +1406,14 @@ func (in *TenantStatus) DeepCopy() *TenantStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantWorkspaceEntry) DeepCopyInto(out *TenantWorkspaceEntry) {
 	*out = *in
+	if in.ValidFrom != nil {
+		in, out := &in.ValidFrom, &out.ValidFrom
+		*out = (*in).DeepCopy()
+	}
+	if in.ValidUntil != nil {
+		in, out := &in.ValidUntil, &out.ValidUntil
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantWorkspaceEntry.
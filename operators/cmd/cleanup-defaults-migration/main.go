@@ -0,0 +1,86 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main contains the entrypoint for the one-off migration of the former default Template cleanup options.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/klog/v2/textlogger"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/instautoctrl"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils/restcfg"
+)
+
+func main() {
+	createdBefore := flag.String("created-before", "", "Only migrate the Templates created before this time (RFC3339), i.e., the upgrade time")
+	apply := flag.Bool("apply", false, "Apply the changes, rather than only showing them")
+
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	log := textlogger.NewLogger(textlogger.NewConfig()).WithName("cleanup-defaults-migration")
+	ctrl.SetLogger(log)
+	ctx := ctrl.LoggerInto(context.Background(), log)
+
+	if *createdBefore == "" {
+		fmt.Fprintln(os.Stderr, "the creation time limit is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	limit, err := time.Parse(time.RFC3339, *createdBefore)
+	if err != nil {
+		log.Error(err, "invalid creation time limit", "created-before", *createdBefore)
+		os.Exit(2)
+	}
+
+	rscheme := runtime.NewScheme()
+	utilruntime.Must(clv1alpha2.AddToScheme(rscheme))
+
+	kubeconfig, err := ctrl.GetConfig()
+	if err != nil {
+		log.Error(err, "unable to get kubeconfig")
+		os.Exit(1)
+	}
+	k8sClient, err := client.New(restcfg.SetRateLimiter(kubeconfig), client.Options{Scheme: rscheme})
+	if err != nil {
+		log.Error(err, "unable to prepare k8s client")
+		os.Exit(1)
+	}
+
+	migrated, err := instautoctrl.MigrateFormerCleanupDefaults(ctx, k8sClient, limit, *apply)
+	for _, template := range migrated {
+		fmt.Println(template.String())
+	}
+	if err != nil {
+		log.Error(err, "unable to migrate some templates")
+		os.Exit(1)
+	}
+	if !*apply {
+		fmt.Println("dry-run: no changes applied (use --apply to apply them)")
+		return
+	}
+	fmt.Println("migration completed")
+}
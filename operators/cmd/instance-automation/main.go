@@ -17,6 +17,8 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -107,6 +109,21 @@ func main() {
 	enableInactivityNotifications := flag.Bool("enable-inactivity-notifications", false, "Enable the sending of inactivity notifications to users on instance inactivity")
 	enableExpirationNotifications := flag.Bool("enable-expiration-notifications", false, "Enable the sending of expiration notifications to users on instance expiration")
 
+	enableNotificationDigests := flag.Bool("enable-notification-digests", false, "Enable the aggregation of the automation notifications into per-tenant digests, instead of sending them immediately")
+	notificationOutboxNamespace := flag.String("notification-outbox-namespace", "default", "The namespace where the ConfigMaps storing the notifications waiting to be sent as digests are created")
+	notificationDigestInterval := flag.Duration("notification-digest-interval", 1*time.Hour, "The interval between two consecutive digests sent to the same tenant")
+	notificationRetryBaseDelay := flag.Duration("notification-retry-base-delay", 5*time.Minute, "The initial delay before retrying the delivery of a digest after a failure (doubled at each consecutive failure)")
+	notificationRetryMaxDelay := flag.Duration("notification-retry-max-delay", 6*time.Hour, "The maximum delay before retrying the delivery of a digest after a failure")
+
+	dryRun := flag.Bool("dry-run", false, "Enable the dry-run mode for all Instances, in which the automation controllers only record the actions they would perform (it can be enabled for specific Templates and Namespaces through the "+forge.AutomationDryRunLabel+" label)")
+
+	enablePreTerminationHooks := flag.Bool("enable-pre-termination-hooks", false, "Enable the pre-termination hooks, archiving the content of the environments configuring them before the instances are automatically stopped/deleted")
+	preTerminationHookTimeout := flag.Duration("pre-termination-hook-timeout", 10*time.Minute, "The maximum time to wait for the pre-termination hooks, unless overridden by the environment, after which the instances are stopped/deleted anyway")
+
+	quietHours := flag.String("quiet-hours", "", "Comma-separated list of daily windows (HH:MM-HH:MM) during which no automation notification is sent and no instance is stopped/deleted")
+	automationTimeZone := flag.String("automation-time-zone", "UTC", "The IANA name of the time zone used to evaluate the quiet hours and the holidays")
+	holidayCalendarFile := flag.String("holiday-calendar-file", "", "The path of an iCalendar file listing the holidays during which no instance is stopped/deleted")
+
 	mailTemplateDir := flag.String("mail-template-dir", "/etc/crownmail/templates", "The directory containing email templates and configuration (typically through a mounted ConfigMap)")
 	mailConfigDir := flag.String("mail-config-dir", "/etc/crownmail/configs", "The directory containing email configuration (typically through a mounted Secret)")
 
@@ -121,9 +138,14 @@ func main() {
 	whiteListMap := parseMap(*namespaceWhiteList)
 	log.Info("restricting reconciled namespaces", "labels", *namespaceWhiteList)
 
+	dryRunAuditor := &instautoctrl.DryRunAuditor{Global: *dryRun}
+
 	mgr, err := ctrl.NewManager(restcfg.SetRateLimiter(ctrl.GetConfigOrDie()), ctrl.Options{
-		Scheme:                 rscheme,
-		Metrics:                server.Options{BindAddress: *metricsAddr},
+		Scheme: rscheme,
+		Metrics: server.Options{
+			BindAddress:   *metricsAddr,
+			ExtraHandlers: map[string]http.Handler{instautoctrl.DryRunReportPath: dryRunAuditor},
+		},
 		LeaderElection:         *enableLeaderElection,
 		HealthProbeBindAddress: ":8081",
 		LivenessEndpointName:   "/healthz",
@@ -149,6 +171,33 @@ func main() {
 		os.Exit(1)
 	}
 
+	schedule, err := buildAutomationSchedule(*quietHours, *automationTimeZone, *holidayCalendarFile)
+	if err != nil {
+		log.Error(err, "unable to configure the automation schedule")
+		os.Exit(1)
+	}
+
+	var outbox *instautoctrl.NotificationOutbox
+	if *enableNotificationDigests {
+		log.Info("Notification digests enabled.", "namespace", *notificationOutboxNamespace, "interval", *notificationDigestInterval)
+		outbox = &instautoctrl.NotificationOutbox{
+			Client:    mgr.GetClient(),
+			Namespace: *notificationOutboxNamespace,
+		}
+
+		if err := mgr.Add(&instautoctrl.NotificationDispatcher{
+			Client:         mgr.GetClient(),
+			Sender:         mailClient,
+			Namespace:      *notificationOutboxNamespace,
+			DigestInterval: *notificationDigestInterval,
+			RetryBaseDelay: *notificationRetryBaseDelay,
+			RetryMaxDelay:  *notificationRetryMaxDelay,
+		}); err != nil {
+			log.Error(err, "unable to add the notification dispatcher")
+			os.Exit(1)
+		}
+	}
+
 	nsWhitelist := metav1.LabelSelector{MatchLabels: whiteListMap, MatchExpressions: []metav1.LabelSelectorRequirement{}}
 
 	if *enableInstanceTermination {
@@ -163,6 +212,7 @@ func main() {
 			NamespaceWhitelist:          nsWhitelist,
 			StatusCheckRequestTimeout:   *instanceTerminationStatusCheckTimeout,
 			InstanceStatusCheckInterval: *instanceTerminationStatusCheckInterval,
+			DryRun:                      dryRunAuditor,
 		}).SetupWithManager(mgr, *maxConcurrentTerminationReconciles); err != nil {
 			log.Error(err, "unable to create controller", "controller", instanceTermination)
 			os.Exit(1)
@@ -182,6 +232,7 @@ func main() {
 			EventsRecorder:     mgr.GetEventRecorderFor(instanceSubmission),
 			ContainerEnvOpts:   containerEnvOpts,
 			NamespaceWhitelist: nsWhitelist,
+			DryRun:             dryRunAuditor,
 		}).SetupWithManager(mgr, *maxConcurrentSubmissionReconciles); err != nil {
 			log.Error(err, "unable to create controller", "controller", instanceSubmission)
 			os.Exit(1)
@@ -204,6 +255,10 @@ func main() {
 			InstanceMaxNumberOfAlerts:       *instanceInactiveTerminationMaxNumberOfAlerts,
 			EnableInactivityNotifications:   *enableInactivityNotifications,
 			MailClient:                      mailClient,
+			Outbox:                          outbox,
+			Schedule:                        schedule,
+			DryRun:                          dryRunAuditor,
+			PreTerminationHooks:             buildPreTerminationHookRunner(*enablePreTerminationHooks, mgr, instanceInactiveTermination, containerEnvOpts, *preTerminationHookTimeout),
 			Prometheus:                      prometheus,
 			NotificationInterval:            *instanceInactiveTerminationNotificationInterval,
 			DestructionNotificationInterval: *inactiveDestructionNotificationInterval,
@@ -231,6 +286,10 @@ func main() {
 			NamespaceWhitelist:            nsWhitelist,
 			EnableExpirationNotifications: *enableExpirationNotifications,
 			MailClient:                    mailClient,
+			Outbox:                        outbox,
+			Schedule:                      schedule,
+			DryRun:                        dryRunAuditor,
+			PreTerminationHooks:           buildPreTerminationHookRunner(*enablePreTerminationHooks, mgr, instanceExpiration, containerEnvOpts, *preTerminationHookTimeout),
 			NotificationInterval:          *expirationNotificationInterval,
 			MarginTime:                    *marginTime,
 		}).SetupWithManager(mgr, *maxConcurrentExpirationReconciles); err != nil {
@@ -272,3 +331,36 @@ func parseMap(raw string) map[string]string {
 	}
 	return m
 }
+
+// buildAutomationSchedule creates the global automation schedule from the command line parameters.
+func buildAutomationSchedule(quietHours, timeZone, holidayCalendarFile string) (instautoctrl.AutomationSchedule, error) {
+	var schedule instautoctrl.AutomationSchedule
+	var err error
+
+	if schedule.QuietHours, err = instautoctrl.ParseQuietHours(quietHours); err != nil {
+		return schedule, err
+	}
+	if schedule.Location, err = time.LoadLocation(timeZone); err != nil {
+		return schedule, fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+	}
+	if holidayCalendarFile != "" {
+		if schedule.Holidays, err = instautoctrl.LoadHolidayCalendar(holidayCalendarFile); err != nil {
+			return schedule, err
+		}
+	}
+	return schedule, nil
+}
+
+// buildPreTerminationHookRunner creates the runner of the pre-termination hooks, returning nil if they are disabled.
+func buildPreTerminationHookRunner(enabled bool, mgr ctrl.Manager, name string, opts forge.ContainerEnvOpts, timeout time.Duration) *instautoctrl.PreTerminationHookRunner {
+	if !enabled {
+		return nil
+	}
+	return &instautoctrl.PreTerminationHookRunner{
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		EventsRecorder:   mgr.GetEventRecorderFor(name),
+		ContainerEnvOpts: opts,
+		DefaultTimeout:   timeout,
+	}
+}
@@ -37,6 +37,7 @@ import (
 
 	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
 	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/instancetransfer"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
 	instancesnapshot_controller "github.com/netgroup-polito/CrownLabs/operators/pkg/instancesnapshot-controller"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/instctrl"
@@ -221,6 +222,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Configure the InstanceTransfer controller
+	instanceTransferCtrl := "InstanceTransfer"
+	if err = (&instancetransfer.Reconciler{
+		Client:                    mgr.GetClient(),
+		EventsRecorder:            mgr.GetEventRecorderFor(instanceTransferCtrl),
+		NamespaceWhitelist:        nsWhitelist,
+		MirrorPVCStorageClassName: mirrorStorageClass,
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "unable to create controller", "controller", instanceTransferCtrl)
+		os.Exit(1)
+	}
+
 	// Add readiness probe
 	err = mgr.AddReadyzCheck("ready-ping", healthz.Ping)
 	if err != nil {
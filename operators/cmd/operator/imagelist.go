@@ -19,19 +19,29 @@ import (
 	"flag"
 
 	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	templatewebhook "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/template/webhook"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/imagelist"
 )
 
+const (
+	// TemplateValidatorWebhookPath -> path on which the Template validator webhook will be bound.
+	TemplateValidatorWebhookPath = "/validator-v1alpha2-template"
+)
+
 var (
 	imageListConfigFile     string
 	imageListUpdateInterval int
+	imageListWebhookAddr    string
 )
 
 func init() {
-	flag.StringVar(&imageListConfigFile, "image-list-config-file", "/etc/config/registries.yaml", "Path to the image list registries configuration file")
+	flag.StringVar(&imageListConfigFile, "image-list-config-file", "/etc/config/registries.yaml", "Path to the image list registries configuration file, complementing the ImageRegistry resources")
 	flag.IntVar(&imageListUpdateInterval, "image-list-update-interval", 300, "Image list update interval in seconds")
+	flag.StringVar(&imageListWebhookAddr, "image-list-webhook-addr", "", "Address the receiver of the registry webhooks listens on (e.g. :8083); if empty, only the periodic update is performed")
 }
 
 func setupImageList(mgr manager.Manager, log logr.Logger) error {
@@ -39,10 +49,32 @@ func setupImageList(mgr manager.Manager, log logr.Logger) error {
 	if err := imagelist.Initialize(mgr.GetClient(), log.WithName("imagelist"), imagelist.UpdaterOptions{
 		ConfigFilePath: imageListConfigFile,
 		Interval:       imageListUpdateInterval,
+		WebhookAddress: imageListWebhookAddr,
 	}); err != nil {
 		return err
 	}
 
+	// Setup the controller processing the ImageRegistry resources as soon as they are created or modified
+	if err := (&imagelist.RegistryReconciler{
+		Client:         mgr.GetClient(),
+		ConfigFilePath: imageListConfigFile,
+	}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	// Setup the webhook warning about the templates using deprecated images, if enabled
+	if enableWebhooks {
+		if err := setupTemplateWebhook(mgr); err != nil {
+			return err
+		}
+	}
+
+	// Add the receiver of the registry webhooks as a runnable to the manager (no-op if disabled),
+	// running on every replica, while the periodic update below is performed by the leader only
+	if err := mgr.Add(imagelist.WebhookReceiverRunnable{}); err != nil {
+		return err
+	}
+
 	// Add the image list scheduler as a runnable to the manager
 	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
 		lg := log.WithName("imagelist-scheduler")
@@ -52,3 +84,14 @@ func setupImageList(mgr manager.Manager, log logr.Logger) error {
 		return nil
 	}))
 }
+
+// setupTemplateWebhook configures the Webhook that warns about the templates using deprecated images.
+func setupTemplateWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&clv1alpha2.Template{}).
+		WithValidator(&templatewebhook.TemplateValidator{
+			Client: mgr.GetClient(),
+		}).
+		WithValidatorCustomPath(TemplateValidatorWebhookPath).
+		Complete()
+}
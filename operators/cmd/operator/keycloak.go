@@ -33,6 +33,17 @@ var (
 	keycloakRolesClientID string // The client ID of the client in which the roles are defined.
 
 	keycloakCompatibilityMode bool // If true, the Keycloak actor will use the compatibility mode for Keycloak old clients.
+
+	identityProvider string // The backend used to manage users and roles (keycloak or scim).
+	scimURL          string
+	scimToken        string
+)
+
+const (
+	// IdentityProviderKeycloak -> the users and roles are managed through the Keycloak admin API.
+	IdentityProviderKeycloak = "keycloak"
+	// IdentityProviderSCIM -> the users and roles are managed through a generic SCIM 2.0 provider.
+	IdentityProviderSCIM = "scim"
 )
 
 func init() {
@@ -42,12 +53,21 @@ func init() {
 	flag.StringVar(&keycloakClientSecret, "keycloak-client-secret", "", "Keycloak Client Secret")
 	flag.StringVar(&keycloakRolesClientID, "keycloak-roles-client-id", "", "Keycloak Roles Client ID (the client in which the roles are defined)")
 	flag.BoolVar(&keycloakCompatibilityMode, "keycloak-compatibility-mode", false, "Enable Keycloak compatibility mode for old clients")
+	flag.StringVar(&identityProvider, "identity-provider", IdentityProviderKeycloak, "The identity provider backend used to manage users and roles (keycloak or scim)")
+	flag.StringVar(&scimURL, "scim-url", "", "The base URL of the SCIM 2.0 endpoint of the identity provider")
+	flag.StringVar(&scimToken, "scim-token", "", "The bearer token used to authenticate to the SCIM 2.0 endpoint of the identity provider")
 }
 
 func setupKeycloak(
 	ctx context.Context,
 	log logr.Logger,
 ) error {
+	if identityProvider == IdentityProviderSCIM {
+		return setupSCIM(ctx, log)
+	} else if identityProvider != IdentityProviderKeycloak {
+		return fmt.Errorf("unsupported identity provider %q", identityProvider)
+	}
+
 	if keycloakURL == "" || keycloakClientID == "" || keycloakClientSecret == "" || keycloakRealm == "" {
 		err := fmt.Errorf("missing parameters for Keycloak configuration")
 		log.Error(err, "Keycloak actor will not be initialized (settings not provided)")
@@ -92,3 +112,18 @@ func setupKeycloak(
 
 	return nil
 }
+
+// setupSCIM initializes the actor managing users and roles through a generic SCIM 2.0 provider.
+func setupSCIM(
+	ctx context.Context,
+	log logr.Logger,
+) error {
+	if scimURL == "" || scimToken == "" {
+		err := fmt.Errorf("missing parameters for SCIM configuration")
+		log.Error(err, "SCIM actor will not be initialized (settings not provided)")
+		return err
+	}
+
+	log.Info("Initializing SCIM actor", "url", scimURL)
+	return ctrlcommon.SetupSCIMActor(ctx, scimURL, scimToken, log)
+}
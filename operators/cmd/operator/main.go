@@ -19,6 +19,7 @@ import (
 	"flag"
 	"time"
 
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -36,9 +37,10 @@ import (
 )
 
 var (
-	rscheme        = runtime.NewScheme()
-	enableWebhooks bool
-	reschedule     = ctrlcommon.Rescheduler{
+	rscheme          = runtime.NewScheme()
+	enableWebhooks   bool
+	contentToolsOpts forge.ContainerEnvOpts
+	reschedule       = ctrlcommon.Rescheduler{
 		RequeueAfterMin: 1 * 24 * time.Hour,
 		RequeueAfterMax: 7 * 24 * time.Hour,
 	}
@@ -49,6 +51,8 @@ func init() {
 
 	utilruntime.Must(clv1alpha1.AddToScheme(rscheme))
 	utilruntime.Must(clv1alpha2.AddToScheme(rscheme))
+
+	utilruntime.Must(snapshotv1.AddToScheme(rscheme))
 }
 
 func main() {
@@ -92,6 +96,9 @@ func main() {
 
 	flag.BoolVar(&enableWebhooks, "enable-webhooks", true, "Enable the webhooks server.")
 
+	flag.StringVar(&contentToolsOpts.ContentToolsImg, "content-tools-img", "crownlabs/content-tools", "The image for the content tools (to populate the volumes from archives and Git repositories)")
+	flag.StringVar(&contentToolsOpts.ImagesTag, "content-tools-tag", "latest", "The tag for the content tools image")
+
 	klog.InitFlags(nil)
 	flag.Parse()
 
@@ -28,11 +28,13 @@ import (
 var (
 	mirrorStorageClass    string
 	mirrorProvisionerName string
+	mirrorBlockVolumes    bool
 )
 
 func init() {
 	flag.StringVar(&mirrorStorageClass, "mirror-storage-class", "pvc-mirror", "The StorageClass to be used for all PVCs which are going to be mirrors")
 	flag.StringVar(&mirrorProvisionerName, "mirror-provisioner-name", "pmp.crownlabs.polito.it", "The provisioner name to be used for the mirror StorageClass")
+	flag.BoolVar(&mirrorBlockVolumes, "mirror-block-volumes", true, "Whether the PVCs in block mode can be mirrored (only for CSI and HostPath volumes)")
 }
 
 func setupPmp(
@@ -51,6 +53,15 @@ func setupPmp(
 		TargetLabel:           targetLabel,
 		MirrorStorageClass:    mirrorStorageClass,
 		MirrorProvisionerName: mirrorProvisionerName,
+		BlockVolumes:          mirrorBlockVolumes,
 	}
-	return mgr.Add(&pmprov)
+	if err := mgr.Add(&pmprov); err != nil {
+		return err
+	}
+
+	mirrorReconciler := pmp.MirrorReconciler{
+		Client:      mgr.GetClient(),
+		TargetLabel: targetLabel,
+	}
+	return mirrorReconciler.SetupWithManager(mgr)
 }
@@ -16,25 +16,40 @@ package main
 
 import (
 	"flag"
+	"time"
 
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
 	ctrlcommon "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/common"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/sharedvolume"
+	sharedvolumewebhook "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/sharedvolume/webhook"
 )
 
 var (
 	sharedVolumeStorageClass     string
 	maxConcurrentShVolReconciles int
+	sharedVolumeUsageInterval    time.Duration
+
+	enableSharedVolumeSnapshots bool
+	sharedVolumeSnapshotClass   string
 )
 
 const (
-	sharedVolumeCtrl = "SharedVolume"
+	sharedVolumeCtrl         = "SharedVolume"
+	sharedVolumeSnapshotCtrl = "SharedVolumeSnapshot"
+
+	// SharedVolumeValidatorWebhookPath -> path on which the SharedVolume validator webhook will be bound.
+	SharedVolumeValidatorWebhookPath = "/validator-v1alpha2-sharedvolume"
 )
 
 func init() {
 	flag.StringVar(&sharedVolumeStorageClass, "shared-volume-storage-class", "rook-nfs", "The StorageClass to be used for all SharedVolumes' PVC (if unique can be used to enforce ResourceQuota on Workspaces, about number and size of ShVols)")
 	flag.IntVar(&maxConcurrentShVolReconciles, "max-concurrent-reconciles-shvol", 1, "The maximum number of concurrent Reconciles which can be run for the Instance Shared Volume controller")
+	flag.DurationVar(&sharedVolumeUsageInterval, "shared-volume-usage-interval", time.Hour, "How often the usage of the SharedVolumes is measured and reported in their status (0 to disable)")
+	flag.BoolVar(&enableSharedVolumeSnapshots, "enable-sharedvolume-snapshots", false, "Enable the snapshots and restores of the SharedVolumes (it requires the CSI VolumeSnapshot CRDs to be installed)")
+	flag.StringVar(&sharedVolumeSnapshotClass, "shared-volume-snapshot-class", "", "The VolumeSnapshotClass to be used for the snapshots of the SharedVolumes (the default one if empty)")
 }
 
 func setupSharedVolume(
@@ -46,11 +61,41 @@ func setupSharedVolume(
 		TargetLabel:     targetLabel,
 		EventsRecorder:  mgr.GetEventRecorderFor(sharedVolumeCtrl),
 		PVCStorageClass: sharedVolumeStorageClass,
+
+		UsageReportInterval: sharedVolumeUsageInterval,
+		EnableSnapshots:     enableSharedVolumeSnapshots,
+		ContentToolsOpts:    contentToolsOpts,
 	}
 
 	if err := shvol.SetupWithManager(mgr, maxConcurrentShVolReconciles); err != nil {
 		return err
 	}
 
+	if enableSharedVolumeSnapshots {
+		snapshot := &sharedvolume.SnapshotReconciler{
+			Client:              mgr.GetClient(),
+			TargetLabel:         targetLabel,
+			EventsRecorder:      mgr.GetEventRecorderFor(sharedVolumeSnapshotCtrl),
+			VolumeSnapshotClass: sharedVolumeSnapshotClass,
+		}
+
+		if err := snapshot.SetupWithManager(mgr, maxConcurrentShVolReconciles); err != nil {
+			return err
+		}
+	}
+
+	if enableWebhooks {
+		return setupSharedVolumeWebhook(mgr)
+	}
+
 	return nil
 }
+
+// setupSharedVolumeWebhook configures the Webhook that prevents the SharedVolumes from being shrunk.
+func setupSharedVolumeWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&clv1alpha2.SharedVolume{}).
+		WithValidator(&sharedvolumewebhook.SharedVolumeValidator{}).
+		WithValidatorCustomPath(SharedVolumeValidatorWebhookPath).
+		Complete()
+}
@@ -17,7 +17,9 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,10 +31,12 @@ import (
 
 	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
 	ctrlcommon "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/common"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/enrollment"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/tenant"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/tenant/webhook"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils/args"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils/mail"
 )
 
 var (
@@ -46,6 +50,17 @@ var (
 	mydrivePVCsStorageClassName   string
 	myDrivePVCsNamespace          string
 	waitUserVerification          bool // If true, the reconciliation will wait for the user to be verified in Keycloak before creating resources.
+	enrollmentRequestTTL          time.Duration
+	tenantNotifications           bool
+	membershipExpirationNotice    time.Duration
+	mailTemplateDir               string
+	mailConfigDir                 string
+	keycloakDriftAuditInterval    time.Duration
+	keycloakDriftCorrection       bool
+	myDriveContentArchiveURL      string
+	myDriveContentGit             clv1alpha2.GitContentSource
+	myDriveUsageInterval          time.Duration
+	myDriveUsageThresholds        string
 )
 
 const (
@@ -56,6 +71,10 @@ const (
 	TenantValidatorWebhookPath = "/validator-v1alpha2-tenant"
 	// TenantDefaulterWebhookPath -> path on which the Tenant defaulter webhook will be bound.
 	TenantDefaulterWebhookPath = "/defaulter-v1alpha2-tenant"
+	// EnrollmentRequestValidatorWebhookPath -> path on which the EnrollmentRequest validator webhook will be bound.
+	EnrollmentRequestValidatorWebhookPath = "/validator-v1alpha2-enrollmentrequest"
+	// InstanceTransferValidatorWebhookPath -> path on which the InstanceTransfer validator webhook will be bound.
+	InstanceTransferValidatorWebhookPath = "/validator-v1alpha2-instancetransfer"
 )
 
 func init() {
@@ -70,12 +89,34 @@ func init() {
 	flag.Var(&mydrivePVCsSize, "mydrive-pvcs-size", "The dimension of the user's personal space")
 	flag.StringVar(&mydrivePVCsStorageClassName, "mydrive-pvcs-storage-class-name", "rook-nfs", "The name for the user's storage class")
 	flag.StringVar(&myDrivePVCsNamespace, "mydrive-pvcs-namespace", "mydrive-pvcs", "The namespace where the PVCs are created")
+	flag.StringVar(&myDriveContentArchiveURL, "mydrive-content-archive-url", "", "The URL of an archive the new MyDrive volumes are populated with (disabled if empty)")
+	flag.StringVar(&myDriveContentGit.Repository, "mydrive-content-git-repository", "", "The URL of a Git repository the new MyDrive volumes are populated with (disabled if empty)")
+	flag.StringVar(&myDriveContentGit.Ref, "mydrive-content-git-ref", "HEAD", "The branch, tag or commit of the Git repository the new MyDrive volumes are populated with")
+	flag.DurationVar(&myDriveUsageInterval, "mydrive-usage-interval", time.Hour,
+		"How often the usage of the MyDrive volumes is measured and reported in the tenant status (0 to disable)")
+	flag.StringVar(&myDriveUsageThresholds, "mydrive-usage-thresholds", "80,95",
+		"The usage thresholds (in percentage, comma separated) the tenants are warned about when crossed by their MyDrive")
 	flag.BoolVar(&waitUserVerification, "wait-user-verification", true, "Wait for the user to be verified in Keycloak before creating resources. If false, resources will be created immediately after the Tenant is created.")
 
 	flag.Int64Var(&forge.CapInstance, "cap-instance", 10, "The cap number of instances that can be requested by a Tenant.")
 	flag.IntVar(&forge.CapCPU, "cap-cpu", 25, "The cap amount of CPU cores that can be requested by a Tenant.")
 	flag.IntVar(&forge.CapMemoryGiga, "cap-memory-giga", 50, "The cap amount of RAM memory in gigabytes that can be requested by a Tenant.")
 
+	flag.DurationVar(&enrollmentRequestTTL, "enrollment-request-ttl", 30*24*time.Hour,
+		"The time after which the enrollment requests not decided by the workspace managers expire (0 to disable)")
+	flag.BoolVar(&tenantNotifications, "tenant-notifications", false,
+		"Notify managers and tenants by email about the enrollment requests and the expiring workspace memberships")
+	flag.BoolVar(&tenantNotifications, "enrollment-notifications", false, "Deprecated: use --tenant-notifications instead")
+	flag.DurationVar(&membershipExpirationNotice, "membership-expiration-notice", 7*24*time.Hour,
+		"How long before the expiration of a workspace membership the tenant is notified (0 to disable)")
+	flag.StringVar(&mailTemplateDir, "mail-template-dir", "/etc/crownmail/templates", "The directory containing email templates (typically through a mounted ConfigMap)")
+	flag.StringVar(&mailConfigDir, "mail-config-dir", "/etc/crownmail/configs", "The directory containing email configuration (typically through a mounted Secret)")
+
+	flag.DurationVar(&keycloakDriftAuditInterval, "keycloak-drift-audit-interval", 6*time.Hour,
+		"How often the tenants are compared with Keycloak to detect the roles and users modified manually (0 to disable)")
+	flag.BoolVar(&keycloakDriftCorrection, "keycloak-drift-correction", false,
+		"Automatically correct the drifts between the tenants and Keycloak detected by the periodic audit")
+
 	flag.IntVar(&tenantMaxConcurrentReconciles, "max-concurrent-reconciles", 1, "The maximum number of concurrent Reconciles which can be run")
 }
 
@@ -91,6 +132,21 @@ func setupTenant(
 		log.Info("Base workspaces for tenants to be enforced", "workspaces", baseWorkspacesList)
 	}
 
+	usageThresholds, err := parseUsageThresholds(myDriveUsageThresholds)
+	if err != nil {
+		return err
+	}
+
+	var mailClient *mail.Client
+	if tenantNotifications {
+		var err error
+		if mailClient, err = mail.NewMailClientFromFilesystem(mailConfigDir, mailTemplateDir); err != nil {
+			// the tenants are still managed, although nobody gets notified
+			log.Error(err, "unable to create mail client, tenant notifications disabled", "templateDir", mailTemplateDir)
+			mailClient = nil
+		}
+	}
+
 	tn := &tenant.Reconciler{
 		Client:                      mgr.GetClient(),
 		Scheme:                      mgr.GetScheme(),
@@ -101,6 +157,8 @@ func setupTenant(
 		MyDrivePVCsSize:             mydrivePVCsSize.Quantity,
 		MyDrivePVCsStorageClassName: mydrivePVCsStorageClassName,
 		MyDrivePVCsNamespace:        myDrivePVCsNamespace,
+		MyDriveContentSource:        myDriveContentSource(),
+		ContentToolsOpts:            contentToolsOpts,
 		MirrorPVCStorageClassName:   mirrorStorageClass,
 		KeycloakActor:               ctrlcommon.GetKeycloakActor(),
 		WaitUserVerification:        waitUserVerification,
@@ -108,15 +166,37 @@ func setupTenant(
 		BaseWorkspaces:              baseWorkspacesList,
 		Concurrency:                 tenantMaxConcurrentReconciles,
 		Reschedule:                  reschedule,
+		MembershipExpirationNotice:  membershipExpirationNotice,
+		MyDriveUsageInterval:        myDriveUsageInterval,
+		MyDriveUsageThresholds:      usageThresholds,
+		EventsRecorder:              mgr.GetEventRecorderFor("tenant-controller"),
+	}
+	if mailClient != nil {
+		tn.MailClient = mailClient
 	}
 
 	if err := tn.SetupWithManager(mgr, log); err != nil {
 		return err
 	}
 
+	if err := setupEnrollment(mgr, mailClient); err != nil {
+		return err
+	}
+
 	// Register the Keycloak event handler for tenant webhook events
 	startKeycloakWebhookHTTPServer(tn, log, mgr)
 
+	if keycloakDriftAuditInterval > 0 {
+		if err := mgr.Add(&tenant.DriftAuditor{
+			Reconciler:     tn,
+			EventsRecorder: mgr.GetEventRecorderFor("tenant-drift-auditor"),
+			Interval:       keycloakDriftAuditInterval,
+			Correct:        keycloakDriftCorrection,
+		}); err != nil {
+			return err
+		}
+	}
+
 	// Setup the webhook for tenant validation and defaulting
 	if enableWebhooks {
 		if err := setupTenantWebhook(mgr, targetLabel, baseWorkspacesList); err != nil {
@@ -209,5 +289,66 @@ func setupTenantWebhook(
 		return err
 	}
 
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&clv1alpha2.EnrollmentRequest{}).
+		WithValidator(&webhook.EnrollmentRequestValidator{
+			TenantWebhook: tnWh,
+		}).
+		WithValidatorCustomPath(EnrollmentRequestValidatorWebhookPath).
+		Complete(); err != nil {
+		return err
+	}
+
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&clv1alpha2.InstanceTransfer{}).
+		WithValidator(&webhook.InstanceTransferValidator{
+			TenantWebhook: tnWh,
+		}).
+		WithValidatorCustomPath(InstanceTransferValidatorWebhookPath).
+		Complete(); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// setupEnrollment registers the controller managing the enrollment requests.
+func setupEnrollment(mgr manager.Manager, mailClient *mail.Client) error {
+	er := &enrollment.Reconciler{
+		Client:     mgr.GetClient(),
+		RequestTTL: enrollmentRequestTTL,
+	}
+	if mailClient != nil {
+		er.MailClient = mailClient
+	}
+
+	return er.SetupWithManager(mgr)
+}
+
+// myDriveContentSource returns the source the new MyDrive volumes are populated with, if configured.
+func myDriveContentSource() *clv1alpha2.ContentSource {
+	switch {
+	case myDriveContentGit.Repository != "":
+		return &clv1alpha2.ContentSource{Git: &myDriveContentGit}
+	case myDriveContentArchiveURL != "":
+		return &clv1alpha2.ContentSource{ArchiveURL: myDriveContentArchiveURL}
+	default:
+		return nil
+	}
+}
+
+// parseUsageThresholds parses a comma separated list of usage thresholds, expressed in percentage.
+func parseUsageThresholds(value string) ([]int, error) {
+	var thresholds []int
+	for field := range strings.SplitSeq(value, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		threshold, err := strconv.Atoi(field)
+		if err != nil || threshold <= 0 || threshold > 100 {
+			return nil, fmt.Errorf("invalid usage threshold %q: it shall be a percentage between 1 and 100", field)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds, nil
+}
@@ -16,10 +16,18 @@ package main
 
 import (
 	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
 	ctrlcommon "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/common"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/workspace"
+	workspacewebhook "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/workspace/webhook"
+)
+
+const (
+	// WorkspaceValidatorWebhookPath -> path on which the Workspace validator webhook will be bound.
+	WorkspaceValidatorWebhookPath = "/validator-v1alpha1-workspace"
 )
 
 func init() {}
@@ -38,6 +46,24 @@ func setupWorkspace(
 		Reschedule:    reschedule,
 	}
 
+	// Setup the webhook if enabled
+	if enableWebhooks {
+		if err := setupWorkspaceWebhook(mgr); err != nil {
+			return err
+		}
+	}
+
 	// Register the WorkspaceReconciler with the manager
 	return wr.SetupWithManager(mgr, log)
 }
+
+// setupWorkspaceWebhook configures the Webhook that validates the consistency of the Workspace hierarchy.
+func setupWorkspaceWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&clv1alpha1.Workspace{}).
+		WithValidator(&workspacewebhook.WorkspaceValidator{
+			Client: mgr.GetClient(),
+		}).
+		WithValidatorCustomPath(WorkspaceValidatorWebhookPath).
+		Complete()
+}
@@ -0,0 +1,114 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main contains the entrypoint for the bulk import of tenants from roster files.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/klog/v2/textlogger"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/tenantimport"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils/restcfg"
+)
+
+func main() {
+	rosterFile := flag.String("file", "", "The roster file to import (\"-\" for the standard input)")
+	format := flag.String("format", string(tenantimport.FormatAuto), "The format of the roster (auto, csv or json)")
+	baseWorkspaces := flag.String("base-workspaces", "", "List of comma separated workspaces to be enforced to the new tenants")
+	manager := flag.String("manager", "", "The tenant performing the import, whose permissions are checked in advance (if specified)")
+	apply := flag.Bool("apply", false, "Apply the changes, rather than only showing them")
+	serverDryRun := flag.Bool("server-dry-run", false, "Submit the changes in dry-run mode, to have them validated by the API server")
+
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	log := textlogger.NewLogger(textlogger.NewConfig()).WithName("tenant-import")
+	ctrl.SetLogger(log)
+	ctx := ctrl.LoggerInto(context.Background(), log)
+
+	if *rosterFile == "" {
+		fmt.Fprintln(os.Stderr, "the roster file is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	entries, err := tenantimport.LoadRoster(*rosterFile, tenantimport.Format(*format))
+	if err != nil {
+		log.Error(err, "unable to load roster", "file", *rosterFile)
+		os.Exit(1)
+	}
+
+	rscheme := runtime.NewScheme()
+	utilruntime.Must(clv1alpha1.AddToScheme(rscheme))
+	utilruntime.Must(clv1alpha2.AddToScheme(rscheme))
+
+	kubeconfig, err := ctrl.GetConfig()
+	if err != nil {
+		log.Error(err, "unable to get kubeconfig")
+		os.Exit(1)
+	}
+	k8sClient, err := client.New(restcfg.SetRateLimiter(kubeconfig), client.Options{Scheme: rscheme})
+	if err != nil {
+		log.Error(err, "unable to prepare k8s client")
+		os.Exit(1)
+	}
+
+	importer := tenantimport.Importer{
+		Client:       k8sClient,
+		Manager:      *manager,
+		ServerDryRun: *serverDryRun,
+	}
+	if *baseWorkspaces != "" {
+		importer.BaseWorkspaces = strings.Split(*baseWorkspaces, ",")
+	}
+
+	plan, err := importer.Plan(ctx, entries)
+	if err != nil {
+		log.Error(err, "unable to plan the import")
+		os.Exit(1)
+	}
+	plan.Print(os.Stdout)
+
+	if len(plan.Errors) > 0 {
+		fmt.Fprintln(os.Stderr, "the roster is not valid, no changes applied")
+		os.Exit(1)
+	}
+	if !*apply && !*serverDryRun {
+		fmt.Println("dry-run: no changes applied (use --apply to apply them)")
+		return
+	}
+
+	if err := importer.Apply(ctx, plan); err != nil {
+		log.Error(err, "unable to import some tenants")
+		os.Exit(1)
+	}
+	if *serverDryRun {
+		fmt.Println("server dry-run: the changes have been validated, but not persisted")
+		return
+	}
+	fmt.Println("import completed")
+}
@@ -74,6 +74,13 @@ Create a qualified app name for the webhook components.
 {{ .Values.rbacResourcesName }}
 {{- end }}
 
+{{/*
+Create the name of the ImageList registry webhook service endpoint.
+*/}}
+{{- define "operator.imageListWebhookServiceName" -}}
+{{ include "operator.fullname" . }}-imagelist-wh
+{{- end }}
+
 {{/*
 Create the name of the Keycloak webhook service endpoint.
 */}}
@@ -87,3 +94,10 @@ Create the URL of the Keycloak webhook service endpoint.
 {{- define "operator.keycloakWebhookServiceURL" -}}
 http://{{ include "operator.keycloakWebhookServiceName" . }}.{{ .Release.Namespace }}.svc
 {{- end }}
+
+{{/*
+Whether managers and tenants are notified by email, honoring also the former enrollment.notifications value.
+*/}}
+{{- define "operator.tenantNotifications" -}}
+{{ or .Values.configurations.tenantNotifications (dig "enrollment" "notifications" false .Values.configurations) }}
+{{- end }}
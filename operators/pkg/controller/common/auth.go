@@ -47,6 +47,8 @@ type KeycloakActor struct {
 
 const tokenRefreshBuffer = 30 // the token is considered about to expire if it has less than this many seconds left
 
+const listUsersPageSize = 100 // the number of users retrieved with each request when listing all of them
+
 var actor KeycloakActor
 var actorIface KeycloakActorIface = &actor
 
@@ -181,6 +183,30 @@ func (a *KeycloakActor) GetUser(
 	return user, nil
 }
 
+// ListUsers returns all the users registered in the Keycloak realm.
+func (a *KeycloakActor) ListUsers(
+	ctx context.Context,
+) ([]*gocloak13.User, error) {
+	log := klog.FromContext(ctx)
+
+	var users []*gocloak13.User
+	for first := 0; ; first += listUsersPageSize {
+		page, err := a.Client.GetUsers(ctx, a.GetAccessToken(ctx), a.Realm, gocloak13.GetUsersParams{
+			First:               gocloak13.IntP(first),
+			Max:                 gocloak13.IntP(listUsersPageSize),
+			BriefRepresentation: gocloak13.BoolP(true),
+		})
+		if err != nil {
+			log.Error(err, "Unable to list users from keycloak")
+			return nil, err
+		}
+		users = append(users, page...)
+		if len(page) < listUsersPageSize {
+			return users, nil
+		}
+	}
+}
+
 // CreateUser creates a user in Keycloak.
 func (a *KeycloakActor) CreateUser(
 	ctx context.Context,
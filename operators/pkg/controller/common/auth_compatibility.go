@@ -176,6 +176,32 @@ func (a *KeycloakActorCompatibility) GetUser(
 	return a.convertUserV7to13(user), nil
 }
 
+// ListUsers returns all the users registered in the Keycloak realm.
+func (a *KeycloakActorCompatibility) ListUsers(
+	ctx context.Context,
+) ([]*gocloak13.User, error) {
+	log := klog.FromContext(ctx)
+
+	var users []*gocloak13.User
+	for first := 0; ; first += listUsersPageSize {
+		page, err := a.Client.GetUsers(ctx, a.GetAccessToken(ctx), a.Realm, gocloak7.GetUsersParams{
+			First:               gocloak7.IntP(first),
+			Max:                 gocloak7.IntP(listUsersPageSize),
+			BriefRepresentation: gocloak7.BoolP(true),
+		})
+		if err != nil {
+			log.Error(err, "Unable to list users from keycloak")
+			return nil, err
+		}
+		for _, u := range page {
+			users = append(users, a.convertUserV7to13(u))
+		}
+		if len(page) < listUsersPageSize {
+			return users, nil
+		}
+	}
+}
+
 // CreateUser creates a user in Keycloak.
 func (a *KeycloakActorCompatibility) CreateUser(
 	ctx context.Context,
@@ -19,6 +19,8 @@ type KeycloakActorIface interface {
 	GetAccessToken(ctx context.Context) string
 	// GetUser returns the user associated with the given username.
 	GetUser(ctx context.Context, username string) (*gocloak.User, error)
+	// ListUsers returns all the users registered in the Keycloak realm.
+	ListUsers(ctx context.Context) ([]*gocloak.User, error)
 	// CreateUser creates a user in Keycloak.
 	CreateUser(ctx context.Context, username string, email string, firstName string, lastName string) (string, error)
 	// DeleteUser removes a user from Keycloak.
@@ -0,0 +1,504 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	gocloak13 "github.com/Nerzal/gocloak/v13"
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// SCIMContentType -> the media type of the SCIM 2.0 requests and responses.
+	SCIMContentType = "application/scim+json"
+
+	// scimUserSchema -> the schema identifier of the SCIM core user resource.
+	scimUserSchema = "urn:ietf:params:scim:schemas:core:2.0:User"
+	// scimGroupSchema -> the schema identifier of the SCIM core group resource.
+	scimGroupSchema = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	// scimPatchSchema -> the schema identifier of the SCIM patch operations.
+	scimPatchSchema = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+
+	// scimRequestTimeout -> the maximum duration of a single request to the SCIM provider.
+	scimRequestTimeout = 30 * time.Second
+)
+
+// SCIMActor contains the functionality to interact with a generic identity provider through the SCIM 2.0 protocol
+// (RFC 7643 and RFC 7644), mapping the CrownLabs users to SCIM users and the roles to SCIM groups.
+type SCIMActor struct {
+	initialized bool
+	HTTPClient  *http.Client
+	BaseURL     string
+	token       string
+	mutex       sync.RWMutex
+}
+
+// scimName is the name of a SCIM user.
+type scimName struct {
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// scimMultiValued is a generic SCIM multi-valued attribute (e.g., emails, members).
+type scimMultiValued struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// scimUser is a SCIM user resource.
+type scimUser struct {
+	Schemas  []string          `json:"schemas,omitempty"`
+	ID       string            `json:"id,omitempty"`
+	UserName string            `json:"userName"`
+	Name     scimName          `json:"name"`
+	Emails   []scimMultiValued `json:"emails,omitempty"`
+	Active   bool              `json:"active"`
+	Groups   []scimMultiValued `json:"groups,omitempty"`
+}
+
+// scimGroup is a SCIM group resource.
+type scimGroup struct {
+	Schemas     []string          `json:"schemas,omitempty"`
+	ID          string            `json:"id,omitempty"`
+	DisplayName string            `json:"displayName"`
+	Members     []scimMultiValued `json:"members,omitempty"`
+}
+
+// scimListResponse is the response of a SCIM query.
+type scimListResponse[T any] struct {
+	TotalResults int `json:"totalResults"`
+	Resources    []T `json:"Resources"`
+}
+
+// scimPatchOperation is a single operation of a SCIM patch request.
+type scimPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// scimPatchRequest is a SCIM patch request.
+type scimPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []scimPatchOperation `json:"Operations"`
+}
+
+// scimError is returned when the SCIM provider answers with an unexpected status code.
+type scimError struct {
+	StatusCode int
+	Detail     string
+}
+
+func (e *scimError) Error() string {
+	if e.StatusCode == http.StatusNotFound {
+		// consistent with the error returned by the Keycloak actors
+		return fmt.Sprintf("%d", http.StatusNotFound)
+	}
+	return fmt.Sprintf("scim request failed with status %d: %s", e.StatusCode, e.Detail)
+}
+
+var actorSCIM SCIMActor
+
+// SetupSCIMActor creates and initializes a new SCIMActor, which replaces the Keycloak one.
+func SetupSCIMActor(
+	ctx context.Context,
+	baseURL string,
+	token string,
+	log logr.Logger,
+) error {
+	if actorSCIM.IsInitialized() {
+		return nil
+	}
+
+	actorIface = &actorSCIM
+
+	if actorSCIM.HTTPClient == nil {
+		actorSCIM.HTTPClient = &http.Client{Timeout: scimRequestTimeout}
+	}
+	actorSCIM.BaseURL = strings.TrimSuffix(baseURL, "/")
+	actorSCIM.token = token
+
+	// check the provider is reachable and the credentials are valid
+	if err := actorSCIM.do(ctx, http.MethodGet, "/ServiceProviderConfig", nil, nil); err != nil {
+		log.Error(err, "Unable to contact the SCIM provider")
+		return err
+	}
+
+	actorSCIM.initialized = true
+	return nil
+}
+
+// IsInitialized checks if the SCIMActor has been initialized.
+func (a *SCIMActor) IsInitialized() bool {
+	return a.initialized
+}
+
+// Reset clears the SCIMActor's token.
+func (a *SCIMActor) Reset(log logr.Logger) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.initialized = false
+	a.BaseURL = ""
+	a.token = ""
+	log.Info("SCIM actor has been reset")
+}
+
+// GetAccessToken returns the access token of the actor.
+// SCIM providers are accessed through a long-lived bearer token, hence no refresh is needed.
+func (a *SCIMActor) GetAccessToken(_ context.Context) string {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.token
+}
+
+// GetUser returns the user associated with the given username.
+func (a *SCIMActor) GetUser(
+	ctx context.Context,
+	username string,
+) (*gocloak13.User, error) {
+	log := klog.FromContext(ctx)
+
+	var users scimListResponse[scimUser]
+	if err := a.do(ctx, http.MethodGet, "/Users?filter="+url.QueryEscape(scimEqualFilter("userName", username)), nil, &users); err != nil {
+		log.Error(err, "Unable to get user from the SCIM provider")
+		return nil, err
+	}
+
+	// the filter may be case insensitive, hence the exact match is checked
+	for i := range users.Resources {
+		if users.Resources[i].UserName == username {
+			return users.Resources[i].toGocloak(), nil
+		}
+	}
+
+	log.Info("User not found in the SCIM provider", "username", username)
+	return nil, &scimError{StatusCode: http.StatusNotFound}
+}
+
+// ListUsers returns all the users registered in the SCIM provider.
+func (a *SCIMActor) ListUsers(
+	ctx context.Context,
+) ([]*gocloak13.User, error) {
+	log := klog.FromContext(ctx)
+
+	var users []*gocloak13.User
+	// SCIM indexes are 1-based
+	for start := 1; ; {
+		var page scimListResponse[scimUser]
+		path := fmt.Sprintf("/Users?startIndex=%d&count=%d", start, listUsersPageSize)
+		if err := a.do(ctx, http.MethodGet, path, nil, &page); err != nil {
+			log.Error(err, "Unable to list users from the SCIM provider")
+			return nil, err
+		}
+		for i := range page.Resources {
+			users = append(users, page.Resources[i].toGocloak())
+		}
+		start += len(page.Resources)
+		if len(page.Resources) == 0 || start > page.TotalResults {
+			return users, nil
+		}
+	}
+}
+
+// CreateUser creates a user in the SCIM provider.
+func (a *SCIMActor) CreateUser(
+	ctx context.Context,
+	username string,
+	email string,
+	firstName string,
+	lastName string,
+) (string, error) {
+	user := scimUser{
+		Schemas:  []string{scimUserSchema},
+		UserName: username,
+		Name:     scimName{GivenName: firstName, FamilyName: lastName},
+		Emails:   []scimMultiValued{{Value: email, Primary: true}},
+		Active:   true,
+	}
+
+	var created scimUser
+	if err := a.do(ctx, http.MethodPost, "/Users", &user, &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// DeleteUser removes a user from the SCIM provider.
+func (a *SCIMActor) DeleteUser(
+	ctx context.Context,
+	userID string,
+) error {
+	return a.do(ctx, http.MethodDelete, "/Users/"+url.PathEscape(userID), nil, nil)
+}
+
+// GetRole gets a role (i.e., a SCIM group) from the SCIM provider.
+func (a *SCIMActor) GetRole(
+	ctx context.Context,
+	roleName string,
+) (*gocloak13.Role, error) {
+	log := klog.FromContext(ctx)
+
+	group, err := a.getGroup(ctx, roleName)
+	if err != nil {
+		log.Error(err, "Unable to get role from the SCIM provider")
+		return nil, err
+	}
+	if group == nil {
+		log.Info("Role not found in the SCIM provider", "roleName", roleName)
+		return nil, &scimError{StatusCode: http.StatusNotFound}
+	}
+
+	return group.toGocloak(), nil
+}
+
+// CreateRole creates a new role (i.e., a SCIM group) in the SCIM provider.
+// The description is not supported by the SCIM core group schema, hence it is ignored.
+func (a *SCIMActor) CreateRole(
+	ctx context.Context,
+	roleName string,
+	_ string,
+) (string, error) {
+	log := klog.FromContext(ctx)
+
+	group := scimGroup{
+		Schemas:     []string{scimGroupSchema},
+		DisplayName: roleName,
+	}
+
+	var created scimGroup
+	if err := a.do(ctx, http.MethodPost, "/Groups", &group, &created); err != nil {
+		log.Error(err, "Unable to create role in the SCIM provider")
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// DeleteRole removes a role (i.e., a SCIM group) from the SCIM provider.
+func (a *SCIMActor) DeleteRole(
+	ctx context.Context,
+	roleName string,
+) error {
+	log := klog.FromContext(ctx)
+
+	group, err := a.getGroup(ctx, roleName)
+	if err != nil {
+		log.Error(err, "Unable to get role from the SCIM provider")
+		return err
+	}
+	if group == nil {
+		return nil
+	}
+
+	if err := a.do(ctx, http.MethodDelete, "/Groups/"+url.PathEscape(group.ID), nil, nil); err != nil && !isSCIMNotFound(err) {
+		log.Error(err, "Unable to delete role from the SCIM provider")
+		return err
+	}
+	return nil
+}
+
+// GetUserRoles gets the roles (i.e., the SCIM groups) assigned to a user.
+func (a *SCIMActor) GetUserRoles(
+	ctx context.Context,
+	userID string,
+) ([]*gocloak13.Role, error) {
+	log := klog.FromContext(ctx)
+
+	var user scimUser
+	if err := a.do(ctx, http.MethodGet, "/Users/"+url.PathEscape(userID), nil, &user); err != nil {
+		if isSCIMNotFound(err) {
+			log.Info("User not found in the SCIM provider", "userID", userID)
+		} else {
+			log.Error(err, "Unable to get user roles from the SCIM provider")
+		}
+		return nil, err
+	}
+
+	roles := make([]*gocloak13.Role, 0, len(user.Groups))
+	for i := range user.Groups {
+		roles = append(roles, &gocloak13.Role{
+			ID:   gocloak13.StringP(user.Groups[i].Value),
+			Name: gocloak13.StringP(user.Groups[i].Display),
+		})
+	}
+	return roles, nil
+}
+
+// AddUserToRoles adds a user to the specified roles (i.e., the SCIM groups).
+func (a *SCIMActor) AddUserToRoles(
+	ctx context.Context,
+	userID string,
+	roles []*gocloak13.Role,
+) error {
+	return a.patchRolesMembership(ctx, roles, scimPatchOperation{
+		Op:    "add",
+		Path:  "members",
+		Value: []scimMultiValued{{Value: userID}},
+	})
+}
+
+// RemoveUserFromRoles removes a user from the specified roles (i.e., the SCIM groups).
+func (a *SCIMActor) RemoveUserFromRoles(
+	ctx context.Context,
+	userID string,
+	roles []*gocloak13.Role,
+) error {
+	return a.patchRolesMembership(ctx, roles, scimPatchOperation{
+		Op:   "remove",
+		Path: fmt.Sprintf("members[%s]", scimEqualFilter("value", userID)),
+	})
+}
+
+// patchRolesMembership applies the given operation to the members of the specified roles.
+func (a *SCIMActor) patchRolesMembership(
+	ctx context.Context,
+	roles []*gocloak13.Role,
+	operation scimPatchOperation,
+) error {
+	log := klog.FromContext(ctx)
+
+	patch := scimPatchRequest{
+		Schemas:    []string{scimPatchSchema},
+		Operations: []scimPatchOperation{operation},
+	}
+	for _, role := range roles {
+		if role == nil || role.ID == nil {
+			continue
+		}
+		if err := a.do(ctx, http.MethodPatch, "/Groups/"+url.PathEscape(*role.ID), &patch, nil); err != nil {
+			log.Error(err, "Unable to update role membership in the SCIM provider", "operation", operation.Op)
+			return err
+		}
+	}
+	return nil
+}
+
+// getGroup returns the SCIM group with the given name, or nil if not found.
+func (a *SCIMActor) getGroup(
+	ctx context.Context,
+	name string,
+) (*scimGroup, error) {
+	var groups scimListResponse[scimGroup]
+	if err := a.do(ctx, http.MethodGet, "/Groups?excludedAttributes=members&filter="+url.QueryEscape(scimEqualFilter("displayName", name)), nil, &groups); err != nil {
+		return nil, err
+	}
+
+	for i := range groups.Resources {
+		if groups.Resources[i].DisplayName == name {
+			return &groups.Resources[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// do performs a request to the SCIM provider, encoding the body and decoding the response (if not nil).
+func (a *SCIMActor) do(
+	ctx context.Context,
+	method string,
+	path string,
+	body any,
+	result any,
+) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode scim request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.BaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create scim request: %w", err)
+	}
+	req.Header.Set("Accept", SCIMContentType)
+	if body != nil {
+		req.Header.Set("Content-Type", SCIMContentType)
+	}
+	if token := a.GetAccessToken(ctx); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform scim request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &scimError{StatusCode: resp.StatusCode, Detail: strings.TrimSpace(string(detail))}
+	}
+
+	if result != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("failed to decode scim response: %w", err)
+		}
+	}
+	return nil
+}
+
+// toGocloak converts the SCIM user to the representation used by the KeycloakActorIface.
+// SCIM has no notion of email verification, hence active users are considered verified.
+func (u *scimUser) toGocloak() *gocloak13.User {
+	user := &gocloak13.User{
+		ID:            gocloak13.StringP(u.ID),
+		Username:      gocloak13.StringP(u.UserName),
+		FirstName:     gocloak13.StringP(u.Name.GivenName),
+		LastName:      gocloak13.StringP(u.Name.FamilyName),
+		Enabled:       gocloak13.BoolP(u.Active),
+		EmailVerified: gocloak13.BoolP(u.Active),
+	}
+	for i := range u.Emails {
+		if u.Emails[i].Primary || i == 0 {
+			user.Email = gocloak13.StringP(u.Emails[i].Value)
+		}
+	}
+	return user
+}
+
+// toGocloak converts the SCIM group to the representation used by the KeycloakActorIface.
+func (g *scimGroup) toGocloak() *gocloak13.Role {
+	return &gocloak13.Role{
+		ID:   gocloak13.StringP(g.ID),
+		Name: gocloak13.StringP(g.DisplayName),
+	}
+}
+
+// scimEqualFilter returns a SCIM filter matching the resources whose attribute equals the given value.
+func scimEqualFilter(attribute, value string) string {
+	escaped := strings.ReplaceAll(strings.ReplaceAll(value, `\`, `\\`), `"`, `\"`)
+	return fmt.Sprintf(`%s eq "%s"`, attribute, escaped)
+}
+
+// isSCIMNotFound returns whether the error corresponds to a not found SCIM resource.
+func isSCIMNotFound(err error) bool {
+	var scimErr *scimError
+	return errors.As(err, &scimErr) && scimErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,173 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	gocloak13 "github.com/Nerzal/gocloak/v13"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SCIM auth", func() {
+	const token = "scim-token"
+
+	var (
+		ctx    context.Context
+		server *httptest.Server
+		scim   *scimServer
+		a      *SCIMActor
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scim = newSCIMServer(token)
+		server = httptest.NewServer(scim)
+
+		actorSCIM = SCIMActor{}
+		Expect(SetupSCIMActor(ctx, server.URL+"/", token, logr.Discard())).To(Succeed())
+		a = &actorSCIM
+	})
+
+	AfterEach(func() {
+		server.Close()
+		actorIface = &actor
+	})
+
+	Describe("SetupSCIMActor", func() {
+		It("should initialize the actor and select it as the current one", func() {
+			Expect(a.IsInitialized()).To(BeTrue())
+			Expect(a.BaseURL).To(Equal(server.URL))
+			Expect(GetKeycloakActor()).To(BeIdenticalTo(a))
+		})
+
+		It("should return an error if the credentials are not valid", func() {
+			actorSCIM = SCIMActor{}
+			err := SetupSCIMActor(ctx, server.URL, "wrong-token", logr.Discard())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("%d", http.StatusUnauthorized)))
+			Expect(actorSCIM.IsInitialized()).To(BeFalse())
+		})
+	})
+
+	Describe("Users", func() {
+		It("should create, retrieve and delete a user", func() {
+			id, err := a.CreateUser(ctx, "john", "john@example.com", "John", "Doe")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(id).ToNot(BeEmpty())
+
+			user, err := a.GetUser(ctx, "john")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*user.ID).To(Equal(id))
+			Expect(*user.Email).To(Equal("john@example.com"))
+			Expect(*user.FirstName).To(Equal("John"))
+			Expect(*user.LastName).To(Equal("Doe"))
+			Expect(*user.EmailVerified).To(BeTrue())
+
+			Expect(a.DeleteUser(ctx, id)).To(Succeed())
+			_, err = a.GetUser(ctx, "john")
+			Expect(err).To(MatchError(fmt.Sprintf("%d", http.StatusNotFound)))
+		})
+
+		It("should return a not found error for missing users", func() {
+			_, err := a.GetUser(ctx, "missing")
+			Expect(err).To(MatchError(fmt.Sprintf("%d", http.StatusNotFound)))
+
+			_, err = a.GetUserRoles(ctx, "missing")
+			Expect(err).To(MatchError(fmt.Sprintf("%d", http.StatusNotFound)))
+		})
+
+		It("should list all the users, across multiple pages", func() {
+			for i := range listUsersPageSize + 1 {
+				_, err := a.CreateUser(ctx, fmt.Sprintf("user-%d", i), "user@example.com", "User", "Test")
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			users, err := a.ListUsers(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(users).To(HaveLen(listUsersPageSize + 1))
+		})
+
+		It("should fail to create a duplicated user", func() {
+			_, err := a.CreateUser(ctx, "john", "john@example.com", "John", "Doe")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = a.CreateUser(ctx, "john", "john@example.com", "John", "Doe")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Roles", func() {
+		It("should create, retrieve and delete a role", func() {
+			id, err := a.CreateRole(ctx, "workspace-test:user", "Test User Role")
+			Expect(err).ToNot(HaveOccurred())
+
+			role, err := a.GetRole(ctx, "workspace-test:user")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*role.ID).To(Equal(id))
+			Expect(*role.Name).To(Equal("workspace-test:user"))
+
+			Expect(a.DeleteRole(ctx, "workspace-test:user")).To(Succeed())
+			_, err = a.GetRole(ctx, "workspace-test:user")
+			Expect(err).To(MatchError(fmt.Sprintf("%d", http.StatusNotFound)))
+		})
+
+		It("should ignore the deletion of missing roles", func() {
+			Expect(a.DeleteRole(ctx, "missing")).To(Succeed())
+		})
+
+		It("should manage the roles of a user", func() {
+			userID, err := a.CreateUser(ctx, "john", "john@example.com", "John", "Doe")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = a.CreateRole(ctx, "workspace-a:user", "")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = a.CreateRole(ctx, "workspace-b:manager", "")
+			Expect(err).ToNot(HaveOccurred())
+
+			roleA, err := a.GetRole(ctx, "workspace-a:user")
+			Expect(err).ToNot(HaveOccurred())
+			roleB, err := a.GetRole(ctx, "workspace-b:manager")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(a.AddUserToRoles(ctx, userID, []*gocloak13.Role{roleA, roleB})).To(Succeed())
+			roles, err := a.GetUserRoles(ctx, userID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(roleNames(roles)).To(ConsistOf("workspace-a:user", "workspace-b:manager"))
+
+			Expect(a.RemoveUserFromRoles(ctx, userID, []*gocloak13.Role{roleA})).To(Succeed())
+			roles, err = a.GetUserRoles(ctx, userID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(roleNames(roles)).To(ConsistOf("workspace-b:manager"))
+		})
+	})
+
+	Describe("scimEqualFilter", func() {
+		It("should escape the quotes in the value", func() {
+			Expect(scimEqualFilter("userName", `a"b`)).To(Equal(`userName eq "a\"b"`))
+		})
+	})
+})
+
+func roleNames(roles []*gocloak13.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, *role.Name)
+	}
+	return names
+}
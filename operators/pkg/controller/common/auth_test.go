@@ -312,6 +312,55 @@ var _ = Describe("Auth", func() {
 		})
 	})
 
+	Describe("ListUsers", func() {
+		BeforeEach(func() {
+			actor = KeycloakActor{
+				Client:         mKcClient,
+				Realm:          "test-realm",
+				credentials:    struct{ ClientID, ClientSecret string }{ClientID: "test-client", ClientSecret: "test-secret"},
+				tokenMutex:     sync.RWMutex{},
+				token:          &gocloak13.JWT{AccessToken: "test-token"},
+				tokenExpiresAt: time.Now().Unix() + 3600, // valid for 1 hour
+			}
+		})
+
+		pageParams := func(first int) gocloak13.GetUsersParams {
+			return gocloak13.GetUsersParams{
+				First:               gocloak13.IntP(first),
+				Max:                 gocloak13.IntP(listUsersPageSize),
+				BriefRepresentation: gocloak13.BoolP(true),
+			}
+		}
+
+		It("should retrieve all the pages of users", func() {
+			fullPage := make([]*gocloak13.User, listUsersPageSize)
+			for i := range fullPage {
+				fullPage[i] = &gocloak13.User{Username: gocloak13.StringP(fmt.Sprintf("user-%d", i))}
+			}
+			lastUser := &gocloak13.User{Username: gocloak13.StringP("last-user")}
+
+			gomock.InOrder(
+				mKcClient.EXPECT().GetUsers(gomock.Any(), "test-token", "test-realm", pageParams(0)).Return(fullPage, nil),
+				mKcClient.EXPECT().GetUsers(gomock.Any(), "test-token", "test-realm", pageParams(listUsersPageSize)).
+					Return([]*gocloak13.User{lastUser}, nil),
+			)
+
+			users, err := actor.ListUsers(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(users).To(HaveLen(listUsersPageSize + 1))
+			Expect(users[listUsersPageSize]).To(Equal(lastUser))
+		})
+
+		It("should return an error if a page cannot be retrieved", func() {
+			mKcClient.EXPECT().GetUsers(gomock.Any(), "test-token", "test-realm", pageParams(0)).
+				Return(nil, fmt.Errorf("error fetching users"))
+
+			users, err := actor.ListUsers(context.Background())
+			Expect(err).To(HaveOccurred())
+			Expect(users).To(BeNil())
+		})
+	})
+
 	Describe("CreateUser", func() {
 		BeforeEach(func() {
 			actor = KeycloakActor{
@@ -0,0 +1,267 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// scimServer is an in-memory stand-in of a SCIM 2.0 provider, implementing the subset of the
+// protocol used by the SCIMActor (equality filters, users pagination and patches of the group members).
+type scimServer struct {
+	mutex  sync.Mutex
+	token  string
+	nextID int
+	users  map[string]*scimUser
+	groups map[string]*scimGroup
+}
+
+var scimFilterRegex = regexp.MustCompile(`^(\w+) eq "((?:[^"\\]|\\.)*)"$`)
+var scimMemberPathRegex = regexp.MustCompile(`^members\[value eq "((?:[^"\\]|\\.)*)"\]$`)
+
+func newSCIMServer(token string) *scimServer {
+	return &scimServer{
+		token:  token,
+		users:  make(map[string]*scimUser),
+		groups: make(map[string]*scimGroup),
+	}
+}
+
+func (s *scimServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer "+s.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case segments[0] == "ServiceProviderConfig":
+		s.reply(w, http.StatusOK, map[string]any{"patch": map[string]bool{"supported": true}})
+	case segments[0] == "Users" && len(segments) == 1:
+		s.handleUsers(w, r)
+	case segments[0] == "Users" && len(segments) == 2:
+		s.handleUser(w, r, segments[1])
+	case segments[0] == "Groups" && len(segments) == 1:
+		s.handleGroups(w, r)
+	case segments[0] == "Groups" && len(segments) == 2:
+		s.handleGroup(w, r, segments[1])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *scimServer) handleUsers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		filter := r.URL.Query().Get("filter")
+		attribute, value := parseSCIMFilter(filter)
+		list := scimListResponse[scimUser]{Resources: []scimUser{}}
+		for _, user := range s.users {
+			if filter == "" || (attribute == "userName" && strings.EqualFold(user.UserName, value)) {
+				list.Resources = append(list.Resources, s.withGroups(user))
+			}
+		}
+		sort.Slice(list.Resources, func(i, j int) bool { return list.Resources[i].ID < list.Resources[j].ID })
+		list.TotalResults = len(list.Resources)
+		list.Resources = scimPage(list.Resources, r.URL.Query())
+		s.reply(w, http.StatusOK, list)
+	case http.MethodPost:
+		var user scimUser
+		if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, existing := range s.users {
+			if existing.UserName == user.UserName {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+		}
+		user.ID = s.newID()
+		s.users[user.ID] = &user
+		s.reply(w, http.StatusCreated, user)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *scimServer) handleUser(w http.ResponseWriter, r *http.Request, id string) {
+	user, found := s.users[id]
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.reply(w, http.StatusOK, s.withGroups(user))
+	case http.MethodDelete:
+		delete(s.users, id)
+		for _, group := range s.groups {
+			group.Members = removeSCIMMember(group.Members, id)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *scimServer) handleGroups(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		attribute, value := parseSCIMFilter(r.URL.Query().Get("filter"))
+		list := scimListResponse[scimGroup]{Resources: []scimGroup{}}
+		for _, group := range s.groups {
+			if attribute == "displayName" && group.DisplayName == value {
+				list.Resources = append(list.Resources, *group)
+			}
+		}
+		list.TotalResults = len(list.Resources)
+		s.reply(w, http.StatusOK, list)
+	case http.MethodPost:
+		var group scimGroup
+		if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, existing := range s.groups {
+			if existing.DisplayName == group.DisplayName {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+		}
+		group.ID = s.newID()
+		s.groups[group.ID] = &group
+		s.reply(w, http.StatusCreated, group)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *scimServer) handleGroup(w http.ResponseWriter, r *http.Request, id string) {
+	group, found := s.groups[id]
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		delete(s.groups, id)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPatch:
+		var patch struct {
+			Operations []struct {
+				Op    string            `json:"op"`
+				Path  string            `json:"path"`
+				Value []scimMultiValued `json:"value"`
+			} `json:"Operations"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, op := range patch.Operations {
+			switch {
+			case op.Op == "add" && op.Path == "members":
+				for _, member := range op.Value {
+					if _, found := s.users[member.Value]; !found {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					group.Members = append(removeSCIMMember(group.Members, member.Value), member)
+				}
+			case op.Op == "remove" && scimMemberPathRegex.MatchString(op.Path):
+				group.Members = removeSCIMMember(group.Members, scimMemberPathRegex.FindStringSubmatch(op.Path)[1])
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *scimServer) newID() string {
+	s.nextID++
+	return fmt.Sprintf("id-%d", s.nextID)
+}
+
+// withGroups returns a copy of the user, with the groups it is member of.
+func (s *scimServer) withGroups(user *scimUser) scimUser {
+	result := *user
+	result.Groups = nil
+	for _, group := range s.groups {
+		for _, member := range group.Members {
+			if member.Value == user.ID {
+				result.Groups = append(result.Groups, scimMultiValued{Value: group.ID, Display: group.DisplayName})
+			}
+		}
+	}
+	return result
+}
+
+func (s *scimServer) reply(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", SCIMContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// scimPage returns the page of resources selected by the startIndex and count query parameters.
+func scimPage[T any](resources []T, query url.Values) []T {
+	start, err := strconv.Atoi(query.Get("startIndex"))
+	if err != nil || start < 1 {
+		start = 1
+	}
+	if start > len(resources) {
+		return []T{}
+	}
+	resources = resources[start-1:]
+	if count, err := strconv.Atoi(query.Get("count")); err == nil && count < len(resources) {
+		resources = resources[:count]
+	}
+	return resources
+}
+
+func parseSCIMFilter(filter string) (attribute, value string) {
+	matches := scimFilterRegex.FindStringSubmatch(filter)
+	if matches == nil {
+		return "", ""
+	}
+	return matches[1], strings.ReplaceAll(strings.ReplaceAll(matches[2], `\"`, `"`), `\\`, `\`)
+}
+
+func removeSCIMMember(members []scimMultiValued, id string) []scimMultiValued {
+	result := make([]scimMultiValued, 0, len(members))
+	for _, member := range members {
+		if member.Value != id {
+			result = append(result, member)
+		}
+	}
+	return result
+}
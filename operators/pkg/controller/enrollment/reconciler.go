@@ -0,0 +1,308 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package enrollment implements the controller managing the requests of enrollment in the workspaces requiring approval.
+package enrollment
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils/mail"
+)
+
+const (
+	// RequestNotificationMailTemplatePath -> the template of the email notifying the managers about a new enrollment request.
+	RequestNotificationMailTemplatePath = "enrollment_request_notification.yaml"
+	// DecisionNotificationMailTemplatePath -> the template of the email notifying the tenant about the outcome of the enrollment request.
+	DecisionNotificationMailTemplatePath = "enrollment_decision_notification.yaml"
+)
+
+// MailSender is the interface used to deliver the notifications about the enrollment requests.
+type MailSender interface {
+	SendCrownLabsMail(ctx context.Context, emailContentTemplatePath string, ph *mail.Placeholders) error
+}
+
+// Reconciler reconciles EnrollmentRequest objects, applying the decisions of the workspace managers to the
+// corresponding Tenants, notifying the parties involved and expiring the requests not decided in time.
+type Reconciler struct {
+	client.Client
+	// MailClient delivers the notifications (disabled if nil).
+	MailClient MailSender
+	// RequestTTL is the duration after which the pending requests expire (disabled if zero).
+	RequestTTL time.Duration
+}
+
+// Reconcile reconciles the state of an EnrollmentRequest resource.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	var request clv1alpha2.EnrollmentRequest
+	if err := r.Get(ctx, req.NamespacedName, &request); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if isCompleted(&request) {
+		return ctrl.Result{}, nil
+	}
+
+	original := request.Status.DeepCopy()
+	now := time.Now()
+	if request.Status.RequestTime == nil {
+		request.Status.Phase = clv1alpha2.EnrollmentRequestPending
+		request.Status.RequestTime = ptr.To(metav1.NewTime(now))
+		if r.RequestTTL > 0 {
+			request.Status.ExpirationTime = ptr.To(metav1.NewTime(now.Add(r.RequestTTL)))
+		}
+	}
+
+	result, err := r.enforceEnrollmentRequest(ctx, log, &request, now)
+
+	if !equalStatus(original, &request.Status) {
+		if updateErr := r.Status().Update(ctx, &request); updateErr != nil {
+			log.Error(updateErr, "failed to update enrollment request status")
+			return ctrl.Result{}, kerrors.NewAggregate([]error{err, updateErr})
+		}
+	}
+
+	return result, err
+}
+
+// enforceEnrollmentRequest determines the outcome of the request, depending on the decision and the current state of the tenant.
+func (r *Reconciler) enforceEnrollmentRequest(
+	ctx context.Context,
+	log logr.Logger,
+	request *clv1alpha2.EnrollmentRequest,
+	now time.Time,
+) (ctrl.Result, error) {
+	var tenant clv1alpha2.Tenant
+	if err := r.Get(ctx, types.NamespacedName{Name: request.Spec.TenantRef.Name}, &tenant); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to get tenant %s: %w", request.Spec.TenantRef.Name, err)
+		}
+		r.complete(ctx, log, request, nil, clv1alpha2.EnrollmentRequestWithdrawn, now)
+		return ctrl.Result{}, nil
+	}
+
+	workspace := request.Spec.WorkspaceRef.Name
+	idx := slices.IndexFunc(tenant.Spec.Workspaces, func(ws clv1alpha2.TenantWorkspaceEntry) bool { return ws.Name == workspace })
+	isCandidate := idx >= 0 && tenant.Spec.Workspaces[idx].Role == clv1alpha2.Candidate
+
+	switch {
+	case request.Spec.Decision == clv1alpha2.EnrollmentApproved:
+		if isCandidate {
+			if err := r.patchTenantWorkspace(ctx, &tenant, workspace, clv1alpha2.User); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		r.complete(ctx, log, request, &tenant, clv1alpha2.EnrollmentRequestApproved, now)
+
+	case request.Spec.Decision == clv1alpha2.EnrollmentRejected:
+		if isCandidate {
+			if err := r.patchTenantWorkspace(ctx, &tenant, workspace, ""); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		r.complete(ctx, log, request, &tenant, clv1alpha2.EnrollmentRequestRejected, now)
+
+	case idx < 0:
+		// the tenant withdrew the request, or a manager removed the workspace directly from the tenant
+		r.complete(ctx, log, request, nil, clv1alpha2.EnrollmentRequestWithdrawn, now)
+
+	case !isCandidate:
+		// a manager approved the enrollment directly in the tenant
+		r.complete(ctx, log, request, &tenant, clv1alpha2.EnrollmentRequestApproved, now)
+
+	case request.Status.ExpirationTime != nil && !now.Before(request.Status.ExpirationTime.Time):
+		if err := r.patchTenantWorkspace(ctx, &tenant, workspace, ""); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.complete(ctx, log, request, &tenant, clv1alpha2.EnrollmentRequestExpired, now)
+
+	default:
+		if !request.Status.ManagersNotified && r.MailClient != nil {
+			if err := r.notifyManagers(ctx, request, &tenant); err != nil {
+				return ctrl.Result{}, err
+			}
+			request.Status.ManagersNotified = true
+		}
+		if request.Status.ExpirationTime != nil {
+			return ctrl.Result{RequeueAfter: request.Status.ExpirationTime.Sub(now)}, nil
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// patchTenantWorkspace sets the role of the tenant in the workspace, removing the workspace if the role is empty.
+func (r *Reconciler) patchTenantWorkspace(
+	ctx context.Context,
+	tenant *clv1alpha2.Tenant,
+	workspace string,
+	role clv1alpha2.WorkspaceUserRole,
+) error {
+	patch := client.MergeFrom(tenant.DeepCopy())
+	if role == "" {
+		tenant.Spec.Workspaces = slices.DeleteFunc(tenant.Spec.Workspaces, func(ws clv1alpha2.TenantWorkspaceEntry) bool { return ws.Name == workspace })
+	} else {
+		// the role is modified in place, to preserve the validity period of the membership
+		for i := range tenant.Spec.Workspaces {
+			if tenant.Spec.Workspaces[i].Name == workspace {
+				tenant.Spec.Workspaces[i].Role = role
+			}
+		}
+	}
+
+	if err := r.Patch(ctx, tenant, patch); err != nil {
+		return fmt.Errorf("failed to update the workspaces of tenant %s: %w", tenant.Name, err)
+	}
+	return nil
+}
+
+// complete marks the request as completed with the given phase, notifying the tenant about the outcome (if not nil).
+func (r *Reconciler) complete(
+	ctx context.Context,
+	log logr.Logger,
+	request *clv1alpha2.EnrollmentRequest,
+	tenant *clv1alpha2.Tenant,
+	phase clv1alpha2.EnrollmentRequestPhase,
+	now time.Time,
+) {
+	request.Status.Phase = phase
+	request.Status.CompletionTime = ptr.To(metav1.NewTime(now))
+	enrollmentRequestsCompleted.WithLabelValues(string(phase)).Inc()
+	log.Info("enrollment request completed", "phase", phase)
+
+	if tenant == nil || r.MailClient == nil {
+		return
+	}
+
+	// the failure to deliver the notification does not prevent the completion of the request
+	if err := r.MailClient.SendCrownLabsMail(ctx, DecisionNotificationMailTemplatePath, &mail.Placeholders{
+		TenantName:        tenant.Name,
+		TenantEmail:       tenant.Spec.Email,
+		WorkspaceName:     r.workspacePrettyName(ctx, request.Spec.WorkspaceRef.Name),
+		EnrollmentOutcome: string(phase),
+		Reason:            request.Spec.Reason,
+	}); err != nil {
+		log.Error(err, "failed to notify the tenant about the outcome of the enrollment request")
+	}
+}
+
+// notifyManagers notifies the managers of the workspace about a new enrollment request,
+// skipping the ones already notified by a previous (partially failed) attempt.
+func (r *Reconciler) notifyManagers(
+	ctx context.Context,
+	request *clv1alpha2.EnrollmentRequest,
+	candidate *clv1alpha2.Tenant,
+) error {
+	var managers clv1alpha2.TenantList
+	if err := r.List(ctx, &managers, client.MatchingLabels{
+		forge.GetWorkspaceTargetLabel(request.Spec.WorkspaceRef.Name): string(clv1alpha2.Manager),
+	}); err != nil {
+		return fmt.Errorf("failed to list the managers of workspace %s: %w", request.Spec.WorkspaceRef.Name, err)
+	}
+
+	remaining := "it is decided"
+	if request.Status.ExpirationTime != nil {
+		remaining = request.Status.ExpirationTime.Format(time.RFC1123)
+	}
+
+	workspace := r.workspacePrettyName(ctx, request.Spec.WorkspaceRef.Name)
+	var errs []error
+	for i := range managers.Items {
+		manager := &managers.Items[i]
+		if slices.Contains(request.Status.NotifiedManagers, manager.Name) {
+			continue
+		}
+		if err := r.MailClient.SendCrownLabsMail(ctx, RequestNotificationMailTemplatePath, &mail.Placeholders{
+			TenantName:    manager.Name,
+			TenantEmail:   manager.Spec.Email,
+			CandidateName: fmt.Sprintf("%s %s (%s)", candidate.Spec.FirstName, candidate.Spec.LastName, candidate.Name),
+			WorkspaceName: workspace,
+			RemainingTime: remaining,
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify manager %s: %w", manager.Name, err))
+			continue
+		}
+		// the notified managers are recorded, so that only the failed deliveries are retried
+		request.Status.NotifiedManagers = append(request.Status.NotifiedManagers, manager.Name)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// workspacePrettyName returns the pretty name of the workspace, falling back to its name.
+func (r *Reconciler) workspacePrettyName(ctx context.Context, name string) string {
+	var workspace clv1alpha1.Workspace
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, &workspace); err != nil || workspace.Spec.PrettyName == "" {
+		return name
+	}
+	return workspace.Spec.PrettyName
+}
+
+// isCompleted returns whether the request reached a final phase.
+func isCompleted(request *clv1alpha2.EnrollmentRequest) bool {
+	return request.Status.Phase != "" && request.Status.Phase != clv1alpha2.EnrollmentRequestPending
+}
+
+// equalStatus returns whether the two statuses are equal.
+func equalStatus(a, b *clv1alpha2.EnrollmentRequestStatus) bool {
+	return a.Phase == b.Phase && a.ManagersNotified == b.ManagersNotified && slices.Equal(a.NotifiedManagers, b.NotifiedManagers) &&
+		a.RequestTime.Equal(b.RequestTime) && a.ExpirationTime.Equal(b.ExpirationTime) && a.CompletionTime.Equal(b.CompletionTime)
+}
+
+// SetupWithManager registers a new controller for EnrollmentRequest resources.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// The generation changed predicate allows to avoid reconciling on the status changes of the EnrollmentRequest
+		For(&clv1alpha2.EnrollmentRequest{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// The requests are reconciled also when the workspaces of the tenant are changed directly
+		Watches(&clv1alpha2.Tenant{}, handler.EnqueueRequestsFromMapFunc(r.tenantToEnrollmentRequests)).
+		WithLogConstructor(utils.LogConstructor(mgr.GetLogger(), "EnrollmentRequest")).
+		Complete(r)
+}
+
+// tenantToEnrollmentRequests returns the requests to reconcile the enrollment requests of the given tenant.
+func (r *Reconciler) tenantToEnrollmentRequests(ctx context.Context, tenant client.Object) []ctrl.Request {
+	var requests clv1alpha2.EnrollmentRequestList
+	if err := r.List(ctx, &requests, client.MatchingLabels{forge.LabelTenantKey: tenant.GetName()}); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list the enrollment requests of tenant", "tenant", tenant.GetName())
+		return nil
+	}
+
+	var enqueues []ctrl.Request
+	for i := range requests.Items {
+		if !isCompleted(&requests.Items[i]) {
+			enqueues = append(enqueues, ctrl.Request{NamespacedName: forge.NamespacedNameFromObject(&requests.Items[i])})
+		}
+	}
+	return enqueues
+}
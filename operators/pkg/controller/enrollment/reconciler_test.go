@@ -0,0 +1,230 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enrollment_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/enrollment"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+)
+
+var _ = Describe("EnrollmentRequest reconciler", func() {
+	var (
+		cl         client.Client
+		mailSender *fakeMailSender
+		reconciler *enrollment.Reconciler
+
+		candidate *clv1alpha2.Tenant
+		request   *clv1alpha2.EnrollmentRequest
+	)
+
+	BeforeEach(func() {
+		candidate = &clv1alpha2.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: testTenantName},
+			Spec: clv1alpha2.TenantSpec{
+				FirstName: "Mario", LastName: "Rossi", Email: "candidate@example.com",
+				Workspaces: []clv1alpha2.TenantWorkspaceEntry{{Name: testWorkspace, Role: clv1alpha2.Candidate}},
+			},
+		}
+		request = &clv1alpha2.EnrollmentRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   forge.GetEnrollmentRequestName(testTenantName, testWorkspace),
+				Labels: forge.EnrollmentRequestLabels(nil, testTenantName, testWorkspace),
+			},
+			Spec: clv1alpha2.EnrollmentRequestSpec{
+				TenantRef:    clv1alpha2.GenericRef{Name: testTenantName},
+				WorkspaceRef: clv1alpha2.GenericRef{Name: testWorkspace},
+			},
+		}
+		mailSender = &fakeMailSender{}
+	})
+
+	JustBeforeEach(func() {
+		manager := &clv1alpha2.Tenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   testManagerName,
+				Labels: map[string]string{forge.GetWorkspaceTargetLabel(testWorkspace): string(clv1alpha2.Manager)},
+			},
+			Spec: clv1alpha2.TenantSpec{Email: "manager@example.com"},
+		}
+		workspace := &clv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: testWorkspace},
+			Spec:       clv1alpha1.WorkspaceSpec{PrettyName: "Pretty Workspace"},
+		}
+
+		cl = fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(candidate, manager, workspace, request).
+			WithStatusSubresource(&clv1alpha2.EnrollmentRequest{}).Build()
+		reconciler = &enrollment.Reconciler{Client: cl, MailClient: mailSender, RequestTTL: time.Hour}
+	})
+
+	reconcile := func() ctrl.Result {
+		res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: forge.NamespacedNameFromObject(request)})
+		Expect(err).NotTo(HaveOccurred())
+		return res
+	}
+
+	getRequest := func() *clv1alpha2.EnrollmentRequest {
+		var er clv1alpha2.EnrollmentRequest
+		Expect(cl.Get(ctx, forge.NamespacedNameFromObject(request), &er)).To(Succeed())
+		return &er
+	}
+
+	getCandidateWorkspaces := func() []clv1alpha2.TenantWorkspaceEntry {
+		var tn clv1alpha2.Tenant
+		Expect(cl.Get(ctx, forge.NamespacedNameFromObject(candidate), &tn)).To(Succeed())
+		return tn.Spec.Workspaces
+	}
+
+	Context("A new request is observed", func() {
+		It("Should set the request pending and notify the managers once", func() {
+			res := reconcile()
+			Expect(res.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+
+			er := getRequest()
+			Expect(er.Status.Phase).To(Equal(clv1alpha2.EnrollmentRequestPending))
+			Expect(er.Status.RequestTime).NotTo(BeNil())
+			Expect(er.Status.ExpirationTime).NotTo(BeNil())
+			Expect(er.Status.ManagersNotified).To(BeTrue())
+
+			Expect(mailSender.Sent).To(HaveLen(1))
+			Expect(mailSender.Sent[0].Template).To(Equal(enrollment.RequestNotificationMailTemplatePath))
+			Expect(mailSender.Sent[0].Mail.TenantEmail).To(Equal("manager@example.com"))
+			Expect(mailSender.Sent[0].Mail.WorkspaceName).To(Equal("Pretty Workspace"))
+			Expect(mailSender.Sent[0].Mail.CandidateName).To(ContainSubstring(testTenantName))
+
+			reconcile()
+			Expect(mailSender.Sent).To(HaveLen(1))
+		})
+
+		It("Should retry the failed notifications only", func() {
+			Expect(cl.Create(ctx, &clv1alpha2.Tenant{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "other-manager",
+					Labels: map[string]string{forge.GetWorkspaceTargetLabel(testWorkspace): string(clv1alpha2.Manager)},
+				},
+				Spec: clv1alpha2.TenantSpec{Email: "other-manager@example.com"},
+			})).To(Succeed())
+			mailSender.Failing = map[string]bool{"other-manager@example.com": true}
+
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: forge.NamespacedNameFromObject(request)})
+			Expect(err).To(HaveOccurred())
+			Expect(getRequest().Status.ManagersNotified).To(BeFalse())
+			Expect(getRequest().Status.NotifiedManagers).To(ConsistOf(testManagerName))
+			Expect(mailSender.Sent).To(HaveLen(1))
+
+			mailSender.Failing = nil
+			reconcile()
+			Expect(getRequest().Status.ManagersNotified).To(BeTrue())
+			Expect(getRequest().Status.NotifiedManagers).To(ConsistOf(testManagerName, "other-manager"))
+			Expect(mailSender.Sent).To(HaveLen(2))
+			Expect(mailSender.Sent[1].Mail.TenantEmail).To(Equal("other-manager@example.com"))
+		})
+	})
+
+	Context("The request is approved", func() {
+		BeforeEach(func() { request.Spec.Decision = clv1alpha2.EnrollmentApproved })
+
+		It("Should promote the candidate to user and notify the tenant", func() {
+			reconcile()
+			Expect(getCandidateWorkspaces()).To(ConsistOf(clv1alpha2.TenantWorkspaceEntry{Name: testWorkspace, Role: clv1alpha2.User}))
+
+			er := getRequest()
+			Expect(er.Status.Phase).To(Equal(clv1alpha2.EnrollmentRequestApproved))
+			Expect(er.Status.CompletionTime).NotTo(BeNil())
+
+			Expect(mailSender.Sent).To(HaveLen(1))
+			Expect(mailSender.Sent[0].Template).To(Equal(enrollment.DecisionNotificationMailTemplatePath))
+			Expect(mailSender.Sent[0].Mail.TenantEmail).To(Equal("candidate@example.com"))
+			Expect(mailSender.Sent[0].Mail.EnrollmentOutcome).To(Equal(string(clv1alpha2.EnrollmentRequestApproved)))
+		})
+	})
+
+	Context("The request is rejected", func() {
+		BeforeEach(func() {
+			request.Spec.Decision = clv1alpha2.EnrollmentRejected
+			request.Spec.Reason = "not enrolled in the course"
+		})
+
+		It("Should remove the workspace from the candidate and notify the reason", func() {
+			reconcile()
+			Expect(getCandidateWorkspaces()).To(BeEmpty())
+			Expect(getRequest().Status.Phase).To(Equal(clv1alpha2.EnrollmentRequestRejected))
+			Expect(mailSender.Sent).To(HaveLen(1))
+			Expect(mailSender.Sent[0].Mail.Reason).To(Equal("not enrolled in the course"))
+		})
+	})
+
+	Context("The request is expired", func() {
+		BeforeEach(func() {
+			request.Status = clv1alpha2.EnrollmentRequestStatus{
+				Phase:          clv1alpha2.EnrollmentRequestPending,
+				RequestTime:    ptr.To(metav1.NewTime(time.Now().Add(-2 * time.Hour))),
+				ExpirationTime: ptr.To(metav1.NewTime(time.Now().Add(-time.Hour))),
+			}
+		})
+
+		It("Should remove the workspace from the candidate and mark the request expired", func() {
+			reconcile()
+			Expect(getCandidateWorkspaces()).To(BeEmpty())
+			Expect(getRequest().Status.Phase).To(Equal(clv1alpha2.EnrollmentRequestExpired))
+			Expect(mailSender.Sent).To(HaveLen(1))
+			Expect(mailSender.Sent[0].Mail.EnrollmentOutcome).To(Equal(string(clv1alpha2.EnrollmentRequestExpired)))
+		})
+	})
+
+	Context("The candidate withdraws the request", func() {
+		BeforeEach(func() { candidate.Spec.Workspaces = nil })
+
+		It("Should mark the request withdrawn without notifications", func() {
+			reconcile()
+			Expect(getRequest().Status.Phase).To(Equal(clv1alpha2.EnrollmentRequestWithdrawn))
+			Expect(mailSender.Sent).To(BeEmpty())
+		})
+	})
+
+	Context("A manager approves the enrollment directly in the tenant", func() {
+		BeforeEach(func() { candidate.Spec.Workspaces[0].Role = clv1alpha2.User })
+
+		It("Should mark the request approved", func() {
+			reconcile()
+			Expect(getRequest().Status.Phase).To(Equal(clv1alpha2.EnrollmentRequestApproved))
+		})
+	})
+
+	Context("The request is already completed", func() {
+		BeforeEach(func() {
+			request.Spec.Decision = clv1alpha2.EnrollmentApproved
+			request.Status.Phase = clv1alpha2.EnrollmentRequestRejected
+		})
+
+		It("Should not modify the tenant", func() {
+			reconcile()
+			Expect(getCandidateWorkspaces()).To(ConsistOf(clv1alpha2.TenantWorkspaceEntry{Name: testWorkspace, Role: clv1alpha2.Candidate}))
+			Expect(mailSender.Sent).To(BeEmpty())
+		})
+	})
+})
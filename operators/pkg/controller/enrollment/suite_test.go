@@ -0,0 +1,72 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enrollment_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils/mail"
+)
+
+var (
+	scheme *runtime.Scheme
+	ctx    = context.Background()
+)
+
+const (
+	testTenantName  = "candidate"
+	testManagerName = "manager"
+	testWorkspace   = "workspace"
+)
+
+func TestEnrollment(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Enrollment Suite")
+}
+
+var _ = BeforeSuite(func() {
+	scheme = runtime.NewScheme()
+	Expect(clv1alpha1.AddToScheme(scheme)).To(Succeed())
+	Expect(clv1alpha2.AddToScheme(scheme)).To(Succeed())
+})
+
+// sentMail records a notification delivered through the fakeMailSender.
+type sentMail struct {
+	Template string
+	Mail     mail.Placeholders
+}
+
+// fakeMailSender records the notifications instead of delivering them.
+type fakeMailSender struct {
+	Sent []sentMail
+	// Failing contains the email addresses the notifications cannot be delivered to.
+	Failing map[string]bool
+}
+
+func (f *fakeMailSender) SendCrownLabsMail(_ context.Context, templatePath string, ph *mail.Placeholders) error {
+	if f.Failing[ph.TenantEmail] {
+		return fmt.Errorf("failed to deliver the notification to %s", ph.TenantEmail)
+	}
+	f.Sent = append(f.Sent, sentMail{Template: templatePath, Mail: *ph})
+	return nil
+}
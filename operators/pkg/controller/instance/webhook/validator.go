@@ -18,6 +18,8 @@ package webhook
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"slices"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -41,13 +43,64 @@ type InstanceValidator struct {
 	Client client.Client
 }
 
-// accumulateEnvResources aggregates the resource footprints from a list of environments into the running totals.
-func accumulateEnvResources(envList []clv1alpha2.Environment, total *apicommon.ResourceSpec) {
+// accumulateEnvResources aggregates the resource footprints from a list of environments into the running totals,
+// considering the disk size possibly requested for the given instance and the additional disks of each environment.
+func accumulateEnvResources(instance *clv1alpha2.Instance, envList []clv1alpha2.Environment, total *apicommon.ResourceSpec) {
 	for i := range envList {
-		total.Accumulate(&envList[i].Resources.ResourceSpec)
+		resources := envList[i].Resources.ResourceSpec
+		resources.Disk = forge.InstanceDiskSize(instance, &envList[i])
+		resources.Disk.Add(forge.AdditionalDisksSize(&envList[i]))
+		total.Accumulate(&resources)
 	}
 }
 
+// validateDiskSizes checks that the disk sizes requested for the instance refer to persistent environments of the template,
+// and, in case of update, that none of the disks is shrunk.
+func validateDiskSizes(oldInstance, newInstance *clv1alpha2.Instance, template *clv1alpha2.Template) error {
+	for name := range newInstance.Spec.DiskSizes {
+		idx := slices.IndexFunc(template.Spec.EnvironmentList, func(env clv1alpha2.Environment) bool { return env.Name == name })
+		if idx < 0 || !template.Spec.EnvironmentList[idx].Persistent {
+			return fmt.Errorf("the disk size can be requested only for persistent environments, %s is not", name)
+		}
+	}
+
+	if oldInstance == nil {
+		return nil
+	}
+	for i := range template.Spec.EnvironmentList {
+		env := &template.Spec.EnvironmentList[i]
+		oldSize, newSize := forge.InstanceDiskSize(oldInstance, env), forge.InstanceDiskSize(newInstance, env)
+		if newSize.Cmp(oldSize) < 0 {
+			return fmt.Errorf("the disk of environment %s cannot be shrunk (%s < %s)", env.Name, newSize.String(), oldSize.String())
+		}
+	}
+	return nil
+}
+
+// validateNoTransferInProgress checks that the given instance is not the source of an accepted and not yet completed
+// transfer, whose volumes would be concurrently written if the instance was started.
+func validateNoTransferInProgress(ctx context.Context, instance *clv1alpha2.Instance, cl client.Client) error {
+	transfers := &clv1alpha2.InstanceTransferList{}
+	if err := cl.List(ctx, transfers); err != nil {
+		return fmt.Errorf("failed to list instance transfers: %w", err)
+	}
+
+	for i := range transfers.Items {
+		transfer := &transfers.Items[i]
+		if transfer.Spec.InstanceRef.Name != instance.Name || transfer.Spec.InstanceRef.Namespace != instance.Namespace {
+			continue
+		}
+		switch transfer.Status.Phase {
+		case clv1alpha2.InstanceTransferPhaseCompleted, clv1alpha2.InstanceTransferPhaseRejected, clv1alpha2.InstanceTransferPhaseFailed:
+			continue
+		}
+		if transfer.Spec.Decision == clv1alpha2.InstanceTransferAccepted {
+			return fmt.Errorf("the instance cannot be started while it is being transferred by %s", transfer.Name)
+		}
+	}
+	return nil
+}
+
 func validateQuota(ctx context.Context, instance *clv1alpha2.Instance, cl client.Client) (admission.Warnings, error) {
 	var warnings admission.Warnings
 
@@ -59,6 +112,10 @@ func validateQuota(ctx context.Context, instance *clv1alpha2.Instance, cl client
 		return warnings, fmt.Errorf("failed to get instance template: %w", err)
 	}
 
+	if err := validateDiskSizes(nil, instance, instanceTemplate); err != nil {
+		return warnings, err
+	}
+
 	// Get the workspace details (quota, templates namespace)
 	wsName := instanceTemplate.Spec.WorkspaceRef.Name
 	wsQuota := apicommon.WorkspaceResourceQuota{}
@@ -124,7 +181,7 @@ func validateQuota(ctx context.Context, instance *clv1alpha2.Instance, cl client
 	}
 
 	// Add the resources of the instance being created
-	accumulateEnvResources(instanceTemplate.Spec.EnvironmentList, &totalResources)
+	accumulateEnvResources(instance, instanceTemplate.Spec.EnvironmentList, &totalResources)
 
 	// Add the resources of the other instances
 	for i := range workspaceInstances.Items {
@@ -146,7 +203,7 @@ func validateQuota(ctx context.Context, instance *clv1alpha2.Instance, cl client
 			continue
 		}
 
-		accumulateEnvResources(tmpl.Spec.EnvironmentList, &totalResources)
+		accumulateEnvResources(&workspaceInstances.Items[i], tmpl.Spec.EnvironmentList, &totalResources)
 	}
 
 	// Check against the workspace quota
@@ -198,7 +255,7 @@ func (iv *InstanceValidator) ValidateCreate(
 	return validateQuota(ctx, instance, iv.Client)
 }
 
-// ValidateUpdate checks if a paused instance can be started again.
+// ValidateUpdate checks if a paused instance can be started again, and whether the requested disks can be expanded.
 func (iv *InstanceValidator) ValidateUpdate(
 	ctx context.Context,
 	oldObj, newObj runtime.Object,
@@ -216,6 +273,25 @@ func (iv *InstanceValidator) ValidateUpdate(
 		return warnings, fmt.Errorf("expected Instance resource but got %T", newObj)
 	}
 
+	// The instance cannot be started while its volumes are being copied by a transfer
+	if !oldInstance.Spec.Running && newInstance.Spec.Running {
+		if err := validateNoTransferInProgress(ctx, newInstance, iv.Client); err != nil {
+			return warnings, err
+		}
+	}
+
+	// The disks can only be expanded, within the limits of the quota
+	if !reflect.DeepEqual(oldInstance.Spec.DiskSizes, newInstance.Spec.DiskSizes) {
+		instanceTemplate := &clv1alpha2.Template{}
+		if err := iv.Client.Get(ctx, forge.NamespacedNameFromGenericRef(newInstance.Spec.Template), instanceTemplate); err != nil {
+			return warnings, fmt.Errorf("failed to get instance template: %w", err)
+		}
+		if err := validateDiskSizes(oldInstance, newInstance, instanceTemplate); err != nil {
+			return warnings, err
+		}
+		return validateQuota(ctx, newInstance, iv.Client)
+	}
+
 	// If the instance is not being started, no further checks are needed
 	if oldInstance.Spec.Running || !newInstance.Spec.Running {
 		return warnings, nil
@@ -211,6 +211,60 @@ var _ = Describe("InstanceValidator", func() {
 		Expect(warnings).To(BeEmpty())
 	})
 
+	It("should count the additional disks in the disk quota", func() {
+		ws := &clv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: testWorkspace},
+			Spec: clv1alpha1.WorkspaceSpec{
+				Quota: apicommon.WorkspaceResourceQuota{
+					Instances: 2,
+					ResourceSpec: apicommon.ResourceSpec{
+						CPU:    4,
+						Memory: resource.MustParse("8Gi"),
+						Disk:   resource.MustParse("20Gi"),
+					},
+				},
+			},
+		}
+		tmpl := &clv1alpha2.Template{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplate, Namespace: testWorkspaceNamespace},
+			Spec: clv1alpha2.TemplateSpec{
+				EnvironmentList: []clv1alpha2.Environment{{
+					Name:            testEnvironment,
+					EnvironmentType: clv1alpha2.ClassVM,
+					Resources: clv1alpha2.EnvironmentResources{
+						ResourceSpec: apicommon.ResourceSpec{
+							CPU:    2,
+							Memory: resource.MustParse("2Gi"),
+							Disk:   resource.MustParse("12Gi"),
+						},
+					},
+					AdditionalDisks: []clv1alpha2.AdditionalDisk{
+						{Name: "db", Size: resource.MustParse("6Gi"), Persistent: true},
+						{Name: "scratch", Size: resource.MustParse("4Gi")},
+					},
+				}},
+				WorkspaceRef: clv1alpha2.GenericRef{Name: testWorkspace},
+			},
+		}
+		inst := &clv1alpha2.Instance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testNewInstance,
+				Namespace: testTenantNamespace,
+				Labels:    map[string]string{forge.LabelWorkspaceKey: testWorkspace},
+			},
+			Spec: clv1alpha2.InstanceSpec{
+				Template: clv1alpha2.GenericRef{Name: testTemplate, Namespace: testWorkspaceNamespace},
+				Running:  true,
+			},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws, tmpl).Build()
+		validator := &webhook.InstanceValidator{Client: fakeClient}
+		warnings, err := validator.ValidateCreate(ctx, inst)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("quota exceeded: Disk (22Gi > 20Gi)"))
+		Expect(warnings).To(BeEmpty())
+	})
+
 	It("should deny creation when extended resource quota is exceeded", func() {
 		ws := &clv1alpha1.Workspace{
 			ObjectMeta: metav1.ObjectMeta{Name: testWorkspace},
@@ -295,4 +349,145 @@ var _ = Describe("InstanceValidator", func() {
 		Expect(err.Error()).To(ContainSubstring("failed to get instance template"))
 		Expect(warnings).To(BeEmpty())
 	})
+
+	Describe("The request of a larger disk", func() {
+		var (
+			validator   *webhook.InstanceValidator
+			tmpl        *clv1alpha2.Template
+			oldInstance *clv1alpha2.Instance
+			newInstance *clv1alpha2.Instance
+		)
+
+		BeforeEach(func() {
+			ws := &clv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: testWorkspace},
+				Spec: clv1alpha1.WorkspaceSpec{
+					Quota: apicommon.WorkspaceResourceQuota{
+						Instances: 2,
+						ResourceSpec: apicommon.ResourceSpec{
+							CPU:    4,
+							Memory: resource.MustParse("8Gi"),
+							Disk:   resource.MustParse("30Gi"),
+						},
+					},
+				},
+			}
+			tmpl = &clv1alpha2.Template{
+				ObjectMeta: metav1.ObjectMeta{Name: testTemplate, Namespace: testWorkspaceNamespace},
+				Spec: clv1alpha2.TemplateSpec{
+					EnvironmentList: []clv1alpha2.Environment{{
+						Name:       testEnvironment,
+						Persistent: true,
+						Resources: clv1alpha2.EnvironmentResources{
+							ResourceSpec: apicommon.ResourceSpec{
+								CPU:    2,
+								Memory: resource.MustParse("2Gi"),
+								Disk:   resource.MustParse("10Gi"),
+							},
+						},
+					}},
+					WorkspaceRef: clv1alpha2.GenericRef{Name: testWorkspace},
+				},
+			}
+			oldInstance = &clv1alpha2.Instance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testNewInstance,
+					Namespace: testTenantNamespace,
+					Labels:    map[string]string{forge.LabelWorkspaceKey: testWorkspace},
+				},
+				Spec: clv1alpha2.InstanceSpec{
+					Template:  clv1alpha2.GenericRef{Name: testTemplate, Namespace: testWorkspaceNamespace},
+					Running:   true,
+					DiskSizes: map[string]resource.Quantity{testEnvironment: resource.MustParse("15Gi")},
+				},
+			}
+			newInstance = oldInstance.DeepCopy()
+
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws, tmpl).Build()
+			validator = &webhook.InstanceValidator{Client: fakeClient}
+		})
+
+		It("should be allowed within the disk quota", func() {
+			newInstance.Spec.DiskSizes[testEnvironment] = resource.MustParse("25Gi")
+			_, err := validator.ValidateUpdate(ctx, oldInstance, newInstance)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should be denied when the disk quota is exceeded", func() {
+			newInstance.Spec.DiskSizes[testEnvironment] = resource.MustParse("40Gi")
+			_, err := validator.ValidateUpdate(ctx, oldInstance, newInstance)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("quota exceeded: Disk"))
+		})
+
+		It("should deny shrinking the disk", func() {
+			newInstance.Spec.DiskSizes[testEnvironment] = resource.MustParse("12Gi")
+			_, err := validator.ValidateUpdate(ctx, oldInstance, newInstance)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cannot be shrunk"))
+		})
+
+		It("should deny the request for unknown environments", func() {
+			newInstance.Spec.DiskSizes = map[string]resource.Quantity{"other": resource.MustParse("20Gi")}
+			_, err := validator.ValidateCreate(ctx, newInstance)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("persistent environments"))
+		})
+	})
+
+	Describe("The start of an instance being transferred", func() {
+		var (
+			transfer    *clv1alpha2.InstanceTransfer
+			oldInstance *clv1alpha2.Instance
+			newInstance *clv1alpha2.Instance
+		)
+
+		BeforeEach(func() {
+			oldInstance = &clv1alpha2.Instance{
+				ObjectMeta: metav1.ObjectMeta{Name: testNewInstance, Namespace: testTenantNamespace},
+				Spec: clv1alpha2.InstanceSpec{
+					Template: clv1alpha2.GenericRef{Name: testTemplate, Namespace: testWorkspaceNamespace},
+				},
+			}
+			newInstance = oldInstance.DeepCopy()
+			newInstance.Spec.Running = true
+			transfer = &clv1alpha2.InstanceTransfer{
+				ObjectMeta: metav1.ObjectMeta{Name: "transfer"},
+				Spec: clv1alpha2.InstanceTransferSpec{
+					InstanceRef:  clv1alpha2.GenericRef{Name: testNewInstance, Namespace: testTenantNamespace},
+					RecipientRef: clv1alpha2.GenericRef{Name: "recipient"},
+					Decision:     clv1alpha2.InstanceTransferAccepted,
+				},
+				Status: clv1alpha2.InstanceTransferStatus{Phase: clv1alpha2.InstanceTransferPhaseCopying},
+			}
+		})
+
+		validate := func() error {
+			ws := &clv1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: testWorkspace}}
+			tmpl := &clv1alpha2.Template{
+				ObjectMeta: metav1.ObjectMeta{Name: testTemplate, Namespace: testWorkspaceNamespace},
+				Spec:       clv1alpha2.TemplateSpec{WorkspaceRef: clv1alpha2.GenericRef{Name: testWorkspace}},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws, tmpl, transfer).Build()
+			_, err := (&webhook.InstanceValidator{Client: fakeClient}).ValidateUpdate(ctx, oldInstance, newInstance)
+			return err
+		}
+
+		It("should be denied while the volumes are being copied", func() {
+			err := validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("being transferred"))
+		})
+
+		It("should be allowed once the transfer is completed", func() {
+			transfer.Status.Phase = clv1alpha2.InstanceTransferPhaseCompleted
+			Expect(validate()).NotTo(HaveOccurred())
+		})
+
+		It("should be allowed while the recipient has not accepted the transfer", func() {
+			transfer.Spec.Decision = ""
+			transfer.Status.Phase = clv1alpha2.InstanceTransferPhasePending
+			Expect(validate()).NotTo(HaveOccurred())
+		})
+	})
 })
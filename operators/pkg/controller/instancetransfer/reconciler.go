@@ -0,0 +1,395 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package instancetransfer implements the controller handing a copy of a persistent Instance to another tenant,
+// once the transfer has been accepted by the recipient.
+package instancetransfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
+)
+
+const (
+	// EventTransferStarted -> the reason of the event emitted when the copy of the volumes starts.
+	EventTransferStarted = "TransferStarted"
+	// EventTransferCompleted -> the reason of the event emitted when the transfer is completed.
+	EventTransferCompleted = "TransferCompleted"
+	// EventTransferRejected -> the reason of the event emitted when the transfer is rejected by the recipient.
+	EventTransferRejected = "TransferRejected"
+	// EventTransferFailed -> the reason of the event emitted when the transfer fails.
+	EventTransferFailed = "TransferFailed"
+
+	// recipientNamespaceRetryInterval -> the interval the transfer is checked again while the namespace of the recipient is not ready.
+	recipientNamespaceRetryInterval = time.Minute
+)
+
+// errTransferFailed is wrapped by the errors which cause the transfer to fail permanently.
+var errTransferFailed = errors.New("transfer failed")
+
+// Reconciler reconciles InstanceTransfer objects, copying the persistent volumes of the source Instance
+// into the namespace of the recipient, and creating there a matching (stopped) Instance.
+type Reconciler struct {
+	client.Client
+	EventsRecorder record.EventRecorder
+	// NamespaceWhitelist selects the namespaces of the source Instances handled by this controller.
+	NamespaceWhitelist metav1.LabelSelector
+	// MirrorPVCStorageClassName is the StorageClass mirroring the volumes of the source containerized environments.
+	MirrorPVCStorageClassName string
+
+	// This function, if configured, is deferred at the beginning of the Reconcile.
+	// Specifically, it is meant to be set to GinkgoRecover during the tests,
+	// in order to lead to a controlled failure in case the Reconcile panics.
+	ReconcileDeferHook func()
+}
+
+// Reconcile reconciles the state of an InstanceTransfer resource.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if r.ReconcileDeferHook != nil {
+		defer r.ReconcileDeferHook()
+	}
+	log := ctrl.LoggerFrom(ctx)
+
+	var transfer clv1alpha2.InstanceTransfer
+	if err := r.Get(ctx, req.NamespacedName, &transfer); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// Check the selector label of the source namespace, in order to know whether to perform or not reconciliation.
+	// Transfers of instances in no longer existing namespaces are reconciled, to be marked as failed.
+	if proceed, err := utils.CheckSelectorLabel(ctx, r.Client, transfer.Spec.InstanceRef.Namespace, r.NamespaceWhitelist.MatchLabels); !proceed && !kerrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.enforceVisibility(ctx, &transfer); err != nil {
+		log.Error(err, "failed to enforce the visibility of the instance transfer")
+		return ctrl.Result{}, err
+	}
+
+	if isCompleted(&transfer) {
+		return ctrl.Result{}, nil
+	}
+
+	original := transfer.Status.DeepCopy()
+	if transfer.Status.Phase == "" {
+		transfer.Status.Phase = clv1alpha2.InstanceTransferPhasePending
+	}
+
+	result, err := r.enforceInstanceTransfer(ctx, log, &transfer)
+	if errors.Is(err, errTransferFailed) {
+		err = r.complete(ctx, log, &transfer, clv1alpha2.InstanceTransferPhaseFailed, err.Error())
+	}
+
+	if !reflect.DeepEqual(original, &transfer.Status) {
+		if updateErr := r.Status().Update(ctx, &transfer); updateErr != nil {
+			log.Error(updateErr, "failed to update instance transfer status")
+			return ctrl.Result{}, utilerrors.NewAggregate([]error{err, updateErr})
+		}
+	}
+
+	return result, err
+}
+
+// enforceVisibility grants access to the given (cluster-scoped) transfer to its recipient and to the owner of the source Instance only.
+// The resources are owned by the transfer, hence they are garbage collected together with it.
+func (r *Reconciler) enforceVisibility(ctx context.Context, transfer *clv1alpha2.InstanceTransfer) error {
+	// The owner of a no longer existing instance is unknown: in that case, the previous subjects are preserved.
+	var owner string
+	var source clv1alpha2.Instance
+	if err := r.Get(ctx, forge.NamespacedNameFromGenericRef(transfer.Spec.InstanceRef), &source); err == nil {
+		owner = source.Spec.Tenant.Name
+	} else if !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get instance %s: %w", transfer.Spec.InstanceRef.Name, err)
+	}
+
+	cr := rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: forge.InstanceTransferClusterRoleName(transfer)}}
+	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, &cr, func() error {
+		forge.ConfigureInstanceTransferClusterRole(&cr, transfer)
+		return ctrl.SetControllerReference(transfer, &cr, r.Scheme())
+	}); err != nil {
+		return fmt.Errorf("failed to enforce cluster role %s: %w", cr.Name, err)
+	}
+
+	crb := rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: forge.InstanceTransferClusterRoleName(transfer)}}
+	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, &crb, func() error {
+		if owner != "" || crb.ResourceVersion == "" {
+			forge.ConfigureInstanceTransferClusterRoleBinding(&crb, transfer, owner)
+		}
+		return ctrl.SetControllerReference(transfer, &crb, r.Scheme())
+	}); err != nil {
+		return fmt.Errorf("failed to enforce cluster role binding %s: %w", crb.Name, err)
+	}
+	return nil
+}
+
+// enforceInstanceTransfer advances the transfer, depending on the decision of the recipient and the state of the source Instance.
+func (r *Reconciler) enforceInstanceTransfer(ctx context.Context, log logr.Logger, transfer *clv1alpha2.InstanceTransfer) (ctrl.Result, error) {
+	var source clv1alpha2.Instance
+	if err := r.Get(ctx, forge.NamespacedNameFromGenericRef(transfer.Spec.InstanceRef), &source); err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("%w: the instance to be transferred does not exist", errTransferFailed)
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get instance %s: %w", transfer.Spec.InstanceRef.Name, err)
+	}
+
+	var template clv1alpha2.Template
+	if err := r.Get(ctx, forge.NamespacedNameFromGenericRef(source.Spec.Template), &template); err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("%w: the template of the instance does not exist", errTransferFailed)
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get template %s: %w", source.Spec.Template.Name, err)
+	}
+	if slices.ContainsFunc(template.Spec.EnvironmentList, func(env clv1alpha2.Environment) bool { return !env.Persistent }) {
+		return ctrl.Result{}, fmt.Errorf("%w: only persistent instances can be transferred", errTransferFailed)
+	}
+
+	var recipient clv1alpha2.Tenant
+	if err := r.Get(ctx, types.NamespacedName{Name: transfer.Spec.RecipientRef.Name}, &recipient); err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("%w: the recipient does not exist", errTransferFailed)
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get tenant %s: %w", transfer.Spec.RecipientRef.Name, err)
+	}
+	if !slices.ContainsFunc(recipient.Spec.Workspaces, func(ws clv1alpha2.TenantWorkspaceEntry) bool {
+		return ws.Name == template.Spec.WorkspaceRef.Name && ws.Role != clv1alpha2.Candidate && forge.IsWorkspaceMembershipActive(&ws, time.Now())
+	}) {
+		return ctrl.Result{}, fmt.Errorf("%w: the recipient is not enrolled in workspace %s", errTransferFailed, template.Spec.WorkspaceRef.Name)
+	}
+
+	switch transfer.Spec.Decision {
+	case clv1alpha2.InstanceTransferRejected:
+		return ctrl.Result{}, r.complete(ctx, log, transfer, clv1alpha2.InstanceTransferPhaseRejected, "the transfer has been rejected by the recipient")
+	case clv1alpha2.InstanceTransferAccepted:
+		// continue
+	default:
+		transfer.Status.Message = "waiting for the recipient to accept the transfer"
+		return ctrl.Result{}, nil
+	}
+
+	if transfer.Status.Phase == clv1alpha2.InstanceTransferPhasePending {
+		if !recipient.Status.PersonalNamespace.Created {
+			transfer.Status.Message = "waiting for the namespace of the recipient to be created"
+			return ctrl.Result{RequeueAfter: recipientNamespaceRetryInterval}, nil
+		}
+		if source.Spec.Running || source.Status.Phase != clv1alpha2.EnvironmentPhaseOff {
+			transfer.Status.Message = "waiting for the instance to be stopped"
+			return ctrl.Result{}, nil
+		}
+
+		target := clv1alpha2.GenericRef{Name: transfer.Name, Namespace: recipient.Status.PersonalNamespace.Name}
+		if err := r.Get(ctx, forge.NamespacedNameFromGenericRef(target), &clv1alpha2.Instance{}); err == nil {
+			return ctrl.Result{}, fmt.Errorf("%w: an instance named %s already exists in the namespace of the recipient", errTransferFailed, target.Name)
+		} else if !kerrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to check the existence of instance %s: %w", target.Name, err)
+		}
+
+		transfer.Status.Phase = clv1alpha2.InstanceTransferPhaseCopying
+		transfer.Status.TargetInstanceRef = &target
+		transfer.Status.Message = "copying the persistent volumes of the instance"
+		r.EventsRecorder.Eventf(transfer, corev1.EventTypeNormal, EventTransferStarted, "Copying instance %s into namespace %s", source.Name, target.Namespace)
+		log.Info("instance transfer started", "instance", forge.NamespacedNameFromObject(&source), "target", target)
+	}
+
+	// The source volumes cannot be safely mirrored or cloned if the instance is (re)started in the meanwhile,
+	// as they would be concurrently written. The instance webhook rejects such requests, this is a safety net.
+	if source.Spec.Running {
+		return ctrl.Result{}, fmt.Errorf("%w: the instance has been started while its volumes were being copied", errTransferFailed)
+	}
+
+	target := &metav1.ObjectMeta{Name: transfer.Status.TargetInstanceRef.Name, Namespace: transfer.Status.TargetInstanceRef.Namespace}
+	completed := true
+	for i := range template.Spec.EnvironmentList {
+		environment := &template.Spec.EnvironmentList[i]
+
+		var done bool
+		var err error
+		switch environment.EnvironmentType {
+		case clv1alpha2.ClassContainer, clv1alpha2.ClassStandalone:
+			done, err = r.enforcePVCCopy(ctx, transfer, &source, environment, target)
+		default:
+			done, err = r.enforceDataVolumeCopy(ctx, transfer, &source, environment, target)
+		}
+		if err != nil {
+			log.Error(err, "failed to copy the volume of environment", "environment", environment.Name)
+			return ctrl.Result{}, err
+		}
+		completed = completed && done
+	}
+	if !completed {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.enforceTargetInstance(ctx, transfer, &source, &template, target); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, r.complete(ctx, log, transfer, clv1alpha2.InstanceTransferPhaseCompleted,
+		fmt.Sprintf("instance %s created in namespace %s", target.Name, target.Namespace))
+}
+
+// enforceTargetInstance creates the (stopped) copy of the source Instance, which takes the ownership of the copied volumes.
+func (r *Reconciler) enforceTargetInstance(
+	ctx context.Context,
+	transfer *clv1alpha2.InstanceTransfer,
+	source *clv1alpha2.Instance,
+	template *clv1alpha2.Template,
+	target *metav1.ObjectMeta,
+) error {
+	instance := clv1alpha2.Instance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      target.Name,
+			Namespace: target.Namespace,
+			Labels:    forge.InstanceTransferLabels(nil, transfer),
+		},
+		Spec: forge.TransferredInstanceSpec(source, transfer.Spec.RecipientRef.Name),
+	}
+	if err := r.Create(ctx, &instance); kerrors.IsAlreadyExists(err) {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(&instance), &instance); err != nil {
+			return fmt.Errorf("failed to get instance %s: %w", instance.Name, err)
+		}
+		if instance.Labels[forge.LabelInstanceTransferKey] != transfer.Name {
+			return fmt.Errorf("%w: an instance named %s already exists in the namespace of the recipient", errTransferFailed, instance.Name)
+		}
+	} else if kerrors.IsForbidden(err) || kerrors.IsInvalid(err) {
+		// e.g., the quota of the recipient would be exceeded
+		return fmt.Errorf("%w: the instance cannot be created: %w", errTransferFailed, err)
+	} else if err != nil {
+		return fmt.Errorf("failed to create instance %s: %w", instance.Name, err)
+	}
+
+	for i := range template.Spec.EnvironmentList {
+		environment := &template.Spec.EnvironmentList[i]
+
+		var volumes []client.Object
+		if environment.EnvironmentType == clv1alpha2.ClassContainer || environment.EnvironmentType == clv1alpha2.ClassStandalone {
+			volumes = append(volumes, &corev1.PersistentVolumeClaim{ObjectMeta: forge.ObjectMetaWithSuffix(target, environment.Name)})
+		} else {
+			for _, suffix := range forge.DataVolumeSuffixes(environment) {
+				volumes = append(volumes, &cdiv1beta1.DataVolume{ObjectMeta: forge.ObjectMetaWithSuffix(target, suffix)})
+			}
+		}
+
+		for _, volume := range volumes {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(volume), volume); err != nil {
+				return fmt.Errorf("failed to get volume %s: %w", volume.GetName(), err)
+			}
+			if metav1.IsControlledBy(volume, &instance) {
+				continue
+			}
+			if err := ctrl.SetControllerReference(&instance, volume, r.Scheme()); err != nil {
+				return fmt.Errorf("failed to set the owner of volume %s: %w", volume.GetName(), err)
+			}
+			if err := r.Update(ctx, volume); err != nil {
+				return fmt.Errorf("failed to update volume %s: %w", volume.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// complete marks the transfer as completed with the given phase, releasing the resources no longer necessary.
+func (r *Reconciler) complete(
+	ctx context.Context,
+	log logr.Logger,
+	transfer *clv1alpha2.InstanceTransfer,
+	phase clv1alpha2.InstanceTransferPhase,
+	message string,
+) error {
+	if err := r.cleanup(ctx, transfer, phase == clv1alpha2.InstanceTransferPhaseCompleted); err != nil {
+		log.Error(err, "failed to release the resources of the instance transfer")
+		return err
+	}
+
+	transfer.Status.Phase = phase
+	transfer.Status.Message = message
+	transfer.Status.CompletionTime = ptr.To(metav1.Now())
+	instanceTransfersCompleted.WithLabelValues(string(phase)).Inc()
+	log.Info("instance transfer completed", "phase", phase, "message", message)
+
+	switch phase {
+	case clv1alpha2.InstanceTransferPhaseCompleted:
+		r.EventsRecorder.Event(transfer, corev1.EventTypeNormal, EventTransferCompleted, message)
+	case clv1alpha2.InstanceTransferPhaseRejected:
+		r.EventsRecorder.Event(transfer, corev1.EventTypeNormal, EventTransferRejected, message)
+	default:
+		r.EventsRecorder.Event(transfer, corev1.EventTypeWarning, EventTransferFailed, message)
+	}
+	return nil
+}
+
+// isCompleted returns whether the transfer reached a final phase.
+func isCompleted(transfer *clv1alpha2.InstanceTransfer) bool {
+	switch transfer.Status.Phase {
+	case clv1alpha2.InstanceTransferPhaseCompleted, clv1alpha2.InstanceTransferPhaseRejected, clv1alpha2.InstanceTransferPhaseFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetupWithManager registers a new controller for InstanceTransfer resources.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// The generation changed predicate allows to avoid reconciling on the status changes of the InstanceTransfer
+		For(&clv1alpha2.InstanceTransfer{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// The transfers are reconciled when the source instance is stopped
+		Watches(&clv1alpha2.Instance{}, handler.EnqueueRequestsFromMapFunc(r.instanceToInstanceTransfers)).
+		// The copied volumes are owned by the transfer, until adopted by the target instance
+		Watches(&cdiv1beta1.DataVolume{}, handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &clv1alpha2.InstanceTransfer{})).
+		Owns(&batchv1.Job{}).
+		WithLogConstructor(utils.LogConstructor(mgr.GetLogger(), "InstanceTransfer")).
+		Complete(r)
+}
+
+// instanceToInstanceTransfers returns the requests to reconcile the pending transfers of the given instance.
+func (r *Reconciler) instanceToInstanceTransfers(ctx context.Context, instance client.Object) []ctrl.Request {
+	var transfers clv1alpha2.InstanceTransferList
+	if err := r.List(ctx, &transfers); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list the instance transfers", "instance", forge.NamespacedNameFromObject(instance))
+		return nil
+	}
+
+	var enqueues []ctrl.Request
+	for i := range transfers.Items {
+		ref := transfers.Items[i].Spec.InstanceRef
+		if ref.Name == instance.GetName() && ref.Namespace == instance.GetNamespace() && !isCompleted(&transfers.Items[i]) {
+			enqueues = append(enqueues, ctrl.Request{NamespacedName: forge.NamespacedNameFromObject(&transfers.Items[i])})
+		}
+	}
+	return enqueues
+}
@@ -0,0 +1,443 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instancetransfer_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apicommon "github.com/netgroup-polito/CrownLabs/operators/api/common"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/instancetransfer"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+)
+
+var _ = Describe("InstanceTransfer reconciler", func() {
+	const envName = "env"
+
+	var (
+		cl         client.Client
+		reconciler *instancetransfer.Reconciler
+		recorder   *record.FakeRecorder
+
+		environment clv1alpha2.Environment
+		source      *clv1alpha2.Instance
+		recipient   *clv1alpha2.Tenant
+		transfer    *clv1alpha2.InstanceTransfer
+		extraObjs   []client.Object
+	)
+
+	BeforeEach(func() {
+		environment = clv1alpha2.Environment{
+			Name:            envName,
+			Image:           "some/image:v1",
+			EnvironmentType: clv1alpha2.ClassContainer,
+			Persistent:      true,
+			Resources:       clv1alpha2.EnvironmentResources{ResourceSpec: apicommon.ResourceSpec{Disk: resource.MustParse("5Gi")}},
+		}
+		source = &clv1alpha2.Instance{
+			ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: testSourceNs, UID: "source-uid"},
+			Spec: clv1alpha2.InstanceSpec{
+				Template:   clv1alpha2.GenericRef{Name: "template", Namespace: "workspace-" + testWorkspace},
+				Tenant:     clv1alpha2.GenericRef{Name: testOwnerName},
+				PrettyName: "My instance",
+			},
+			Status: clv1alpha2.InstanceStatus{Phase: clv1alpha2.EnvironmentPhaseOff},
+		}
+		recipient = &clv1alpha2.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: testRecipientName},
+			Spec: clv1alpha2.TenantSpec{Workspaces: []clv1alpha2.TenantWorkspaceEntry{{
+				Name: testWorkspace,
+				Role: clv1alpha2.User,
+			}}},
+			Status: clv1alpha2.TenantStatus{PersonalNamespace: clv1alpha2.NameCreated{Name: testTargetNs, Created: true}},
+		}
+		transfer = &clv1alpha2.InstanceTransfer{
+			ObjectMeta: metav1.ObjectMeta{Name: "transfer", UID: "transfer-uid"},
+			Spec: clv1alpha2.InstanceTransferSpec{
+				InstanceRef:  clv1alpha2.GenericRef{Name: source.Name, Namespace: source.Namespace},
+				RecipientRef: clv1alpha2.GenericRef{Name: testRecipientName},
+				Decision:     clv1alpha2.InstanceTransferAccepted,
+			},
+		}
+		extraObjs = nil
+	})
+
+	JustBeforeEach(func() {
+		template := &clv1alpha2.Template{
+			ObjectMeta: metav1.ObjectMeta{Name: source.Spec.Template.Name, Namespace: source.Spec.Template.Namespace},
+			Spec: clv1alpha2.TemplateSpec{
+				WorkspaceRef:    clv1alpha2.GenericRef{Name: testWorkspace},
+				EnvironmentList: []clv1alpha2.Environment{environment},
+			},
+		}
+		sourceNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   testSourceNs,
+			Labels: map[string]string{testWhitelistKey: testWhitelistVal},
+		}}
+
+		cl = fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(append(extraObjs, sourceNs, template, source, recipient, transfer)...).
+			WithStatusSubresource(&clv1alpha2.InstanceTransfer{}, &clv1alpha2.Instance{}).Build()
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &instancetransfer.Reconciler{
+			Client:                    cl,
+			EventsRecorder:            recorder,
+			NamespaceWhitelist:        metav1.LabelSelector{MatchLabels: map[string]string{testWhitelistKey: testWhitelistVal}},
+			MirrorPVCStorageClassName: "mirror",
+		}
+	})
+
+	reconcile := func() ctrl.Result {
+		res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: forge.NamespacedNameFromObject(transfer)})
+		Expect(err).NotTo(HaveOccurred())
+		return res
+	}
+
+	getTransfer := func() *clv1alpha2.InstanceTransfer {
+		var itr clv1alpha2.InstanceTransfer
+		Expect(cl.Get(ctx, forge.NamespacedNameFromObject(transfer), &itr)).To(Succeed())
+		return &itr
+	}
+
+	targetKey := func(suffix string) client.ObjectKey {
+		return client.ObjectKey{Namespace: testTargetNs, Name: transfer.Name + "-" + suffix}
+	}
+
+	sourcePVC := func() *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: source.Name + "-" + envName, Namespace: testSourceNs},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("8Gi")},
+				},
+			},
+		}
+	}
+
+	When("the recipient has not decided yet", func() {
+		BeforeEach(func() { transfer.Spec.Decision = "" })
+
+		It("should keep the transfer pending", func() {
+			reconcile()
+			itr := getTransfer()
+			Expect(itr.Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhasePending))
+			Expect(itr.Status.Message).To(ContainSubstring("accept"))
+		})
+	})
+
+	When("the transfer is reconciled", func() {
+		BeforeEach(func() { transfer.Spec.Decision = "" })
+
+		It("should grant access to the transfer to the owner and the recipient only", func() {
+			reconcile()
+
+			var cr rbacv1.ClusterRole
+			Expect(cl.Get(ctx, client.ObjectKey{Name: "crownlabs-instance-transfer-" + transfer.Name}, &cr)).To(Succeed())
+			Expect(cr.Rules).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Resources":     ConsistOf("instancetransfers"),
+				"ResourceNames": ConsistOf(transfer.Name),
+			})))
+			Expect(metav1.IsControlledBy(&cr, transfer)).To(BeTrue())
+
+			var crb rbacv1.ClusterRoleBinding
+			Expect(cl.Get(ctx, client.ObjectKey{Name: cr.Name}, &crb)).To(Succeed())
+			Expect(crb.RoleRef.Name).To(Equal(cr.Name))
+			Expect(crb.Subjects).To(ConsistOf(
+				MatchFields(IgnoreExtras, Fields{"Kind": Equal(rbacv1.UserKind), "Name": Equal(testOwnerName)}),
+				MatchFields(IgnoreExtras, Fields{"Kind": Equal(rbacv1.UserKind), "Name": Equal(testRecipientName)}),
+			))
+			Expect(metav1.IsControlledBy(&crb, transfer)).To(BeTrue())
+		})
+
+		When("the instance is deleted afterwards", func() {
+			It("should preserve the access of the owner", func() {
+				reconcile()
+				Expect(cl.Delete(ctx, source)).To(Succeed())
+				reconcile()
+
+				var crb rbacv1.ClusterRoleBinding
+				Expect(cl.Get(ctx, client.ObjectKey{Name: "crownlabs-instance-transfer-" + transfer.Name}, &crb)).To(Succeed())
+				Expect(crb.Subjects).To(HaveLen(2))
+				Expect(getTransfer().Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseFailed))
+			})
+		})
+	})
+
+	When("the recipient rejects the transfer", func() {
+		BeforeEach(func() { transfer.Spec.Decision = clv1alpha2.InstanceTransferRejected })
+
+		It("should mark the transfer as rejected", func() {
+			reconcile()
+			itr := getTransfer()
+			Expect(itr.Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseRejected))
+			Expect(itr.Status.CompletionTime).NotTo(BeNil())
+			Expect(recorder.Events).To(Receive(ContainSubstring(instancetransfer.EventTransferRejected)))
+		})
+	})
+
+	When("the recipient is not enrolled in the workspace", func() {
+		BeforeEach(func() { recipient.Spec.Workspaces = nil })
+
+		It("should mark the transfer as failed", func() {
+			reconcile()
+			itr := getTransfer()
+			Expect(itr.Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseFailed))
+			Expect(itr.Status.Message).To(ContainSubstring("not enrolled"))
+			Expect(recorder.Events).To(Receive(ContainSubstring(instancetransfer.EventTransferFailed)))
+		})
+	})
+
+	When("the environment of the instance is not persistent", func() {
+		BeforeEach(func() { environment.Persistent = false })
+
+		It("should mark the transfer as failed", func() {
+			reconcile()
+			Expect(getTransfer().Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseFailed))
+		})
+	})
+
+	When("the instance is still running", func() {
+		BeforeEach(func() {
+			source.Spec.Running = true
+			source.Status.Phase = clv1alpha2.EnvironmentPhaseReady
+		})
+
+		It("should wait for the instance to be stopped", func() {
+			reconcile()
+			itr := getTransfer()
+			Expect(itr.Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhasePending))
+			Expect(itr.Status.Message).To(ContainSubstring("stopped"))
+		})
+	})
+
+	When("the instance is started while its volumes are being copied", func() {
+		BeforeEach(func() {
+			source.Spec.Running = true
+			transfer.Status.Phase = clv1alpha2.InstanceTransferPhaseCopying
+			transfer.Status.TargetInstanceRef = &clv1alpha2.GenericRef{Name: transfer.Name, Namespace: testTargetNs}
+		})
+
+		It("should mark the transfer as failed", func() {
+			reconcile()
+			itr := getTransfer()
+			Expect(itr.Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseFailed))
+			Expect(itr.Status.Message).To(ContainSubstring("started"))
+		})
+	})
+
+	When("an instance with the same name exists in the namespace of the recipient", func() {
+		BeforeEach(func() {
+			extraObjs = append(extraObjs, &clv1alpha2.Instance{ObjectMeta: metav1.ObjectMeta{Name: transfer.Name, Namespace: testTargetNs}})
+		})
+
+		It("should mark the transfer as failed", func() {
+			reconcile()
+			Expect(getTransfer().Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseFailed))
+		})
+	})
+
+	When("the instance is a virtual machine", func() {
+		BeforeEach(func() {
+			environment.EnvironmentType = clv1alpha2.ClassVM
+			extraObjs = append(extraObjs, &cdiv1beta1.DataVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: source.Name + "-" + envName, Namespace: testSourceNs},
+				Spec: cdiv1beta1.DataVolumeSpec{PVC: &corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")},
+					},
+				}},
+			})
+		})
+
+		It("should clone the datavolume into the namespace of the recipient", func() {
+			reconcile()
+			itr := getTransfer()
+			Expect(itr.Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseCopying))
+			Expect(itr.Status.TargetInstanceRef).To(PointTo(Equal(clv1alpha2.GenericRef{Name: transfer.Name, Namespace: testTargetNs})))
+
+			var dv cdiv1beta1.DataVolume
+			Expect(cl.Get(ctx, targetKey(envName), &dv)).To(Succeed())
+			Expect(dv.Spec.Source.PVC).To(PointTo(Equal(cdiv1beta1.DataVolumeSourcePVC{Namespace: testSourceNs, Name: source.Name + "-" + envName})))
+			Expect(dv.Spec.PVC.Resources.Requests).To(HaveKeyWithValue(corev1.ResourceStorage, resource.MustParse("20Gi")))
+			Expect(dv.Labels).To(HaveKeyWithValue(forge.LabelInstanceTransferKey, transfer.Name))
+			Expect(metav1.GetControllerOf(&dv)).To(BeNil())
+		})
+
+		When("the clone completes", func() {
+			It("should create the target instance owning the datavolume", func() {
+				reconcile()
+
+				var dv cdiv1beta1.DataVolume
+				Expect(cl.Get(ctx, targetKey(envName), &dv)).To(Succeed())
+				dv.Status.Phase = cdiv1beta1.Succeeded
+				Expect(cl.Update(ctx, &dv)).To(Succeed())
+				reconcile()
+
+				Expect(getTransfer().Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseCompleted))
+
+				var instance clv1alpha2.Instance
+				Expect(cl.Get(ctx, client.ObjectKey{Namespace: testTargetNs, Name: transfer.Name}, &instance)).To(Succeed())
+				Expect(instance.Spec.Tenant.Name).To(Equal(testRecipientName))
+				Expect(instance.Spec.Running).To(BeFalse())
+				Expect(instance.Spec.PrettyName).To(Equal(source.Spec.PrettyName))
+
+				Expect(cl.Get(ctx, targetKey(envName), &dv)).To(Succeed())
+				Expect(metav1.IsControlledBy(&dv, &instance)).To(BeTrue())
+			})
+		})
+
+		When("the clone fails", func() {
+			It("should mark the transfer as failed and delete the copy", func() {
+				reconcile()
+
+				var dv cdiv1beta1.DataVolume
+				Expect(cl.Get(ctx, targetKey(envName), &dv)).To(Succeed())
+				dv.Status.Phase = cdiv1beta1.Failed
+				Expect(cl.Update(ctx, &dv)).To(Succeed())
+				reconcile()
+
+				Expect(getTransfer().Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseFailed))
+				Expect(kerrors.IsNotFound(cl.Get(ctx, targetKey(envName), &dv))).To(BeTrue())
+			})
+		})
+
+		When("the environment has persistent additional disks", func() {
+			const diskSuffix = envName + "-db"
+
+			BeforeEach(func() {
+				environment.AdditionalDisks = []clv1alpha2.AdditionalDisk{
+					{Name: "db", Size: resource.MustParse("10Gi"), Persistent: true},
+					{Name: "scratch", Size: resource.MustParse("5Gi")},
+				}
+				extraObjs = append(extraObjs, &cdiv1beta1.DataVolume{
+					ObjectMeta: metav1.ObjectMeta{Name: source.Name + "-" + diskSuffix, Namespace: testSourceNs},
+					Spec: cdiv1beta1.DataVolumeSpec{PVC: &corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("15Gi")},
+						},
+					}},
+				})
+			})
+
+			It("should clone the datavolumes of the disks and wait for all of them", func() {
+				reconcile()
+
+				var dv cdiv1beta1.DataVolume
+				Expect(cl.Get(ctx, targetKey(diskSuffix), &dv)).To(Succeed())
+				Expect(dv.Spec.Source.PVC).To(PointTo(Equal(cdiv1beta1.DataVolumeSourcePVC{Namespace: testSourceNs, Name: source.Name + "-" + diskSuffix})))
+				Expect(dv.Spec.PVC.Resources.Requests).To(HaveKeyWithValue(corev1.ResourceStorage, resource.MustParse("15Gi")))
+				Expect(kerrors.IsNotFound(cl.Get(ctx, targetKey(envName+"-scratch"), &dv))).To(BeTrue())
+
+				Expect(cl.Get(ctx, targetKey(envName), &dv)).To(Succeed())
+				dv.Status.Phase = cdiv1beta1.Succeeded
+				Expect(cl.Update(ctx, &dv)).To(Succeed())
+				reconcile()
+				Expect(getTransfer().Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseCopying))
+
+				Expect(cl.Get(ctx, targetKey(diskSuffix), &dv)).To(Succeed())
+				dv.Status.Phase = cdiv1beta1.Succeeded
+				Expect(cl.Update(ctx, &dv)).To(Succeed())
+				reconcile()
+				Expect(getTransfer().Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseCompleted))
+
+				var instance clv1alpha2.Instance
+				Expect(cl.Get(ctx, client.ObjectKey{Namespace: testTargetNs, Name: transfer.Name}, &instance)).To(Succeed())
+				Expect(cl.Get(ctx, targetKey(diskSuffix), &dv)).To(Succeed())
+				Expect(metav1.IsControlledBy(&dv, &instance)).To(BeTrue())
+			})
+		})
+	})
+
+	When("the instance is a container", func() {
+		BeforeEach(func() { extraObjs = append(extraObjs, sourcePVC()) })
+
+		It("should copy the volume through a mirror of the source one", func() {
+			reconcile()
+			Expect(getTransfer().Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseCopying))
+
+			var pvc corev1.PersistentVolumeClaim
+			Expect(cl.Get(ctx, client.ObjectKeyFromObject(sourcePVC()), &pvc)).To(Succeed())
+			Expect(pvc.Annotations).To(HaveKeyWithValue(forge.AuthorizationAnnotationKey, forge.InstanceTransferAuthorizationAnnotationValue(testRecipientName)))
+			Expect(pvc.Annotations).To(HaveKeyWithValue(forge.ReadOnlyMirrorAnnotationKey, "true"))
+
+			Expect(cl.Get(ctx, targetKey(envName), &pvc)).To(Succeed())
+			Expect(pvc.Spec.Resources.Requests).To(HaveKeyWithValue(corev1.ResourceStorage, resource.MustParse("8Gi")))
+
+			Expect(cl.Get(ctx, targetKey(envName+"-source"), &pvc)).To(Succeed())
+			Expect(pvc.Spec.AccessModes).To(ConsistOf(corev1.ReadOnlyMany))
+			Expect(pvc.Spec.StorageClassName).To(PointTo(Equal("mirror")))
+			Expect(pvc.Labels).To(HaveKeyWithValue(forge.LabelVolumeTypeKey, forge.VolumeTypeValueMirror))
+
+			var job batchv1.Job
+			Expect(cl.Get(ctx, targetKey(envName+"-transfer"), &job)).To(Succeed())
+		})
+
+		When("the copy completes", func() {
+			It("should create the target instance and revoke the mirror", func() {
+				reconcile()
+
+				var job batchv1.Job
+				Expect(cl.Get(ctx, targetKey(envName+"-transfer"), &job)).To(Succeed())
+				job.Status.Succeeded = 1
+				Expect(cl.Status().Update(ctx, &job)).To(Succeed())
+				reconcile()
+
+				Expect(getTransfer().Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseCompleted))
+
+				var pvc corev1.PersistentVolumeClaim
+				Expect(kerrors.IsNotFound(cl.Get(ctx, targetKey(envName+"-source"), &pvc))).To(BeTrue())
+				Expect(cl.Get(ctx, client.ObjectKeyFromObject(sourcePVC()), &pvc)).To(Succeed())
+				Expect(pvc.Annotations).NotTo(HaveKey(forge.AuthorizationAnnotationKey))
+				Expect(pvc.Annotations).NotTo(HaveKey(forge.ReadOnlyMirrorAnnotationKey))
+				Expect(pvc.Labels).NotTo(HaveKey(forge.LabelInstanceTransferKey))
+
+				var instance clv1alpha2.Instance
+				Expect(cl.Get(ctx, client.ObjectKey{Namespace: testTargetNs, Name: transfer.Name}, &instance)).To(Succeed())
+				Expect(cl.Get(ctx, targetKey(envName), &pvc)).To(Succeed())
+				Expect(metav1.IsControlledBy(&pvc, &instance)).To(BeTrue())
+			})
+		})
+
+		When("the source volume is already shared", func() {
+			BeforeEach(func() {
+				pvc := sourcePVC()
+				pvc.Annotations = map[string]string{forge.AuthorizationAnnotationKey: "something-else"}
+				extraObjs = []client.Object{pvc}
+			})
+
+			It("should mark the transfer as failed", func() {
+				reconcile()
+				Expect(getTransfer().Status.Phase).To(Equal(clv1alpha2.InstanceTransferPhaseFailed))
+
+				var pvc corev1.PersistentVolumeClaim
+				Expect(kerrors.IsNotFound(cl.Get(ctx, targetKey(envName), &pvc))).To(BeTrue())
+			})
+		})
+	})
+})
@@ -0,0 +1,55 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instancetransfer_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+)
+
+var (
+	scheme *runtime.Scheme
+	ctx    = context.Background()
+)
+
+const (
+	testOwnerName     = "owner"
+	testRecipientName = "recipient"
+	testWorkspace     = "workspace"
+	testSourceNs      = "tenant-owner"
+	testTargetNs      = "tenant-recipient"
+	testWhitelistKey  = "crownlabs.polito.it/operator-selector"
+	testWhitelistVal  = "test"
+)
+
+func TestInstanceTransfer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "InstanceTransfer Suite")
+}
+
+var _ = BeforeSuite(func() {
+	scheme = runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(clv1alpha2.AddToScheme(scheme)).To(Succeed())
+	Expect(cdiv1beta1.AddToScheme(scheme)).To(Succeed())
+})
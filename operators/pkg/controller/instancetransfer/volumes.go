@@ -0,0 +1,272 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instancetransfer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+)
+
+// enforceDataVolumeCopy enforces the DataVolumes cloning (through CDI) the ones of the given environment of the source Instance
+// (i.e., the root disk and the persistent additional disks) into the namespace of the recipient. Returns true once all the clones
+// have completed successfully.
+func (r *Reconciler) enforceDataVolumeCopy(
+	ctx context.Context,
+	transfer *clv1alpha2.InstanceTransfer,
+	source *clv1alpha2.Instance,
+	environment *clv1alpha2.Environment,
+	target metav1.Object,
+) (bool, error) {
+	completed, err := r.enforceDataVolumeClone(ctx, transfer, source, target, environment.Name,
+		func(sourceDV *cdiv1beta1.DataVolume) (cdiv1beta1.DataVolumeSpec, error) {
+			return forge.TransferDataVolumeSpec(environment, sourceDV)
+		})
+	if err != nil {
+		return false, err
+	}
+
+	for i := range environment.AdditionalDisks {
+		disk := &environment.AdditionalDisks[i]
+		if !disk.Persistent {
+			continue
+		}
+		done, err := r.enforceDataVolumeClone(ctx, transfer, source, target, environment.Name+forge.StringSeparator+disk.Name,
+			func(sourceDV *cdiv1beta1.DataVolume) (cdiv1beta1.DataVolumeSpec, error) {
+				return forge.TransferAdditionalDiskDataVolumeSpec(environment, disk, sourceDV), nil
+			})
+		if err != nil {
+			return false, err
+		}
+		completed = completed && done
+	}
+	return completed, nil
+}
+
+// enforceDataVolumeClone enforces the DataVolume with the given name suffix cloning the corresponding one of the source Instance,
+// with the spec forged by the given function. Returns true once the clone has completed successfully.
+func (r *Reconciler) enforceDataVolumeClone(
+	ctx context.Context,
+	transfer *clv1alpha2.InstanceTransfer,
+	source *clv1alpha2.Instance,
+	target metav1.Object,
+	suffix string,
+	forgeSpec func(sourceDV *cdiv1beta1.DataVolume) (cdiv1beta1.DataVolumeSpec, error),
+) (bool, error) {
+	sourceDV := cdiv1beta1.DataVolume{ObjectMeta: forge.ObjectMetaWithSuffix(source, suffix)}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(&sourceDV), &sourceDV); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, fmt.Errorf("%w: the volume %s does not exist", errTransferFailed, sourceDV.Name)
+		}
+		return false, fmt.Errorf("failed to get datavolume %s: %w", sourceDV.Name, err)
+	}
+
+	spec, err := forgeSpec(&sourceDV)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", errTransferFailed, err)
+	}
+
+	dv := cdiv1beta1.DataVolume{ObjectMeta: forge.ObjectMetaWithSuffix(target, suffix)}
+	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, &dv, func() error {
+		// The DataVolume specifications are forged only at creation time, as they cannot be changed later.
+		if dv.CreationTimestamp.IsZero() {
+			dv.Spec = spec
+		}
+		dv.SetLabels(forge.InstanceTransferLabels(dv.GetLabels(), transfer))
+		// The transfer is not the controller, so that the DataVolume can be adopted by the target instance.
+		return ctrlutil.SetOwnerReference(transfer, &dv, r.Scheme())
+	}); err != nil {
+		return false, fmt.Errorf("failed to enforce datavolume %s: %w", dv.Name, err)
+	}
+
+	switch dv.Status.Phase {
+	case cdiv1beta1.Succeeded:
+		return true, nil
+	case cdiv1beta1.Failed:
+		return false, fmt.Errorf("%w: the clone of the volume %s failed", errTransferFailed, sourceDV.Name)
+	default:
+		return false, nil
+	}
+}
+
+// enforcePVCCopy enforces the PVC receiving the copy of the one of the given environment of the source Instance, through a job
+// reading a (read-only) mirror of the source volume in the namespace of the recipient. Returns true once the copy has completed successfully.
+func (r *Reconciler) enforcePVCCopy(
+	ctx context.Context,
+	transfer *clv1alpha2.InstanceTransfer,
+	source *clv1alpha2.Instance,
+	environment *clv1alpha2.Environment,
+	target metav1.Object,
+) (bool, error) {
+	pvc := corev1.PersistentVolumeClaim{ObjectMeta: forge.ObjectMetaWithSuffix(target, environment.Name)}
+	mirror := corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: forge.TransferMirrorPVCName(pvc.Name), Namespace: pvc.Namespace}}
+	job := batchv1.Job{ObjectMeta: forge.ObjectMetaWithSuffix(&pvc, "transfer")}
+
+	if err := r.Get(ctx, client.ObjectKeyFromObject(&job), &job); client.IgnoreNotFound(err) != nil {
+		return false, fmt.Errorf("failed to get job %s: %w", job.Name, err)
+	}
+	switch {
+	case job.Status.Succeeded > 0:
+		// The mirror is released as soon as the copy completes
+		return true, client.IgnoreNotFound(r.Delete(ctx, &mirror))
+	case job.Status.Failed > forge.TransferJobMaxRetries:
+		return false, fmt.Errorf("%w: the copy of the volume of environment %s failed", errTransferFailed, environment.Name)
+	}
+
+	sourcePVC := corev1.PersistentVolumeClaim{ObjectMeta: forge.ObjectMetaWithSuffix(source, environment.Name)}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(&sourcePVC), &sourcePVC); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, fmt.Errorf("%w: the volume of environment %s does not exist", errTransferFailed, environment.Name)
+		}
+		return false, fmt.Errorf("failed to get pvc %s: %w", sourcePVC.Name, err)
+	}
+	if err := r.authorizeSourceMirror(ctx, transfer, &sourcePVC); err != nil {
+		return false, err
+	}
+
+	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, &pvc, func() error {
+		// PVC's spec is immutable, it has to be set at creation
+		if pvc.CreationTimestamp.IsZero() {
+			pvc.Spec = forge.TransferPVCSpec(environment, &sourcePVC)
+		}
+		pvc.SetLabels(forge.InstanceTransferLabels(pvc.GetLabels(), transfer))
+		// The transfer is not the controller, so that the PVC can be adopted by the target instance.
+		return ctrlutil.SetOwnerReference(transfer, &pvc, r.Scheme())
+	}); err != nil {
+		return false, fmt.Errorf("failed to enforce pvc %s: %w", pvc.Name, err)
+	}
+
+	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, &mirror, func() error {
+		if mirror.CreationTimestamp.IsZero() {
+			mirror.Spec = forge.TransferMirrorPVCSpec(&sourcePVC, r.MirrorPVCStorageClassName)
+		}
+		labels := forge.InstanceTransferLabels(mirror.GetLabels(), transfer)
+		labels[forge.LabelVolumeTypeKey] = forge.VolumeTypeValueMirror
+		mirror.SetLabels(labels)
+		return ctrl.SetControllerReference(transfer, &mirror, r.Scheme())
+	}); err != nil {
+		return false, fmt.Errorf("failed to enforce mirror pvc %s: %w", mirror.Name, err)
+	}
+
+	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, &job, func() error {
+		if job.CreationTimestamp.IsZero() {
+			job.Spec = forge.PVCTransferJobSpec(&mirror, &pvc)
+		}
+		job.SetLabels(forge.InstanceTransferLabels(job.GetLabels(), transfer))
+		return ctrl.SetControllerReference(transfer, &job, r.Scheme())
+	}); err != nil {
+		return false, fmt.Errorf("failed to enforce job %s: %w", job.Name, err)
+	}
+
+	return false, nil
+}
+
+// authorizeSourceMirror annotates the source PVC to allow its read-only mirror in the namespace of the recipient.
+func (r *Reconciler) authorizeSourceMirror(ctx context.Context, transfer *clv1alpha2.InstanceTransfer, pvc *corev1.PersistentVolumeClaim) error {
+	authorization := forge.InstanceTransferAuthorizationAnnotationValue(transfer.Spec.RecipientRef.Name)
+	if pvc.Labels[forge.LabelInstanceTransferKey] == transfer.Name && pvc.Annotations[forge.AuthorizationAnnotationKey] == authorization {
+		return nil
+	}
+	if current, ok := pvc.Annotations[forge.AuthorizationAnnotationKey]; ok && current != authorization {
+		return fmt.Errorf("%w: the volume %s is already shared", errTransferFailed, pvc.Name)
+	}
+
+	patch := client.MergeFrom(pvc.DeepCopy())
+	pvc.SetLabels(forge.InstanceTransferLabels(pvc.GetLabels(), transfer))
+	if pvc.Annotations == nil {
+		pvc.Annotations = make(map[string]string, 2)
+	}
+	pvc.Annotations[forge.AuthorizationAnnotationKey] = authorization
+	pvc.Annotations[forge.ReadOnlyMirrorAnnotationKey] = strconv.FormatBool(true)
+	if err := r.Patch(ctx, pvc, patch); err != nil {
+		return fmt.Errorf("failed to authorize the mirror of pvc %s: %w", pvc.Name, err)
+	}
+	return nil
+}
+
+// cleanup revokes the authorization to mirror the source volumes and deletes the mirrors. Unless the transfer succeeded,
+// the volumes copied so far are deleted as well, provided that they have not been adopted by any instance.
+func (r *Reconciler) cleanup(ctx context.Context, transfer *clv1alpha2.InstanceTransfer, succeeded bool) error {
+	if transfer.Status.TargetInstanceRef == nil {
+		// The copy has not been started
+		return nil
+	}
+	selector := client.MatchingLabels{forge.LabelInstanceTransferKey: transfer.Name}
+	var errs []error
+
+	var sources corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &sources, client.InNamespace(transfer.Spec.InstanceRef.Namespace), selector); err != nil {
+		return fmt.Errorf("failed to list the source pvcs: %w", err)
+	}
+	for i := range sources.Items {
+		pvc := &sources.Items[i]
+		patch := client.MergeFrom(pvc.DeepCopy())
+		delete(pvc.Labels, forge.LabelInstanceTransferKey)
+		delete(pvc.Annotations, forge.AuthorizationAnnotationKey)
+		delete(pvc.Annotations, forge.ReadOnlyMirrorAnnotationKey)
+		if err := r.Patch(ctx, pvc, patch); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf("failed to revoke the mirror of pvc %s: %w", pvc.Name, err))
+		}
+	}
+
+	removable := func(obj client.Object) bool {
+		if metav1.IsControlledBy(obj, transfer) {
+			return true
+		}
+		owned := slices.ContainsFunc(obj.GetOwnerReferences(), func(ref metav1.OwnerReference) bool { return ref.UID == transfer.UID })
+		return !succeeded && owned && metav1.GetControllerOf(obj) == nil
+	}
+
+	namespace := client.InNamespace(transfer.Status.TargetInstanceRef.Namespace)
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &pvcs, namespace, selector); err != nil {
+		return fmt.Errorf("failed to list the copied pvcs: %w", err)
+	}
+	for i := range pvcs.Items {
+		if removable(&pvcs.Items[i]) {
+			if err := r.Delete(ctx, &pvcs.Items[i]); client.IgnoreNotFound(err) != nil {
+				errs = append(errs, fmt.Errorf("failed to delete pvc %s: %w", pvcs.Items[i].Name, err))
+			}
+		}
+	}
+
+	var dvs cdiv1beta1.DataVolumeList
+	if err := r.List(ctx, &dvs, namespace, selector); err != nil {
+		return fmt.Errorf("failed to list the copied datavolumes: %w", err)
+	}
+	for i := range dvs.Items {
+		if removable(&dvs.Items[i]) {
+			if err := r.Delete(ctx, &dvs.Items[i]); client.IgnoreNotFound(err) != nil {
+				errs = append(errs, fmt.Errorf("failed to delete datavolume %s: %w", dvs.Items[i].Name, err))
+			}
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
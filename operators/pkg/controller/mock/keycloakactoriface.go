@@ -187,6 +187,21 @@ func (mr *MockKeycloakActorIfaceMockRecorder) IsInitialized() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsInitialized", reflect.TypeOf((*MockKeycloakActorIface)(nil).IsInitialized))
 }
 
+// ListUsers mocks base method.
+func (m *MockKeycloakActorIface) ListUsers(ctx context.Context) ([]*gocloak.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx)
+	ret0, _ := ret[0].([]*gocloak.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockKeycloakActorIfaceMockRecorder) ListUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockKeycloakActorIface)(nil).ListUsers), ctx)
+}
+
 // RemoveUserFromRole mocks base method.
 func (m *MockKeycloakActorIface) RemoveUserFromRoles(ctx context.Context, userID string, roles []*gocloak.Role) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,54 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pmp
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	mirrorVolumes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pmp_mirror_volumes",
+		Help: "The number of mirror PVs of each origin PVC, either active or orphaned (i.e., revoked but not yet removed)",
+	},
+		[]string{"origin_namespace", "origin_name", "state"},
+	)
+
+	mirrorRevocations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pmp_mirror_revocations_total",
+		Help: "The number of mirror PVs revoked, by reason",
+	},
+		[]string{"reason"},
+	)
+)
+
+func init() {
+	// Register custom metrics with the global prometheus registry
+	metrics.Registry.MustRegister(mirrorVolumes, mirrorRevocations)
+}
+
+// updateMirrorMetrics exposes the number of active and orphaned mirror PVs of the given origin PVC.
+func updateMirrorMetrics(origin types.NamespacedName, active, orphaned int) {
+	mirrorVolumes.WithLabelValues(origin.Namespace, origin.Name, "active").Set(float64(active))
+	mirrorVolumes.WithLabelValues(origin.Namespace, origin.Name, "orphaned").Set(float64(orphaned))
+}
+
+// deleteMirrorMetrics removes the metrics of an origin PVC no longer mirrored.
+func deleteMirrorMetrics(origin types.NamespacedName) {
+	mirrorVolumes.DeleteLabelValues(origin.Namespace, origin.Name, "active")
+	mirrorVolumes.DeleteLabelValues(origin.Namespace, origin.Name, "orphaned")
+}
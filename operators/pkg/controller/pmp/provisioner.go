@@ -18,7 +18,9 @@ package pmp
 import (
 	"context"
 	"errors"
+	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -35,6 +37,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/sig-storage-lib-external-provisioner/v13/controller"
 
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
 	ctrlcommon "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/common"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
@@ -65,6 +68,7 @@ type PvcMirrorProvisioner struct {
 	TargetLabel           ctrlcommon.KVLabel
 	MirrorStorageClass    string
 	MirrorProvisionerName string
+	BlockVolumes          bool // Whether the origin PVs in block mode can be mirrored.
 }
 
 // Provision is the Provisioner interface function called when a PVC has to be provisioned, and returns the PV to be created on the cluster.
@@ -132,6 +136,17 @@ func (p *PvcMirrorProvisioner) Provision(ctx context.Context, options controller
 		return nil, controller.ProvisioningFinished, status.Error(codes.InvalidArgument, "Unauthorized")
 	}
 
+	// Check the access control rules of the SharedVolume (if any)
+	access, err := p.sharedVolumeAccess(ctx, mirrorPVC, &originPVC)
+	if err != nil {
+		p.Logger.Error(err, "failed checking sharedvolume access control rules")
+		return nil, controller.ProvisioningFinished, err
+	}
+	if access == clv1alpha2.SharedVolumeAccessNone {
+		p.Logger.Error(errSlowRetry, "tenant not allowed by the sharedvolume access control rules, access denied")
+		return nil, controller.ProvisioningFinished, status.Error(codes.InvalidArgument, "Unauthorized")
+	}
+
 	// Check origin PVC's Phase
 	switch originPVC.Status.Phase {
 	case corev1.ClaimPending:
@@ -147,29 +162,44 @@ func (p *PvcMirrorProvisioner) Provision(ctx context.Context, options controller
 		return nil, controller.ProvisioningFinished, errPendingPVC
 	}
 
-	// Check origin PVC's AccessModes
+	// Check origin PVC's AccessModes: the volume shall be shareable among multiple nodes,
+	// while ReadOnlyMany volumes (or single node ones, if explicitly allowed) can be mirrored only for read-only access.
+	originAccessModes := originPVC.Status.AccessModes
+	if originPVC.Annotations[forge.ReadOnlyMirrorAnnotationKey] == strconv.FormatBool(true) {
+		originAccessModes = append(slices.Clone(originAccessModes), corev1.ReadOnlyMany)
+	}
+	if !mirrorAccessModesSupported(originAccessModes, mirrorPVC.Spec.AccessModes) {
+		p.Logger.Error(errStopProvision, "origin PVC is not accessible in the requested modes", "origin", originPVC.Status.AccessModes, "requested", mirrorPVC.Spec.AccessModes)
+		return nil, controller.ProvisioningFinished, &controller.IgnoredError{Reason: "PVC is not accessible in the requested modes"}
+	}
 	if !slices.Contains(originPVC.Status.AccessModes, corev1.ReadWriteMany) {
-		p.Logger.Error(errStopProvision, "origin PVC is not accessible in RWX mode")
-		return nil, controller.ProvisioningFinished, &controller.IgnoredError{Reason: "PVC is not RWX"}
+		access = clv1alpha2.SharedVolumeAccessReadOnly
 	}
 
-	// Check origin PV's CSI spec
 	var originPV corev1.PersistentVolume
 	if err := p.Client.Get(ctx, types.NamespacedName{Name: originPVC.Spec.VolumeName}, &originPV); err != nil {
 		// Surely it's not a NotFound error
 		p.Logger.Error(err, "could not retrieve origin PV")
 		return nil, controller.ProvisioningFinished, err
 	}
-	if originPV.Spec.CSI == nil {
-		p.Logger.Error(errStopProvision, "Cannot retrieve CSI spec from PV (CSI is missing)")
-		return nil, controller.ProvisioningFinished, &controller.IgnoredError{Reason: "PV's CSI spec is missing"}
-	}
 
 	// Compare actual and requested VolumeMode
-	if ptr.Deref(originPV.Spec.VolumeMode, corev1.PersistentVolumeFilesystem) != ptr.Deref(mirrorPVC.Spec.VolumeMode, corev1.PersistentVolumeFilesystem) {
+	volumeMode := ptr.Deref(originPV.Spec.VolumeMode, corev1.PersistentVolumeFilesystem)
+	if volumeMode != ptr.Deref(mirrorPVC.Spec.VolumeMode, corev1.PersistentVolumeFilesystem) {
 		p.Logger.Error(errStopProvision, "Requested PVC's VolumeMode differs from the actual in the origin PV")
 		return nil, controller.ProvisioningFinished, &controller.IgnoredError{Reason: "Requested PVC's VolumeMode differs from the actual in the origin PV"}
 	}
+	if volumeMode == corev1.PersistentVolumeBlock && !p.BlockVolumes {
+		p.Logger.Error(errStopProvision, "Mirroring of block volumes is disabled")
+		return nil, controller.ProvisioningFinished, &controller.IgnoredError{Reason: "Mirroring of block volumes is disabled"}
+	}
+
+	// Check origin PV's source, enforcing read-only access at the volume level, if required
+	source, err := mirrorVolumeSource(&originPV, access == clv1alpha2.SharedVolumeAccessReadOnly)
+	if err != nil {
+		p.Logger.Error(errStopProvision, "Cannot mirror the origin PV", "reason", err.Error())
+		return nil, controller.ProvisioningFinished, &controller.IgnoredError{Reason: err.Error()}
+	}
 
 	// Create mirror PV
 	pv := &corev1.PersistentVolume{
@@ -187,21 +217,120 @@ func (p *PvcMirrorProvisioner) Provision(ctx context.Context, options controller
 			Capacity: corev1.ResourceList{
 				corev1.ResourceStorage: forge.DefaultMirrorCapacity,
 			},
-			PersistentVolumeSource: corev1.PersistentVolumeSource{
-				CSI: originPV.Spec.CSI,
-			},
-			MountOptions:     originPV.Spec.MountOptions,
-			StorageClassName: options.StorageClass.Name,
-			VolumeMode:       originPV.Spec.VolumeMode,
+			PersistentVolumeSource: source,
+			MountOptions:           originPV.Spec.MountOptions,
+			StorageClassName:       options.StorageClass.Name,
+			VolumeMode:             originPV.Spec.VolumeMode,
+			NodeAffinity:           originPV.Spec.NodeAffinity,
 		},
 	}
 
 	return pv, controller.ProvisioningFinished, nil
 }
 
+// mirrorAccessModesSupported returns whether a volume accessible in the origin modes can be mirrored in the requested ones.
+// ReadWriteMany volumes can be mirrored in any mode (but ReadWriteOncePod), ReadOnlyMany volumes only in ReadOnlyMany mode,
+// while volumes limited to a single node cannot be mirrored, as the mirror could be attached to a different node
+// (unless the origin PVC is annotated to allow read-only mirrors, in which case the annotating controller is in charge
+// of keeping the origin volume detached while the mirror exists, e.g., the instance transfer prevents starting the instance).
+func mirrorAccessModesSupported(origin, requested []corev1.PersistentVolumeAccessMode) bool {
+	if slices.Contains(requested, corev1.ReadWriteOncePod) {
+		return false
+	}
+	if slices.Contains(origin, corev1.ReadWriteMany) {
+		return true
+	}
+	return slices.Contains(origin, corev1.ReadOnlyMany) && len(requested) > 0 &&
+		!slices.ContainsFunc(requested, func(mode corev1.PersistentVolumeAccessMode) bool { return mode != corev1.ReadOnlyMany })
+}
+
+// mirrorVolumeSource returns the source of the mirror PV, referring to the same volume of the origin one, after
+// checking that it can be safely shared according to its type. If readOnly is set, the volume is mounted read-only.
+func mirrorVolumeSource(originPV *corev1.PersistentVolume, readOnly bool) (corev1.PersistentVolumeSource, error) {
+	block := ptr.Deref(originPV.Spec.VolumeMode, corev1.PersistentVolumeFilesystem) == corev1.PersistentVolumeBlock
+
+	switch origin := originPV.Spec.PersistentVolumeSource; {
+	case origin.CSI != nil:
+		// The sharing of the volume (either filesystem or block) is delegated to the CSI driver
+		csi := origin.CSI.DeepCopy()
+		csi.ReadOnly = csi.ReadOnly || readOnly
+		return corev1.PersistentVolumeSource{CSI: csi}, nil
+
+	case origin.NFS != nil:
+		if block {
+			return corev1.PersistentVolumeSource{}, errors.New("NFS volumes do not support the block mode")
+		}
+		nfs := origin.NFS.DeepCopy()
+		nfs.ReadOnly = nfs.ReadOnly || readOnly
+		return corev1.PersistentVolumeSource{NFS: nfs}, nil
+
+	case origin.HostPath != nil:
+		// The path refers to the local filesystem, hence the mirror shall be bound to the same nodes
+		if originPV.Spec.NodeAffinity == nil || originPV.Spec.NodeAffinity.Required == nil {
+			return corev1.PersistentVolumeSource{}, errors.New("HostPath volumes can be mirrored only if bound to nodes through the node affinity")
+		}
+		if readOnly {
+			return corev1.PersistentVolumeSource{}, errors.New("read-only access cannot be enforced on HostPath volumes")
+		}
+		if block && ptr.Deref(origin.HostPath.Type, corev1.HostPathUnset) != corev1.HostPathBlockDev {
+			return corev1.PersistentVolumeSource{}, errors.New("HostPath volumes in block mode shall refer to a block device")
+		}
+		return corev1.PersistentVolumeSource{HostPath: origin.HostPath.DeepCopy()}, nil
+
+	default:
+		return corev1.PersistentVolumeSource{}, fmt.Errorf("unsupported volume source for PV %s", originPV.Name)
+	}
+}
+
+// sharedVolumeAccess returns the access granted to the tenant owning the namespace of the mirror PVC, according to
+// the access control rules of the SharedVolume owning the origin PVC. The template is retrieved from the instance
+// owning the mirror PVC, if any. PVCs not belonging to a SharedVolume (e.g., MyDrive) are granted read-write access.
+func (p *PvcMirrorProvisioner) sharedVolumeAccess(ctx context.Context, mirrorPVC, originPVC *corev1.PersistentVolumeClaim) (clv1alpha2.SharedVolumeAccessMode, error) {
+	owner := metav1.GetControllerOf(originPVC)
+	if owner == nil || owner.Kind != "SharedVolume" {
+		return clv1alpha2.SharedVolumeAccessReadWrite, nil
+	}
+
+	var shvol clv1alpha2.SharedVolume
+	if err := p.Client.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: originPVC.Namespace}, &shvol); err != nil {
+		if kerrors.IsNotFound(err) {
+			return clv1alpha2.SharedVolumeAccessNone, nil
+		}
+		return clv1alpha2.SharedVolumeAccessNone, err
+	}
+	if len(shvol.Spec.AccessControl) == 0 {
+		return clv1alpha2.SharedVolumeAccessReadWrite, nil
+	}
+
+	// The tenant is identified through the namespace, since the labels of the mirror PVC are not trusted
+	var ns corev1.Namespace
+	if err := p.Client.Get(ctx, types.NamespacedName{Name: mirrorPVC.Namespace}, &ns); err != nil {
+		return clv1alpha2.SharedVolumeAccessNone, err
+	}
+	var tenant clv1alpha2.Tenant
+	if err := p.Client.Get(ctx, types.NamespacedName{Name: ns.Labels[forge.LabelNameKey]}, &tenant); err != nil {
+		if kerrors.IsNotFound(err) {
+			return clv1alpha2.SharedVolumeAccessNone, nil
+		}
+		return clv1alpha2.SharedVolumeAccessNone, err
+	}
+
+	var template *clv1alpha2.GenericRef
+	if instanceOwner := metav1.GetControllerOf(mirrorPVC); instanceOwner != nil && instanceOwner.Kind == "Instance" {
+		var instance clv1alpha2.Instance
+		if err := p.Client.Get(ctx, types.NamespacedName{Name: instanceOwner.Name, Namespace: mirrorPVC.Namespace}, &instance); client.IgnoreNotFound(err) != nil {
+			return clv1alpha2.SharedVolumeAccessNone, err
+		} else if err == nil && instance.UID == instanceOwner.UID {
+			template = &instance.Spec.Template
+		}
+	}
+
+	return forge.SharedVolumeAccess(&shvol, &tenant, template), nil
+}
+
 // Delete is the Provisioner interface function called when a PVC has to be deleted.
 func (p *PvcMirrorProvisioner) Delete(_ context.Context, _ *corev1.PersistentVolume) error {
-	// Nothing to be done here, since there is no "backed" volume
+	// Nothing to be done here, since there is no "backed" volume (mirrors are revoked by the MirrorReconciler)
 	return nil
 }
 
@@ -244,5 +373,5 @@ func (p *PvcMirrorProvisioner) ShouldDelete(context.Context, *corev1.PersistentV
 
 // SupportsBlock is the BlockProvisioner interface function that returns whether this provisioner supports block volume provisioning.
 func (p *PvcMirrorProvisioner) SupportsBlock(context.Context) bool {
-	return true
+	return p.BlockVolumes
 }
@@ -96,6 +96,8 @@ var _ = Describe("The PVC Mirror Provisioner methods", func() {
 			pvcAccessMode   corev1.PersistentVolumeAccessMode
 			mirrDataSrcRef  *corev1.TypedObjectReference
 			mirrVolumeMode  corev1.PersistentVolumeMode
+			mirrAccessMode  corev1.PersistentVolumeAccessMode
+			origAffinity    *corev1.VolumeNodeAffinity
 
 			isPVCOrigCreated bool
 			isPVOrigCreated  bool
@@ -187,6 +189,8 @@ var _ = Describe("The PVC Mirror Provisioner methods", func() {
 			isPVOrigCreated = false
 			origVolumeMode = corev1.PersistentVolumeFilesystem
 			mirrVolumeMode = corev1.PersistentVolumeFilesystem
+			mirrAccessMode = corev1.ReadWriteMany
+			origAffinity = nil
 		})
 
 		JustBeforeEach(func() {
@@ -208,6 +212,7 @@ var _ = Describe("The PVC Mirror Provisioner methods", func() {
 					PersistentVolumeSource:        pvSource,
 					StorageClassName:              scName,
 					VolumeMode:                    &origVolumeMode,
+					NodeAffinity:                  origAffinity,
 				},
 			}
 			pvcOrig = corev1.PersistentVolumeClaim{
@@ -232,7 +237,7 @@ var _ = Describe("The PVC Mirror Provisioner methods", func() {
 				},
 				Spec: corev1.PersistentVolumeClaimSpec{
 					AccessModes: []corev1.PersistentVolumeAccessMode{
-						corev1.ReadWriteMany,
+						mirrAccessMode,
 					},
 					DataSourceRef:    mirrDataSrcRef,
 					StorageClassName: &scName,
@@ -337,10 +342,60 @@ var _ = Describe("The PVC Mirror Provisioner methods", func() {
 											Expect(pvMirr).ToNot(BeNil())
 											Expect(pvMirr.Spec.CSI).ToNot(BeNil())
 											Expect(pvMirr.Spec.CSI.VolumeHandle).To(Equal("/nfs/path"))
+											Expect(pvMirr.Spec.CSI.ReadOnly).To(BeFalse())
+										})
+
+										When("the volumes are in block mode", func() {
+											BeforeEach(func() {
+												origVolumeMode = corev1.PersistentVolumeBlock
+												mirrVolumeMode = corev1.PersistentVolumeBlock
+											})
+
+											It("should successfully provision the mirror PV", func() {
+												ExpectNoError()
+												Expect(pvMirr).ToNot(BeNil())
+												Expect(pvMirr.Spec.CSI).ToNot(BeNil())
+												Expect(*pvMirr.Spec.VolumeMode).To(Equal(corev1.PersistentVolumeBlock))
+											})
+
+											When("the mirroring of block volumes is disabled", func() {
+												BeforeEach(func() {
+													pmprov.BlockVolumes = false
+												})
+
+												AfterEach(func() {
+													pmprov.BlockVolumes = true
+												})
+
+												It("should return an ignored error", func() {
+													ExpectIgnoredError()
+												})
+											})
 										})
 									})
 
-									When("the origin PV does not have CSI spec", func() {
+									When("the origin PV has NFS spec", func() {
+										BeforeEach(func() {
+											pvSource = corev1.PersistentVolumeSource{
+												NFS: &corev1.NFSVolumeSource{
+													Server: "my-nfs-cluster",
+													Path:   "/nfs/path",
+												},
+											}
+										})
+
+										It("should successfully provision the mirror PV", func() {
+											ExpectNoError()
+											Expect(pvMirr).ToNot(BeNil())
+											Expect(pvMirr.Spec.CSI).To(BeNil())
+											Expect(pvMirr.Spec.NFS).ToNot(BeNil())
+											Expect(pvMirr.Spec.NFS.Server).To(Equal("my-nfs-cluster"))
+											Expect(pvMirr.Spec.NFS.Path).To(Equal("/nfs/path"))
+											Expect(pvMirr.Spec.NFS.ReadOnly).To(BeFalse())
+										})
+									})
+
+									When("the origin PV is a HostPath not bound to any node", func() {
 										BeforeEach(func() {
 											pvSource = corev1.PersistentVolumeSource{
 												HostPath: &corev1.HostPathVolumeSource{
@@ -353,9 +408,94 @@ var _ = Describe("The PVC Mirror Provisioner methods", func() {
 											ExpectIgnoredError()
 										})
 									})
+
+									When("the origin PV is a HostPath bound to a node", func() {
+										BeforeEach(func() {
+											pvSource = corev1.PersistentVolumeSource{
+												HostPath: &corev1.HostPathVolumeSource{
+													Path: "/tmp/pv",
+												},
+											}
+											origAffinity = &corev1.VolumeNodeAffinity{
+												Required: &corev1.NodeSelector{
+													NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+														MatchExpressions: []corev1.NodeSelectorRequirement{{
+															Key:      corev1.LabelHostname,
+															Operator: corev1.NodeSelectorOpIn,
+															Values:   []string{"node-1"},
+														}},
+													}},
+												},
+											}
+										})
+
+										It("should provision the mirror PV bound to the same node", func() {
+											ExpectNoError()
+											Expect(pvMirr).ToNot(BeNil())
+											Expect(pvMirr.Spec.HostPath).ToNot(BeNil())
+											Expect(pvMirr.Spec.HostPath.Path).To(Equal("/tmp/pv"))
+											Expect(pvMirr.Spec.NodeAffinity).To(Equal(origAffinity))
+										})
+									})
+
+									When("the origin PV has an unsupported source", func() {
+										BeforeEach(func() {
+											pvSource = corev1.PersistentVolumeSource{
+												Local: &corev1.LocalVolumeSource{
+													Path: "/mnt/disks/pv",
+												},
+											}
+											origAffinity = &corev1.VolumeNodeAffinity{
+												Required: &corev1.NodeSelector{
+													NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+														MatchExpressions: []corev1.NodeSelectorRequirement{{
+															Key:      corev1.LabelHostname,
+															Operator: corev1.NodeSelectorOpIn,
+															Values:   []string{"node-1"},
+														}},
+													}},
+												},
+											}
+										})
+
+										It("should return an ignored error", func() {
+											ExpectIgnoredError()
+										})
+									})
 								})
 
-								When("the origin PVC is not RWX", func() {
+								When("the origin PVC is ROX", func() {
+									BeforeEach(func() {
+										pvcAccessMode = corev1.ReadOnlyMany
+										pvSource = corev1.PersistentVolumeSource{
+											NFS: &corev1.NFSVolumeSource{
+												Server: "my-nfs-cluster",
+												Path:   "/nfs/path",
+											},
+										}
+									})
+
+									When("the mirror PVC is requested in ROX mode", func() {
+										BeforeEach(func() {
+											mirrAccessMode = corev1.ReadOnlyMany
+										})
+
+										It("should provision a read-only mirror PV", func() {
+											ExpectNoError()
+											Expect(pvMirr).ToNot(BeNil())
+											Expect(pvMirr.Spec.NFS).ToNot(BeNil())
+											Expect(pvMirr.Spec.NFS.ReadOnly).To(BeTrue())
+										})
+									})
+
+									When("the mirror PVC is requested in RWX mode", func() {
+										It("should return an ignored error", func() {
+											ExpectIgnoredError()
+										})
+									})
+								})
+
+								When("the origin PVC is RWO", func() {
 									BeforeEach(func() {
 										pvcAccessMode = corev1.ReadWriteOnce
 									})
@@ -363,6 +503,37 @@ var _ = Describe("The PVC Mirror Provisioner methods", func() {
 									It("should return an ignored error", func() {
 										ExpectIgnoredError()
 									})
+
+									When("the origin PVC allows read-only mirrors", func() {
+										BeforeEach(func() {
+											pvcAnnotations[forge.ReadOnlyMirrorAnnotationKey] = "true"
+											pvSource = corev1.PersistentVolumeSource{
+												CSI: &corev1.CSIPersistentVolumeSource{
+													Driver:       "rbd.csi.ceph.com",
+													VolumeHandle: "volume-handle",
+												},
+											}
+										})
+
+										When("the mirror PVC is requested in ROX mode", func() {
+											BeforeEach(func() {
+												mirrAccessMode = corev1.ReadOnlyMany
+											})
+
+											It("should provision a read-only mirror PV", func() {
+												ExpectNoError()
+												Expect(pvMirr).ToNot(BeNil())
+												Expect(pvMirr.Spec.CSI).ToNot(BeNil())
+												Expect(pvMirr.Spec.CSI.ReadOnly).To(BeTrue())
+											})
+										})
+
+										When("the mirror PVC is requested in RWX mode", func() {
+											It("should return an ignored error", func() {
+												ExpectIgnoredError()
+											})
+										})
+									})
 								})
 							})
 
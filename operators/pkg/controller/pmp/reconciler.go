@@ -0,0 +1,197 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pmp
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ctrlcommon "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/common"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
+)
+
+const (
+	// RevocationOriginDeleted -> the mirror is revoked since the origin PVC has been deleted.
+	RevocationOriginDeleted = "origin-deleted"
+	// RevocationOriginReplaced -> the mirror is revoked since the origin PVC has been recreated, bound to a different PV.
+	RevocationOriginReplaced = "origin-replaced"
+	// RevocationUnauthorized -> the mirror is revoked since the namespace no longer matches the authorization selector of the origin PVC.
+	RevocationUnauthorized = "unauthorized"
+	// RevocationNamespaceDeleted -> the mirror is revoked since the namespace of the mirror PVC has been deleted.
+	RevocationNamespaceDeleted = "namespace-deleted"
+)
+
+// MirrorReconciler tracks the lifecycle of the mirror PVs created by the PvcMirrorProvisioner, revoking them (i.e., deleting
+// both the mirror PVC and PV) when the origin PVC is deleted, or the namespace of the mirror is no longer authorized to access it.
+// The requests refer to the origin PVCs, as the mirror PVs are identified through the MirroredPvcNameLabel and MirroredPvcNamespaceLabel labels.
+type MirrorReconciler struct {
+	client.Client
+	TargetLabel ctrlcommon.KVLabel
+}
+
+// SetupWithManager registers a new controller tracking the mirror PVs.
+func (r *MirrorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("pvc-mirror").
+		For(&corev1.PersistentVolumeClaim{}).
+		Watches(&corev1.PersistentVolume{},
+			handler.EnqueueRequestsFromMapFunc(mirrorToOrigin)).
+		Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceToOrigins)).
+		WithLogConstructor(utils.LogConstructor(mgr.GetLogger(), "PVCMirror")).
+		Complete(r)
+}
+
+// Reconcile revokes the mirror PVs of the given origin PVC which are no longer legitimate, and updates the corresponding metrics.
+func (r *MirrorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx, "origin", req.NamespacedName)
+
+	var mirrors corev1.PersistentVolumeList
+	if err := r.List(ctx, &mirrors, client.MatchingLabels{MirroredPvcNameLabel: req.Name, MirroredPvcNamespaceLabel: req.Namespace}); err != nil {
+		log.Error(err, "failed listing mirror PVs")
+		return ctrl.Result{}, err
+	}
+	if len(mirrors.Items) == 0 {
+		deleteMirrorMetrics(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	var origin *corev1.PersistentVolumeClaim
+	var pvc corev1.PersistentVolumeClaim
+	if err := r.Get(ctx, req.NamespacedName, &pvc); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "failed retrieving origin PVC")
+		return ctrl.Result{}, err
+	} else if err == nil && pvc.DeletionTimestamp.IsZero() {
+		origin = &pvc
+	}
+
+	active, orphaned := 0, 0
+	for i := range mirrors.Items {
+		mirror := &mirrors.Items[i]
+		if mirror.Spec.ClaimRef == nil {
+			// The mirror PV has not been bound yet
+			continue
+		}
+		if !mirror.DeletionTimestamp.IsZero() {
+			orphaned++
+			continue
+		}
+
+		if proceed, err := utils.CheckNamespaceTargetLabel(ctx, r.Client, mirror.Spec.ClaimRef.Namespace, r.TargetLabel); err == nil && !proceed {
+			// The mirror is not responsibility of this controller
+			continue
+		}
+
+		reason, err := r.revocationReason(ctx, origin, mirror)
+		if err != nil {
+			log.Error(err, "failed checking mirror PV", "pv", mirror.Name)
+			return ctrl.Result{}, err
+		}
+		if reason == "" {
+			active++
+			continue
+		}
+
+		if err := r.revokeMirror(ctx, mirror); err != nil {
+			log.Error(err, "failed revoking mirror PV", "pv", mirror.Name, "reason", reason)
+			return ctrl.Result{}, err
+		}
+		log.Info("mirror PV revoked", "pv", mirror.Name, "claim", mirror.Spec.ClaimRef.Namespace+"/"+mirror.Spec.ClaimRef.Name, "reason", reason)
+		mirrorRevocations.WithLabelValues(reason).Inc()
+		orphaned++
+	}
+
+	updateMirrorMetrics(req.NamespacedName, active, orphaned)
+	return ctrl.Result{}, nil
+}
+
+// revocationReason returns the reason why the given mirror PV shall be revoked, or an empty string if it is still legitimate.
+func (r *MirrorReconciler) revocationReason(ctx context.Context, origin *corev1.PersistentVolumeClaim, mirror *corev1.PersistentVolume) (string, error) {
+	if origin == nil {
+		return RevocationOriginDeleted, nil
+	}
+	if origin.Spec.VolumeName != mirror.Labels[MirroredPvLabel] {
+		return RevocationOriginReplaced, nil
+	}
+
+	selector, present := origin.Annotations[forge.AuthorizationAnnotationKey]
+	if !present {
+		return RevocationUnauthorized, nil
+	}
+	authorized, err := utils.CheckNamespaceWithSelector(ctx, r.Client, mirror.Spec.ClaimRef.Namespace, selector)
+	switch {
+	case kerrors.IsNotFound(err):
+		return RevocationNamespaceDeleted, nil
+	case err != nil:
+		return "", err
+	case !authorized:
+		return RevocationUnauthorized, nil
+	default:
+		return "", nil
+	}
+}
+
+// revokeMirror deletes the mirror PVC bound to the given PV, as well as the PV itself. The actual removal
+// is delayed by the protection finalizers, until the volume is no longer in use by any pod.
+func (r *MirrorReconciler) revokeMirror(ctx context.Context, mirror *corev1.PersistentVolume) error {
+	claim := corev1.PersistentVolumeClaim{}
+	claim.SetName(mirror.Spec.ClaimRef.Name)
+	claim.SetNamespace(mirror.Spec.ClaimRef.Namespace)
+
+	var opts []client.DeleteOption
+	if mirror.Spec.ClaimRef.UID != "" {
+		opts = append(opts, client.Preconditions{UID: &mirror.Spec.ClaimRef.UID})
+	}
+	if err := r.Delete(ctx, &claim, opts...); client.IgnoreNotFound(err) != nil && !kerrors.IsConflict(err) {
+		return err
+	}
+
+	return client.IgnoreNotFound(r.Delete(ctx, mirror))
+}
+
+// mirrorToOrigin maps a mirror PV to the corresponding origin PVC.
+func mirrorToOrigin(_ context.Context, pv client.Object) []reconcile.Request {
+	name, namespace := pv.GetLabels()[MirroredPvcNameLabel], pv.GetLabels()[MirroredPvcNamespaceLabel]
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
+}
+
+// namespaceToOrigins maps a namespace to the origin PVCs of the mirror PVs bound to the PVCs therein,
+// since the changes to its labels may affect the authorization to access them.
+func (r *MirrorReconciler) namespaceToOrigins(ctx context.Context, ns client.Object) []reconcile.Request {
+	var mirrors corev1.PersistentVolumeList
+	if err := r.List(ctx, &mirrors, client.HasLabels{MirroredPvLabel}); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed listing mirror PVs", "namespace", ns.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range mirrors.Items {
+		if claim := mirrors.Items[i].Spec.ClaimRef; claim != nil && claim.Namespace == ns.GetName() {
+			requests = append(requests, mirrorToOrigin(ctx, &mirrors.Items[i])...)
+		}
+	}
+	return requests
+}
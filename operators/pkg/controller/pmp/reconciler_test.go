@@ -0,0 +1,175 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pmp_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ctrlcommon "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/common"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/pmp"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+)
+
+var _ = Describe("The PVC mirror reconciler", func() {
+	const (
+		targetLabelKey = "crownlabs.polito.it/operator-selector"
+		targetLabelVal = "test"
+		originNsName   = "origin-ns"
+		targetNsName   = "target-ns"
+		pvcOrigName    = "pvc-origin"
+		pvOrigName     = "pv-origin"
+		pvcMirrName    = "pvc-mirror"
+		pvMirrName     = "pv-mirror"
+	)
+
+	var (
+		reconciler pmp.MirrorReconciler
+		k8sClient  client.Client
+		objects    []client.Object
+
+		pvcOrig  *corev1.PersistentVolumeClaim
+		pvcMirr  *corev1.PersistentVolumeClaim
+		pvMirr   *corev1.PersistentVolume
+		nsTarget *corev1.Namespace
+
+		reconcileErr error
+	)
+
+	Exists := func(obj client.Object) bool {
+		err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if kerrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	BeforeEach(func() {
+		nsTarget = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   targetNsName,
+			Labels: map[string]string{targetLabelKey: targetLabelVal, "crownlabs.polito.it/tenant": "s123456"},
+		}}
+		pvcOrig = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        pvcOrigName,
+				Namespace:   originNsName,
+				Annotations: map[string]string{forge.AuthorizationAnnotationKey: "crownlabs.polito.it/tenant"},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{VolumeName: pvOrigName},
+		}
+		pvcMirr = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: pvcMirrName, Namespace: targetNsName, UID: "mirror-uid"},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: pvMirrName},
+		}
+		pvMirr = &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: pvMirrName,
+				Labels: map[string]string{
+					pmp.MirroredPvLabel:           pvOrigName,
+					pmp.MirroredPvcNameLabel:      pvcOrigName,
+					pmp.MirroredPvcNamespaceLabel: originNsName,
+				},
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				ClaimRef: &corev1.ObjectReference{Name: pvcMirrName, Namespace: targetNsName, UID: "mirror-uid"},
+			},
+		}
+		objects = []client.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: originNsName}},
+			nsTarget, pvcOrig, pvcMirr, pvMirr,
+		}
+	})
+
+	JustBeforeEach(func() {
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build()
+		reconciler = pmp.MirrorReconciler{
+			Client:      k8sClient,
+			TargetLabel: ctrlcommon.NewLabel(targetLabelKey, targetLabelVal),
+		}
+		_, reconcileErr = reconciler.Reconcile(ctx, ctrl.Request{
+			NamespacedName: types.NamespacedName{Name: pvcOrigName, Namespace: originNsName},
+		})
+	})
+
+	When("the mirror is still authorized", func() {
+		It("Should keep the mirror", func() {
+			Expect(reconcileErr).ToNot(HaveOccurred())
+			Expect(Exists(pvcMirr)).To(BeTrue())
+			Expect(Exists(pvMirr)).To(BeTrue())
+		})
+	})
+
+	When("the origin PVC has been deleted", func() {
+		BeforeEach(func() { objects = []client.Object{objects[0], nsTarget, pvcMirr, pvMirr} })
+
+		It("Should revoke the mirror", func() {
+			Expect(reconcileErr).ToNot(HaveOccurred())
+			Expect(Exists(pvcMirr)).To(BeFalse())
+			Expect(Exists(pvMirr)).To(BeFalse())
+		})
+	})
+
+	When("the origin PVC has been recreated on a different PV", func() {
+		BeforeEach(func() { pvcOrig.Spec.VolumeName = "another-pv" })
+
+		It("Should revoke the mirror", func() {
+			Expect(reconcileErr).ToNot(HaveOccurred())
+			Expect(Exists(pvcMirr)).To(BeFalse())
+			Expect(Exists(pvMirr)).To(BeFalse())
+		})
+	})
+
+	When("the authorization annotation has been removed", func() {
+		BeforeEach(func() { pvcOrig.Annotations = nil })
+
+		It("Should revoke the mirror", func() {
+			Expect(reconcileErr).ToNot(HaveOccurred())
+			Expect(Exists(pvcMirr)).To(BeFalse())
+			Expect(Exists(pvMirr)).To(BeFalse())
+		})
+	})
+
+	When("the namespace of the mirror no longer matches the selector", func() {
+		BeforeEach(func() { delete(nsTarget.Labels, "crownlabs.polito.it/tenant") })
+
+		It("Should revoke the mirror", func() {
+			Expect(reconcileErr).ToNot(HaveOccurred())
+			Expect(Exists(pvcMirr)).To(BeFalse())
+			Expect(Exists(pvMirr)).To(BeFalse())
+		})
+	})
+
+	When("the namespace of the mirror is not responsibility of the controller", func() {
+		BeforeEach(func() {
+			pvcOrig.Annotations = nil
+			nsTarget.Labels[targetLabelKey] = "other"
+		})
+
+		It("Should keep the mirror", func() {
+			Expect(reconcileErr).ToNot(HaveOccurred())
+			Expect(Exists(pvcMirr)).To(BeTrue())
+			Expect(Exists(pvMirr)).To(BeTrue())
+		})
+	})
+})
@@ -73,6 +73,7 @@ var _ = BeforeSuite(func() {
 		TargetLabel:           ctrlcommon.NewLabel("crownlabs.polito.it/operator-selector", "local"),
 		MirrorStorageClass:    mirrorStorageClassName,
 		MirrorProvisionerName: mirrorProvisionerName,
+		BlockVolumes:          true,
 	}
 })
 
@@ -20,6 +20,48 @@ const (
 	// EvPVCSmallerMsg -> the event message corresponding to an invalid resizing.
 	EvPVCSmallerMsg = "Size cannot be less than previous value"
 
+	// EvPVCResizing -> the event key corresponding to the expansion of the PVC.
+	EvPVCResizing = "Resizing"
+	// EvPVCResizingMsg -> the event message corresponding to the expansion of the PVC.
+	EvPVCResizingMsg = "Expanding volume from %s to %s"
+
+	// EvUsageCollectionFailed -> the event key corresponding to a failed usage measurement.
+	EvUsageCollectionFailed = "UsageCollectionFailed"
+	// EvUsageCollectionFailedMsg -> the event message corresponding to a failed usage measurement.
+	EvUsageCollectionFailedMsg = "Unable to measure the volume usage: %v"
+
+	// EvContentSynced -> the event key corresponding to the synchronization of the content from the source.
+	EvContentSynced = "ContentSynced"
+	// EvContentSyncedMsg -> the event message corresponding to the synchronization of the content from the source.
+	EvContentSyncedMsg = "Content synchronized at revision %q"
+
+	// EvContentSyncFailed -> the event key corresponding to a failed synchronization of the content from the source.
+	EvContentSyncFailed = "ContentSyncFailed"
+	// EvContentSyncFailedMsg -> the event message corresponding to a failed synchronization of the content from the source.
+	EvContentSyncFailedMsg = "Unable to synchronize the content from the source: %v"
+
+	// EvRestoreFailed -> the event key corresponding to a failed restore from a snapshot.
+	EvRestoreFailed = "RestoreFailed"
+	// EvRestoreFailedMsg -> the event message corresponding to a failed restore from a snapshot.
+	EvRestoreFailedMsg = "Unable to restore from snapshot %q: %s"
+
+	// EvSnapshotScheduled -> the event key corresponding to the creation of a scheduled snapshot.
+	EvSnapshotScheduled = "SnapshotScheduled"
+	// EvSnapshotScheduledMsg -> the event message corresponding to the creation of a scheduled snapshot.
+	EvSnapshotScheduledMsg = "Created scheduled snapshot %q"
+
+	// EvSnapshotPruned -> the event key corresponding to the deletion of a scheduled snapshot exceeding the retention, or failed.
+	EvSnapshotPruned = "SnapshotPruned"
+	// EvSnapshotPrunedMsg -> the event message corresponding to the deletion of a scheduled snapshot exceeding the retention.
+	EvSnapshotPrunedMsg = "Deleted scheduled snapshot %q exceeding the retention"
+	// EvFailedSnapshotPrunedMsg -> the event message corresponding to the deletion of a failed scheduled snapshot.
+	EvFailedSnapshotPrunedMsg = "Deleted failed scheduled snapshot %q"
+
+	// EvSnapshotFailed -> the event key corresponding to a failed snapshot.
+	EvSnapshotFailed = "SnapshotFailed"
+	// EvSnapshotFailedMsg -> the event message corresponding to a failed snapshot.
+	EvSnapshotFailedMsg = "Unable to snapshot the volume: %s"
+
 	// EvPVCResQuotaExceeded -> the event key corresponding to exceeded PVC quota.
 	EvPVCResQuotaExceeded = "ResourceQuotaExceeded"
 	// EvPVCResQuotaExceededMsg -> the event message corresponding to exceeded PVC quota.
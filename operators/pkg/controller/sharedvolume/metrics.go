@@ -0,0 +1,58 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharedvolume
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+)
+
+var (
+	shVolUsedBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sharedvolume_used_bytes",
+		Help: "The space used on the shared volume, as measured by the last usage collection",
+	},
+		[]string{"namespace", "name"},
+	)
+
+	shVolAvailableBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sharedvolume_available_bytes",
+		Help: "The space available on the shared volume, as measured by the last usage collection",
+	},
+		[]string{"namespace", "name"},
+	)
+)
+
+func init() {
+	// Register custom metrics with the global prometheus registry
+	metrics.Registry.MustRegister(shVolUsedBytes, shVolAvailableBytes)
+}
+
+// updateUsageMetrics exposes the usage reported in the status of the shared volume.
+func updateUsageMetrics(shvol *clv1alpha2.SharedVolume) {
+	if shvol.Status.Usage == nil {
+		return
+	}
+	shVolUsedBytes.WithLabelValues(shvol.Namespace, shvol.Name).Set(shvol.Status.Usage.Used.AsApproximateFloat64())
+	shVolAvailableBytes.WithLabelValues(shvol.Namespace, shvol.Name).Set(shvol.Status.Usage.Available.AsApproximateFloat64())
+}
+
+// deleteUsageMetrics removes the usage metrics of a deleted shared volume.
+func deleteUsageMetrics(shvol *clv1alpha2.SharedVolume) {
+	shVolUsedBytes.DeleteLabelValues(shvol.Namespace, shvol.Name)
+	shVolAvailableBytes.DeleteLabelValues(shvol.Namespace, shvol.Name)
+}
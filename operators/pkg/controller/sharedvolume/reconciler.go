@@ -19,11 +19,15 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -42,7 +46,8 @@ import (
 )
 
 const (
-	phaseFieldIndex = "phase"
+	phaseFieldIndex       = "phase"
+	restoreFromFieldIndex = "restoreFrom"
 )
 
 // Reconciler reconciles a SharedVolume object.
@@ -52,6 +57,15 @@ type Reconciler struct {
 	EventsRecorder  record.EventRecorder
 	PVCStorageClass string
 
+	// How often the usage of the ready SharedVolumes is measured (disabled if zero).
+	UsageReportInterval time.Duration
+
+	// Whether the SharedVolumes can be snapshotted and restored, which requires the CSI snapshot CRDs.
+	EnableSnapshots bool
+
+	// The options of the content tools, used to populate the SharedVolumes from their source.
+	ContentToolsOpts forge.ContainerEnvOpts
+
 	// This function, if configured, is deferred at the beginning of the Reconcile.
 	// Specifically, it is meant to be set to GinkgoRecover during the tests,
 	// in order to lead to a controlled failure in case the Reconcile panics.
@@ -73,12 +87,27 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, concurrency int) error {
 		return err
 	}
 
+	// Register index to filter by the snapshot to restore from
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&clv1alpha2.SharedVolume{},
+		restoreFromFieldIndex,
+		func(obj client.Object) []string {
+			shvol := obj.(*clv1alpha2.SharedVolume)
+			return []string{shvol.Spec.RestoreFrom}
+		},
+	); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&clv1alpha2.SharedVolume{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
 		Owns(&batchv1.Job{}).
 		Watches(&clv1alpha2.Template{},
 			handler.EnqueueRequestsFromMapFunc(r.getDeletingSharedVolumes)).
+		Watches(&clv1alpha2.SharedVolumeSnapshot{},
+			handler.EnqueueRequestsFromMapFunc(r.getRestoringSharedVolumes)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: concurrency,
 		}).
@@ -165,6 +194,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 	// Change the Phase of the SharedVolume, so that if something happens it goes into Error.
 	shvolume.Status.Phase = clv1alpha2.SharedVolumePhaseError
 
+	// Retrieve the snapshot to restore the volume from, as long as it has not been provisioned yet.
+	var dataSource *corev1.TypedLocalObjectReference
+	if shvolume.Spec.RestoreFrom != "" && shvolume.Status.PVName == "" {
+		if dataSource, err = r.restoreDataSource(ctx, log, &shvolume); err != nil || dataSource == nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Create or Update the PVC, reconciling it with the SharedVolume spec.
 	pvc := corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
 		Name:      forge.ShVolPVCName(shvolume.GetName()),
@@ -176,6 +213,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 
 		if pvc.CreationTimestamp.IsZero() {
 			pvc.Spec = forge.SharedVolumePVCSpec(&r.PVCStorageClass)
+			pvc.Spec.DataSource = dataSource
 		}
 
 		pvc.SetLabels(forge.SharedVolumeObjectLabels(pvc.GetLabels()))
@@ -189,6 +227,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		// Set Error phase if ShVol size is forbidden (less than previous)
 		if sizeDiff := shvolume.Spec.Size.Cmp(oldSize); sizeDiff > 0 || oldSize.IsZero() {
 			pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: shvolume.Spec.Size}
+			if !oldSize.IsZero() {
+				r.EventsRecorder.Eventf(&shvolume, corev1.EventTypeNormal, EvPVCResizing, EvPVCResizingMsg, oldSize.String(), shvolume.Spec.Size.String())
+			}
 
 			log.V(utils.LogDebugLevel).Info("Size updated",
 				"previous", oldSize, "current", shvolume.Spec.Size)
@@ -231,6 +272,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 
 		shvolume.Status.PVName = pv.Name
 		shvolume.Status.Phase = clv1alpha2.SharedVolumePhaseProvisioning
+		if capacity, found := pvc.Status.Capacity[corev1.ResourceStorage]; found {
+			shvolume.Status.Capacity = &capacity
+		}
 
 		done, err := storage.RunPVCProvisioning(ctx, log, r.Client, &pvc, &shvolume)
 		if err != nil {
@@ -243,8 +287,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 				return ctrl.Result{}, err
 			}
 
-			shvolume.Status.Phase = clv1alpha2.SharedVolumePhaseReady
+			ready, err := r.syncContent(ctx, log, &pvc, &shvolume)
+			if ready {
+				shvolume.Status.Phase = clv1alpha2.SharedVolumePhaseReady
+			}
+			if err != nil || !ready {
+				return ctrl.Result{}, err
+			}
+
+			result, err := r.reportUsage(ctx, log, &pvc, &shvolume)
+			if err != nil {
+				// The usage is only informational: its collection is retried later, without preventing the snapshots.
+				log.Error(err, "failed collecting usage")
+				result = ctrl.Result{RequeueAfter: r.UsageReportInterval}
+			}
+
+			next, err := r.scheduleSnapshots(ctx, log, &shvolume)
+			if next > 0 && (result.RequeueAfter == 0 || next < result.RequeueAfter) {
+				result.RequeueAfter = next
+			}
+			return result, err
 		}
+	} else if shvolume.Spec.RestoreFrom != "" {
+		shvolume.Status.Phase = clv1alpha2.SharedVolumePhaseRestoring
 	} else {
 		shvolume.Status.Phase = clv1alpha2.SharedVolumePhasePending
 	}
@@ -286,12 +351,287 @@ func (r *Reconciler) handleDeletion(ctx context.Context, log logr.Logger, shvol
 			log.Error(err, "failed removing finalizer")
 			return err
 		}
+		deleteUsageMetrics(shvol)
 		log.Info("deletion ok, removed finalizer")
 	}
 
 	return nil
 }
 
+// reportUsage periodically measures the usage of a ready SharedVolume, reporting it in the status and through the metrics.
+func (r *Reconciler) reportUsage(
+	ctx context.Context,
+	log logr.Logger,
+	pvc *corev1.PersistentVolumeClaim,
+	shvol *clv1alpha2.SharedVolume,
+) (ctrl.Result, error) {
+	if r.UsageReportInterval <= 0 {
+		return ctrl.Result{}, nil
+	}
+
+	if usage := shvol.Status.Usage; usage != nil {
+		if elapsed := time.Since(usage.LastUpdateTime.Time); elapsed < r.UsageReportInterval {
+			// Ensure the metrics are exposed also after a restart of the operator.
+			updateUsageMetrics(shvol)
+			return ctrl.Result{RequeueAfter: r.UsageReportInterval - elapsed}, nil
+		}
+	}
+
+	usage, err := storage.RunPVCUsageCollection(ctx, log, r.Client, pvc, shvol)
+	if err != nil {
+		r.EventsRecorder.Eventf(shvol, corev1.EventTypeWarning, EvUsageCollectionFailed, EvUsageCollectionFailedMsg, err)
+		return ctrl.Result{}, err
+	}
+	if usage == nil {
+		// The reconciliation is triggered again by the completion of the job.
+		return ctrl.Result{}, nil
+	}
+
+	shvol.Status.Usage = &clv1alpha2.SharedVolumeUsage{
+		Used:           *resource.NewQuantity(usage.Used, resource.BinarySI),
+		Available:      *resource.NewQuantity(usage.Available, resource.BinarySI),
+		LastUpdateTime: metav1.Now(),
+	}
+	updateUsageMetrics(shvol)
+	log.Info("usage updated", "used", shvol.Status.Usage.Used, "available", shvol.Status.Usage.Available)
+
+	return ctrl.Result{RequeueAfter: r.UsageReportInterval}, nil
+}
+
+// syncContent populates the SharedVolume with the content of its source, the first time once provisioned and then
+// whenever a new synchronization is requested. It returns whether the SharedVolume can be considered ready, as the
+// initial synchronization is part of the provisioning, while the following ones do not affect the readiness.
+func (r *Reconciler) syncContent(
+	ctx context.Context,
+	log logr.Logger,
+	pvc *corev1.PersistentVolumeClaim,
+	shvol *clv1alpha2.SharedVolume,
+) (bool, error) {
+	source, content := shvol.Spec.Source, shvol.Status.Content
+	if source == nil || (content != nil && content.SyncRequest == shvol.Spec.SyncRequest) {
+		return true, nil
+	}
+
+	// The files already present are overwritten only when explicitly requesting a new synchronization.
+	initial := content == nil
+	revision, done, err := storage.RunPVCContentSync(ctx, log, r.Client, pvc, shvol, source, !initial, &r.ContentToolsOpts)
+	if err != nil {
+		if initial {
+			shvol.Status.Phase = clv1alpha2.SharedVolumePhaseError
+		}
+		r.EventsRecorder.Eventf(shvol, corev1.EventTypeWarning, EvContentSyncFailed, EvContentSyncFailedMsg, err)
+		return !initial, err
+	}
+	if !done {
+		// The reconciliation is triggered again by the completion of the job.
+		return !initial, nil
+	}
+
+	shvol.Status.Content = &clv1alpha2.SharedVolumeContentStatus{
+		Revision:     revision,
+		SyncRequest:  shvol.Spec.SyncRequest,
+		LastSyncTime: metav1.Now(),
+	}
+	r.EventsRecorder.Eventf(shvol, corev1.EventTypeNormal, EvContentSynced, EvContentSyncedMsg, revision)
+	log.Info("content synchronized", "revision", revision)
+
+	return true, nil
+}
+
+// restoreDataSource returns the data source to restore the PVC of the SharedVolume from the configured snapshot,
+// or nil if the snapshot is not ready to be restored (setting the phase of the SharedVolume accordingly).
+func (r *Reconciler) restoreDataSource(
+	ctx context.Context,
+	log logr.Logger,
+	shvol *clv1alpha2.SharedVolume,
+) (*corev1.TypedLocalObjectReference, error) {
+	restoreFailed := func(reason string) {
+		shvol.Status.Phase = clv1alpha2.SharedVolumePhaseError
+		log.Info("unable to restore from snapshot", "snapshot", shvol.Spec.RestoreFrom, "reason", reason)
+		r.EventsRecorder.Eventf(shvol, corev1.EventTypeWarning, EvRestoreFailed, EvRestoreFailedMsg, shvol.Spec.RestoreFrom, reason)
+	}
+
+	if !r.EnableSnapshots {
+		restoreFailed("snapshots are not enabled")
+		return nil, nil
+	}
+
+	var snapshot clv1alpha2.SharedVolumeSnapshot
+	if err := r.Get(ctx, types.NamespacedName{Name: shvol.Spec.RestoreFrom, Namespace: shvol.Namespace}, &snapshot); err != nil {
+		if kerrors.IsNotFound(err) {
+			// The reconciliation is triggered again by the creation of the snapshot.
+			restoreFailed("snapshot not found")
+			return nil, nil
+		}
+		log.Error(err, "failed retrieving snapshot", "snapshot", shvol.Spec.RestoreFrom)
+		return nil, err
+	}
+
+	switch snapshot.Status.Phase {
+	case clv1alpha2.SharedVolumeSnapshotPhaseReady:
+	case clv1alpha2.SharedVolumeSnapshotPhaseError:
+		restoreFailed("snapshot failed")
+		return nil, nil
+	default:
+		// The reconciliation is triggered again when the snapshot becomes ready.
+		shvol.Status.Phase = clv1alpha2.SharedVolumePhaseRestoring
+		log.Info("waiting for the snapshot to be ready", "snapshot", snapshot.Name, "phase", snapshot.Status.Phase)
+		return nil, nil
+	}
+
+	if restoreSize := snapshot.Status.RestoreSize; restoreSize != nil && shvol.Spec.Size.Cmp(*restoreSize) < 0 {
+		restoreFailed(fmt.Sprintf("size smaller than the restore size (%s)", restoreSize.String()))
+		return nil, nil
+	}
+
+	return forge.ShVolRestoreDataSource(snapshot.Status.VolumeSnapshotName), nil
+}
+
+// scheduleSnapshots creates the scheduled snapshots of a ready SharedVolume according to its snapshot policy,
+// pruning the ones exceeding the retention. It returns the time remaining until the next snapshot is due.
+func (r *Reconciler) scheduleSnapshots(
+	ctx context.Context,
+	log logr.Logger,
+	shvol *clv1alpha2.SharedVolume,
+) (time.Duration, error) {
+	policy := shvol.Spec.SnapshotPolicy
+	if !r.EnableSnapshots || policy == nil || policy.Schedule.Duration <= 0 {
+		return 0, nil
+	}
+
+	var snapshots clv1alpha2.SharedVolumeSnapshotList
+	if err := r.List(ctx, &snapshots, client.InNamespace(shvol.Namespace),
+		client.MatchingLabels(forge.ScheduledSnapshotSelectorLabels(shvol))); err != nil {
+		log.Error(err, "failed retrieving scheduled snapshots")
+		return 0, err
+	}
+
+	// Sort the snapshots from the oldest to the most recent one (the name includes the timestamp).
+	items := snapshots.Items
+	slices.SortFunc(items, func(a, b clv1alpha2.SharedVolumeSnapshot) int {
+		if cmp := a.CreationTimestamp.Compare(b.CreationTimestamp.Time); cmp != 0 {
+			return cmp
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	elapsed := policy.Schedule.Duration
+	if len(items) > 0 {
+		elapsed = time.Since(items[len(items)-1].CreationTimestamp.Time)
+	}
+
+	if elapsed >= policy.Schedule.Duration {
+		snapshot := clv1alpha2.SharedVolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      forge.ShVolScheduledSnapshotName(shvol.Name, time.Now()),
+				Namespace: shvol.Namespace,
+				Labels:    forge.ScheduledSnapshotSelectorLabels(shvol),
+			},
+			Spec: clv1alpha2.SharedVolumeSnapshotSpec{SharedVolumeName: shvol.Name},
+		}
+		if err := r.Create(ctx, &snapshot); err != nil && !kerrors.IsAlreadyExists(err) {
+			log.Error(err, "failed creating scheduled snapshot", "snapshot", snapshot.Name)
+			return 0, err
+		}
+
+		r.EventsRecorder.Eventf(shvol, corev1.EventTypeNormal, EvSnapshotScheduled, EvSnapshotScheduledMsg, snapshot.Name)
+		log.Info("scheduled snapshot created", "snapshot", snapshot.Name)
+		items = append(items, snapshot)
+		elapsed = 0
+	}
+
+	if err := r.pruneSnapshots(ctx, log, shvol, items); err != nil {
+		return 0, err
+	}
+
+	return policy.Schedule.Duration - elapsed, nil
+}
+
+// pruneSnapshots deletes the oldest ready scheduled snapshots exceeding the retention, along with the failed ones.
+// Only the ready snapshots count toward the retention, so that failed or in-progress ones never push the restorable
+// ones out, and the snapshots still referenced by a SharedVolume being restored are always preserved.
+// The snapshots are expected to be sorted from the oldest to the most recent one.
+func (r *Reconciler) pruneSnapshots(
+	ctx context.Context,
+	log logr.Logger,
+	shvol *clv1alpha2.SharedVolume,
+	snapshots []clv1alpha2.SharedVolumeSnapshot,
+) error {
+	var ready, failed []*clv1alpha2.SharedVolumeSnapshot
+	for i := range snapshots {
+		switch snapshots[i].Status.Phase {
+		case clv1alpha2.SharedVolumeSnapshotPhaseReady:
+			ready = append(ready, &snapshots[i])
+		case clv1alpha2.SharedVolumeSnapshotPhaseError:
+			// The most recent snapshot is kept even if failed, to report the error until the next one is due.
+			if i < len(snapshots)-1 {
+				failed = append(failed, &snapshots[i])
+			}
+		}
+	}
+
+	var shvols clv1alpha2.SharedVolumeList
+	if err := r.List(ctx, &shvols, client.InNamespace(shvol.Namespace)); err != nil {
+		log.Error(err, "failed retrieving shared volumes")
+		return err
+	}
+	restoring := make(map[string]bool)
+	for i := range shvols.Items {
+		if shvols.Items[i].Spec.RestoreFrom != "" && shvols.Items[i].Status.PVName == "" {
+			restoring[shvols.Items[i].Spec.RestoreFrom] = true
+		}
+	}
+
+	// At least the most recent ready snapshot is always kept, regardless of the retention.
+	pruned := ready[:max(len(ready)-max(shvol.Spec.SnapshotPolicy.Retention, 1), 0)]
+	for _, snapshot := range slices.Concat(pruned, failed) {
+		if restoring[snapshot.Name] {
+			log.Info("scheduled snapshot not deleted as still being restored", "snapshot", snapshot.Name)
+			continue
+		}
+
+		if err := r.Delete(ctx, snapshot); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "failed deleting scheduled snapshot", "snapshot", snapshot.Name)
+			return err
+		}
+
+		if snapshot.Status.Phase == clv1alpha2.SharedVolumeSnapshotPhaseError {
+			r.EventsRecorder.Eventf(shvol, corev1.EventTypeNormal, EvSnapshotPruned, EvFailedSnapshotPrunedMsg, snapshot.Name)
+			log.Info("failed scheduled snapshot deleted", "snapshot", snapshot.Name)
+			continue
+		}
+		r.EventsRecorder.Eventf(shvol, corev1.EventTypeNormal, EvSnapshotPruned, EvSnapshotPrunedMsg, snapshot.Name)
+		log.Info("scheduled snapshot exceeding the retention deleted", "snapshot", snapshot.Name)
+	}
+
+	return nil
+}
+
+// getRestoringSharedVolumes returns reconciliation requests for the SharedVolumes to be restored from the given snapshot,
+// so that the restore can proceed once the snapshot is ready.
+func (r *Reconciler) getRestoringSharedVolumes(ctx context.Context, obj client.Object) []ctrl.Request {
+	var enqueues []ctrl.Request
+	var shvols clv1alpha2.SharedVolumeList
+
+	log := ctrl.LoggerFrom(ctx, "woken-by-snapshot", forge.NamespacedNameFromObject(obj))
+
+	filter := client.MatchingFields{restoreFromFieldIndex: obj.GetName()}
+	if err := r.List(ctx, &shvols, client.InNamespace(obj.GetNamespace()), filter); err != nil {
+		log.Error(err, "could not retrieve SharedVolumes restoring from snapshot")
+		return nil
+	}
+
+	for i := range shvols.Items {
+		if shvols.Items[i].Status.PVName == "" {
+			enqueues = append(enqueues, ctrl.Request{
+				NamespacedName: forge.NamespacedNameFromObject(&shvols.Items[i]),
+			})
+		}
+	}
+
+	return enqueues
+}
+
 // getDeletingSharedVolumes returns reconciliation requests for SharedVolumes in Deleting phase so they can be reenqueued,
 // since the SharedVolumes cannot be deleted until all Templates have removed their mounts.
 func (r *Reconciler) getDeletingSharedVolumes(ctx context.Context, obj client.Object) []ctrl.Request {
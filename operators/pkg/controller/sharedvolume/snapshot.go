@@ -0,0 +1,245 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharedvolume
+
+import (
+	"context"
+	"reflect"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	ctrlcommon "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/common"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
+)
+
+// SnapshotReconciler reconciles a SharedVolumeSnapshot object, taking a CSI VolumeSnapshot of the PVC of the SharedVolume.
+type SnapshotReconciler struct {
+	client.Client
+	TargetLabel    ctrlcommon.KVLabel
+	EventsRecorder record.EventRecorder
+
+	// The VolumeSnapshotClass used for the snapshots (the default one if empty).
+	VolumeSnapshotClass string
+
+	// This function, if configured, is deferred at the beginning of the Reconcile.
+	// Specifically, it is meant to be set to GinkgoRecover during the tests,
+	// in order to lead to a controlled failure in case the Reconcile panics.
+	ReconcileDeferHook func()
+}
+
+// SetupWithManager registers a new controller for SharedVolumeSnapshot resources.
+func (r *SnapshotReconciler) SetupWithManager(mgr ctrl.Manager, concurrency int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&clv1alpha2.SharedVolumeSnapshot{}).
+		Owns(&snapshotv1.VolumeSnapshot{}).
+		Watches(&clv1alpha2.SharedVolume{},
+			handler.EnqueueRequestsFromMapFunc(r.getPendingSnapshots)).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: concurrency,
+		}).
+		WithLogConstructor(utils.LogConstructor(mgr.GetLogger(), "SharedVolumeSnapshot")).
+		Complete(r)
+}
+
+// Reconcile reconciles the state of a SharedVolumeSnapshot resource.
+func (r *SnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	if r.ReconcileDeferHook != nil {
+		defer r.ReconcileDeferHook()
+	}
+
+	log := ctrl.LoggerFrom(ctx, "sharedvolumesnapshot", req.NamespacedName)
+
+	tracer := trace.New("reconcile", trace.Field{Key: "sharedvolumesnapshot", Value: req.NamespacedName})
+	ctx = trace.ContextWithTrace(ctx, tracer)
+	defer tracer.LogIfLong(utils.LongThreshold())
+
+	// Get the shared volume snapshot object.
+	var snapshot clv1alpha2.SharedVolumeSnapshot
+	if err = r.Get(ctx, req.NamespacedName, &snapshot); err != nil {
+		if !kerrors.IsNotFound(err) {
+			log.Error(err, "Failed retrieving shared volume snapshot")
+		}
+		// The VolumeSnapshot is deleted by the garbage collector, through the owner reference.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !snapshot.GetDeletionTimestamp().IsZero() {
+		log.Info("Shared volume snapshot is being deleted, skipping reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	// Check the target label, in order to know whether to perform or not reconciliation.
+	if proceed, err := utils.CheckNamespaceTargetLabel(ctx, r.Client, snapshot.GetNamespace(), r.TargetLabel); !proceed {
+		// If there was an error while checking, show the error and try again.
+		if err != nil {
+			log.Error(err, "Failed checking target label")
+			return ctrl.Result{}, err
+		}
+		log.Info("SharedVolumeSnapshot is not responsibility of this controller, skipping reconcile")
+		return ctrl.Result{}, nil
+	}
+	log.Info("Reconciling sharedvolumesnapshot")
+
+	// Defer the function to update the SharedVolumeSnapshot status depending on the modifications
+	// performed while enforcing the desired specification.
+	defer func(original, updated *clv1alpha2.SharedVolumeSnapshot) {
+		// Avoid triggering the status update if not necessary.
+		if !reflect.DeepEqual(original.Status, updated.Status) {
+			if err2 := r.Status().Patch(ctx, updated, client.MergeFrom(original)); err2 != nil {
+				log.Error(err2, "failed to update the sharedvolumesnapshot status")
+				err = err2
+			} else {
+				tracer.Step("sharedvolumesnapshot status updated")
+				log.Info("sharedvolumesnapshot status correctly updated")
+			}
+		}
+	}(snapshot.DeepCopy(), &snapshot)
+
+	// Patch the shared volume snapshot labels.
+	labels, updated := forge.SharedVolumeSnapshotLabels(snapshot.GetLabels(), &snapshot)
+	if updated {
+		original := snapshot.DeepCopy()
+		snapshot.SetLabels(labels)
+		if err := r.Patch(ctx, &snapshot, client.MergeFrom(original)); err != nil {
+			log.Error(err, "failed to update the sharedvolumesnapshot labels")
+			return ctrl.Result{}, err
+		}
+		tracer.Step("sharedvolumesnapshot labels updated")
+		log.Info("sharedvolumesnapshot labels correctly configured")
+	}
+
+	// The point-in-time snapshot is taken only once: afterwards, the shared volume is no longer involved.
+	volumeSnapshot := snapshotv1.VolumeSnapshot{}
+	err = r.Get(ctx, types.NamespacedName{Name: snapshot.Name, Namespace: snapshot.Namespace}, &volumeSnapshot)
+	switch {
+	case kerrors.IsNotFound(err):
+		return ctrl.Result{}, r.createVolumeSnapshot(ctx, &snapshot)
+	case err != nil:
+		log.Error(err, "failed retrieving volume snapshot")
+		return ctrl.Result{}, err
+	}
+
+	snapshot.Status.VolumeSnapshotName = volumeSnapshot.Name
+	switch status := volumeSnapshot.Status; {
+	case status == nil:
+		snapshot.Status.Phase = clv1alpha2.SharedVolumeSnapshotPhaseCreating
+	case status.Error != nil:
+		if snapshot.Status.Phase != clv1alpha2.SharedVolumeSnapshotPhaseError {
+			r.EventsRecorder.Eventf(&snapshot, corev1.EventTypeWarning, EvSnapshotFailed, EvSnapshotFailedMsg, ptr.Deref(status.Error.Message, "unknown error"))
+		}
+		snapshot.Status.Phase = clv1alpha2.SharedVolumeSnapshotPhaseError
+	case ptr.Deref(status.ReadyToUse, false):
+		snapshot.Status.Phase = clv1alpha2.SharedVolumeSnapshotPhaseReady
+		snapshot.Status.RestoreSize = status.RestoreSize
+		snapshot.Status.CreationTime = status.CreationTime
+	default:
+		snapshot.Status.Phase = clv1alpha2.SharedVolumeSnapshotPhaseCreating
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// createVolumeSnapshot creates the CSI VolumeSnapshot of the PVC of the shared volume, once it is ready.
+func (r *SnapshotReconciler) createVolumeSnapshot(ctx context.Context, snapshot *clv1alpha2.SharedVolumeSnapshot) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	var shvol clv1alpha2.SharedVolume
+	if err := r.Get(ctx, types.NamespacedName{Name: snapshot.Spec.SharedVolumeName, Namespace: snapshot.Namespace}, &shvol); err != nil {
+		if kerrors.IsNotFound(err) {
+			snapshot.Status.Phase = clv1alpha2.SharedVolumeSnapshotPhaseError
+			r.EventsRecorder.Eventf(snapshot, corev1.EventTypeWarning, EvSnapshotFailed, EvSnapshotFailedMsg, "shared volume not found")
+			log.Info("shared volume not found", "sharedvolume", snapshot.Spec.SharedVolumeName)
+			return nil
+		}
+		log.Error(err, "failed retrieving shared volume", "sharedvolume", snapshot.Spec.SharedVolumeName)
+		return err
+	}
+
+	if shvol.Status.Phase != clv1alpha2.SharedVolumePhaseReady {
+		// The reconciliation is triggered again when the shared volume becomes ready.
+		snapshot.Status.Phase = clv1alpha2.SharedVolumeSnapshotPhasePending
+		log.Info("waiting for the shared volume to be ready", "sharedvolume", shvol.Name, "phase", shvol.Status.Phase)
+		return nil
+	}
+
+	volumeSnapshot := snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshot.Name,
+			Namespace: snapshot.Namespace,
+			Labels:    forge.SharedVolumeObjectLabels(map[string]string{forge.LabelSharedVolumeKey: shvol.Name}),
+		},
+		Spec: forge.ShVolVolumeSnapshotSpec(shvol.Name, r.VolumeSnapshotClass),
+	}
+	if err := ctrl.SetControllerReference(snapshot, &volumeSnapshot, r.Scheme()); err != nil {
+		log.Error(err, "failed setting the controller reference")
+		return err
+	}
+	if err := r.Create(ctx, &volumeSnapshot); err != nil {
+		if utils.IsResourceQuotaExceeded(err) {
+			snapshot.Status.Phase = clv1alpha2.SharedVolumeSnapshotPhaseError
+			r.EventsRecorder.Eventf(snapshot, corev1.EventTypeWarning, EvSnapshotFailed, EvSnapshotFailedMsg, "resource quota exceeded")
+			return nil
+		}
+		log.Error(err, "failed creating volume snapshot")
+		return err
+	}
+
+	snapshot.Status.Phase = clv1alpha2.SharedVolumeSnapshotPhaseCreating
+	snapshot.Status.VolumeSnapshotName = volumeSnapshot.Name
+	log.Info("volume snapshot created", "volumesnapshot", volumeSnapshot.Name)
+	return nil
+}
+
+// getPendingSnapshots returns reconciliation requests for the SharedVolumeSnapshots waiting for the given SharedVolume,
+// so that the VolumeSnapshot can be created once it becomes ready.
+func (r *SnapshotReconciler) getPendingSnapshots(ctx context.Context, obj client.Object) []ctrl.Request {
+	var enqueues []ctrl.Request
+	var snapshots clv1alpha2.SharedVolumeSnapshotList
+
+	if shvol := obj.(*clv1alpha2.SharedVolume); shvol.Status.Phase != clv1alpha2.SharedVolumePhaseReady {
+		return nil
+	}
+
+	log := ctrl.LoggerFrom(ctx, "woken-by-sharedvolume", forge.NamespacedNameFromObject(obj))
+
+	filter := client.MatchingLabels{forge.LabelSharedVolumeKey: obj.GetName()}
+	if err := r.List(ctx, &snapshots, client.InNamespace(obj.GetNamespace()), filter); err != nil {
+		log.Error(err, "could not retrieve SharedVolumeSnapshots of the SharedVolume")
+		return nil
+	}
+
+	for i := range snapshots.Items {
+		if snapshots.Items[i].Status.Phase == clv1alpha2.SharedVolumeSnapshotPhasePending {
+			enqueues = append(enqueues, ctrl.Request{
+				NamespacedName: forge.NamespacedNameFromObject(&snapshots.Items[i]),
+			})
+		}
+	}
+
+	return enqueues
+}
@@ -0,0 +1,425 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharedvolume_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+)
+
+var _ = Describe("The sharedvolume snapshots", Ordered, func() {
+	ctx := context.Background()
+	var (
+		shvol    clv1alpha2.SharedVolume
+		snapshot clv1alpha2.SharedVolumeSnapshot
+		volSnap  snapshotv1.VolumeSnapshot
+		counter  int
+	)
+
+	const (
+		testName = "test-snapshot"
+	)
+
+	RunSnapshotReconciler := func() error {
+		_, err := snapshotReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: forge.NamespacedNameFromObject(&snapshot),
+		})
+		if err != nil {
+			return err
+		}
+		return k8sClient.Get(ctx, forge.NamespacedNameFromObject(&snapshot), &snapshot)
+	}
+
+	RunShVolReconciler := func() (reconcile.Result, error) {
+		result, err := shvolReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: forge.NamespacedNameFromObject(&shvol),
+		})
+		if err != nil {
+			return result, err
+		}
+		return result, k8sClient.Get(ctx, forge.NamespacedNameFromObject(&shvol), &shvol)
+	}
+
+	SetShVolPhase := func(phase clv1alpha2.SharedVolumePhase) {
+		shvol.Status.Phase = phase
+		Expect(k8sClient.Status().Update(ctx, &shvol)).To(Succeed())
+	}
+
+	BeforeAll(func() {
+		ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testName, Labels: labelMap}}
+		Expect(k8sClient.Create(ctx, &ns)).To(Succeed())
+	})
+
+	BeforeEach(func() {
+		// Each test relies on different resources, as they cannot be deleted in envtest (no garbage collector).
+		counter++
+		shvol = clv1alpha2.SharedVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("shvol-%d", counter),
+				Namespace: testName,
+			},
+			Spec: clv1alpha2.SharedVolumeSpec{
+				PrettyName: "My Test Drive",
+				Size:       resource.MustParse("1Gi"),
+			},
+		}
+		snapshot = clv1alpha2.SharedVolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("snapshot-%d", counter),
+				Namespace: testName,
+			},
+			Spec: clv1alpha2.SharedVolumeSnapshotSpec{SharedVolumeName: shvol.Name},
+		}
+	})
+
+	Context("The SharedVolumeSnapshot reconciler", func() {
+		JustBeforeEach(func() {
+			Expect(k8sClient.Create(ctx, &snapshot)).To(Succeed())
+			Expect(RunSnapshotReconciler()).To(Succeed())
+		})
+
+		When("the shared volume does not exist", func() {
+			It("Should transition to phase Error", func() {
+				Expect(snapshot.Status.Phase).To(Equal(clv1alpha2.SharedVolumeSnapshotPhaseError))
+				Expect(snapshot.Labels).To(HaveKeyWithValue(forge.LabelSharedVolumeKey, shvol.Name))
+			})
+		})
+
+		When("the shared volume is not ready", func() {
+			BeforeEach(func() {
+				Expect(k8sClient.Create(ctx, &shvol)).To(Succeed())
+				SetShVolPhase(clv1alpha2.SharedVolumePhasePending)
+			})
+
+			It("Should transition to phase Pending, without creating the volume snapshot", func() {
+				Expect(snapshot.Status.Phase).To(Equal(clv1alpha2.SharedVolumeSnapshotPhasePending))
+				Expect(k8sClient.Get(ctx, forge.NamespacedNameFromObject(&snapshot), &volSnap)).ToNot(Succeed())
+			})
+		})
+
+		When("the shared volume is ready", func() {
+			BeforeEach(func() {
+				Expect(k8sClient.Create(ctx, &shvol)).To(Succeed())
+				SetShVolPhase(clv1alpha2.SharedVolumePhaseReady)
+			})
+
+			It("Should create the volume snapshot of the shared volume PVC", func() {
+				Expect(snapshot.Status.Phase).To(Equal(clv1alpha2.SharedVolumeSnapshotPhaseCreating))
+				Expect(snapshot.Status.VolumeSnapshotName).To(Equal(snapshot.Name))
+
+				Expect(k8sClient.Get(ctx, forge.NamespacedNameFromObject(&snapshot), &volSnap)).To(Succeed())
+				Expect(volSnap.Spec.Source.PersistentVolumeClaimName).To(Equal(ptr.To(forge.ShVolPVCName(shvol.Name))))
+				Expect(volSnap.Spec.VolumeSnapshotClassName).To(Equal(ptr.To(snapshotClass)))
+				Expect(metav1.IsControlledBy(&volSnap, &snapshot)).To(BeTrue())
+			})
+
+			When("the volume snapshot becomes ready", func() {
+				JustBeforeEach(func() {
+					Expect(k8sClient.Get(ctx, forge.NamespacedNameFromObject(&snapshot), &volSnap)).To(Succeed())
+					volSnap.Status = &snapshotv1.VolumeSnapshotStatus{
+						ReadyToUse:   ptr.To(true),
+						RestoreSize:  ptr.To(resource.MustParse("1Gi")),
+						CreationTime: ptr.To(metav1.Now()),
+					}
+					Expect(k8sClient.Status().Update(ctx, &volSnap)).To(Succeed())
+					Expect(RunSnapshotReconciler()).To(Succeed())
+				})
+
+				It("Should transition to phase Ready, reporting the restore size", func() {
+					Expect(snapshot.Status.Phase).To(Equal(clv1alpha2.SharedVolumeSnapshotPhaseReady))
+					Expect(snapshot.Status.RestoreSize).To(Equal(ptr.To(resource.MustParse("1Gi"))))
+					Expect(snapshot.Status.CreationTime).ToNot(BeNil())
+				})
+			})
+
+			When("the volume snapshot fails", func() {
+				JustBeforeEach(func() {
+					Expect(k8sClient.Get(ctx, forge.NamespacedNameFromObject(&snapshot), &volSnap)).To(Succeed())
+					volSnap.Status = &snapshotv1.VolumeSnapshotStatus{
+						ReadyToUse: ptr.To(false),
+						Error:      &snapshotv1.VolumeSnapshotError{Message: ptr.To("driver failure")},
+					}
+					Expect(k8sClient.Status().Update(ctx, &volSnap)).To(Succeed())
+					Expect(RunSnapshotReconciler()).To(Succeed())
+				})
+
+				It("Should transition to phase Error", func() {
+					Expect(snapshot.Status.Phase).To(Equal(clv1alpha2.SharedVolumeSnapshotPhaseError))
+				})
+			})
+		})
+	})
+
+	Context("The shared volume is restored from a snapshot", func() {
+		var snapshotStatus *clv1alpha2.SharedVolumeSnapshotStatus
+
+		BeforeEach(func() {
+			shvol.Spec.RestoreFrom = snapshot.Name
+			snapshotStatus = nil
+		})
+
+		JustBeforeEach(func() {
+			if snapshotStatus != nil {
+				Expect(k8sClient.Create(ctx, &snapshot)).To(Succeed())
+				snapshot.Status = *snapshotStatus
+				Expect(k8sClient.Status().Update(ctx, &snapshot)).To(Succeed())
+			}
+
+			Expect(k8sClient.Create(ctx, &shvol)).To(Succeed())
+			_, err := RunShVolReconciler()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		When("the snapshot does not exist", func() {
+			It("Should transition to phase Error, without creating the PVC", func() {
+				Expect(shvol.Status.Phase).To(Equal(clv1alpha2.SharedVolumePhaseError))
+
+				var pvc corev1.PersistentVolumeClaim
+				Expect(k8sClient.Get(ctx, client.ObjectKey{Name: forge.ShVolPVCName(shvol.Name), Namespace: testName}, &pvc)).ToNot(Succeed())
+			})
+		})
+
+		When("the snapshot is not ready yet", func() {
+			BeforeEach(func() {
+				snapshotStatus = &clv1alpha2.SharedVolumeSnapshotStatus{Phase: clv1alpha2.SharedVolumeSnapshotPhaseCreating}
+			})
+
+			It("Should transition to phase Restoring, without creating the PVC", func() {
+				Expect(shvol.Status.Phase).To(Equal(clv1alpha2.SharedVolumePhaseRestoring))
+
+				var pvc corev1.PersistentVolumeClaim
+				Expect(k8sClient.Get(ctx, client.ObjectKey{Name: forge.ShVolPVCName(shvol.Name), Namespace: testName}, &pvc)).ToNot(Succeed())
+			})
+		})
+
+		When("the snapshot is ready", func() {
+			BeforeEach(func() {
+				snapshotStatus = &clv1alpha2.SharedVolumeSnapshotStatus{
+					Phase:              clv1alpha2.SharedVolumeSnapshotPhaseReady,
+					VolumeSnapshotName: snapshot.Name,
+					RestoreSize:        ptr.To(resource.MustParse("1Gi")),
+				}
+			})
+
+			It("Should create the PVC from the volume snapshot", func() {
+				Expect(shvol.Status.Phase).To(Equal(clv1alpha2.SharedVolumePhaseRestoring))
+
+				var pvc corev1.PersistentVolumeClaim
+				Expect(k8sClient.Get(ctx, client.ObjectKey{Name: forge.ShVolPVCName(shvol.Name), Namespace: testName}, &pvc)).To(Succeed())
+				Expect(pvc.Spec.DataSource).To(Equal(forge.ShVolRestoreDataSource(snapshot.Name)))
+			})
+
+			When("the restore size exceeds the size of the shared volume", func() {
+				BeforeEach(func() {
+					snapshotStatus.RestoreSize = ptr.To(resource.MustParse("2Gi"))
+				})
+
+				It("Should transition to phase Error", func() {
+					Expect(shvol.Status.Phase).To(Equal(clv1alpha2.SharedVolumePhaseError))
+				})
+			})
+		})
+	})
+
+	Context("The shared volume has a snapshot policy", func() {
+		var (
+			result reconcile.Result
+			// The phases of the scheduled snapshots already taken, from the oldest to the most recent one.
+			phases []clv1alpha2.SharedVolumeSnapshotPhase
+			names  []string
+			// The index of the snapshot another shared volume is being restored from, if any.
+			restoring int
+		)
+
+		BeforeEach(func() {
+			shvol.Spec.SnapshotPolicy = &clv1alpha2.SharedVolumeSnapshotPolicy{
+				Schedule:  metav1.Duration{Duration: time.Hour},
+				Retention: 2,
+			}
+			phases, names, restoring = nil, nil, -1
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			// Simulate a ready shared volume, whose PVC has already been provisioned.
+			Expect(k8sClient.Create(ctx, &shvol)).To(Succeed())
+			pvc := corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      forge.ShVolPVCName(shvol.Name),
+					Namespace: testName,
+					Labels:    map[string]string{forge.ProvisionJobLabel: forge.ProvisionJobValueOk},
+				},
+				Spec: forge.SharedVolumePVCSpec(ptr.To(pvcStorageClass)),
+			}
+			pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: shvol.Spec.Size}
+			pvc.Spec.VolumeName = fmt.Sprintf("pv-%s", shvol.Name)
+			Expect(k8sClient.Create(ctx, &pvc)).To(Succeed())
+			pvc.Status.Phase = corev1.ClaimBound
+			Expect(k8sClient.Status().Update(ctx, &pvc)).To(Succeed())
+			pv := corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: pvc.Spec.VolumeName},
+				Spec: corev1.PersistentVolumeSpec{
+					Capacity:    corev1.ResourceList{corev1.ResourceStorage: shvol.Spec.Size},
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+					PersistentVolumeSource: corev1.PersistentVolumeSource{
+						CSI: &corev1.CSIPersistentVolumeSource{Driver: "nfs.example.com", VolumeHandle: "/nfs/path"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, &pv)).To(Succeed())
+
+			for i, phase := range phases {
+				old := clv1alpha2.SharedVolumeSnapshot{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      forge.ShVolScheduledSnapshotName(shvol.Name, time.Now().Add(-time.Duration(len(phases)+1-i)*time.Hour)),
+						Namespace: testName,
+						Labels:    forge.ScheduledSnapshotSelectorLabels(&shvol),
+					},
+					Spec: clv1alpha2.SharedVolumeSnapshotSpec{SharedVolumeName: shvol.Name},
+				}
+				Expect(k8sClient.Create(ctx, &old)).To(Succeed())
+				old.Status.Phase = phase
+				Expect(k8sClient.Status().Update(ctx, &old)).To(Succeed())
+				names = append(names, old.Name)
+			}
+
+			if restoring >= 0 {
+				Expect(k8sClient.Create(ctx, &clv1alpha2.SharedVolume{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-restored", shvol.Name), Namespace: testName},
+					Spec: clv1alpha2.SharedVolumeSpec{
+						PrettyName:  "Restored Volume",
+						Size:        shvol.Spec.Size,
+						RestoreFrom: names[restoring],
+					},
+				})).To(Succeed())
+			}
+
+			result, err = RunShVolReconciler()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		ScheduledSnapshots := func() []clv1alpha2.SharedVolumeSnapshot {
+			var snapshots clv1alpha2.SharedVolumeSnapshotList
+			Expect(k8sClient.List(ctx, &snapshots, client.InNamespace(testName),
+				client.MatchingLabels(forge.ScheduledSnapshotSelectorLabels(&shvol)))).To(Succeed())
+			return snapshots.Items
+		}
+
+		ScheduledSnapshotNames := func() []string {
+			var scheduled []string
+			for _, snapshot := range ScheduledSnapshots() {
+				scheduled = append(scheduled, snapshot.Name)
+			}
+			return scheduled
+		}
+
+		When("no snapshot has been taken yet", func() {
+			It("Should create a scheduled snapshot and requeue after the schedule", func() {
+				Expect(shvol.Status.Phase).To(Equal(clv1alpha2.SharedVolumePhaseReady))
+				Expect(ScheduledSnapshots()).To(HaveLen(1))
+				Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+			})
+		})
+
+		When("the usage collection fails", func() {
+			BeforeEach(func() {
+				shvolReconciler.UsageReportInterval = time.Hour
+				DeferCleanup(func() { shvolReconciler.UsageReportInterval = 0 })
+
+				pvc := corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: forge.ShVolPVCName(shvol.Name), Namespace: testName}}
+				job := batchv1.Job{ObjectMeta: forge.ObjectMetaWithSuffix(&pvc, "usage"), Spec: forge.PVCUsageJobSpec(&pvc)}
+				Expect(k8sClient.Create(ctx, &job)).To(Succeed())
+				job.Status.Failed = forge.UsageJobMaxRetries + 1
+				Expect(k8sClient.Status().Update(ctx, &job)).To(Succeed())
+			})
+
+			It("Should create the scheduled snapshot anyway", func() {
+				Expect(shvol.Status.Phase).To(Equal(clv1alpha2.SharedVolumePhaseReady))
+				Expect(ScheduledSnapshots()).To(HaveLen(1))
+				Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+			})
+		})
+
+		When("some scheduled snapshots have been recently taken", func() {
+			BeforeEach(func() {
+				phases = []clv1alpha2.SharedVolumeSnapshotPhase{
+					clv1alpha2.SharedVolumeSnapshotPhaseReady,
+					clv1alpha2.SharedVolumeSnapshotPhaseReady,
+				}
+			})
+
+			It("Should not create a new snapshot before the schedule elapses", func() {
+				// The existing snapshots have just been created, hence the schedule has not elapsed yet.
+				Expect(ScheduledSnapshotNames()).To(ConsistOf(names))
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			})
+
+			When("the retention is exceeded", func() {
+				BeforeEach(func() {
+					shvol.Spec.SnapshotPolicy.Retention = 1
+				})
+
+				It("Should delete the oldest ready snapshots", func() {
+					Expect(ScheduledSnapshotNames()).To(ConsistOf(names[1]))
+				})
+
+				When("another shared volume is still being restored from the oldest snapshot", func() {
+					BeforeEach(func() {
+						restoring = 0
+					})
+
+					It("Should not delete the snapshot being restored", func() {
+						Expect(ScheduledSnapshotNames()).To(ConsistOf(names))
+					})
+				})
+			})
+
+			When("the most recent snapshots are failed or still being created", func() {
+				BeforeEach(func() {
+					shvol.Spec.SnapshotPolicy.Retention = 1
+					phases = append(phases,
+						clv1alpha2.SharedVolumeSnapshotPhaseError,
+						clv1alpha2.SharedVolumeSnapshotPhaseCreating,
+						clv1alpha2.SharedVolumeSnapshotPhaseError,
+					)
+				})
+
+				It("Should never delete the most recent ready snapshot", func() {
+					Expect(ScheduledSnapshotNames()).To(ContainElement(names[1]))
+				})
+
+				It("Should delete the failed snapshots, except the most recent one", func() {
+					Expect(ScheduledSnapshotNames()).To(ConsistOf(names[1], names[3], names[4]))
+				})
+			})
+		})
+	})
+})
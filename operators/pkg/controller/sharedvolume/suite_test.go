@@ -19,6 +19,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -41,9 +42,10 @@ import (
 // http://onsi.github.io/ginkgo/ to learn more about Ginkgo.
 
 var (
-	shvolReconciler sharedvolume.Reconciler
-	k8sClient       client.Client
-	testEnv         = envtest.Environment{
+	shvolReconciler    sharedvolume.Reconciler
+	snapshotReconciler sharedvolume.SnapshotReconciler
+	k8sClient          client.Client
+	testEnv            = envtest.Environment{
 		CRDDirectoryPaths: []string{
 			filepath.Join("..", "..", "..", "deploy", "crds"),
 			filepath.Join("..", "..", "..", "tests", "crds"),
@@ -57,6 +59,7 @@ var (
 	}
 
 	pvcStorageClass = "rook-ceph-shvol"
+	snapshotClass   = "rook-ceph-snapclass"
 )
 
 func TestAPIs(t *testing.T) {
@@ -75,6 +78,7 @@ var _ = BeforeSuite(func() {
 	Expect(clv1alpha1.AddToScheme(scheme.Scheme)).NotTo(HaveOccurred())
 	Expect(virtv1.AddToScheme(scheme.Scheme)).NotTo(HaveOccurred())
 	Expect(cdiv1beta1.AddToScheme(scheme.Scheme)).NotTo(HaveOccurred())
+	Expect(snapshotv1.AddToScheme(scheme.Scheme)).NotTo(HaveOccurred())
 
 	ctrl.SetLogger(textlogger.NewLogger(textlogger.NewConfig()))
 
@@ -87,8 +91,17 @@ var _ = BeforeSuite(func() {
 		TargetLabel:        targetLabel,
 		EventsRecorder:     record.NewFakeRecorder(1024),
 		PVCStorageClass:    pvcStorageClass,
+		EnableSnapshots:    true,
 		ReconcileDeferHook: GinkgoRecover,
 	}
+
+	snapshotReconciler = sharedvolume.SnapshotReconciler{
+		Client:              k8sClient,
+		TargetLabel:         targetLabel,
+		EventsRecorder:      record.NewFakeRecorder(1024),
+		VolumeSnapshotClass: snapshotClass,
+		ReconcileDeferHook:  GinkgoRecover,
+	}
 })
 
 var _ = AfterSuite(func() {
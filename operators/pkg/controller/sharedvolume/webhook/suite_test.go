@@ -0,0 +1,42 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+)
+
+func TestSharedVolumeWebhook(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SharedVolume Webhook Suite")
+}
+
+// forgeSharedVolume returns a SharedVolume with the given size.
+func forgeSharedVolume(size string) *clv1alpha2.SharedVolume {
+	return &clv1alpha2.SharedVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "shvol", Namespace: "workspace-test"},
+		Spec: clv1alpha2.SharedVolumeSpec{
+			PrettyName: "Shared Volume",
+			Size:       resource.MustParse(size),
+		},
+	}
+}
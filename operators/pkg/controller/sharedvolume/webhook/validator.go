@@ -0,0 +1,138 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook implements the webhook handlers for sharedvolume resources.
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+)
+
+// SharedVolumeValidator implements a validating webhook for SharedVolume resources,
+// ensuring that the size is positive and never decreased (volumes can only be expanded),
+// that the volume is not restored from a different snapshot once created, and that the
+// access control rules and the snapshot policy are well formed.
+type SharedVolumeValidator struct {
+	admission.CustomValidator
+}
+
+// ValidateCreate validates a new sharedvolume creation request.
+func (sv *SharedVolumeValidator) ValidateCreate(
+	ctx context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	shvol, ok := obj.(*clv1alpha2.SharedVolume)
+	if !ok {
+		return nil, fmt.Errorf("expected a SharedVolume object, got %T", obj)
+	}
+
+	if shvol.Spec.Size.Sign() <= 0 {
+		ctrl.LoggerFrom(ctx).Info("denied: non positive size", "sharedvolume", shvol.Name)
+		return nil, kerrors.NewForbidden(schema.GroupResource{}, shvol.Name,
+			fmt.Errorf("the size of the shared volume must be positive"))
+	}
+
+	if err := sv.validateSnapshotPolicy(ctx, shvol); err != nil {
+		return nil, err
+	}
+
+	return nil, sv.validateAccessControl(ctx, shvol)
+}
+
+// ValidateUpdate validates a sharedvolume update request, denying the reduction of the size.
+func (sv *SharedVolumeValidator) ValidateUpdate(
+	ctx context.Context,
+	oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	oldShvol, ok := oldObj.(*clv1alpha2.SharedVolume)
+	if !ok {
+		return nil, fmt.Errorf("expected a SharedVolume object, got %T", oldObj)
+	}
+	shvol, ok := newObj.(*clv1alpha2.SharedVolume)
+	if !ok {
+		return nil, fmt.Errorf("expected a SharedVolume object, got %T", newObj)
+	}
+
+	if shvol.Spec.Size.Cmp(oldShvol.Spec.Size) < 0 {
+		ctrl.LoggerFrom(ctx).Info("denied: shrinking size", "sharedvolume", shvol.Name,
+			"previous", oldShvol.Spec.Size, "current", shvol.Spec.Size)
+		return nil, kerrors.NewForbidden(schema.GroupResource{}, shvol.Name,
+			fmt.Errorf("the size of the shared volume cannot be reduced (from %s to %s)", oldShvol.Spec.Size.String(), shvol.Spec.Size.String()))
+	}
+
+	if shvol.Spec.RestoreFrom != oldShvol.Spec.RestoreFrom {
+		ctrl.LoggerFrom(ctx).Info("denied: changing restore source", "sharedvolume", shvol.Name,
+			"previous", oldShvol.Spec.RestoreFrom, "current", shvol.Spec.RestoreFrom)
+		return nil, kerrors.NewForbidden(schema.GroupResource{}, shvol.Name,
+			fmt.Errorf("the snapshot the shared volume is restored from cannot be changed"))
+	}
+
+	if err := sv.validateSnapshotPolicy(ctx, shvol); err != nil {
+		return nil, err
+	}
+
+	return nil, sv.validateAccessControl(ctx, shvol)
+}
+
+// validateSnapshotPolicy checks that the snapshot policy of the sharedvolume, if any, has a positive schedule.
+func (sv *SharedVolumeValidator) validateSnapshotPolicy(ctx context.Context, shvol *clv1alpha2.SharedVolume) error {
+	if policy := shvol.Spec.SnapshotPolicy; policy != nil && policy.Schedule.Duration <= 0 {
+		ctrl.LoggerFrom(ctx).Info("denied: non positive snapshot schedule", "sharedvolume", shvol.Name)
+		return kerrors.NewForbidden(schema.GroupResource{}, shvol.Name,
+			fmt.Errorf("the snapshot schedule of the shared volume must be positive"))
+	}
+
+	return nil
+}
+
+// validateAccessControl checks that the access control rules of the sharedvolume are well formed:
+// roles are meaningful only along with a workspace, and template references shall specify the template name.
+func (sv *SharedVolumeValidator) validateAccessControl(ctx context.Context, shvol *clv1alpha2.SharedVolume) error {
+	for i := range shvol.Spec.AccessControl {
+		rule := &shvol.Spec.AccessControl[i]
+
+		var err error
+		switch {
+		case rule.Role != "" && rule.Workspace == "":
+			err = fmt.Errorf("access control rule %d specifies a role without a workspace", i)
+		case rule.Template != nil && rule.Template.Name == "":
+			err = fmt.Errorf("access control rule %d specifies a template without a name", i)
+		}
+
+		if err != nil {
+			ctrl.LoggerFrom(ctx).Info("denied: invalid access control rule", "sharedvolume", shvol.Name, "rule", i)
+			return kerrors.NewForbidden(schema.GroupResource{}, shvol.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateDelete validates a sharedvolume deletion request, which is always allowed
+// (the controller prevents the deletion of the volumes still mounted by some templates).
+func (sv *SharedVolumeValidator) ValidateDelete(
+	_ context.Context,
+	_ runtime.Object,
+) (admission.Warnings, error) {
+	return nil, nil
+}
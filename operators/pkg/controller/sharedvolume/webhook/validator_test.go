@@ -0,0 +1,145 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/sharedvolume/webhook"
+)
+
+var _ = Describe("SharedVolumeValidator", func() {
+	var (
+		ctx       context.Context
+		validator *webhook.SharedVolumeValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		validator = &webhook.SharedVolumeValidator{}
+	})
+
+	Describe("Creating a shared volume", func() {
+		It("Should allow a positive size", func() {
+			_, err := validator.ValidateCreate(ctx, forgeSharedVolume("1Gi"))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny a zero size", func() {
+			_, err := validator.ValidateCreate(ctx, forgeSharedVolume("0"))
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+		})
+	})
+
+	Describe("Updating a shared volume", func() {
+		It("Should allow expanding the volume", func() {
+			_, err := validator.ValidateUpdate(ctx, forgeSharedVolume("1Gi"), forgeSharedVolume("2Gi"))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should allow keeping the same size, even if expressed differently", func() {
+			_, err := validator.ValidateUpdate(ctx, forgeSharedVolume("1Gi"), forgeSharedVolume("1024Mi"))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny shrinking the volume", func() {
+			_, err := validator.ValidateUpdate(ctx, forgeSharedVolume("2Gi"), forgeSharedVolume("1Gi"))
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("cannot be reduced"))
+		})
+	})
+
+	Describe("Configuring the snapshots", func() {
+		It("Should allow a positive snapshot schedule", func() {
+			shvol := forgeSharedVolume("1Gi")
+			shvol.Spec.SnapshotPolicy = &clv1alpha2.SharedVolumeSnapshotPolicy{Schedule: metav1.Duration{Duration: time.Hour}, Retention: 3}
+			_, err := validator.ValidateCreate(ctx, shvol)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny a zero snapshot schedule", func() {
+			shvol := forgeSharedVolume("1Gi")
+			shvol.Spec.SnapshotPolicy = &clv1alpha2.SharedVolumeSnapshotPolicy{Retention: 3}
+			_, err := validator.ValidateCreate(ctx, shvol)
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("Should allow updating a volume restored from a snapshot", func() {
+			oldShvol, shvol := forgeSharedVolume("1Gi"), forgeSharedVolume("2Gi")
+			oldShvol.Spec.RestoreFrom, shvol.Spec.RestoreFrom = "snapshot", "snapshot"
+			_, err := validator.ValidateUpdate(ctx, oldShvol, shvol)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny changing the snapshot the volume is restored from", func() {
+			shvol := forgeSharedVolume("1Gi")
+			shvol.Spec.RestoreFrom = "snapshot"
+			_, err := validator.ValidateUpdate(ctx, forgeSharedVolume("1Gi"), shvol)
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("cannot be changed"))
+		})
+	})
+
+	Describe("Configuring the access control rules", func() {
+		forgeWithRule := func(rule clv1alpha2.SharedVolumeAccessRule) *clv1alpha2.SharedVolume {
+			shvol := forgeSharedVolume("1Gi")
+			shvol.Spec.AccessControl = []clv1alpha2.SharedVolumeAccessRule{rule}
+			return shvol
+		}
+
+		It("Should allow a rule granting access to a workspace role", func() {
+			_, err := validator.ValidateCreate(ctx, forgeWithRule(clv1alpha2.SharedVolumeAccessRule{
+				Workspace: "ws", Role: clv1alpha2.Manager, Access: clv1alpha2.SharedVolumeAccessReadWrite,
+			}))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should allow a rule granting access to a template", func() {
+			_, err := validator.ValidateCreate(ctx, forgeWithRule(clv1alpha2.SharedVolumeAccessRule{
+				Template: &clv1alpha2.GenericRef{Name: "tmpl"}, Access: clv1alpha2.SharedVolumeAccessReadOnly,
+			}))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should allow a catch-all rule matching every tenant", func() {
+			_, err := validator.ValidateCreate(ctx, forgeWithRule(clv1alpha2.SharedVolumeAccessRule{
+				Access: clv1alpha2.SharedVolumeAccessReadOnly,
+			}))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny a rule with a role but no workspace", func() {
+			_, err := validator.ValidateCreate(ctx, forgeWithRule(clv1alpha2.SharedVolumeAccessRule{
+				Tenant: "tester", Role: clv1alpha2.User, Access: clv1alpha2.SharedVolumeAccessReadOnly,
+			}))
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("without a workspace"))
+		})
+
+		It("Should deny an update introducing a template without name", func() {
+			_, err := validator.ValidateUpdate(ctx, forgeSharedVolume("1Gi"), forgeWithRule(clv1alpha2.SharedVolumeAccessRule{
+				Template: &clv1alpha2.GenericRef{Namespace: "workspace-test"}, Access: clv1alpha2.SharedVolumeAccessReadOnly,
+			}))
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,120 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook implements the webhook handlers for template resources.
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/imagelist"
+)
+
+// TemplateValidator implements a validating webhook for Template resources, warning about the
+// environments (and additional disks) referring to image versions deprecated in the ImageLists.
+type TemplateValidator struct {
+	admission.CustomValidator
+	Client client.Client
+}
+
+// deprecationWarnings returns a warning for each image (of the environments and of their additional disks)
+// of the template corresponding to a deprecated version.
+func deprecationWarnings(ctx context.Context, template *clv1alpha2.Template, cl client.Client) (admission.Warnings, error) {
+	var warnings admission.Warnings
+
+	imageLists := &clv1alpha1.ImageListList{}
+	if err := cl.List(ctx, imageLists); err != nil {
+		return warnings, fmt.Errorf("failed to list image lists: %w", err)
+	}
+
+	deprecation := func(image string) *clv1alpha1.ImageVersionStatus {
+		ref := imagelist.ParseImageReference(image)
+		for j := range imageLists.Items {
+			if status := imagelist.FindImageVersionStatus(&imageLists.Items[j], ref); status != nil && status.Deprecated {
+				return status
+			}
+		}
+		return nil
+	}
+
+	for i := range template.Spec.EnvironmentList {
+		env := &template.Spec.EnvironmentList[i]
+		if status := deprecation(env.Image); status != nil {
+			warnings = append(warnings, fmt.Sprintf("environment %s uses the deprecated image %s (%s)", env.Name, env.Image, status.DeprecationReason))
+		}
+		for j := range env.AdditionalDisks {
+			disk := &env.AdditionalDisks[j]
+			if disk.Image == "" {
+				continue
+			}
+			if status := deprecation(disk.Image); status != nil {
+				warnings = append(warnings, fmt.Sprintf("additional disk %s of environment %s uses the deprecated image %s (%s)",
+					disk.Name, env.Name, disk.Image, status.DeprecationReason))
+			}
+		}
+	}
+
+	return warnings, nil
+}
+
+// ValidateCreate warns about the deprecated images used by a new template.
+func (tv *TemplateValidator) ValidateCreate(
+	ctx context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	template, ok := obj.(*clv1alpha2.Template)
+	if !ok {
+		return nil, fmt.Errorf("expected Template resource but got %T", obj)
+	}
+
+	return tv.warn(ctx, template)
+}
+
+// ValidateUpdate warns about the deprecated images used by an updated template.
+func (tv *TemplateValidator) ValidateUpdate(
+	ctx context.Context,
+	_, newObj runtime.Object,
+) (admission.Warnings, error) {
+	template, ok := newObj.(*clv1alpha2.Template)
+	if !ok {
+		return nil, fmt.Errorf("expected Template resource but got %T", newObj)
+	}
+
+	return tv.warn(ctx, template)
+}
+
+// ValidateDelete always allows the deletion of templates.
+func (tv *TemplateValidator) ValidateDelete(
+	_ context.Context,
+	_ runtime.Object,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// warn returns the deprecation warnings for the given template. Deprecated images never cause
+// the request to be denied, and failures retrieving the ImageLists are reported as warnings.
+func (tv *TemplateValidator) warn(ctx context.Context, template *clv1alpha2.Template) (admission.Warnings, error) {
+	warnings, err := deprecationWarnings(ctx, template, tv.Client)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("unable to check the deprecation of the images: %v", err))
+	}
+	return warnings, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/template/webhook"
+)
+
+func TestTemplateValidator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TemplateValidator Suite")
+}
+
+var _ = Describe("TemplateValidator", func() {
+	var (
+		ctx       context.Context
+		validator *webhook.TemplateValidator
+	)
+
+	template := func(image string) *clv1alpha2.Template {
+		return &clv1alpha2.Template{
+			ObjectMeta: metav1.ObjectMeta{Name: testTemplate, Namespace: testNamespace},
+			Spec: clv1alpha2.TemplateSpec{
+				EnvironmentList: []clv1alpha2.Environment{{Name: "env1", Image: image}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		imageList := &clv1alpha1.ImageList{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry"},
+			Spec: clv1alpha1.ImageListSpec{
+				RegistryName: testRegistry,
+				Images:       []clv1alpha1.ImageListItem{{Name: "desktop", Versions: []string{"v1", "v2"}}},
+			},
+			Status: clv1alpha1.ImageListStatus{
+				Images: []clv1alpha1.ImageStatus{{Name: "desktop", Versions: []clv1alpha1.ImageVersionStatus{
+					{Version: "v1", Deprecated: true, DeprecationReason: "superseded by the 1 most recent versions"},
+					{Version: "v2"},
+				}}},
+			},
+		}
+		validator = &webhook.TemplateValidator{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(imageList).Build(),
+		}
+	})
+
+	It("should warn about the creation of templates using deprecated images", func() {
+		warnings, err := validator.ValidateCreate(ctx, template(testRegistry+"/desktop:v1"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("superseded"))
+	})
+
+	It("should allow the update of templates using current images without warnings", func() {
+		warnings, err := validator.ValidateUpdate(ctx, template(testRegistry+"/desktop:v1"), template(testRegistry+"/desktop:v2"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("should warn about the additional disks using deprecated images", func() {
+		tmpl := template(testRegistry + "/desktop:v2")
+		tmpl.Spec.EnvironmentList[0].AdditionalDisks = []clv1alpha2.AdditionalDisk{
+			{Name: "data", Image: testRegistry + "/desktop:v1"},
+			{Name: "blank"},
+		}
+
+		warnings, err := validator.ValidateCreate(ctx, tmpl)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(And(ContainSubstring("additional disk data"), ContainSubstring("superseded"))))
+	})
+
+	It("should ignore the images not belonging to any image list", func() {
+		warnings, err := validator.ValidateCreate(ctx, template("ghcr.io/crownlabs/desktop:v1"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+})
@@ -0,0 +1,254 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	gocloak13 "github.com/Nerzal/gocloak/v13"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+)
+
+const (
+	// EventKeycloakRoleDrift -> the reason of the events generated when the Keycloak roles of a tenant drifted.
+	EventKeycloakRoleDrift = "KeycloakRoleDrift"
+	// EventKeycloakUserMissing -> the reason of the events generated when the Keycloak user of a tenant no longer exists.
+	EventKeycloakUserMissing = "KeycloakUserMissing"
+	// EventKeycloakDriftCorrected -> the reason of the events generated when the drift of a tenant has been corrected.
+	EventKeycloakDriftCorrected = "KeycloakDriftCorrected"
+
+	serviceAccountUsernamePrefix = "service-account-"
+)
+
+// DriftAuditor periodically compares the workspace roles assigned in Keycloak to each Tenant with
+// the ones it is entitled to, to detect the changes performed manually in Keycloak. The drifts are
+// reported through metrics and events, and optionally corrected. Additionally, the Keycloak users
+// not associated with any Tenant are reported as orphans (and never deleted automatically).
+type DriftAuditor struct {
+	*Reconciler
+	EventsRecorder record.EventRecorder
+	Interval       time.Duration
+	Correct        bool // If true, the drifted roles are realigned and the missing users recreated.
+}
+
+// TenantDrift describes the differences between a Tenant and the corresponding Keycloak user.
+type TenantDrift struct {
+	Tenant          string
+	UserMissing     bool     // The Keycloak user associated with the tenant no longer exists.
+	MissingRoles    []string // The roles the tenant is entitled to, but not assigned in Keycloak.
+	UnexpectedRoles []string // The roles assigned in Keycloak, which the tenant is not entitled to.
+}
+
+// DriftReport summarizes the outcome of an audit.
+type DriftReport struct {
+	Tenants     []TenantDrift
+	OrphanUsers []string // The Keycloak users not associated with any Tenant.
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, to avoid concurrent audits.
+func (a *DriftAuditor) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements the Runnable interface, performing an audit every Interval.
+func (a *DriftAuditor) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("keycloak-drift-auditor")
+	log.Info("starting the keycloak drift auditor", "interval", a.Interval, "correct", a.Correct)
+
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("stopping the keycloak drift auditor")
+			return nil
+		case <-ticker.C:
+			if _, err := a.Audit(ctrl.LoggerInto(ctx, log)); err != nil {
+				tnOpinternalErrors.WithLabelValues("tenant", "keycloak-drift-audit").Inc()
+				log.Error(err, "failed auditing keycloak drift")
+			}
+		}
+	}
+}
+
+// Audit compares the Tenants with the Keycloak users, updating the drift metrics and emitting
+// the corresponding events. If Correct is set, the detected drifts are corrected as well.
+func (a *DriftAuditor) Audit(ctx context.Context) (*DriftReport, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if !a.KeycloakActor.IsInitialized() {
+		log.Info("Keycloak actor is not initialized, skipping drift audit")
+		return &DriftReport{}, nil
+	}
+
+	var tenants clv1alpha2.TenantList
+	if err := a.List(ctx, &tenants); err != nil {
+		return nil, fmt.Errorf("failed listing tenants: %w", err)
+	}
+
+	report := &DriftReport{}
+	var errs []error
+	for i := range tenants.Items {
+		tn := &tenants.Items[i]
+		if !a.TargetLabel.IsIncluded(tn.Labels) || !tn.DeletionTimestamp.IsZero() || !tn.Status.Keycloak.UserCreated.Created {
+			continue
+		}
+
+		drift, err := a.auditTenant(ctx, log.WithValues("tenant", tn.Name), tn)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if drift != nil {
+			report.Tenants = append(report.Tenants, *drift)
+		}
+	}
+
+	orphans, err := a.orphanUsers(ctx, tenants.Items)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	report.OrphanUsers = orphans
+	for _, username := range orphans {
+		log.Info("Keycloak user not associated with any tenant", "username", username)
+	}
+
+	report.updateMetrics()
+	log.Info("keycloak drift audit completed", "drifted", len(report.Tenants), "orphans", len(report.OrphanUsers))
+	return report, errors.Join(errs...)
+}
+
+// auditTenant detects (and optionally corrects) the drift of a single tenant, returning nil if none is found.
+func (a *DriftAuditor) auditTenant(
+	ctx context.Context,
+	log logr.Logger,
+	tn *clv1alpha2.Tenant,
+) (*TenantDrift, error) {
+	drift := TenantDrift{Tenant: tn.Name}
+
+	currentRoles, err := a.obtainCurrentRoles(ctx, log, tn)
+	switch {
+	case err != nil && err.Error() == fmt.Sprintf("%d", http.StatusNotFound):
+		drift.UserMissing = true
+		a.EventsRecorder.Eventf(tn, corev1.EventTypeWarning, EventKeycloakUserMissing,
+			"Keycloak user %s no longer exists", tn.Status.Keycloak.UserCreated.Name)
+	case err != nil:
+		return nil, fmt.Errorf("failed retrieving keycloak roles of tenant %s: %w", tn.Name, err)
+	default:
+		wantedRoles := a.obtainWantedRoles(tn)
+		for _, role := range wantedRoles {
+			if !slices.ContainsFunc(currentRoles, func(current *gocloak13.Role) bool { return *current.Name == role }) {
+				drift.MissingRoles = append(drift.MissingRoles, role)
+			}
+		}
+		for _, role := range a.getRolesToDelete(wantedRoles, currentRoles) {
+			drift.UnexpectedRoles = append(drift.UnexpectedRoles, *role.Name)
+		}
+		if len(drift.MissingRoles) == 0 && len(drift.UnexpectedRoles) == 0 {
+			return nil, nil
+		}
+		a.EventsRecorder.Eventf(tn, corev1.EventTypeWarning, EventKeycloakRoleDrift,
+			"Keycloak roles drifted (missing: [%s], unexpected: [%s])",
+			strings.Join(drift.MissingRoles, ", "), strings.Join(drift.UnexpectedRoles, ", "))
+	}
+
+	log.Info("keycloak drift detected", "userMissing", drift.UserMissing,
+		"missingRoles", drift.MissingRoles, "unexpectedRoles", drift.UnexpectedRoles)
+	if !a.Correct {
+		return &drift, nil
+	}
+
+	if drift.UserMissing {
+		// the reconciliation recreates the user, together with the associated roles
+		if err := a.triggerReconcile(ctx, tn.Name); err != nil {
+			return &drift, err
+		}
+		keycloakDriftCorrections.WithLabelValues("user").Inc()
+		a.EventsRecorder.Event(tn, corev1.EventTypeNormal, EventKeycloakDriftCorrected, "Keycloak user recreation triggered")
+		return &drift, nil
+	}
+
+	if err := a.syncWorkspacesAuthorizationRoles(ctx, log, tn); err != nil {
+		return &drift, fmt.Errorf("failed correcting keycloak roles of tenant %s: %w", tn.Name, err)
+	}
+	keycloakDriftCorrections.WithLabelValues("roles").Inc()
+	a.EventsRecorder.Event(tn, corev1.EventTypeNormal, EventKeycloakDriftCorrected, "Keycloak roles realigned")
+	return &drift, nil
+}
+
+// orphanUsers returns the usernames of the Keycloak users not associated with any tenant, ignoring service accounts.
+func (a *DriftAuditor) orphanUsers(ctx context.Context, tenants []clv1alpha2.Tenant) ([]string, error) {
+	users, err := a.KeycloakActor.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing keycloak users: %w", err)
+	}
+
+	var orphans []string
+	for _, user := range users {
+		if user.Username == nil || strings.HasPrefix(*user.Username, serviceAccountUsernamePrefix) {
+			continue
+		}
+		if !slices.ContainsFunc(tenants, func(tn clv1alpha2.Tenant) bool { return tn.Name == *user.Username }) {
+			orphans = append(orphans, *user.Username)
+		}
+	}
+	slices.Sort(orphans)
+	return orphans, nil
+}
+
+// triggerReconcile enqueues the given tenant for reconciliation.
+func (a *DriftAuditor) triggerReconcile(ctx context.Context, name string) error {
+	if a.TriggerReconcileChannel == nil {
+		return fmt.Errorf("unable to trigger the reconciliation of tenant %s: channel not configured", name)
+	}
+
+	select {
+	case a.TriggerReconcileChannel <- event.GenericEvent{Object: &clv1alpha2.Tenant{ObjectMeta: metav1.ObjectMeta{Name: name}}}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// updateMetrics exposes the totals of the report through the drift metrics.
+func (r *DriftReport) updateMetrics() {
+	var missingUsers, missingRoles, unexpectedRoles int
+	for i := range r.Tenants {
+		if r.Tenants[i].UserMissing {
+			missingUsers++
+		}
+		missingRoles += len(r.Tenants[i].MissingRoles)
+		unexpectedRoles += len(r.Tenants[i].UnexpectedRoles)
+	}
+
+	keycloakDrift.WithLabelValues("missing_users").Set(float64(missingUsers))
+	keycloakDrift.WithLabelValues("missing_roles").Set(float64(missingRoles))
+	keycloakDrift.WithLabelValues("unexpected_roles").Set(float64(unexpectedRoles))
+	keycloakDrift.WithLabelValues("orphan_users").Set(float64(len(r.OrphanUsers)))
+}
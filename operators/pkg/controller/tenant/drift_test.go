@@ -0,0 +1,179 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant_test
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	gocloak13 "github.com/Nerzal/gocloak/v13"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/mock"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/tenant"
+)
+
+var _ = Describe("Keycloak drift audit", func() {
+	var (
+		auditor  *tenant.DriftAuditor
+		recorder *record.FakeRecorder
+		report   *tenant.DriftReport
+		auditErr error
+		correct  bool
+		trigger  chan event.GenericEvent
+	)
+
+	role := func(name string) *gocloak13.Role {
+		return &gocloak13.Role{ID: gocloak13.StringP(name + "-id"), Name: gocloak13.StringP(name)}
+	}
+
+	user := func(username string) *gocloak13.User {
+		return &gocloak13.User{ID: gocloak13.StringP(username + "-id"), Username: gocloak13.StringP(username)}
+	}
+
+	BeforeEach(func() {
+		keycloakActor = mock.NewMockKeycloakActorIface(mockCtrl)
+		keycloakActor.EXPECT().IsInitialized().Return(true).AnyTimes()
+		runReconcile = false
+		correct = false
+		trigger = make(chan event.GenericEvent, 1)
+
+		tnResource.Spec.Workspaces = []clv1alpha2.TenantWorkspaceEntry{
+			{Name: "ws1", Role: clv1alpha2.Manager},
+			{Name: "ws2", Role: clv1alpha2.User},
+		}
+		tnResource.Status.Keycloak = clv1alpha2.KeycloakStatus{
+			UserCreated:   clv1alpha2.NameCreated{Name: "user-id", Created: true},
+			UserConfirmed: true,
+		}
+	})
+
+	AfterEach(func() {
+		runReconcile = true
+	})
+
+	JustBeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		tenantReconciler.TriggerReconcileChannel = trigger
+		auditor = &tenant.DriftAuditor{
+			Reconciler:     &tenantReconciler,
+			EventsRecorder: recorder,
+			Interval:       time.Hour,
+			Correct:        correct,
+		}
+		report, auditErr = auditor.Audit(ctx)
+	})
+
+	Context("The Keycloak roles match the tenant workspaces", func() {
+		BeforeEach(func() {
+			keycloakActor.EXPECT().GetUserRoles(gomock.Any(), "user-id").Return([]*gocloak13.Role{
+				role("workspace-ws1:manager"), role("workspace-ws2:user"), role("offline_access"),
+			}, nil)
+			keycloakActor.EXPECT().ListUsers(gomock.Any()).Return([]*gocloak13.User{user(tnName)}, nil)
+		})
+
+		It("Should not report any drift", func() {
+			Expect(auditErr).ToNot(HaveOccurred())
+			Expect(report.Tenants).To(BeEmpty())
+			Expect(report.OrphanUsers).To(BeEmpty())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+	})
+
+	Context("The Keycloak roles have been modified manually", func() {
+		BeforeEach(func() {
+			keycloakActor.EXPECT().GetUserRoles(gomock.Any(), "user-id").Return([]*gocloak13.Role{
+				role("workspace-ws1:manager"), role("workspace-ws3:user"),
+			}, nil).AnyTimes()
+			keycloakActor.EXPECT().ListUsers(gomock.Any()).Return([]*gocloak13.User{
+				user(tnName), user("ghost"), user("service-account-crownlabs"),
+			}, nil)
+		})
+
+		It("Should report the drifted roles and the orphan users", func() {
+			Expect(auditErr).ToNot(HaveOccurred())
+			Expect(report.Tenants).To(ConsistOf(tenant.TenantDrift{
+				Tenant:          tnName,
+				MissingRoles:    []string{"workspace-ws2:user"},
+				UnexpectedRoles: []string{"workspace-ws3:user"},
+			}))
+			Expect(report.OrphanUsers).To(ConsistOf("ghost"))
+			Expect(recorder.Events).To(Receive(ContainSubstring(tenant.EventKeycloakRoleDrift)))
+		})
+
+		When("The correction is enabled", func() {
+			BeforeEach(func() {
+				correct = true
+				keycloakActor.EXPECT().GetRole(gomock.Any(), "workspace-ws2:user").Return(role("workspace-ws2:user"), nil)
+				keycloakActor.EXPECT().AddUserToRoles(gomock.Any(), "user-id", []*gocloak13.Role{role("workspace-ws2:user")}).Return(nil)
+				keycloakActor.EXPECT().RemoveUserFromRoles(gomock.Any(), "user-id", []*gocloak13.Role{role("workspace-ws3:user")}).Return(nil)
+			})
+
+			It("Should realign the roles", func() {
+				Expect(auditErr).ToNot(HaveOccurred())
+				Expect(report.Tenants).To(HaveLen(1))
+				Expect(recorder.Events).To(Receive(ContainSubstring(tenant.EventKeycloakRoleDrift)))
+				Expect(recorder.Events).To(Receive(ContainSubstring(tenant.EventKeycloakDriftCorrected)))
+			})
+		})
+	})
+
+	Context("The Keycloak user has been deleted manually", func() {
+		BeforeEach(func() {
+			keycloakActor.EXPECT().GetUserRoles(gomock.Any(), "user-id").Return(nil, fmt.Errorf("%d", http.StatusNotFound))
+			keycloakActor.EXPECT().ListUsers(gomock.Any()).Return([]*gocloak13.User{}, nil)
+		})
+
+		It("Should report the missing user", func() {
+			Expect(auditErr).ToNot(HaveOccurred())
+			Expect(report.Tenants).To(ConsistOf(tenant.TenantDrift{Tenant: tnName, UserMissing: true}))
+			Expect(recorder.Events).To(Receive(ContainSubstring(tenant.EventKeycloakUserMissing)))
+			Expect(trigger).ToNot(Receive())
+		})
+
+		When("The correction is enabled", func() {
+			BeforeEach(func() {
+				correct = true
+			})
+
+			It("Should trigger the reconciliation of the tenant", func() {
+				Expect(auditErr).ToNot(HaveOccurred())
+				var ev event.GenericEvent
+				Expect(trigger).To(Receive(&ev))
+				Expect(ev.Object.GetName()).To(Equal(tnName))
+			})
+		})
+	})
+
+	Context("The Keycloak users cannot be listed", func() {
+		BeforeEach(func() {
+			keycloakActor.EXPECT().GetUserRoles(gomock.Any(), "user-id").Return([]*gocloak13.Role{
+				role("workspace-ws1:manager"), role("workspace-ws2:user"),
+			}, nil)
+			keycloakActor.EXPECT().ListUsers(gomock.Any()).Return(nil, fmt.Errorf("connection refused"))
+		})
+
+		It("Should return an error, while still auditing the tenants", func() {
+			Expect(auditErr).To(HaveOccurred())
+			Expect(report.Tenants).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,101 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+)
+
+// enforceEnrollmentRequests ensures a pending EnrollmentRequest exists for each workspace the tenant is a candidate of,
+// so that the managers of the workspace can approve or reject the enrollment.
+func (r *Reconciler) enforceEnrollmentRequests(
+	ctx context.Context,
+	log logr.Logger,
+	tn *clv1alpha2.Tenant,
+) error {
+	for i := range tn.Spec.Workspaces {
+		ws := &tn.Spec.Workspaces[i]
+		// the workspaces not allowing the candidate role are marked as failing
+		if ws.Role != clv1alpha2.Candidate || slices.Contains(tn.Status.FailingWorkspaces, ws.Name) ||
+			slices.Contains(tn.Status.InactiveWorkspaces, ws.Name) {
+			continue
+		}
+
+		if err := r.enforceEnrollmentRequest(ctx, log, tn, ws.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) enforceEnrollmentRequest(
+	ctx context.Context,
+	log logr.Logger,
+	tn *clv1alpha2.Tenant,
+	workspaceName string,
+) error {
+	name := forge.GetEnrollmentRequestName(tn.Name, workspaceName)
+
+	var existing clv1alpha2.EnrollmentRequest
+	err := r.Get(ctx, types.NamespacedName{Name: name}, &existing)
+	switch {
+	case kerrors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("error getting enrollment request %s: %w", name, err)
+	case existing.Status.Phase == "" || existing.Status.Phase == clv1alpha2.EnrollmentRequestPending:
+		// the request is already waiting for a decision
+		return nil
+	default:
+		// the tenant enrolled again after the previous request has been completed
+		if err := r.Delete(ctx, &existing); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("error deleting completed enrollment request %s: %w", name, err)
+		}
+		log.Info("Deleted completed enrollment request", "request", name, "phase", existing.Status.Phase)
+	}
+
+	request := &clv1alpha2.EnrollmentRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: forge.EnrollmentRequestLabels(nil, tn.Name, workspaceName),
+		},
+		Spec: clv1alpha2.EnrollmentRequestSpec{
+			TenantRef:    clv1alpha2.GenericRef{Name: tn.Name},
+			WorkspaceRef: clv1alpha2.GenericRef{Name: workspaceName},
+		},
+	}
+	if err := ctrlutil.SetControllerReference(tn, request, r.Scheme); err != nil {
+		return fmt.Errorf("error setting owner reference of enrollment request %s: %w", name, err)
+	}
+
+	if err := r.Create(ctx, request); err != nil && !kerrors.IsAlreadyExists(err) {
+		return fmt.Errorf("error creating enrollment request %s: %w", name, err)
+	}
+	log.Info("Enrollment request created", "request", name, "workspace", workspaceName)
+
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils/mail"
+)
+
+// MembershipExpirationMailTemplatePath -> the template of the email notifying the tenant about an expiring workspace membership.
+const MembershipExpirationMailTemplatePath = "tenant_membership_expiration_notification.yaml"
+
+// MailSender is the interface used to deliver the notifications to the tenants.
+type MailSender interface {
+	SendCrownLabsMail(ctx context.Context, emailContentTemplatePath string, ph *mail.Placeholders) error
+}
+
+// notifyExpiringMemberships notifies the tenant about the workspace memberships expiring within the notice period,
+// keeping track of the notifications already delivered in the tenant status.
+func (r *Reconciler) notifyExpiringMemberships(
+	ctx context.Context,
+	log logr.Logger,
+	tn *clv1alpha2.Tenant,
+) {
+	if r.MailClient == nil || r.MembershipExpirationNotice <= 0 {
+		return
+	}
+
+	var notified []string
+	noticeStart := time.Now().Add(r.MembershipExpirationNotice)
+	for _, ws := range r.getEnrolledWorkspaces(tn) {
+		if ws.ValidUntil == nil || ws.ValidUntil.After(noticeStart) {
+			// memberships renewed after the notification are notified again when approaching the new expiration
+			continue
+		}
+
+		if !slices.Contains(tn.Status.ExpiringWorkspacesNotified, ws.Name) {
+			// the failure is retried at the next reconciliation
+			if err := r.MailClient.SendCrownLabsMail(ctx, MembershipExpirationMailTemplatePath, &mail.Placeholders{
+				TenantName:    tn.Name,
+				TenantEmail:   tn.Spec.Email,
+				WorkspaceName: r.workspacePrettyName(ctx, ws.Name),
+				RemainingTime: ws.ValidUntil.Format(time.RFC1123),
+			}); err != nil {
+				log.Error(err, "Error notifying tenant about expiring workspace membership", "workspace", ws.Name)
+				tnOpinternalErrors.WithLabelValues("tenant", "membership-notification").Inc()
+				continue
+			}
+			log.Info("Notified tenant about expiring workspace membership", "workspace", ws.Name, "validUntil", ws.ValidUntil)
+		}
+		notified = append(notified, ws.Name)
+	}
+
+	tn.Status.ExpiringWorkspacesNotified = notified
+}
+
+// scheduleMembershipChanges adjusts the reconcile result, to reconcile the tenant again as soon as the validity
+// of any of its workspace memberships changes (or the expiration notice period starts).
+func (r *Reconciler) scheduleMembershipChanges(
+	tn *clv1alpha2.Tenant,
+	result ctrl.Result,
+) ctrl.Result {
+	notice := r.MembershipExpirationNotice
+	if r.MailClient == nil {
+		notice = 0
+	}
+
+	next := forge.NextWorkspaceMembershipChange(tn.Spec.Workspaces, time.Now(), notice)
+	if next.IsZero() {
+		return result
+	}
+
+	if requeueAfter := time.Until(next); result.RequeueAfter == 0 || requeueAfter < result.RequeueAfter {
+		result.RequeueAfter = requeueAfter
+	}
+	return result
+}
+
+// workspacePrettyName returns the pretty name of the workspace, falling back to its name.
+func (r *Reconciler) workspacePrettyName(ctx context.Context, name string) string {
+	var workspace clv1alpha1.Workspace
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, &workspace); err != nil || workspace.Spec.PrettyName == "" {
+		return name
+	}
+	return workspace.Spec.PrettyName
+}
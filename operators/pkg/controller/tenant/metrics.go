@@ -26,9 +26,37 @@ var (
 	},
 		[]string{"controller", "reason"},
 	)
+
+	keycloakDrift = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tenant_operator_keycloak_drift",
+		Help: "The number of drifts between the tenants and Keycloak detected by the last audit",
+	},
+		[]string{"type"},
+	)
+
+	keycloakDriftCorrections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tenant_operator_keycloak_drift_corrections",
+		Help: "The number of drifts between the tenants and Keycloak corrected by the audits",
+	},
+		[]string{"type"},
+	)
+
+	myDriveUsedBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tenant_mydrive_used_bytes",
+		Help: "The space used on the MyDrive of the tenants, as of the last measurement",
+	},
+		[]string{"tenant"},
+	)
+
+	myDriveAvailableBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tenant_mydrive_available_bytes",
+		Help: "The space available on the MyDrive of the tenants, as of the last measurement",
+	},
+		[]string{"tenant"},
+	)
 )
 
 func init() {
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(tnOpinternalErrors)
+	metrics.Registry.MustRegister(tnOpinternalErrors, keycloakDrift, keycloakDriftCorrections, myDriveUsedBytes, myDriveAvailableBytes)
 }
@@ -17,16 +17,30 @@ package tenant
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/storage"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils/mail"
+)
+
+const (
+	// MyDriveUsageMailTemplatePath -> the template of the email warning the tenant about the usage of the MyDrive.
+	MyDriveUsageMailTemplatePath = "tenant_mydrive_usage_notification.yaml"
+
+	// EventMyDriveUsageThreshold -> the reason of the events generated when the MyDrive of a tenant crosses a usage threshold.
+	EventMyDriveUsageThreshold = "MyDriveUsageThreshold"
+	// EventMyDriveUsageCollectionFailed -> the reason of the events generated when the usage of the MyDrive cannot be measured.
+	EventMyDriveUsageCollectionFailed = "MyDriveUsageCollectionFailed"
 )
 
 // enforcePersonalStorage creates the MyDrive PVC (and connected resources) for tenant's personal storage in cross-namespace.
@@ -37,13 +51,22 @@ func (r *Reconciler) enforcePersonalStorage(ctx context.Context, log logr.Logger
 		return nil
 	}
 
+	// Compute the size of the Personal PVC, considering the workspaces of the tenant
+	workspaces, err := r.getWorkspacesList(ctx, log, r.getEnrolledWorkspaces(tn))
+	if err != nil {
+		log.Error(err, "Unable to retrieve the workspaces for the MyDrive size")
+		return err
+	}
+	size := forge.MyDriveSize(r.MyDrivePVCsSize, tn.Spec.MyDriveSize, workspaces)
+
 	// Enforce the presence of the Personal PVC
-	pvc, err := r.enforceMyDrivePVC(ctx, tn)
+	pvc, err := r.enforceMyDrivePVC(ctx, tn, size)
 	if err != nil {
 		log.Error(err, "Unable to create or update PVC for tenant")
 		return err
 	}
 	log.Info("PVC enforced")
+	updateMyDriveStatus(tn, pvc)
 
 	switch pvc.Status.Phase {
 	case corev1.ClaimBound:
@@ -57,9 +80,17 @@ func (r *Reconciler) enforcePersonalStorage(ctx context.Context, log logr.Logger
 			log.Info("Tenant namespace does not exist, skipping PVC Mirror creation")
 		}
 
-		if _, err := storage.RunPVCProvisioning(ctx, log, r.Client, pvc, tn); err != nil {
+		done, err := storage.RunPVCProvisioning(ctx, log, r.Client, pvc, tn)
+		if err != nil {
 			return err
 		}
+		if !done {
+			return nil
+		}
+		if err := r.enforceMyDriveContent(ctx, log, tn, pvc); err != nil {
+			return err
+		}
+		return r.reportMyDriveUsage(ctx, log, tn, pvc)
 	case corev1.ClaimPending:
 		log.Info("PVC pending for tenant")
 	default:
@@ -69,6 +100,151 @@ func (r *Reconciler) enforcePersonalStorage(ctx context.Context, log logr.Logger
 	return nil
 }
 
+// enforceMyDriveContent populates the MyDrive PVC with the configured content, once provisioned.
+// The content is synchronized only once, without overwriting the files of the tenant, and its
+// revision is recorded as annotation of the PVC.
+func (r *Reconciler) enforceMyDriveContent(ctx context.Context, log logr.Logger, tn *clv1alpha2.Tenant, pvc *corev1.PersistentVolumeClaim) error {
+	if r.MyDriveContentSource == nil {
+		return nil
+	}
+	if _, found := pvc.Annotations[forge.ContentRevisionAnnotation]; found {
+		return nil
+	}
+
+	revision, done, err := storage.RunPVCContentSync(ctx, log, r.Client, pvc, tn, r.MyDriveContentSource, false, &r.ContentToolsOpts)
+	if err != nil || !done {
+		return err
+	}
+
+	if err := utils.PatchObject(ctx, r.Client, pvc, func(pvc *corev1.PersistentVolumeClaim) *corev1.PersistentVolumeClaim {
+		pvc.SetAnnotations(forge.UpdateMyDriveContentRevisionAnnotation(pvc.Annotations, revision))
+		return pvc
+	}); err != nil {
+		log.Error(err, "unable to update the MyDrive PVC content revision")
+		return err
+	}
+
+	log.Info("MyDrive content synchronized", "revision", revision)
+	return nil
+}
+
+// updateMyDriveStatus reflects the size requested for the MyDrive PVC, and the capacity actually provisioned, in the tenant status.
+func updateMyDriveStatus(tn *clv1alpha2.Tenant, pvc *corev1.PersistentVolumeClaim) {
+	if tn.Status.MyDrive == nil {
+		tn.Status.MyDrive = &clv1alpha2.MyDriveStatus{}
+	}
+
+	tn.Status.MyDrive.Size = pvc.Spec.Resources.Requests.Storage().DeepCopy()
+	tn.Status.MyDrive.Capacity = nil
+	if capacity, found := pvc.Status.Capacity[corev1.ResourceStorage]; found {
+		tn.Status.MyDrive.Capacity = &capacity
+	}
+}
+
+// reportMyDriveUsage periodically measures the usage of the MyDrive PVC, reporting it in the tenant status and through
+// the metrics, and warns the tenant (through an event and an email) when the usage crosses any of the configured thresholds.
+func (r *Reconciler) reportMyDriveUsage(ctx context.Context, log logr.Logger, tn *clv1alpha2.Tenant, pvc *corev1.PersistentVolumeClaim) error {
+	if r.MyDriveUsageInterval <= 0 {
+		return nil
+	}
+
+	if usage := tn.Status.MyDrive.Usage; usage != nil && time.Since(usage.LastUpdateTime.Time) < r.MyDriveUsageInterval {
+		// The usage is still fresh, the next measurement is scheduled through the reconcile result.
+		updateMyDriveUsageMetrics(tn)
+		return nil
+	}
+
+	usage, err := storage.RunPVCUsageCollection(ctx, log, r.Client, pvc, tn)
+	if err != nil {
+		// The usage is only informational, hence the failure does not affect the readiness of the tenant:
+		// the measurement is retried once the interval elapses (see scheduleMyDriveUsage).
+		r.EventsRecorder.Eventf(tn, corev1.EventTypeWarning, EventMyDriveUsageCollectionFailed, "Failed measuring the MyDrive usage: %v", err)
+		log.Error(err, "Unable to measure the MyDrive usage")
+		tnOpinternalErrors.WithLabelValues("tenant", "mydrive-usage-collection").Inc()
+		return nil
+	}
+	if usage == nil {
+		// The job is still running, and the completion triggers a new reconciliation.
+		return nil
+	}
+
+	tn.Status.MyDrive.Usage = &clv1alpha2.MyDriveUsage{
+		Used:           *resource.NewQuantity(usage.Used, resource.BinarySI),
+		Available:      *resource.NewQuantity(usage.Available, resource.BinarySI),
+		LastUpdateTime: metav1.Now(),
+	}
+	updateMyDriveUsageMetrics(tn)
+	log.Info("MyDrive usage updated", "used", tn.Status.MyDrive.Usage.Used, "available", tn.Status.MyDrive.Usage.Available)
+
+	r.notifyMyDriveUsage(ctx, log, tn)
+	return nil
+}
+
+// notifyMyDriveUsage warns the tenant when the usage of the MyDrive crosses a threshold higher than the one already notified.
+// Once the usage decreases, the notified threshold is lowered accordingly, so that crossing it again triggers a new warning.
+func (r *Reconciler) notifyMyDriveUsage(ctx context.Context, log logr.Logger, tn *clv1alpha2.Tenant) {
+	status := tn.Status.MyDrive
+	threshold := forge.MyDriveUsageThreshold(status.Usage, r.MyDriveUsageThresholds)
+	if threshold <= status.NotifiedUsageThreshold {
+		status.NotifiedUsageThreshold = threshold
+		return
+	}
+
+	total := status.Usage.Used.DeepCopy()
+	total.Add(status.Usage.Available)
+	usage := fmt.Sprintf("%s of %s (over %d%%)", status.Usage.Used.String(), total.String(), threshold)
+	r.EventsRecorder.Eventf(tn, corev1.EventTypeWarning, EventMyDriveUsageThreshold, "The MyDrive usage crossed the %d%% threshold: %s", threshold, usage)
+
+	if r.MailClient != nil {
+		// the failure is retried at the next measurement
+		if err := r.MailClient.SendCrownLabsMail(ctx, MyDriveUsageMailTemplatePath, &mail.Placeholders{
+			TenantName:  tn.Name,
+			TenantEmail: tn.Spec.Email,
+			VolumeUsage: usage,
+		}); err != nil {
+			log.Error(err, "Error notifying tenant about the MyDrive usage", "threshold", threshold)
+			tnOpinternalErrors.WithLabelValues("tenant", "mydrive-usage-notification").Inc()
+			return
+		}
+	}
+
+	log.Info("Notified tenant about the MyDrive usage", "threshold", threshold)
+	status.NotifiedUsageThreshold = threshold
+}
+
+// scheduleMyDriveUsage adjusts the reconcile result, to reconcile the tenant again when the next measurement of the MyDrive usage is due.
+func (r *Reconciler) scheduleMyDriveUsage(tn *clv1alpha2.Tenant, result ctrl.Result) ctrl.Result {
+	if r.MyDriveUsageInterval <= 0 || tn.Status.MyDrive == nil {
+		return result
+	}
+
+	// If the measurement is not up to date, it is either in progress (and its completion triggers a new reconciliation
+	// earlier) or failed: in both cases, it is attempted again after a full interval at the latest.
+	requeueAfter := r.MyDriveUsageInterval
+	if usage := tn.Status.MyDrive.Usage; usage != nil && time.Since(usage.LastUpdateTime.Time) < r.MyDriveUsageInterval {
+		requeueAfter = time.Until(usage.LastUpdateTime.Add(r.MyDriveUsageInterval))
+	}
+	if result.RequeueAfter == 0 || requeueAfter < result.RequeueAfter {
+		result.RequeueAfter = requeueAfter
+	}
+	return result
+}
+
+// updateMyDriveUsageMetrics exposes the usage reported in the status of the tenant.
+func updateMyDriveUsageMetrics(tn *clv1alpha2.Tenant) {
+	if tn.Status.MyDrive == nil || tn.Status.MyDrive.Usage == nil {
+		return
+	}
+	myDriveUsedBytes.WithLabelValues(tn.Name).Set(tn.Status.MyDrive.Usage.Used.AsApproximateFloat64())
+	myDriveAvailableBytes.WithLabelValues(tn.Name).Set(tn.Status.MyDrive.Usage.Available.AsApproximateFloat64())
+}
+
+// deleteMyDriveUsageMetrics removes the usage metrics of a deleted tenant.
+func deleteMyDriveUsageMetrics(tn *clv1alpha2.Tenant) {
+	myDriveUsedBytes.DeleteLabelValues(tn.Name)
+	myDriveAvailableBytes.DeleteLabelValues(tn.Name)
+}
+
 // enforceMyDrivePVCAbsence deletes the PVC for tenant's personal storage.
 func (r *Reconciler) enforceMyDrivePVCAbsence(ctx context.Context, log logr.Logger, tn *clv1alpha2.Tenant) error {
 	pvc := corev1.PersistentVolumeClaim{
@@ -90,6 +266,7 @@ func (r *Reconciler) enforceMyDrivePVCAbsence(ctx context.Context, log logr.Logg
 func (r *Reconciler) enforceMyDrivePVC(
 	ctx context.Context,
 	tn *clv1alpha2.Tenant,
+	size resource.Quantity,
 ) (*corev1.PersistentVolumeClaim, error) {
 	pvc := corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -101,15 +278,15 @@ func (r *Reconciler) enforceMyDrivePVC(
 	_, err := ctrlutil.CreateOrUpdate(ctx, r.Client, &pvc, func() error {
 		// Configure the PVC
 		if pvc.CreationTimestamp.IsZero() {
-			pvc.Spec = forge.MyDrivePVCSpec(r.MyDrivePVCsStorageClassName, r.MyDrivePVCsSize)
+			pvc.Spec = forge.MyDrivePVCSpec(r.MyDrivePVCsStorageClassName, size)
 		}
 		pvc.SetLabels(forge.UpdateTenantResourceCommonLabels(pvc.Labels, r.TargetLabel))
 		pvc.SetAnnotations(forge.UpdateMyDrivePVCAnnotations(pvc.Annotations, tn.Name))
 
 		// Update size only if it needs to be bigger
 		oldSize := *pvc.Spec.Resources.Requests.Storage()
-		if sizeDiff := r.MyDrivePVCsSize.Cmp(oldSize); sizeDiff > 0 || oldSize.IsZero() {
-			pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: r.MyDrivePVCsSize}
+		if sizeDiff := size.Cmp(oldSize); sizeDiff > 0 || oldSize.IsZero() {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: size}
 		}
 
 		return ctrlutil.SetControllerReference(tn, &pvc, r.Scheme)
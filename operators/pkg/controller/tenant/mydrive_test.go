@@ -24,9 +24,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/tenant"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
 )
 
@@ -84,6 +86,81 @@ var _ = Describe("MyDrive management", func() {
 			Expect(storageRequest).To(Equal(expectedStorage))
 		})
 
+		When("The tenant overrides the MyDrive size", func() {
+			BeforeEach(func() {
+				tnResource.Spec.MyDriveSize = ptr.To(resource.MustParse("20Gi"))
+			})
+
+			It("Should create the PVC with the overridden size", func() {
+				pvc := &corev1.PersistentVolumeClaim{}
+				DoesEventuallyExists(ctx, cl, client.ObjectKey{
+					Name:      forge.MyDrivePVCName(tnName),
+					Namespace: "mydrive-pvcs",
+				}, pvc, BeTrue(), timeout, interval)
+
+				Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("20Gi"))
+			})
+
+			It("Should report the size in the tenant status", func() {
+				tn := &clv1alpha2.Tenant{}
+				Expect(cl.Get(ctx, client.ObjectKey{Name: tnName}, tn)).To(Succeed())
+				Expect(tn.Status.MyDrive).ToNot(BeNil())
+				Expect(tn.Status.MyDrive.Size.String()).To(Equal("20Gi"))
+			})
+		})
+
+		When("The PVC already exists with a smaller size", func() {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              forge.MyDrivePVCName(tnName),
+					Namespace:         "mydrive-pvcs",
+					CreationTimestamp: metav1.Now(),
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+				Status: corev1.PersistentVolumeClaimStatus{
+					Phase:    corev1.ClaimPending,
+					Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			}
+
+			BeforeEach(func() {
+				addObjToObjectsList(pvc)
+			})
+
+			AfterEach(func() {
+				removeObjFromObjectsList(pvc)
+			})
+
+			It("Should not shrink the PVC", func() {
+				got := &corev1.PersistentVolumeClaim{}
+				Expect(cl.Get(ctx, forge.NamespacedNameFromObject(pvc), got)).To(Succeed())
+				Expect(got.Spec.Resources.Requests.Storage().String()).To(Equal("10Gi"))
+			})
+
+			When("The tenant MyDrive size grows", func() {
+				BeforeEach(func() {
+					tnResource.Spec.MyDriveSize = ptr.To(resource.MustParse("15Gi"))
+				})
+
+				It("Should expand the PVC", func() {
+					got := &corev1.PersistentVolumeClaim{}
+					Expect(cl.Get(ctx, forge.NamespacedNameFromObject(pvc), got)).To(Succeed())
+					Expect(got.Spec.Resources.Requests.Storage().String()).To(Equal("15Gi"))
+				})
+
+				It("Should report the capacity still provisioned in the tenant status", func() {
+					tn := &clv1alpha2.Tenant{}
+					Expect(cl.Get(ctx, client.ObjectKey{Name: tnName}, tn)).To(Succeed())
+					Expect(tn.Status.MyDrive.Size.String()).To(Equal("15Gi"))
+					Expect(tn.Status.MyDrive.Capacity.String()).To(Equal("10Gi"))
+				})
+			})
+		})
+
 		Context("When PVC is bound", func() {
 			// Create a PVC and set it to bound status
 			pvc := &corev1.PersistentVolumeClaim{
@@ -216,6 +293,153 @@ var _ = Describe("MyDrive management", func() {
 						return gotPvc.Labels[forge.ProvisionJobLabel]
 					}, timeout, interval).Should(Equal(forge.ProvisionJobValueOk))
 				})
+
+				It("Should not create the content synchronization job", func() {
+					job := &batchv1.Job{}
+					Expect(cl.Get(ctx, client.ObjectKey{
+						Name:      forge.MyDrivePVCName(tnName) + "-sync",
+						Namespace: "mydrive-pvcs",
+					}, job)).ToNot(Succeed())
+				})
+
+				When("The MyDrive content source is configured", func() {
+					BeforeEach(func() {
+						myDriveContentSource = &clv1alpha2.ContentSource{ArchiveURL: "https://example.com/welcome.tar.gz"}
+					})
+
+					It("Should create the content synchronization job", func() {
+						job := &batchv1.Job{}
+						DoesEventuallyExists(ctx, cl, client.ObjectKey{
+							Name:      forge.MyDrivePVCName(tnName) + "-sync",
+							Namespace: "mydrive-pvcs",
+						}, job, BeTrue(), timeout, interval)
+
+						Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+						Expect(job.Spec.Template.Spec.Containers[0].Name).To(Equal(forge.ContentDownloaderName))
+					})
+				})
+
+				When("The MyDrive usage monitoring is enabled", func() {
+					usageJobKey := client.ObjectKey{
+						Name:      forge.MyDrivePVCName(tnName) + "-usage",
+						Namespace: "mydrive-pvcs",
+					}
+
+					BeforeEach(func() {
+						myDriveUsageInterval = time.Hour
+					})
+
+					It("Should create the usage collection job", func() {
+						job := &batchv1.Job{}
+						DoesEventuallyExists(ctx, cl, usageJobKey, job, BeTrue(), timeout, interval)
+					})
+
+					When("The usage collection job succeeds", func() {
+						job := &batchv1.Job{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:              usageJobKey.Name,
+								Namespace:         usageJobKey.Namespace,
+								CreationTimestamp: metav1.Now(),
+							},
+							Status: batchv1.JobStatus{
+								Succeeded: 1,
+							},
+						}
+
+						pod := &corev1.Pod{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      usageJobKey.Name + "-abcde",
+								Namespace: usageJobKey.Namespace,
+								Labels:    map[string]string{batchv1.JobNameLabel: usageJobKey.Name},
+							},
+							Status: corev1.PodStatus{
+								Phase: corev1.PodSucceeded,
+								ContainerStatuses: []corev1.ContainerStatus{{
+									State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+										// 9Gi used out of 10Gi (i.e., 90%)
+										Message: "nfs 10485760 9437184 1048576 90% /data\n",
+									}},
+								}},
+							},
+						}
+
+						BeforeEach(func() {
+							addObjToObjectsList(job)
+							addObjToObjectsList(pod)
+						})
+
+						AfterEach(func() {
+							removeObjFromObjectsList(job)
+							removeObjFromObjectsList(pod)
+						})
+
+						It("Should report the usage in the tenant status", func() {
+							tn := &clv1alpha2.Tenant{}
+							Expect(cl.Get(ctx, client.ObjectKey{Name: tnName}, tn)).To(Succeed())
+							Expect(tn.Status.MyDrive.Usage).ToNot(BeNil())
+							Expect(tn.Status.MyDrive.Usage.Used.String()).To(Equal("9Gi"))
+							Expect(tn.Status.MyDrive.Usage.Available.String()).To(Equal("1Gi"))
+						})
+
+						It("Should warn the tenant about the crossed threshold", func() {
+							tn := &clv1alpha2.Tenant{}
+							Expect(cl.Get(ctx, client.ObjectKey{Name: tnName}, tn)).To(Succeed())
+							Expect(tn.Status.MyDrive.NotifiedUsageThreshold).To(Equal(80))
+
+							Expect(eventsRecorder.Events).To(Receive(ContainSubstring(tenant.EventMyDriveUsageThreshold)))
+							Expect(mailSender.Sent).To(HaveLen(1))
+							Expect(mailSender.Sent[0].VolumeUsage).To(ContainSubstring("80%"))
+						})
+
+						It("Should delete the usage collection job", func() {
+							Expect(cl.Get(ctx, usageJobKey, &batchv1.Job{})).ToNot(Succeed())
+						})
+
+						When("The tenant has already been warned about the threshold", func() {
+							BeforeEach(func() {
+								tnResource.Status.MyDrive = &clv1alpha2.MyDriveStatus{NotifiedUsageThreshold: 80}
+							})
+
+							It("Should not warn the tenant again", func() {
+								Expect(eventsRecorder.Events).ToNot(Receive())
+								Expect(mailSender.Sent).To(BeEmpty())
+							})
+						})
+					})
+
+					When("The usage collection job fails", func() {
+						job := &batchv1.Job{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:              usageJobKey.Name,
+								Namespace:         usageJobKey.Namespace,
+								CreationTimestamp: metav1.Now(),
+							},
+							Status: batchv1.JobStatus{
+								Failed: forge.UsageJobMaxRetries + 1,
+							},
+						}
+
+						BeforeEach(func() {
+							addObjToObjectsList(job)
+						})
+
+						AfterEach(func() {
+							removeObjFromObjectsList(job)
+						})
+
+						It("Should not prevent the tenant from becoming ready", func() {
+							tn := &clv1alpha2.Tenant{}
+							Expect(cl.Get(ctx, client.ObjectKey{Name: tnName}, tn)).To(Succeed())
+							Expect(tn.Status.Ready).To(BeTrue())
+							Expect(tn.Status.MyDrive.Usage).To(BeNil())
+						})
+
+						It("Should report the failure and delete the usage collection job", func() {
+							Expect(eventsRecorder.Events).To(Receive(ContainSubstring(tenant.EventMyDriveUsageCollectionFailed)))
+							Expect(cl.Get(ctx, usageJobKey, &batchv1.Job{})).ToNot(Succeed())
+						})
+					})
+				})
 			})
 
 			Context("When provisioning job fails", func() {
@@ -28,6 +28,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -56,6 +57,10 @@ type Reconciler struct {
 	MyDrivePVCsSize             resource.Quantity
 	MyDrivePVCsStorageClassName string
 	MyDrivePVCsNamespace        string
+	MyDriveContentSource        *clv1alpha2.ContentSource // The content the new MyDrive volumes are populated with (disabled if nil).
+	MyDriveUsageInterval        time.Duration             // How often the usage of the MyDrive volumes is measured (disabled if zero).
+	MyDriveUsageThresholds      []int                     // The usage thresholds (in percentage) the tenants are warned about, when crossed.
+	ContentToolsOpts            forge.ContainerEnvOpts
 	MirrorPVCStorageClassName   string
 	KeycloakActor               ctrlcommon.KeycloakActorIface
 	WaitUserVerification        bool // If true, the reconciliation will wait for the user to be verified in Keycloak before creating resources.
@@ -63,6 +68,9 @@ type Reconciler struct {
 	BaseWorkspaces              []string
 	Concurrency                 int
 	Reschedule                  ctrlcommon.Rescheduler
+	MailClient                  MailSender    // Delivers the notifications to the tenants (disabled if nil).
+	MembershipExpirationNotice  time.Duration // How long before the expiration of a workspace membership the tenant is notified.
+	EventsRecorder              record.EventRecorder
 }
 
 // Reconcile reconciles the state of a tenant resource.
@@ -140,6 +148,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return reschedule, fmt.Errorf("error enforcing workspaces for tenant %s: %w", tn.Name, err)
 	}
 
+	// manage the validity period of the workspace memberships
+	r.notifyExpiringMemberships(ctx, log, &tn)
+	reschedule = r.scheduleMembershipChanges(&tn, reschedule)
+
+	// manage the requests of enrollment in the workspaces requiring approval
+	if err := r.enforceEnrollmentRequests(ctx, log, &tn); err != nil {
+		log.Error(err, "Error enforcing enrollment requests for tenant", "tenant", tn.Name)
+		return reschedule, fmt.Errorf("error enforcing enrollment requests for tenant %s: %w", tn.Name, err)
+	}
+
 	// check if the tenant is already been provisioned in Keycloak
 	// - if not, create the tenant in Keycloak
 	// - if yes, check if the tenant is verified
@@ -224,6 +242,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		log.Error(err, "Error creating MyDrive PVC for tenant", "tenant", tn.Name)
 		return reschedule, err
 	}
+	reschedule = r.scheduleMyDriveUsage(&tn, reschedule)
 
 	tn.Status.Ready = !hasErrors
 
@@ -314,6 +333,7 @@ func (r *Reconciler) deleteTenantDependencies(
 		log.Error(err, "Error deleting MyDrive PVC for tenant", "tenant", tn.Name)
 		return fmt.Errorf("error deleting MyDrive PVC for tenant %s: %w", tn.Name, err)
 	}
+	deleteMyDriveUsageMetrics(tn)
 
 	// remove the tenant from Keycloak
 	err := r.deleteTenantInKeycloak(ctx, log, tn)
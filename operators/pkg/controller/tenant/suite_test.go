@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -40,6 +41,7 @@ import (
 	ctrlcommon "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/common"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/mock"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/tenant"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils/mail"
 )
 
 const (
@@ -57,10 +59,14 @@ var (
 	cl               client.Client
 	mockCtrl         *gomock.Controller
 	keycloakActor    *mock.MockKeycloakActorIface
+	mailSender       *fakeMailSender
 	tenantReconciler tenant.Reconciler
 
 	tnResource             *clv1alpha2.Tenant
 	tnReconcileErrExpected gomegaTypes.GomegaMatcher
+	myDriveContentSource   *clv1alpha2.ContentSource
+	myDriveUsageInterval   time.Duration
+	eventsRecorder         *record.FakeRecorder
 
 	objects []client.Object
 
@@ -86,6 +92,7 @@ var _ = BeforeEach(func() {
 	keycloakActor = mock.NewMockKeycloakActorIface(mockCtrl)
 
 	keycloakActor.EXPECT().IsInitialized().Return(false).AnyTimes()
+	mailSender = &fakeMailSender{}
 
 	tnResource = &clv1alpha2.Tenant{
 		ObjectMeta: metav1.ObjectMeta{
@@ -102,6 +109,9 @@ var _ = BeforeEach(func() {
 		},
 	}
 	tnReconcileErrExpected = Not(HaveOccurred())
+	myDriveContentSource = nil
+	myDriveUsageInterval = 0
+	eventsRecorder = record.NewFakeRecorder(100)
 })
 
 var _ = AfterEach(func() {
@@ -133,6 +143,12 @@ var _ = JustBeforeEach(func() {
 		MyDrivePVCsNamespace:        "mydrive-pvcs",
 		MyDrivePVCsSize:             resource.MustParse("5Gi"),
 		MyDrivePVCsStorageClassName: "nfs",
+		MyDriveContentSource:        myDriveContentSource,
+		MyDriveUsageInterval:        myDriveUsageInterval,
+		MyDriveUsageThresholds:      []int{80, 95},
+		EventsRecorder:              eventsRecorder,
+		MailClient:                  mailSender,
+		MembershipExpirationNotice:  24 * time.Hour,
 	}
 
 	if !runReconcile {
@@ -181,6 +197,16 @@ func timePtr(t metav1.Time) *metav1.Time {
 	return &t
 }
 
+// fakeMailSender records the notifications instead of delivering them.
+type fakeMailSender struct {
+	Sent []mail.Placeholders
+}
+
+func (f *fakeMailSender) SendCrownLabsMail(_ context.Context, _ string, ph *mail.Placeholders) error {
+	f.Sent = append(f.Sent, *ph)
+	return nil
+}
+
 // GinkgoLogWriter implements logr.LogSink.
 type GinkgoLogWriter struct {
 	kandv string
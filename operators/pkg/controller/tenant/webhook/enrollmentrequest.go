@@ -0,0 +1,127 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+)
+
+// EnrollmentRequestValidator implements a validating webhook for EnrollmentRequest resources,
+// ensuring that only the managers of the target workspace can approve or reject the requests.
+type EnrollmentRequestValidator struct {
+	admission.CustomValidator
+	TenantWebhook
+}
+
+// ValidateCreate validates a new enrollment request creation request: requests are created by the operator only.
+func (ev *EnrollmentRequestValidator) ValidateCreate(
+	ctx context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	request, ok := obj.(*clv1alpha2.EnrollmentRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected an EnrollmentRequest object, got %T", obj)
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admission request from context: %w", err)
+	}
+
+	if !ev.CheckWebhookOverride(&req) {
+		ctrl.LoggerFrom(ctx).Info("denied: enrollment request not created by the operator", "request", request.Name)
+		return nil, kerrors.NewForbidden(schema.GroupResource{}, request.Name,
+			fmt.Errorf("enrollment requests are created automatically when enrolling in a workspace with the candidate role"))
+	}
+
+	return nil, nil
+}
+
+// ValidateUpdate validates an enrollment request update request:
+// - the tenant and the workspace cannot be changed;
+// - the decision can be taken only once, by a manager of the workspace, and rejections require a reason.
+func (ev *EnrollmentRequestValidator) ValidateUpdate(
+	ctx context.Context,
+	oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	oldRequest, ok := oldObj.(*clv1alpha2.EnrollmentRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected an EnrollmentRequest object, got %T", oldObj)
+	}
+	newRequest, ok := newObj.(*clv1alpha2.EnrollmentRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected an EnrollmentRequest object, got %T", newObj)
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admission request from context: %w", err)
+	}
+
+	log := ctrl.LoggerFrom(ctx).WithValues("request", newRequest.Name, "username", req.UserInfo.Username)
+	if ev.CheckWebhookOverride(&req) {
+		log.Info("overriding validation")
+		return admission.Warnings{"webhook check overridden"}, nil
+	}
+
+	if reflect.DeepEqual(oldRequest.Spec, newRequest.Spec) {
+		return nil, nil
+	}
+
+	forbidden := func(err error) (admission.Warnings, error) {
+		log.Info("denied: invalid enrollment request change", "reason", err.Error())
+		return nil, kerrors.NewForbidden(schema.GroupResource{}, newRequest.Name, err)
+	}
+
+	if oldRequest.Spec.TenantRef != newRequest.Spec.TenantRef || oldRequest.Spec.WorkspaceRef != newRequest.Spec.WorkspaceRef {
+		return forbidden(fmt.Errorf("the tenant and the workspace of an enrollment request cannot be changed"))
+	}
+	if oldRequest.Spec.Decision != "" || (oldRequest.Status.Phase != "" && oldRequest.Status.Phase != clv1alpha2.EnrollmentRequestPending) {
+		return forbidden(fmt.Errorf("the enrollment request has already been completed, the decision cannot be changed"))
+	}
+	if newRequest.Spec.Decision == clv1alpha2.EnrollmentRejected && newRequest.Spec.Reason == "" {
+		return forbidden(fmt.Errorf("a reason is required to reject an enrollment request"))
+	}
+
+	manager, err := ev.GetClusterTenant(ctx, req.UserInfo.Username)
+	if err != nil {
+		log.Error(err, "failed fetching a (manager) tenant associated to the current actor")
+		return nil, fmt.Errorf("could not fetch a tenant for the current user: %w", err)
+	}
+	if !ev.isInheritedWorkspaceManager(ctx, manager, newRequest.Spec.WorkspaceRef.Name) {
+		return forbidden(fmt.Errorf("you are not a manager for workspace %s, so you cannot decide on its enrollment requests", newRequest.Spec.WorkspaceRef.Name))
+	}
+
+	log.Info("allowed", "decision", newRequest.Spec.Decision)
+	return nil, nil
+}
+
+// ValidateDelete validates an enrollment request deletion request.
+func (ev *EnrollmentRequestValidator) ValidateDelete(
+	_ context.Context,
+	_ runtime.Object,
+) (admission.Warnings, error) {
+	return nil, nil
+}
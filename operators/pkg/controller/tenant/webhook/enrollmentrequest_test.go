@@ -0,0 +1,189 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/tenant/webhook"
+)
+
+var _ = Describe("EnrollmentRequest validator webhook", func() {
+	var (
+		validator  *webhook.EnrollmentRequestValidator
+		oldRequest *clv1alpha2.EnrollmentRequest
+		newRequest *clv1alpha2.EnrollmentRequest
+		username   string
+		groups     []string
+		warnings   admission.Warnings
+		err        error
+	)
+
+	BeforeEach(func() {
+		manager := &clv1alpha2.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "some-manager"},
+			Spec: clv1alpha2.TenantSpec{Workspaces: []clv1alpha2.TenantWorkspaceEntry{{
+				Name: testWorkspace,
+				Role: clv1alpha2.Manager,
+			}}},
+		}
+		user := &clv1alpha2.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "some-user"},
+			Spec: clv1alpha2.TenantSpec{Workspaces: []clv1alpha2.TenantWorkspaceEntry{{
+				Name: testWorkspace,
+				Role: clv1alpha2.User,
+			}}},
+		}
+
+		validator = &webhook.EnrollmentRequestValidator{TenantWebhook: webhook.TenantWebhook{
+			Client:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(manager, user).Build(),
+			BypassGroups: bypassGroups,
+		}}
+
+		oldRequest = &clv1alpha2.EnrollmentRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: testTenantName + "." + testWorkspace},
+			Spec: clv1alpha2.EnrollmentRequestSpec{
+				TenantRef:    clv1alpha2.GenericRef{Name: testTenantName},
+				WorkspaceRef: clv1alpha2.GenericRef{Name: testWorkspace},
+			},
+			Status: clv1alpha2.EnrollmentRequestStatus{Phase: clv1alpha2.EnrollmentRequestPending},
+		}
+		newRequest = oldRequest.DeepCopy()
+		username = "some-manager"
+		groups = nil
+	})
+
+	requestContext := func(op admissionv1.Operation) context.Context {
+		return admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: op,
+			Name:      newRequest.Name,
+			UserInfo:  authenticationv1.UserInfo{Username: username, Groups: groups},
+		}})
+	}
+
+	Describe("The creation of a request", func() {
+		JustBeforeEach(func() {
+			warnings, err = validator.ValidateCreate(requestContext(admissionv1.Create), newRequest)
+		})
+
+		It("should be denied to users", func() {
+			Expect(err).To(HaveOccurred())
+		})
+
+		When("the request is performed by the operator", func() {
+			BeforeEach(func() { groups = bypassGroups })
+
+			It("should be allowed", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("The update of a request", func() {
+		JustBeforeEach(func() {
+			warnings, err = validator.ValidateUpdate(requestContext(admissionv1.Update), oldRequest, newRequest)
+		})
+
+		When("a manager of the workspace approves the request", func() {
+			BeforeEach(func() { newRequest.Spec.Decision = clv1alpha2.EnrollmentApproved })
+
+			It("should allow the change", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(warnings).To(BeEmpty())
+			})
+		})
+
+		When("a manager of the workspace rejects the request with a reason", func() {
+			BeforeEach(func() {
+				newRequest.Spec.Decision = clv1alpha2.EnrollmentRejected
+				newRequest.Spec.Reason = "not attending the course"
+			})
+
+			It("should allow the change", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		When("a manager of the workspace rejects the request without a reason", func() {
+			BeforeEach(func() { newRequest.Spec.Decision = clv1alpha2.EnrollmentRejected })
+
+			It("should deny the change", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("a user who is not a manager of the workspace approves the request", func() {
+			BeforeEach(func() {
+				username = "some-user"
+				newRequest.Spec.Decision = clv1alpha2.EnrollmentApproved
+			})
+
+			It("should deny the change", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("a manager changes a decision already taken", func() {
+			BeforeEach(func() {
+				oldRequest.Spec.Decision = clv1alpha2.EnrollmentApproved
+				newRequest.Spec.Decision = clv1alpha2.EnrollmentRejected
+				newRequest.Spec.Reason = "changed my mind"
+			})
+
+			It("should deny the change", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("a manager approves an expired request", func() {
+			BeforeEach(func() {
+				oldRequest.Status.Phase = clv1alpha2.EnrollmentRequestExpired
+				newRequest.Spec.Decision = clv1alpha2.EnrollmentApproved
+			})
+
+			It("should deny the change", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("a manager changes the target workspace", func() {
+			BeforeEach(func() { newRequest.Spec.WorkspaceRef.Name = "other-workspace" })
+
+			It("should deny the change", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the request is updated by the operator", func() {
+			BeforeEach(func() {
+				groups = bypassGroups
+				newRequest.Spec.WorkspaceRef.Name = "other-workspace"
+			})
+
+			It("should allow the change", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+})
@@ -0,0 +1,153 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+)
+
+// InstanceTransferValidator implements a validating webhook for InstanceTransfer resources, ensuring that
+// the instances can be transferred only by their owners, and that only the recipients can accept or reject them.
+type InstanceTransferValidator struct {
+	admission.CustomValidator
+	TenantWebhook
+}
+
+// ValidateCreate validates a new instance transfer creation request: the transfer shall be requested
+// by the owner of the instance, towards a different tenant, and without a decision.
+func (iv *InstanceTransferValidator) ValidateCreate(
+	ctx context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	transfer, ok := obj.(*clv1alpha2.InstanceTransfer)
+	if !ok {
+		return nil, fmt.Errorf("expected an InstanceTransfer object, got %T", obj)
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admission request from context: %w", err)
+	}
+
+	log := ctrl.LoggerFrom(ctx).WithValues("transfer", transfer.Name, "username", req.UserInfo.Username)
+	if iv.CheckWebhookOverride(&req) {
+		log.Info("overriding validation")
+		return admission.Warnings{"webhook check overridden"}, nil
+	}
+
+	forbidden := func(err error) (admission.Warnings, error) {
+		log.Info("denied: invalid instance transfer", "reason", err.Error())
+		return nil, kerrors.NewForbidden(schema.GroupResource{}, transfer.Name, err)
+	}
+
+	if transfer.Spec.Decision != "" {
+		return forbidden(fmt.Errorf("the decision on an instance transfer can be taken only by the recipient"))
+	}
+	if transfer.Spec.RecipientRef.Name == req.UserInfo.Username {
+		return forbidden(fmt.Errorf("an instance cannot be transferred to its owner"))
+	}
+
+	var instance clv1alpha2.Instance
+	if err := iv.Client.Get(ctx, forge.NamespacedNameFromGenericRef(transfer.Spec.InstanceRef), &instance); err != nil {
+		if kerrors.IsNotFound(err) {
+			return forbidden(fmt.Errorf("instance %s/%s does not exist", transfer.Spec.InstanceRef.Namespace, transfer.Spec.InstanceRef.Name))
+		}
+		log.Error(err, "failed fetching the instance to be transferred")
+		return nil, fmt.Errorf("could not fetch the instance to be transferred: %w", err)
+	}
+	if instance.Spec.Tenant.Name != req.UserInfo.Username {
+		return forbidden(fmt.Errorf("you are not the owner of instance %s, so you cannot transfer it", instance.Name))
+	}
+
+	if _, err := iv.GetClusterTenant(ctx, transfer.Spec.RecipientRef.Name); err != nil {
+		if kerrors.IsNotFound(err) {
+			return forbidden(fmt.Errorf("tenant %s does not exist", transfer.Spec.RecipientRef.Name))
+		}
+		log.Error(err, "failed fetching the recipient of the transfer")
+		return nil, fmt.Errorf("could not fetch the recipient of the transfer: %w", err)
+	}
+
+	log.Info("allowed", "recipient", transfer.Spec.RecipientRef.Name)
+	return nil, nil
+}
+
+// ValidateUpdate validates an instance transfer update request:
+// - the instance and the recipient cannot be changed;
+// - the decision can be taken only once, by the recipient, while the transfer is pending.
+func (iv *InstanceTransferValidator) ValidateUpdate(
+	ctx context.Context,
+	oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	oldTransfer, ok := oldObj.(*clv1alpha2.InstanceTransfer)
+	if !ok {
+		return nil, fmt.Errorf("expected an InstanceTransfer object, got %T", oldObj)
+	}
+	newTransfer, ok := newObj.(*clv1alpha2.InstanceTransfer)
+	if !ok {
+		return nil, fmt.Errorf("expected an InstanceTransfer object, got %T", newObj)
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admission request from context: %w", err)
+	}
+
+	log := ctrl.LoggerFrom(ctx).WithValues("transfer", newTransfer.Name, "username", req.UserInfo.Username)
+	if iv.CheckWebhookOverride(&req) {
+		log.Info("overriding validation")
+		return admission.Warnings{"webhook check overridden"}, nil
+	}
+
+	if reflect.DeepEqual(oldTransfer.Spec, newTransfer.Spec) {
+		return nil, nil
+	}
+
+	forbidden := func(err error) (admission.Warnings, error) {
+		log.Info("denied: invalid instance transfer change", "reason", err.Error())
+		return nil, kerrors.NewForbidden(schema.GroupResource{}, newTransfer.Name, err)
+	}
+
+	if oldTransfer.Spec.InstanceRef != newTransfer.Spec.InstanceRef || oldTransfer.Spec.RecipientRef != newTransfer.Spec.RecipientRef {
+		return forbidden(fmt.Errorf("the instance and the recipient of an instance transfer cannot be changed"))
+	}
+	if oldTransfer.Spec.Decision != "" || (oldTransfer.Status.Phase != "" && oldTransfer.Status.Phase != clv1alpha2.InstanceTransferPhasePending) {
+		return forbidden(fmt.Errorf("the decision on the instance transfer has already been taken, it cannot be changed"))
+	}
+	if newTransfer.Spec.RecipientRef.Name != req.UserInfo.Username {
+		return forbidden(fmt.Errorf("you are not the recipient of the instance transfer, so you cannot decide on it"))
+	}
+
+	log.Info("allowed", "decision", newTransfer.Spec.Decision)
+	return nil, nil
+}
+
+// ValidateDelete validates an instance transfer deletion request.
+func (iv *InstanceTransferValidator) ValidateDelete(
+	_ context.Context,
+	_ runtime.Object,
+) (admission.Warnings, error) {
+	return nil, nil
+}
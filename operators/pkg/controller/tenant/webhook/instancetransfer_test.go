@@ -0,0 +1,204 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/tenant/webhook"
+)
+
+var _ = Describe("InstanceTransfer validator webhook", func() {
+	var (
+		validator   *webhook.InstanceTransferValidator
+		oldTransfer *clv1alpha2.InstanceTransfer
+		newTransfer *clv1alpha2.InstanceTransfer
+		username    string
+		groups      []string
+		err         error
+	)
+
+	BeforeEach(func() {
+		owner := &clv1alpha2.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "some-owner"}}
+		recipient := &clv1alpha2.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "some-recipient"}}
+		instance := &clv1alpha2.Instance{
+			ObjectMeta: metav1.ObjectMeta{Name: "some-instance", Namespace: "tenant-some-owner"},
+			Spec: clv1alpha2.InstanceSpec{
+				Template: clv1alpha2.GenericRef{Name: "some-template", Namespace: "workspace-" + testWorkspace},
+				Tenant:   clv1alpha2.GenericRef{Name: "some-owner"},
+			},
+		}
+
+		validator = &webhook.InstanceTransferValidator{TenantWebhook: webhook.TenantWebhook{
+			Client:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(owner, recipient, instance).Build(),
+			BypassGroups: bypassGroups,
+		}}
+
+		oldTransfer = &clv1alpha2.InstanceTransfer{
+			ObjectMeta: metav1.ObjectMeta{Name: "some-transfer"},
+			Spec: clv1alpha2.InstanceTransferSpec{
+				InstanceRef:  clv1alpha2.GenericRef{Name: instance.Name, Namespace: instance.Namespace},
+				RecipientRef: clv1alpha2.GenericRef{Name: recipient.Name},
+			},
+			Status: clv1alpha2.InstanceTransferStatus{Phase: clv1alpha2.InstanceTransferPhasePending},
+		}
+		newTransfer = oldTransfer.DeepCopy()
+		username = "some-owner"
+		groups = nil
+	})
+
+	requestContext := func(op admissionv1.Operation) context.Context {
+		return admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: op,
+			Name:      newTransfer.Name,
+			UserInfo:  authenticationv1.UserInfo{Username: username, Groups: groups},
+		}})
+	}
+
+	Describe("The creation of a transfer", func() {
+		JustBeforeEach(func() {
+			_, err = validator.ValidateCreate(requestContext(admissionv1.Create), newTransfer)
+		})
+
+		It("should be allowed to the owner of the instance", func() {
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		When("the requester is not the owner of the instance", func() {
+			BeforeEach(func() { username = "some-recipient" })
+
+			It("should be denied", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the instance does not exist", func() {
+			BeforeEach(func() { newTransfer.Spec.InstanceRef.Name = "missing-instance" })
+
+			It("should be denied", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the recipient does not exist", func() {
+			BeforeEach(func() { newTransfer.Spec.RecipientRef.Name = "missing-recipient" })
+
+			It("should be denied", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the recipient is the owner of the instance", func() {
+			BeforeEach(func() { newTransfer.Spec.RecipientRef.Name = "some-owner" })
+
+			It("should be denied", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the transfer is already accepted", func() {
+			BeforeEach(func() { newTransfer.Spec.Decision = clv1alpha2.InstanceTransferAccepted })
+
+			It("should be denied", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the request is performed by the operator", func() {
+			BeforeEach(func() {
+				groups = bypassGroups
+				newTransfer.Spec.Decision = clv1alpha2.InstanceTransferAccepted
+			})
+
+			It("should be allowed", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("The update of a transfer", func() {
+		JustBeforeEach(func() {
+			_, err = validator.ValidateUpdate(requestContext(admissionv1.Update), oldTransfer, newTransfer)
+		})
+
+		When("the recipient accepts the transfer", func() {
+			BeforeEach(func() {
+				username = "some-recipient"
+				newTransfer.Spec.Decision = clv1alpha2.InstanceTransferAccepted
+			})
+
+			It("should allow the change", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		When("the owner accepts the transfer", func() {
+			BeforeEach(func() { newTransfer.Spec.Decision = clv1alpha2.InstanceTransferAccepted })
+
+			It("should deny the change", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the recipient changes a decision already taken", func() {
+			BeforeEach(func() {
+				username = "some-recipient"
+				oldTransfer.Spec.Decision = clv1alpha2.InstanceTransferRejected
+				newTransfer.Spec.Decision = clv1alpha2.InstanceTransferAccepted
+			})
+
+			It("should deny the change", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the recipient accepts a failed transfer", func() {
+			BeforeEach(func() {
+				username = "some-recipient"
+				oldTransfer.Status.Phase = clv1alpha2.InstanceTransferPhaseFailed
+				newTransfer.Spec.Decision = clv1alpha2.InstanceTransferAccepted
+			})
+
+			It("should deny the change", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the owner changes the recipient", func() {
+			BeforeEach(func() { newTransfer.Spec.RecipientRef.Name = "some-other-recipient" })
+
+			It("should deny the change", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the spec is not changed", func() {
+			BeforeEach(func() { newTransfer.Labels = map[string]string{"some": "label"} })
+
+			It("should allow the change", func() {
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+})
@@ -25,12 +25,14 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
 	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
 )
 
@@ -324,13 +326,18 @@ func (tv *TenantValidator) HandleWorkspaceEdit(
 	log := ctrl.LoggerFrom(ctx)
 
 	workspacesDiff := CalculateWorkspacesDiff(newTenant, oldTenant)
-	managerWorkspaces := mapFromWorkspacesList(manager)
+	newWorkspaces := mapFromWorkspacesList(newTenant)
 
 	for ws, changed := range workspacesDiff {
-		if changed && managerWorkspaces[ws] != clv1alpha2.Manager {
+		if changed && !tv.isInheritedWorkspaceManager(ctx, manager, ws) {
 			log.Info("denied: unexpected tenant spec change", "not-a-manager-for", ws)
 			return nil, kerrors.NewForbidden(schema.GroupResource{}, newTenant.Name, fmt.Errorf("you are not a manager for workspace %s, so you cannot change it in the tenant", ws))
 		}
+		// candidates are approved or rejected (either directly or through the EnrollmentRequest), but never created by managers
+		if changed && newWorkspaces[ws] == clv1alpha2.Candidate {
+			log.Info("denied: candidate role assigned by a manager", "workspace", ws)
+			return nil, kerrors.NewForbidden(schema.GroupResource{}, newTenant.Name, fmt.Errorf("the candidate role for workspace %s can only be requested by the tenant itself", ws))
+		}
 	}
 
 	newTenant.Spec.Workspaces = nil
@@ -345,9 +352,15 @@ func (tv *TenantValidator) HandleWorkspaceEdit(
 }
 
 func calculateWorkspacesOneWayDiff(a, b *clv1alpha2.Tenant, changes map[string]bool) map[string]bool {
-	aAsMap := mapFromWorkspacesList(a)
-	for _, v := range b.Spec.Workspaces {
-		if aAsMap[v.Name] != v.Role {
+	aAsMap := make(map[string]*clv1alpha2.TenantWorkspaceEntry, len(a.Spec.Workspaces))
+	for i := range a.Spec.Workspaces {
+		aAsMap[a.Spec.Workspaces[i].Name] = &a.Spec.Workspaces[i]
+	}
+	for i := range b.Spec.Workspaces {
+		v := &b.Spec.Workspaces[i]
+		// changes to the validity period are subject to the same rules of the changes to the role
+		if entry, ok := aAsMap[v.Name]; !ok || entry.Role != v.Role ||
+			!entry.ValidFrom.Equal(v.ValidFrom) || !entry.ValidUntil.Equal(v.ValidUntil) {
 			changes[v.Name] = true
 		}
 	}
@@ -361,6 +374,51 @@ func CalculateWorkspacesDiff(a, b *clv1alpha2.Tenant) map[string]bool {
 	return calculateWorkspacesOneWayDiff(b, a, changes)
 }
 
+// isWorkspaceManager returns whether the given tenant is a manager of the workspace.
+func isWorkspaceManager(tenant *clv1alpha2.Tenant, workspace string) bool {
+	for i := range tenant.Spec.Workspaces {
+		if tenant.Spec.Workspaces[i].Name == workspace {
+			// managers whose membership is not active are not allowed to manage the workspace
+			return tenant.Spec.Workspaces[i].Role == clv1alpha2.Manager &&
+				forge.IsWorkspaceMembershipActive(&tenant.Spec.Workspaces[i], time.Now())
+		}
+	}
+	return false
+}
+
+// isInheritedWorkspaceManager returns whether the given tenant is a manager of the workspace,
+// either directly or through one of its ancestors in the workspace hierarchy.
+func (twh *TenantWebhook) isInheritedWorkspaceManager(ctx context.Context, tenant *clv1alpha2.Tenant, workspace string) bool {
+	if isWorkspaceManager(tenant, workspace) {
+		return true
+	}
+	if twh.Client == nil {
+		return false
+	}
+
+	// the hierarchy is walked upwards through the parents, stopping in case of cycles or missing workspaces
+	visited := map[string]bool{workspace: true}
+	for current := workspace; ; {
+		var ws clv1alpha1.Workspace
+		if err := twh.Client.Get(ctx, types.NamespacedName{Name: current}, &ws); err != nil {
+			if !kerrors.IsNotFound(err) {
+				ctrl.LoggerFrom(ctx).Error(err, "failed to get workspace, ignoring inherited manager roles", "workspace", current)
+			}
+			return false
+		}
+
+		parent := forge.WorkspaceParentName(&ws)
+		if parent == "" || visited[parent] {
+			return false
+		}
+		if isWorkspaceManager(tenant, parent) {
+			return true
+		}
+		visited[parent] = true
+		current = parent
+	}
+}
+
 func mapFromWorkspacesList(tenant *clv1alpha2.Tenant) map[string]clv1alpha2.WorkspaceUserRole {
 	wss := make(map[string]clv1alpha2.WorkspaceUserRole, len(tenant.Spec.Workspaces))
 
@@ -66,6 +66,10 @@ var _ = Describe("Validator webhook", func() {
 		workspaceNAName = "test-workspace-noAutoenroll"
 		workspaceIM     *clv1alpha1.Workspace
 		workspaceIMName = "test-workspace-immediate"
+		workspaceCH     *clv1alpha1.Workspace
+		workspaceCHName = "test-workspace-child"
+		workspaceGCName = "test-workspace-grandchild"
+		workspaceCYName = "test-workspace-cycle"
 	)
 
 	BeforeEach(func() {
@@ -116,12 +120,40 @@ var _ = Describe("Validator webhook", func() {
 			},
 		}
 
+		workspaceCH = &clv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: workspaceCHName},
+			Spec: clv1alpha1.WorkspaceSpec{
+				PrettyName: "test-workspace",
+				Quota: apicommon.WorkspaceResourceQuota{
+					Instances: 1,
+				},
+				Parent: &clv1alpha1.GenericRef{Name: testWorkspace},
+			},
+		}
+
+		workspaceGC := &clv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: workspaceGCName},
+			Spec:       clv1alpha1.WorkspaceSpec{Parent: &clv1alpha1.GenericRef{Name: workspaceCHName}},
+		}
+		workspaceCY := &clv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: workspaceCYName},
+			Spec:       clv1alpha1.WorkspaceSpec{Parent: &clv1alpha1.GenericRef{Name: workspaceCYName + "-parent"}},
+		}
+		workspaceCYParent := &clv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: workspaceCYName + "-parent"},
+			Spec:       clv1alpha1.WorkspaceSpec{Parent: &clv1alpha1.GenericRef{Name: workspaceCYName}},
+		}
+
 		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
 			manager,
 			fakeManager,
 			workspaceWA,
 			workspaceNA,
 			workspaceIM,
+			workspaceCH,
+			workspaceGC,
+			workspaceCY,
+			workspaceCYParent,
 		).Build()
 
 		tnValidator = &webhook.TenantValidator{
@@ -355,6 +387,40 @@ var _ = Describe("Validator webhook", func() {
 			})
 		})
 
+		When("manager adds a child of a workspace he manages", func() {
+			BeforeEach(func() {
+				oldTenant = &clv1alpha2.Tenant{}
+				newTenant = forgeTenantWithWorkspaceUser(workspaceCHName)
+				operation = admissionv1.Update
+			})
+			It("Should allow the change, as the manager role is inherited", func() {
+				Expect(response.Allowed).To(BeTrue())
+			})
+		})
+
+		When("manager adds a grandchild of a workspace he manages", func() {
+			BeforeEach(func() {
+				oldTenant = &clv1alpha2.Tenant{}
+				newTenant = forgeTenantWithWorkspaceUser(workspaceGCName)
+				operation = admissionv1.Update
+			})
+			It("Should allow the change, as the manager role is inherited", func() {
+				Expect(response.Allowed).To(BeTrue())
+			})
+		})
+
+		When("manager adds a workspace belonging to a cyclic hierarchy he doesn't manage", func() {
+			BeforeEach(func() {
+				oldTenant = &clv1alpha2.Tenant{}
+				newTenant = forgeTenantWithWorkspaceUser(workspaceCYName)
+				operation = admissionv1.Update
+			})
+			It("Should deny the change", func() {
+				Expect(response.Allowed).To(BeFalse())
+				Expect(response.Result.Code).To(BeNumerically("==", http.StatusForbidden))
+			})
+		})
+
 		When("manager adds a workspace he doesn't manage", func() {
 			BeforeEach(func() {
 				oldTenant = &clv1alpha2.Tenant{}
@@ -381,6 +447,69 @@ var _ = Describe("Validator webhook", func() {
 			})
 		})
 
+		When("manager approves a candidate of a workspace he manages", func() {
+			BeforeEach(func() {
+				oldTenant = forgeTenantWithWorkspace(clv1alpha2.TenantWorkspaceEntry{Name: testWorkspace, Role: clv1alpha2.Candidate})
+				newTenant = forgeTenantWithWorkspaceUser(testWorkspace)
+				operation = admissionv1.Update
+			})
+			It("Should allow the change", func() {
+				Expect(response.Allowed).To(BeTrue())
+			})
+		})
+
+		When("manager assigns the candidate role in a workspace he manages", func() {
+			BeforeEach(func() {
+				oldTenant = &clv1alpha2.Tenant{}
+				newTenant = forgeTenantWithWorkspace(clv1alpha2.TenantWorkspaceEntry{Name: testWorkspace, Role: clv1alpha2.Candidate})
+				operation = admissionv1.Update
+			})
+			It("Should deny the change", func() {
+				Expect(response.Allowed).To(BeFalse())
+				Expect(response.Result.Code).To(BeNumerically("==", http.StatusForbidden))
+			})
+		})
+
+		When("manager sets the validity period of a membership in a workspace he manages", func() {
+			BeforeEach(func() {
+				oldTenant = forgeTenantWithWorkspaceUser(testWorkspace)
+				newTenant = forgeTenantWithWorkspace(clv1alpha2.TenantWorkspaceEntry{
+					Name: testWorkspace, Role: clv1alpha2.User, ValidUntil: &metav1.Time{Time: time.Now().Add(time.Hour)},
+				})
+				operation = admissionv1.Update
+			})
+			It("Should allow the change", func() {
+				Expect(response.Allowed).To(BeTrue())
+			})
+		})
+
+		When("manager extends the membership in a workspace he doesn't manage", func() {
+			BeforeEach(func() {
+				oldTenant = forgeTenantWithWorkspace(clv1alpha2.TenantWorkspaceEntry{
+					Name: "invalid-workspace", Role: clv1alpha2.User, ValidUntil: &metav1.Time{Time: time.Now().Add(time.Hour)},
+				})
+				newTenant = forgeTenantWithWorkspaceUser("invalid-workspace")
+				operation = admissionv1.Update
+			})
+			It("Should deny the change", func() {
+				Expect(response.Allowed).To(BeFalse())
+				Expect(response.Result.Code).To(BeNumerically("==", http.StatusForbidden))
+			})
+		})
+
+		When("the membership of the manager is expired", func() {
+			BeforeEach(func() {
+				manager.Spec.Workspaces[0].ValidUntil = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+				oldTenant = &clv1alpha2.Tenant{}
+				newTenant = forgeTenantWithWorkspaceUser(testWorkspace)
+				operation = admissionv1.Update
+			})
+			It("Should deny the change", func() {
+				Expect(response.Allowed).To(BeFalse())
+				Expect(response.Result.Code).To(BeNumerically("==", http.StatusForbidden))
+			})
+		})
+
 		When("other fields are changed", func() {
 			BeforeEach(func() {
 				oldTenant = &clv1alpha2.Tenant{Spec: clv1alpha2.TenantSpec{LastName: "test"}}
@@ -456,6 +585,14 @@ var _ = Describe("Validator webhook", func() {
 			expectedChanges: []string{"test-a"},
 		}))
 
+		When("There is a difference in the validity period", WhenBody(CalcWsDiffCase{
+			a: []clv1alpha2.TenantWorkspaceEntry{makeWs("test-a", clv1alpha2.User)},
+			b: []clv1alpha2.TenantWorkspaceEntry{{
+				Name: "test-a", Role: clv1alpha2.User, ValidUntil: &metav1.Time{Time: time.Now()},
+			}},
+			expectedChanges: []string{"test-a"},
+		}))
+
 	})
 	When("Modifying the createPersonalWorkspace spec", func() {
 		var oldTenant, newTenant *clv1alpha2.Tenant
@@ -19,6 +19,7 @@ import (
 	"maps"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,6 +38,7 @@ func (r *Reconciler) syncWorkspaces(
 ) error {
 	// create fresh lists
 	tn.Status.FailingWorkspaces = []string{}
+	tn.Status.InactiveWorkspaces = nil
 	labels := make(map[string]string)
 	maps.Copy(labels, tn.Labels)
 	deleteWorkspacesRelatedLabels(labels)
@@ -86,6 +88,10 @@ func (r *Reconciler) syncSingleWorkspace(
 		log.Error(err, "Error when checking if workspace exists in tenant", "workspace", tenantWorkspace.Name, "tenant", tn.Name)
 		tn.Status.FailingWorkspaces = append(tn.Status.FailingWorkspaces, tenantWorkspace.Name)
 		tnOpinternalErrors.WithLabelValues("tenant", "workspace-not-exist").Inc()
+	case !forge.IsWorkspaceMembershipActive(tenantWorkspace, time.Now()):
+		// the membership is outside of its validity period, hence the tenant is not granted access to the workspace
+		log.Info("Workspace membership not active in tenant", "workspace", tenantWorkspace.Name, "tenant", tn.Name)
+		tn.Status.InactiveWorkspaces = append(tn.Status.InactiveWorkspaces, tenantWorkspace.Name)
 	case tenantWorkspace.Role == clv1alpha2.Candidate && workspace.Spec.AutoEnroll != clv1alpha1.AutoenrollWithApproval:
 		// Candidate role is allowed only if the workspace has autoEnroll = WithApproval
 		log.Error(err, "Workspace has not autoEnroll with approval, Candidate role is not allowed in tenant", "workspace", tenantWorkspace.Name, "tenant", tn.Name)
@@ -147,6 +153,11 @@ func (r *Reconciler) getEnrolledWorkspaces(
 			continue
 		}
 
+		// skip workspaces whose membership is not active
+		if slices.Contains(tn.Status.InactiveWorkspaces, ws.Name) {
+			continue
+		}
+
 		validWorkspaces = append(validWorkspaces, ws)
 	}
 
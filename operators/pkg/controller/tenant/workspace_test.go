@@ -15,10 +15,13 @@
 package tenant_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
 	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
@@ -104,6 +107,43 @@ var _ = Describe("Workspace management", func() {
 
 					Expect(tn.Labels).To(HaveKeyWithValue("crownlabs.polito.it/workspace-ws1", "candidate"))
 				})
+
+				It("Should create a pending enrollment request", func() {
+					request := &clv1alpha2.EnrollmentRequest{}
+					DoesEventuallyExists(ctx, cl, client.ObjectKey{Name: tnName + ".ws1"}, request, BeTrue(), timeout, interval)
+
+					Expect(request.Spec.TenantRef.Name).To(Equal(tnName))
+					Expect(request.Spec.WorkspaceRef.Name).To(Equal("ws1"))
+					Expect(request.Spec.Decision).To(BeEmpty())
+					Expect(request.OwnerReferences).To(HaveLen(1))
+				})
+
+				Context("and a previous enrollment request has been completed", func() {
+					BeforeEach(func() {
+						addObjToObjectsList(&clv1alpha2.EnrollmentRequest{
+							ObjectMeta: metav1.ObjectMeta{Name: tnName + ".ws1"},
+							Spec: clv1alpha2.EnrollmentRequestSpec{
+								TenantRef:    clv1alpha2.GenericRef{Name: tnName},
+								WorkspaceRef: clv1alpha2.GenericRef{Name: "ws1"},
+								Decision:     clv1alpha2.EnrollmentRejected,
+								Reason:       "not attending the course",
+							},
+							Status: clv1alpha2.EnrollmentRequestStatus{Phase: clv1alpha2.EnrollmentRequestRejected},
+						})
+					})
+
+					AfterEach(func() {
+						removeObjFromObjectsList(&clv1alpha2.EnrollmentRequest{ObjectMeta: metav1.ObjectMeta{Name: tnName + ".ws1"}})
+					})
+
+					It("Should replace it with a new pending request", func() {
+						request := &clv1alpha2.EnrollmentRequest{}
+						DoesEventuallyExists(ctx, cl, client.ObjectKey{Name: tnName + ".ws1"}, request, BeTrue(), timeout, interval)
+
+						Expect(request.Spec.Decision).To(BeEmpty())
+						Expect(request.Status.Phase).To(BeEmpty())
+					})
+				})
 			})
 
 			Context("and the workspace is not auto-enrollable", func() {
@@ -127,6 +167,95 @@ var _ = Describe("Workspace management", func() {
 
 					Expect(tn.Status.FailingWorkspaces).To(ContainElement("ws2"))
 				})
+
+				It("Should not create an enrollment request", func() {
+					request := &clv1alpha2.EnrollmentRequest{}
+					Expect(cl.Get(ctx, client.ObjectKey{Name: tnName + ".ws2"}, request)).NotTo(Succeed())
+				})
+			})
+		})
+	})
+
+	Context("When a workspace membership has a validity period", func() {
+		Context("and the membership is expired", func() {
+			BeforeEach(func() {
+				tnResource.Spec.Workspaces = []clv1alpha2.TenantWorkspaceEntry{{
+					Name:       "ws2",
+					Role:       clv1alpha2.User,
+					ValidUntil: timePtr(metav1.NewTime(time.Now().Add(-time.Hour))),
+				}}
+			})
+
+			It("Should not add the workspace label to the tenant", func() {
+				tn := &clv1alpha2.Tenant{}
+				DoesEventuallyExists(ctx, cl, client.ObjectKey{Name: tnName}, tn, BeTrue(), timeout, interval)
+
+				Expect(tn.Labels).NotTo(HaveKey("crownlabs.polito.it/workspace-ws2"))
+				Expect(tn.Labels).To(HaveKeyWithValue("crownlabs.polito.it/no-workspaces", "true"))
+			})
+
+			It("Should add the workspace to the inactive workspaces list", func() {
+				tn := &clv1alpha2.Tenant{}
+				DoesEventuallyExists(ctx, cl, client.ObjectKey{Name: tnName}, tn, BeTrue(), timeout, interval)
+
+				Expect(tn.Status.InactiveWorkspaces).To(ConsistOf("ws2"))
+				Expect(tn.Status.FailingWorkspaces).To(BeEmpty())
+			})
+		})
+
+		Context("and the membership is not yet started", func() {
+			BeforeEach(func() {
+				tnResource.Spec.Workspaces = []clv1alpha2.TenantWorkspaceEntry{{
+					Name:      "ws2",
+					Role:      clv1alpha2.User,
+					ValidFrom: timePtr(metav1.NewTime(time.Now().Add(2 * time.Hour))),
+				}}
+			})
+
+			It("Should add the workspace to the inactive workspaces list", func() {
+				tn := &clv1alpha2.Tenant{}
+				DoesEventuallyExists(ctx, cl, client.ObjectKey{Name: tnName}, tn, BeTrue(), timeout, interval)
+
+				Expect(tn.Labels).NotTo(HaveKey("crownlabs.polito.it/workspace-ws2"))
+				Expect(tn.Status.InactiveWorkspaces).To(ConsistOf("ws2"))
+			})
+
+			It("Should requeue the tenant when the membership starts", func() {
+				res, err := tenantReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKey{Name: tnName}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.RequeueAfter).To(BeNumerically("~", 2*time.Hour, time.Minute))
+			})
+		})
+
+		Context("and the membership is about to expire", func() {
+			BeforeEach(func() {
+				tnResource.Spec.Workspaces = []clv1alpha2.TenantWorkspaceEntry{{
+					Name:       "ws2",
+					Role:       clv1alpha2.User,
+					ValidUntil: timePtr(metav1.NewTime(time.Now().Add(time.Hour))),
+				}}
+			})
+
+			It("Should keep the workspace label on the tenant", func() {
+				tn := &clv1alpha2.Tenant{}
+				DoesEventuallyExists(ctx, cl, client.ObjectKey{Name: tnName}, tn, BeTrue(), timeout, interval)
+
+				Expect(tn.Labels).To(HaveKeyWithValue("crownlabs.polito.it/workspace-ws2", "user"))
+				Expect(tn.Status.InactiveWorkspaces).To(BeEmpty())
+			})
+
+			It("Should notify the tenant only once", func() {
+				tn := &clv1alpha2.Tenant{}
+				DoesEventuallyExists(ctx, cl, client.ObjectKey{Name: tnName}, tn, BeTrue(), timeout, interval)
+				Expect(tn.Status.ExpiringWorkspacesNotified).To(ConsistOf("ws2"))
+
+				res, err := tenantReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKey{Name: tnName}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+
+				Expect(mailSender.Sent).To(HaveLen(1))
+				Expect(mailSender.Sent[0].TenantEmail).To(Equal(tnResource.Spec.Email))
+				Expect(mailSender.Sent[0].WorkspaceName).To(Equal("ws2"))
 			})
 		})
 	})
@@ -28,18 +28,20 @@ import (
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
 )
 
-// enforceClusterRoleBindings ensures that all necessary ClusterRoleBindings exist for the workspace.
+// enforceClusterRoleBindings ensures that all necessary ClusterRoleBindings exist for the workspace,
+// granting the manager permissions also to the managers of the given ancestors.
 func (r *Reconciler) enforceClusterRoleBindings(
 	ctx context.Context,
 	ws *clv1alpha1.Workspace,
+	ancestors []string,
 ) error {
 	// Create or update the ClusterRoleBinding for managing instances
-	if err := r.enforceInstancesManagerBinding(ctx, ws); err != nil {
+	if err := r.enforceInstancesManagerBinding(ctx, ws, ancestors); err != nil {
 		return err
 	}
 
 	// Create or update the ClusterRoleBinding for managing tenants
-	if err := r.enforceTenantsManagerBinding(ctx, ws); err != nil {
+	if err := r.enforceTenantsManagerBinding(ctx, ws, ancestors); err != nil {
 		return err
 	}
 
@@ -50,6 +52,7 @@ func (r *Reconciler) enforceClusterRoleBindings(
 func (r *Reconciler) enforceInstancesManagerBinding(
 	ctx context.Context,
 	ws *clv1alpha1.Workspace,
+	ancestors []string,
 ) error {
 	// Create only the skeleton of the ClusterRoleBinding with immutable information
 	name := forge.GetWorkspaceInstancesManagerBindingName(ws)
@@ -65,7 +68,7 @@ func (r *Reconciler) enforceInstancesManagerBinding(
 		crb.Labels = forge.UpdateWorkspaceResourceCommonLabels(crb.Labels, r.TargetLabel)
 
 		// Configure subjects and roleRef
-		forge.ConfigureWorkspaceInstancesManagerBinding(ws, crb)
+		forge.ConfigureWorkspaceInstancesManagerBinding(ws, crb, ancestors...)
 
 		return ctrlutil.SetControllerReference(ws, crb, r.Scheme)
 	}); err != nil {
@@ -80,6 +83,7 @@ func (r *Reconciler) enforceInstancesManagerBinding(
 func (r *Reconciler) enforceTenantsManagerBinding(
 	ctx context.Context,
 	ws *clv1alpha1.Workspace,
+	ancestors []string,
 ) error {
 	// Create only the skeleton of the ClusterRoleBinding with immutable information
 	name := forge.GetWorkspaceTenantsManagerBindingName(ws)
@@ -95,7 +99,7 @@ func (r *Reconciler) enforceTenantsManagerBinding(
 		crb.Labels = forge.UpdateWorkspaceResourceCommonLabels(crb.Labels, r.TargetLabel)
 
 		// Configure subjects and roleRef
-		forge.ConfigureWorkspaceTenantsManagerBinding(ws, crb)
+		forge.ConfigureWorkspaceTenantsManagerBinding(ws, crb, ancestors...)
 
 		return ctrlutil.SetControllerReference(ws, crb, r.Scheme)
 	}); err != nil {
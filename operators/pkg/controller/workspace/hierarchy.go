@@ -0,0 +1,168 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+)
+
+// workspaceHierarchy describes the position of a Workspace in the hierarchy.
+type workspaceHierarchy struct {
+	// ancestors are the names of the ancestors, starting from the direct parent.
+	ancestors []string
+	// children are the direct children of the Workspace.
+	children []*clv1alpha1.Workspace
+	// descendants are the names of all the descendants of the Workspace.
+	descendants []string
+}
+
+// getWorkspaceHierarchy retrieves the position of the Workspace in the hierarchy.
+func (r *Reconciler) getWorkspaceHierarchy(
+	ctx context.Context,
+	ws *clv1alpha1.Workspace,
+) (*workspaceHierarchy, error) {
+	var workspaces clv1alpha1.WorkspaceList
+	if err := r.List(ctx, &workspaces); err != nil {
+		return nil, fmt.Errorf("error listing workspaces: %w", err)
+	}
+
+	ancestors, err := forge.WorkspaceAncestors(ws, workspaces.Items)
+	if err != nil {
+		// cycles are prevented by the webhook: in case one is found anyway, the
+		// ancestors up to the cycle are still granted the inherited permissions
+		ctrl.LoggerFrom(ctx).Error(err, "Invalid workspace hierarchy")
+	}
+
+	return &workspaceHierarchy{
+		ancestors:   ancestors,
+		children:    forge.WorkspaceChildren(ws.Name, workspaces.Items),
+		descendants: forge.WorkspaceDescendants(ws.Name, workspaces.Items),
+	}, nil
+}
+
+// updateHierarchyStatus updates the hierarchy information in the status of the Workspace,
+// aggregating the quotas sub-allocated to the children and the usage of the whole sub-tree.
+func (r *Reconciler) updateHierarchyStatus(
+	ctx context.Context,
+	ws *clv1alpha1.Workspace,
+	hierarchy *workspaceHierarchy,
+) error {
+	if len(hierarchy.ancestors) == 0 && len(hierarchy.children) == 0 {
+		ws.Status.Hierarchy = nil
+		return nil
+	}
+
+	status := &clv1alpha1.WorkspaceHierarchyStatus{
+		Ancestors: hierarchy.ancestors,
+	}
+
+	if len(hierarchy.children) > 0 {
+		allocated := forge.WorkspacesQuotaSum(hierarchy.children)
+		status.AllocatedQuota = &allocated
+		status.QuotaExceeded = len(forge.ExceededWorkspaceQuota(&allocated, &ws.Spec.Quota)) > 0
+		for _, child := range hierarchy.children {
+			status.Children = append(status.Children, child.Name)
+		}
+	}
+
+	usage, err := r.getSubtreeUsage(ctx, append([]string{ws.Name}, hierarchy.descendants...))
+	if err != nil {
+		return err
+	}
+	status.Usage = usage
+
+	ws.Status.Hierarchy = status
+	return nil
+}
+
+// getSubtreeUsage returns the resources requested by the running Instances of the given Workspaces.
+func (r *Reconciler) getSubtreeUsage(
+	ctx context.Context,
+	workspaces []string,
+) (*clv1alpha1.WorkspaceResourceUsage, error) {
+	usage := &clv1alpha1.WorkspaceResourceUsage{}
+	templates := make(map[types.NamespacedName]*clv1alpha2.Template)
+
+	for _, name := range workspaces {
+		var instances clv1alpha2.InstanceList
+		if err := r.List(ctx, &instances, client.MatchingLabels{forge.LabelWorkspaceKey: name}); err != nil {
+			return nil, fmt.Errorf("error listing instances of workspace %s: %w", name, err)
+		}
+
+		for i := range instances.Items {
+			if !instances.Items[i].Spec.Running {
+				continue
+			}
+
+			key := forge.NamespacedNameFromGenericRef(instances.Items[i].Spec.Template)
+			template, found := templates[key]
+			if !found {
+				template = &clv1alpha2.Template{}
+				if err := r.Get(ctx, key, template); err != nil {
+					if client.IgnoreNotFound(err) != nil {
+						return nil, fmt.Errorf("error getting template %s: %w", key, err)
+					}
+					template = nil
+				}
+				templates[key] = template
+			}
+			if template == nil {
+				continue
+			}
+
+			instanceUsage := forge.InstanceWorkspaceUsage(template)
+			forge.AccumulateWorkspaceUsage(usage, &instanceUsage)
+		}
+	}
+
+	return usage, nil
+}
+
+// workspaceToRelatedWorkspaces returns the requests to reconcile the ancestors and the descendants
+// of the given Workspace, whose bindings and status depend on its position in the hierarchy.
+func (r *Reconciler) workspaceToRelatedWorkspaces(
+	ctx context.Context,
+	obj client.Object,
+) []ctrl.Request {
+	ws, ok := obj.(*clv1alpha1.Workspace)
+	if !ok {
+		return nil
+	}
+
+	var workspaces clv1alpha1.WorkspaceList
+	if err := r.List(ctx, &workspaces); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Error when retrieving workspaces", "workspace", ws.Name)
+		return nil
+	}
+
+	// errors are ignored, as the ancestors up to the cycle are returned anyway
+	related, _ := forge.WorkspaceAncestors(ws, workspaces.Items)
+	related = append(related, forge.WorkspaceDescendants(ws.Name, workspaces.Items)...)
+
+	requests := make([]ctrl.Request, 0, len(related))
+	for _, name := range related {
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+	}
+	return requests
+}
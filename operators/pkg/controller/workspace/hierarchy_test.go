@@ -0,0 +1,165 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apicommon "github.com/netgroup-polito/CrownLabs/operators/api/common"
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+)
+
+var _ = Describe("Hierarchy", func() {
+	const (
+		parentName = "test-parent"
+		childName  = "test-child"
+	)
+
+	quota := func(cpu int64, memory string, instances int64) apicommon.WorkspaceResourceQuota {
+		return apicommon.WorkspaceResourceQuota{
+			ResourceSpec: apicommon.ResourceSpec{CPU: cpu, Memory: resource.MustParse(memory)},
+			Instances:    instances,
+		}
+	}
+
+	var parent, child *clv1alpha1.Workspace
+
+	BeforeEach(func() {
+		parent = &clv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: parentName},
+			Spec:       clv1alpha1.WorkspaceSpec{PrettyName: "Parent", Quota: quota(8, "16Gi", 10)},
+		}
+		child = &clv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: childName},
+			Spec: clv1alpha1.WorkspaceSpec{
+				PrettyName: "Child",
+				Quota:      quota(2, "2Gi", 2),
+				Parent:     &clv1alpha1.GenericRef{Name: wsName},
+			},
+		}
+
+		wsResource.Spec.Quota = quota(4, "8Gi", 5)
+		wsResource.Spec.Parent = &clv1alpha1.GenericRef{Name: parentName}
+		addObjToObjectsList(parent)
+		addObjToObjectsList(child)
+	})
+
+	AfterEach(func() {
+		removeObjFromObjectsList(parent)
+		removeObjFromObjectsList(child)
+	})
+
+	Context("When a workspace has both a parent and a child", func() {
+		It("Should grant the manager permissions also to the managers of the ancestors", func() {
+			crb := &rbacv1.ClusterRoleBinding{}
+			Expect(cl.Get(ctx, client.ObjectKey{Name: "crownlabs-manage-instances-" + wsName}, crb)).To(Succeed())
+			Expect(crb.Subjects).To(HaveLen(2))
+			Expect(crb.Subjects[1].Name).To(Equal("kubernetes:workspace-" + parentName + ":manager"))
+
+			rb := &rbacv1.RoleBinding{}
+			Expect(cl.Get(ctx, client.ObjectKey{Name: "crownlabs-manage-templates", Namespace: "workspace-" + wsName}, rb)).To(Succeed())
+			Expect(rb.Subjects).To(HaveLen(2))
+			Expect(rb.Subjects[1].Name).To(Equal("kubernetes:workspace-" + parentName + ":manager"))
+		})
+
+		It("Should grant the visibility of the templates also to the users of the descendants", func() {
+			rb := &rbacv1.RoleBinding{}
+			Expect(cl.Get(ctx, client.ObjectKey{Name: "crownlabs-view-templates", Namespace: "workspace-" + wsName}, rb)).To(Succeed())
+			Expect(rb.Subjects).To(HaveLen(2))
+			Expect(rb.Subjects[1].Name).To(Equal("kubernetes:workspace-" + childName + ":user"))
+		})
+
+		It("Should report the hierarchy and the allocated quota in the status", func() {
+			ws := &clv1alpha1.Workspace{}
+			Expect(cl.Get(ctx, client.ObjectKey{Name: wsName}, ws)).To(Succeed())
+			Expect(ws.Status.Hierarchy).ToNot(BeNil())
+			Expect(ws.Status.Hierarchy.Ancestors).To(Equal([]string{parentName}))
+			Expect(ws.Status.Hierarchy.Children).To(Equal([]string{childName}))
+			Expect(ws.Status.Hierarchy.AllocatedQuota.CPU).To(BeNumerically("==", 2))
+			Expect(ws.Status.Hierarchy.QuotaExceeded).To(BeFalse())
+		})
+	})
+
+	Context("When the children exceed the quota of the workspace", func() {
+		BeforeEach(func() {
+			child.Spec.Quota = quota(6, "2Gi", 2)
+		})
+
+		It("Should flag the quota as exceeded", func() {
+			ws := &clv1alpha1.Workspace{}
+			Expect(cl.Get(ctx, client.ObjectKey{Name: wsName}, ws)).To(Succeed())
+			Expect(ws.Status.Hierarchy.QuotaExceeded).To(BeTrue())
+		})
+	})
+
+	Context("When instances are running in the sub-tree", func() {
+		var template *clv1alpha2.Template
+		var instances []*clv1alpha2.Instance
+
+		BeforeEach(func() {
+			template = &clv1alpha2.Template{
+				ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "workspace-" + childName},
+				Spec: clv1alpha2.TemplateSpec{
+					WorkspaceRef: clv1alpha2.GenericRef{Name: childName},
+					EnvironmentList: []clv1alpha2.Environment{{
+						Resources: clv1alpha2.EnvironmentResources{
+							ResourceSpec: apicommon.ResourceSpec{CPU: 2, Memory: resource.MustParse("2Gi")},
+						},
+					}},
+				},
+			}
+			instance := func(name string, running bool) *clv1alpha2.Instance {
+				return &clv1alpha2.Instance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name, Namespace: "tenant-test",
+						Labels: map[string]string{"crownlabs.polito.it/workspace": childName},
+					},
+					Spec: clv1alpha2.InstanceSpec{
+						Template: clv1alpha2.GenericRef{Name: template.Name, Namespace: template.Namespace},
+						Running:  running,
+					},
+				}
+			}
+			instances = []*clv1alpha2.Instance{instance("running", true), instance("stopped", false)}
+
+			addObjToObjectsList(template)
+			for _, inst := range instances {
+				addObjToObjectsList(inst)
+			}
+		})
+
+		AfterEach(func() {
+			removeObjFromObjectsList(template)
+			for _, inst := range instances {
+				removeObjFromObjectsList(inst)
+			}
+		})
+
+		It("Should aggregate the usage of the running instances of the descendants", func() {
+			ws := &clv1alpha1.Workspace{}
+			Expect(cl.Get(ctx, client.ObjectKey{Name: wsName}, ws)).To(Succeed())
+			Expect(ws.Status.Hierarchy.Usage).ToNot(BeNil())
+			Expect(ws.Status.Hierarchy.Usage.Instances).To(BeNumerically("==", 1))
+			Expect(ws.Status.Hierarchy.Usage.CPU).To(BeNumerically("==", 2))
+			Expect(ws.Status.Hierarchy.Usage.Memory.Cmp(resource.MustParse("2Gi"))).To(BeZero())
+		})
+	})
+})
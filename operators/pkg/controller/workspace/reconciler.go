@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
 	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
@@ -109,8 +110,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		log.Info("Added finalizer to workspace")
 	}
 
+	// retrieve the position of the Workspace in the hierarchy
+	hierarchy, err := r.getWorkspaceHierarchy(ctx, &ws)
+	if err != nil {
+		log.Error(err, "Error retrieving hierarchy for workspace")
+		return reschedule, fmt.Errorf("error retrieving hierarchy for workspace %s: %w", ws.Name, err)
+	}
+
 	// enforce subresources for the Workspace
-	err := r.enforceSubresources(ctx, log, &ws)
+	err = r.enforceSubresources(ctx, log, &ws, hierarchy)
 	if err != nil {
 		log.Error(err, "Error enforcing subresources for workspace")
 		return reschedule, fmt.Errorf("error enforcing subresources for workspace %s: %w", ws.Name, err)
@@ -136,6 +144,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		log.Info("Keycloak roles updated/created for workspace")
 	}
 
+	// report the quotas and the usage aggregated over the sub-tree
+	if err := r.updateHierarchyStatus(ctx, &ws, hierarchy); err != nil {
+		log.Error(err, "Error updating hierarchy status for workspace")
+		hasErrors = true
+	}
+
 	ws.Status.Ready = !hasErrors
 
 	return reschedule, nil
@@ -154,6 +168,8 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, log logr.Logger) error {
 		Owns(&corev1.Namespace{}).
 		Owns(&rbacv1.ClusterRoleBinding{}).
 		Owns(&rbacv1.RoleBinding{}).
+		Watches(&clv1alpha1.Workspace{},
+			handler.EnqueueRequestsFromMapFunc(r.workspaceToRelatedWorkspaces)).
 		WithLogConstructor(utils.LogConstructor(mgr.GetLogger(), "Workspace")).
 		Complete(r)
 }
@@ -199,6 +215,7 @@ func (r *Reconciler) enforceSubresources(
 	ctx context.Context,
 	log logr.Logger,
 	ws *clv1alpha1.Workspace,
+	hierarchy *workspaceHierarchy,
 ) error {
 	// Enforce the Namespace for the Workspace
 	if err := r.enforceNamespace(ctx, ws); err != nil {
@@ -207,13 +224,13 @@ func (r *Reconciler) enforceSubresources(
 	log.Info("Namespace created/updated for workspace")
 
 	// Enforce the ClusterRoleBinding for the Workspace
-	if err := r.enforceClusterRoleBindings(ctx, ws); err != nil {
+	if err := r.enforceClusterRoleBindings(ctx, ws, hierarchy.ancestors); err != nil {
 		return fmt.Errorf("error enforcing ClusterRoleBinding for workspace %s: %w", ws.Name, err)
 	}
 	log.Info("ClusterRoleBindings created/updated for workspace")
 
 	// Enforce the RoleBindings for the Workspace
-	if err := r.enforceRoleBindings(ctx, ws); err != nil {
+	if err := r.enforceRoleBindings(ctx, ws, hierarchy); err != nil {
 		return fmt.Errorf("error enforcing RoleBindings for workspace %s: %w", ws.Name, err)
 	}
 	log.Info("RoleBindings created/updated for workspace")
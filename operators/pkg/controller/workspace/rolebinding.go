@@ -32,6 +32,7 @@ import (
 func (r *Reconciler) enforceRoleBindings(
 	ctx context.Context,
 	ws *clv1alpha1.Workspace,
+	hierarchy *workspaceHierarchy,
 ) error {
 	if !ws.Status.Namespace.Created {
 		return fmt.Errorf("cannot manage RoleBindings for Workspace %s: namespace not created", ws.Name)
@@ -39,17 +40,17 @@ func (r *Reconciler) enforceRoleBindings(
 	namespace := ws.Status.Namespace.Name
 
 	// Enforce User View Templates RoleBinding
-	if err := r.enforceUserViewTemplatesRoleBinding(ctx, ws, namespace); err != nil {
+	if err := r.enforceUserViewTemplatesRoleBinding(ctx, ws, namespace, hierarchy.descendants); err != nil {
 		return fmt.Errorf("error while managing User View Templates RoleBinding for workspace %s: %w", ws.Name, err)
 	}
 
 	// Enforce Manager Manage Templates RoleBinding
-	if err := r.enforceManagerManageTemplatesRoleBinding(ctx, ws, namespace); err != nil {
+	if err := r.enforceManagerManageTemplatesRoleBinding(ctx, ws, namespace, hierarchy.ancestors); err != nil {
 		return fmt.Errorf("error while managing Manager Manage Templates RoleBinding for workspace %s: %w", ws.Name, err)
 	}
 
 	// Enforce Manager Manage SharedVolumes RoleBinding
-	if err := r.enforceManagerManageSharedVolumesRoleBinding(ctx, ws, namespace); err != nil {
+	if err := r.enforceManagerManageSharedVolumesRoleBinding(ctx, ws, namespace, hierarchy.ancestors); err != nil {
 		return fmt.Errorf("error while managing Manager Manage SharedVolumes RoleBinding for workspace %s: %w", ws.Name, err)
 	}
 
@@ -108,11 +109,13 @@ func (r *Reconciler) deleteSingleRb(
 	return nil
 }
 
-// enforceUserViewTemplatesRoleBinding creates or updates the RoleBinding for User View Templates.
+// enforceUserViewTemplatesRoleBinding creates or updates the RoleBinding for User View Templates,
+// granting the visibility of the templates also to the users of the given descendants.
 func (r *Reconciler) enforceUserViewTemplatesRoleBinding(
 	ctx context.Context,
 	ws *clv1alpha1.Workspace,
 	namespace string,
+	descendants []string,
 ) error {
 	rb := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
@@ -126,7 +129,7 @@ func (r *Reconciler) enforceUserViewTemplatesRoleBinding(
 		rb.Labels = forge.UpdateWorkspaceResourceCommonLabels(rb.Labels, r.TargetLabel)
 
 		// Configure the RoleBinding
-		forge.ConfigureWorkspaceUserViewTemplatesBinding(ws, rb, rb.Labels)
+		forge.ConfigureWorkspaceUserViewTemplatesBinding(ws, rb, rb.Labels, descendants...)
 
 		return ctrlutil.SetControllerReference(ws, rb, r.Scheme)
 	}); err != nil {
@@ -141,6 +144,7 @@ func (r *Reconciler) enforceManagerManageTemplatesRoleBinding(
 	ctx context.Context,
 	ws *clv1alpha1.Workspace,
 	namespace string,
+	ancestors []string,
 ) error {
 	rb := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
@@ -154,7 +158,7 @@ func (r *Reconciler) enforceManagerManageTemplatesRoleBinding(
 		rb.Labels = forge.UpdateWorkspaceResourceCommonLabels(rb.Labels, r.TargetLabel)
 
 		// Configure the RoleBinding
-		forge.ConfigureWorkspaceManagerManageTemplatesBinding(ws, rb, rb.Labels)
+		forge.ConfigureWorkspaceManagerManageTemplatesBinding(ws, rb, rb.Labels, ancestors...)
 
 		return ctrlutil.SetControllerReference(ws, rb, r.Scheme)
 	}); err != nil {
@@ -169,6 +173,7 @@ func (r *Reconciler) enforceManagerManageSharedVolumesRoleBinding(
 	ctx context.Context,
 	ws *clv1alpha1.Workspace,
 	namespace string,
+	ancestors []string,
 ) error {
 	rb := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
@@ -182,7 +187,7 @@ func (r *Reconciler) enforceManagerManageSharedVolumesRoleBinding(
 		rb.Labels = forge.UpdateWorkspaceResourceCommonLabels(rb.Labels, r.TargetLabel)
 
 		// Configure the RoleBinding
-		forge.ConfigureWorkspaceManagerManageSharedVolumesBinding(ws, rb, rb.Labels)
+		forge.ConfigureWorkspaceManagerManageSharedVolumesBinding(ws, rb, rb.Labels, ancestors...)
 
 		return ctrlutil.SetControllerReference(ws, rb, r.Scheme)
 	}); err != nil {
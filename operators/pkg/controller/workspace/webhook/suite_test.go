@@ -0,0 +1,60 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apicommon "github.com/netgroup-polito/CrownLabs/operators/api/common"
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+)
+
+var (
+	scheme *runtime.Scheme
+)
+
+func TestWorkspaceWebhook(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Workspace Webhook Suite")
+}
+
+var _ = BeforeSuite(func() {
+	scheme = runtime.NewScheme()
+	Expect(clv1alpha1.AddToScheme(scheme)).To(Succeed())
+})
+
+// forgeWorkspace returns a Workspace with the given parent and quota.
+func forgeWorkspace(name, parent string, cpu int64, memory string, instances int64) *clv1alpha1.Workspace {
+	ws := &clv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: clv1alpha1.WorkspaceSpec{
+			PrettyName: name,
+			Quota: apicommon.WorkspaceResourceQuota{
+				ResourceSpec: apicommon.ResourceSpec{CPU: cpu, Memory: resource.MustParse(memory)},
+				Instances:    instances,
+			},
+		},
+	}
+	if parent != "" {
+		ws.Spec.Parent = &clv1alpha1.GenericRef{Name: parent}
+	}
+	return ws
+}
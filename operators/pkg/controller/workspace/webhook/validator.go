@@ -0,0 +1,165 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook implements the webhook handlers for workspace resources.
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+)
+
+// WorkspaceValidator implements a validating webhook for Workspace resources,
+// ensuring the consistency of the workspace hierarchy:
+// - the parent exists, and no cycles are introduced;
+// - the quotas sub-allocated to the children do not exceed the quota of the parent;
+// - workspaces with children cannot be deleted.
+type WorkspaceValidator struct {
+	admission.CustomValidator
+	Client client.Client
+}
+
+// ValidateCreate validates a new workspace creation request.
+func (wv *WorkspaceValidator) ValidateCreate(
+	ctx context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	ws, ok := obj.(*clv1alpha1.Workspace)
+	if !ok {
+		return nil, fmt.Errorf("expected a Workspace object, got %T", obj)
+	}
+
+	return wv.validateHierarchy(ctx, ws)
+}
+
+// ValidateUpdate validates a workspace update request, if either the parent or the quota are changed.
+func (wv *WorkspaceValidator) ValidateUpdate(
+	ctx context.Context,
+	oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	oldWs, ok := oldObj.(*clv1alpha1.Workspace)
+	if !ok {
+		return nil, fmt.Errorf("expected a Workspace object, got %T", oldObj)
+	}
+	ws, ok := newObj.(*clv1alpha1.Workspace)
+	if !ok {
+		return nil, fmt.Errorf("expected a Workspace object, got %T", newObj)
+	}
+
+	// changes to other fields (e.g., finalizers) are not affected by the hierarchy
+	if reflect.DeepEqual(oldWs.Spec.Parent, ws.Spec.Parent) && reflect.DeepEqual(oldWs.Spec.Quota, ws.Spec.Quota) {
+		return nil, nil
+	}
+
+	return wv.validateHierarchy(ctx, ws)
+}
+
+// ValidateDelete validates a workspace deletion request, which is denied if the workspace has children.
+func (wv *WorkspaceValidator) ValidateDelete(
+	ctx context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	ws, ok := obj.(*clv1alpha1.Workspace)
+	if !ok {
+		return nil, fmt.Errorf("expected a Workspace object, got %T", obj)
+	}
+
+	var workspaces clv1alpha1.WorkspaceList
+	if err := wv.Client.List(ctx, &workspaces); err != nil {
+		return nil, kerrors.NewInternalError(fmt.Errorf("failed to list workspaces: %w", err))
+	}
+
+	if children := forge.WorkspaceChildren(ws.Name, workspaces.Items); len(children) > 0 {
+		ctrl.LoggerFrom(ctx).Info("denied: workspace with children", "workspace", ws.Name)
+		return nil, kerrors.NewForbidden(schema.GroupResource{}, ws.Name,
+			fmt.Errorf("workspace %s has %d children, which must be deleted or moved to another parent first", ws.Name, len(children)))
+	}
+
+	return nil, nil
+}
+
+// validateHierarchy checks the position of the given workspace in the hierarchy, and the sub-allocation of its quota.
+func (wv *WorkspaceValidator) validateHierarchy(
+	ctx context.Context,
+	ws *clv1alpha1.Workspace,
+) (admission.Warnings, error) {
+	log := ctrl.LoggerFrom(ctx).WithValues("workspace", ws.Name)
+
+	var workspaces clv1alpha1.WorkspaceList
+	if err := wv.Client.List(ctx, &workspaces); err != nil {
+		return nil, kerrors.NewInternalError(fmt.Errorf("failed to list workspaces: %w", err))
+	}
+
+	// replace the current version of the workspace with the one being validated
+	items := make([]clv1alpha1.Workspace, 0, len(workspaces.Items)+1)
+	for i := range workspaces.Items {
+		if workspaces.Items[i].Name != ws.Name {
+			items = append(items, workspaces.Items[i])
+		}
+	}
+	items = append(items, *ws)
+
+	forbidden := func(err error) (admission.Warnings, error) {
+		log.Info("denied: invalid workspace hierarchy", "reason", err.Error())
+		return nil, kerrors.NewForbidden(schema.GroupResource{}, ws.Name, err)
+	}
+
+	if parentName := forge.WorkspaceParentName(ws); parentName != "" {
+		if parentName == ws.Name {
+			return forbidden(fmt.Errorf("a workspace cannot be the parent of itself"))
+		}
+
+		var parent *clv1alpha1.Workspace
+		for i := range items {
+			if items[i].Name == parentName {
+				parent = &items[i]
+			}
+		}
+		if parent == nil {
+			return forbidden(fmt.Errorf("the parent workspace %s does not exist", parentName))
+		}
+
+		if _, err := forge.WorkspaceAncestors(ws, items); err != nil {
+			return forbidden(err)
+		}
+
+		allocated := forge.WorkspacesQuotaSum(forge.WorkspaceChildren(parentName, items))
+		if exceeded := forge.ExceededWorkspaceQuota(&allocated, &parent.Spec.Quota); len(exceeded) > 0 {
+			return forbidden(fmt.Errorf("the quotas of the children exceed the quota of the parent workspace %s: %s",
+				parentName, strings.Join(exceeded, ", ")))
+		}
+	}
+
+	if children := forge.WorkspaceChildren(ws.Name, items); len(children) > 0 {
+		allocated := forge.WorkspacesQuotaSum(children)
+		if exceeded := forge.ExceededWorkspaceQuota(&allocated, &ws.Spec.Quota); len(exceeded) > 0 {
+			return forbidden(fmt.Errorf("the quota of the workspace is lower than the one sub-allocated to its children: %s",
+				strings.Join(exceeded, ", ")))
+		}
+	}
+
+	return nil, nil
+}
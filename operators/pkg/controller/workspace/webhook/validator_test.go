@@ -0,0 +1,114 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/workspace/webhook"
+)
+
+var _ = Describe("WorkspaceValidator", func() {
+	var (
+		ctx       context.Context
+		validator *webhook.WorkspaceValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			forgeWorkspace("department", "", 16, "32Gi", 20),
+			forgeWorkspace("course-a", "department", 8, "16Gi", 10),
+			forgeWorkspace("lab", "course-a", 2, "4Gi", 2),
+		).Build()
+		validator = &webhook.WorkspaceValidator{Client: cl}
+	})
+
+	Describe("Creating a workspace", func() {
+		It("Should allow workspaces without a parent", func() {
+			_, err := validator.ValidateCreate(ctx, forgeWorkspace("standalone", "", 2, "4Gi", 2))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should allow children fitting the remaining quota of the parent", func() {
+			_, err := validator.ValidateCreate(ctx, forgeWorkspace("course-b", "department", 8, "16Gi", 10))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should deny children exceeding the remaining quota of the parent", func() {
+			_, err := validator.ValidateCreate(ctx, forgeWorkspace("course-b", "department", 10, "8Gi", 5))
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("CPU (18 > 16)"))
+		})
+
+		It("Should deny a missing parent", func() {
+			_, err := validator.ValidateCreate(ctx, forgeWorkspace("course-b", "missing", 1, "1Gi", 1))
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("Should deny a workspace being the parent of itself", func() {
+			_, err := validator.ValidateCreate(ctx, forgeWorkspace("course-b", "course-b", 1, "1Gi", 1))
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+		})
+	})
+
+	Describe("Updating a workspace", func() {
+		It("Should deny changes introducing cycles", func() {
+			oldWs := forgeWorkspace("department", "", 16, "32Gi", 20)
+			newWs := forgeWorkspace("department", "lab", 16, "32Gi", 20)
+			_, err := validator.ValidateUpdate(ctx, oldWs, newWs)
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("Should deny reducing the quota below the one sub-allocated to the children", func() {
+			oldWs := forgeWorkspace("department", "", 16, "32Gi", 20)
+			newWs := forgeWorkspace("department", "", 4, "32Gi", 20)
+			_, err := validator.ValidateUpdate(ctx, oldWs, newWs)
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("Should allow increasing the quota of a child within the one of the parent", func() {
+			oldWs := forgeWorkspace("course-a", "department", 8, "16Gi", 10)
+			newWs := forgeWorkspace("course-a", "department", 12, "16Gi", 10)
+			_, err := validator.ValidateUpdate(ctx, oldWs, newWs)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should ignore changes not involving the parent or the quota", func() {
+			oldWs := forgeWorkspace("course-a", "department", 100, "16Gi", 10)
+			newWs := oldWs.DeepCopy()
+			newWs.Finalizers = []string{"crownlabs.polito.it/tenant-operator"}
+			_, err := validator.ValidateUpdate(ctx, oldWs, newWs)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Describe("Deleting a workspace", func() {
+		It("Should deny the deletion of workspaces with children", func() {
+			_, err := validator.ValidateDelete(ctx, forgeWorkspace("course-a", "department", 8, "16Gi", 10))
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("Should allow the deletion of leaf workspaces", func() {
+			_, err := validator.ValidateDelete(ctx, forgeWorkspace("lab", "course-a", 2, "4Gi", 2))
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
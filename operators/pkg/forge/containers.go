@@ -19,6 +19,7 @@ package forge
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -38,6 +39,14 @@ const (
 	ContentDownloaderName = "content-downloader"
 	// ContentUploaderName -> name of the uploader initcontainer.
 	ContentUploaderName = "content-uploader"
+	// ContentGitSyncName -> name of the container checking out a Git repository.
+	ContentGitSyncName = "content-git-sync"
+	// PreTerminationHookName -> name of the container archiving the environment content before termination.
+	PreTerminationHookName = "pre-termination-hook"
+	// PreTerminationHookMyDriveDestination -> destination of the archives saved to the tenant's MyDrive.
+	PreTerminationHookMyDriveDestination = "file://" + MyDriveVolumeMountPath + "/crownlabs-archives"
+	// PreTerminationHookArchiveTimeFormat -> format of the timestamp appended to the name of the pre-termination archives.
+	PreTerminationHookArchiveTimeFormat = "20060102-150405"
 	// PersistentDefaultMountPath -> default path for the container's pvc or persistent storage.
 	PersistentDefaultMountPath = "/media/data"
 	// HealthzEndpoint -> default endpoint for HTTP probes.
@@ -101,6 +110,16 @@ func InstancePVCSpec(environment *clv1alpha2.Environment) corev1.PersistentVolum
 	return PVCSpec(corev1.ReadWriteOnce, InstancePVCStorageClassName(environment), &environment.Resources.Disk)
 }
 
+// InstanceDiskSize returns the size of the persistent disk of the given environment of the instance,
+// i.e., the largest between the one configured in the environment and the per-instance override (if any).
+func InstanceDiskSize(instance *clv1alpha2.Instance, environment *clv1alpha2.Environment) resource.Quantity {
+	size := environment.Resources.Disk.DeepCopy()
+	if override, ok := instance.Spec.DiskSizes[environment.Name]; ok && override.Cmp(size) > 0 {
+		size = override.DeepCopy()
+	}
+	return size
+}
+
 // InstancePVCStorageClassName returns the storage class configured as option, or nil if empty.
 func InstancePVCStorageClassName(environment *clv1alpha2.Environment) *string {
 	if environment.StorageClassName != "" {
@@ -182,6 +201,59 @@ func SubmissionJobSpec(instance *clv1alpha2.Instance, environment *clv1alpha2.En
 	}
 }
 
+// PreTerminationHookArchiveName returns the name of the archive saved by the pre-termination hook started at the given time,
+// which includes the timestamp to prevent subsequent terminations from overwriting the previous archives.
+func PreTerminationHookArchiveName(instance *clv1alpha2.Instance, environment *clv1alpha2.Environment, started time.Time) string {
+	return fmt.Sprintf("%s-%s-%s", instance.Name, environment.Name, started.UTC().Format(PreTerminationHookArchiveTimeFormat))
+}
+
+// PreTerminationHookJobSpec returns the job spec for the job archiving the content of a persistent environment to the
+// given destination, mounting also the given volumes (e.g., the MyDrive of the tenant), for the hook started at the given time.
+func PreTerminationHookJobSpec(instance *clv1alpha2.Instance, environment *clv1alpha2.Environment, destination string, mountInfos []corev1.VolumeMount,
+	started time.Time, opts *ContainerEnvOpts) batchv1.JobSpec {
+	uploader := ContentUploaderJobContainer(destination, PreTerminationHookArchiveName(instance, environment, started), opts)
+	for _, mountInfo := range mountInfos {
+		AddContainerVolumeMount(&uploader, mountInfo.Name, mountInfo.MountPath, mountInfo.ReadOnly)
+	}
+
+	return batchv1.JobSpec{
+		BackoffLimit:            ptr.To[int32](SubmissionJobMaxRetries),
+		TTLSecondsAfterFinished: ptr.To[int32](SubmissionJobTTLSeconds),
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers:                   []corev1.Container{uploader},
+				Volumes:                      ContainerVolumes(instance, environment, mountInfos),
+				SecurityContext:              PodSecurityContext(),
+				AutomountServiceAccountToken: ptr.To(false),
+				RestartPolicy:                corev1.RestartPolicyOnFailure,
+			},
+		},
+	}
+}
+
+// PreTerminationHookEphemeralContainer forges the ephemeral container archiving the content of a running environment
+// to the given destination, for the hook started at the given time. The volumes mounted must be already part of the pod,
+// as ephemeral containers cannot add new ones.
+func PreTerminationHookEphemeralContainer(instance *clv1alpha2.Instance, environment *clv1alpha2.Environment, destination string, mountInfos []corev1.VolumeMount,
+	started time.Time, opts *ContainerEnvOpts) corev1.EphemeralContainer {
+	uploader := ContentUploaderJobContainer(destination, PreTerminationHookArchiveName(instance, environment, started), opts)
+	for _, mountInfo := range mountInfos {
+		AddContainerVolumeMount(&uploader, mountInfo.Name, mountInfo.MountPath, mountInfo.ReadOnly)
+	}
+
+	// Ephemeral containers are not allowed to set resources.
+	return corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:            PreTerminationHookName,
+			Image:           uploader.Image,
+			Args:            uploader.Args,
+			Env:             uploader.Env,
+			VolumeMounts:    uploader.VolumeMounts,
+			SecurityContext: uploader.SecurityContext,
+		},
+	}
+}
+
 // StandaloneContainer forges the Standalone application container of the environment.
 func StandaloneContainer(instance *clv1alpha2.Instance, environment *clv1alpha2.Environment, volumeMountPath string, mountInfos []corev1.VolumeMount) corev1.Container {
 	standaloneContainer := AppContainer(environment, volumeMountPath, mountInfos)
@@ -237,6 +309,18 @@ func ContentDownloaderInitContainer(contentOrigin string, ceOpts *ContainerEnvOp
 	return contentDownloader
 }
 
+// ContentGitSyncContainer forges a Container checking out the given revision of a Git repository into the <MyDriveName> volume.
+func ContentGitSyncContainer(source *clv1alpha2.GitContentSource, ceOpts *ContainerEnvOpts) corev1.Container {
+	contentGitSync := GenericContainer(ContentGitSyncName, fmt.Sprintf("%s:%s", ceOpts.ContentToolsImg, ceOpts.ImagesTag))
+	SetContainerArgs(&contentGitSync, "git-sync")
+	SetContainerResources(&contentGitSync, 0.5, 1, 256, 1024)
+	AddContainerVolumeMount(&contentGitSync, PersistentVolumeName, PersistentDefaultMountPath, false)
+	AddEnvVariableToContainer(&contentGitSync, "GIT_REPOSITORY", source.Repository)
+	AddEnvVariableToContainer(&contentGitSync, "GIT_REF", source.Ref)
+	AddEnvVariableToContainer(&contentGitSync, "DESTINATION_PATH", PersistentDefaultMountPath)
+	return contentGitSync
+}
+
 // ContentUploaderJobContainer forges a Container to be used within a Job to compress and upload an archive file from the <MyDriveName> volume.
 func ContentUploaderJobContainer(contentDestination, filename string, ceOpts *ContainerEnvOpts) corev1.Container {
 	contentUploader := GenericContainer(ContentUploaderName, fmt.Sprintf("%s:%s", ceOpts.ContentToolsImg, ceOpts.ImagesTag))
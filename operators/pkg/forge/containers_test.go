@@ -16,6 +16,8 @@
 package forge_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
@@ -143,6 +145,32 @@ var _ = Describe("Containers and Deployment spec forging", func() {
 		})
 	})
 
+	Describe("The forge.InstanceDiskSize function", func() {
+		var size resource.Quantity
+
+		JustBeforeEach(func() {
+			size = forge.InstanceDiskSize(&instance, &environment)
+		})
+
+		It("Should return the size configured in the environment", func() {
+			Expect(size).To(Equal(resource.MustParse(disk)))
+		})
+
+		When("A larger size is requested for the instance", func() {
+			BeforeEach(func() { instance.Spec.DiskSizes = map[string]resource.Quantity{envName: resource.MustParse("30Gi")} })
+			It("Should return the requested size", func() {
+				Expect(size).To(Equal(resource.MustParse("30Gi")))
+			})
+		})
+
+		When("A smaller size is requested for the instance", func() {
+			BeforeEach(func() { instance.Spec.DiskSizes = map[string]resource.Quantity{envName: resource.MustParse("10Gi")} })
+			It("Should return the size configured in the environment", func() {
+				Expect(size).To(Equal(resource.MustParse(disk)))
+			})
+		})
+	})
+
 	Describe("The forge.PodSecurityContext function", func() {
 		var psc corev1.PodSecurityContext
 
@@ -637,6 +665,70 @@ var _ = Describe("Containers and Deployment spec forging", func() {
 		})
 	})
 
+	Describe("The forge.PreTerminationHookJobSpec function forges the JobSpec for a pre-termination hook job", func() {
+		var actual batchv1.JobSpec
+		mountInfos := []corev1.VolumeMount{forge.MyDriveMountInfo("tenant")}
+		started := time.Date(2026, time.March, 1, 10, 30, 0, 0, time.UTC)
+
+		BeforeEach(func() {
+			environment.Persistent = true
+		})
+
+		JustBeforeEach(func() {
+			actual = forge.PreTerminationHookJobSpec(&instance, &environment, forge.PreTerminationHookMyDriveDestination, mountInfos, started, &opts)
+		})
+
+		It("should return the correct podSpecification", func() {
+			uploader := forge.ContentUploaderJobContainer(forge.PreTerminationHookMyDriveDestination, instance.Name+"-"+environment.Name+"-20260301-103000", &opts)
+			forge.AddContainerVolumeMount(&uploader, mountInfos[0].Name, mountInfos[0].MountPath, mountInfos[0].ReadOnly)
+
+			Expect(actual).To(Equal(batchv1.JobSpec{
+				BackoffLimit:            ptr.To[int32](forge.SubmissionJobMaxRetries),
+				TTLSecondsAfterFinished: ptr.To[int32](forge.SubmissionJobTTLSeconds),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers:                   []corev1.Container{uploader},
+						Volumes:                      forge.ContainerVolumes(&instance, &environment, mountInfos),
+						SecurityContext:              forge.PodSecurityContext(),
+						AutomountServiceAccountToken: ptr.To(false),
+						RestartPolicy:                corev1.RestartPolicyOnFailure,
+					},
+				},
+			}))
+		})
+	})
+
+	Describe("The forge.PreTerminationHookEphemeralContainer function forges the ephemeral container for a pre-termination hook", func() {
+		var actual corev1.EphemeralContainer
+		mountInfos := []corev1.VolumeMount{forge.MyDriveMountInfo("tenant")}
+		started := time.Date(2026, time.March, 1, 10, 30, 0, 0, time.UTC)
+
+		JustBeforeEach(func() {
+			actual = forge.PreTerminationHookEphemeralContainer(&instance, &environment, forge.PreTerminationHookMyDriveDestination, mountInfos, started, &opts)
+		})
+
+		It("Should set the correct container name, args and image", func() {
+			Expect(actual.Name).To(Equal(forge.PreTerminationHookName))
+			Expect(actual.Image).To(Equal("cont-tools:tag"))
+			Expect(actual.Args).To(Equal([]string{"upload"}))
+		})
+		It("Should NOT set resources", func() {
+			Expect(actual.Resources).To(Equal(corev1.ResourceRequirements{}))
+		})
+		It("Should mount both the persistent and the additional volumes", func() {
+			Expect(actual.VolumeMounts).To(ConsistOf(
+				HaveField("Name", forge.PersistentVolumeName),
+				HaveField("Name", mountInfos[0].Name),
+			))
+		})
+		It("Should set the destination of the archive", func() {
+			Expect(actual.Env).To(ContainElement(corev1.EnvVar{Name: "DESTINATION_URL", Value: forge.PreTerminationHookMyDriveDestination}))
+		})
+		It("Should timestamp the name of the archive", func() {
+			Expect(actual.Env).To(ContainElement(corev1.EnvVar{Name: "FILENAME", Value: instance.Name + "-" + environment.Name + "-20260301-103000"}))
+		})
+	})
+
 	Describe("The forge.ContentUploaderJobContainer function forges a container for content submission", func() {
 		const containerName = "content-uploader"
 		var actual, expected corev1.Container
@@ -0,0 +1,209 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge
+
+import (
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+)
+
+const (
+	// TransferJobSourcePath -> Path where the mirror of the source volume is mounted by the Transfer jobs.
+	TransferJobSourcePath = "/source"
+	// TransferJobTargetPath -> Path where the target volume is mounted by the Transfer jobs.
+	TransferJobTargetPath = "/target"
+	// TransferJobMaxRetries -> Maximum number of retries for Transfer jobs.
+	TransferJobMaxRetries = 2
+	// TransferJobTTLSeconds -> Seconds for Transfer jobs before deletion, in case they are not collected.
+	TransferJobTTLSeconds = 3600
+	// InstanceTransferClusterRolePrefix -> the prefix of the ClusterRole (and ClusterRoleBinding) granting access to a single InstanceTransfer.
+	InstanceTransferClusterRolePrefix = "crownlabs-instance-transfer-"
+)
+
+// InstanceTransferAuthorizationAnnotationValue returns the value of the annotation authorizing the personal
+// namespace of the recipient of an InstanceTransfer to mirror the volumes of the source Instance.
+func InstanceTransferAuthorizationAnnotationValue(recipient string) string {
+	return strings.Replace(MyDriveAuthorizationAnnotationValue, "{tenant-id}", recipient, 1)
+}
+
+// InstanceTransferLabels returns the labels identifying the resources created by the given InstanceTransfer.
+func InstanceTransferLabels(labels map[string]string, transfer *clv1alpha2.InstanceTransfer) map[string]string {
+	labels = deepCopyLabels(labels)
+	labels[LabelInstanceTransferKey] = transfer.Name
+	return labels
+}
+
+// InstanceTransferClusterRoleName returns the name of the ClusterRole (and ClusterRoleBinding) granting access to the given InstanceTransfer.
+func InstanceTransferClusterRoleName(transfer *clv1alpha2.InstanceTransfer) string {
+	return InstanceTransferClusterRolePrefix + transfer.Name
+}
+
+// ConfigureInstanceTransferClusterRole configures the ClusterRole granting access to the given InstanceTransfer only,
+// as transfers are cluster-scoped and shall be visible to the involved tenants only.
+func ConfigureInstanceTransferClusterRole(cr *rbacv1.ClusterRole, transfer *clv1alpha2.InstanceTransfer) {
+	cr.Labels = InstanceTransferLabels(cr.Labels, transfer)
+	cr.Rules = []rbacv1.PolicyRule{{
+		APIGroups:     []string{clv1alpha2.GroupVersion.Group},
+		Resources:     []string{"instancetransfers"},
+		ResourceNames: []string{transfer.Name},
+		Verbs:         []string{"get", "list", "watch", "update", "patch"},
+	}}
+}
+
+// ConfigureInstanceTransferClusterRoleBinding configures the ClusterRoleBinding granting access to the given InstanceTransfer
+// to its recipient and, if not empty, to the owner of the source Instance.
+func ConfigureInstanceTransferClusterRoleBinding(crb *rbacv1.ClusterRoleBinding, transfer *clv1alpha2.InstanceTransfer, owner string) {
+	crb.Labels = InstanceTransferLabels(crb.Labels, transfer)
+	crb.RoleRef = rbacv1.RoleRef{
+		Kind:     "ClusterRole",
+		Name:     InstanceTransferClusterRoleName(transfer),
+		APIGroup: rbacv1.GroupName,
+	}
+
+	tenants := []string{transfer.Spec.RecipientRef.Name}
+	if owner != "" {
+		tenants = append(tenants, owner)
+	}
+	crb.Subjects = make([]rbacv1.Subject, 0, len(tenants))
+	for _, tenant := range tenants {
+		crb.Subjects = append(crb.Subjects, rbacv1.Subject{Kind: rbacv1.UserKind, Name: tenant, APIGroup: rbacv1.GroupName})
+	}
+}
+
+// TransferredInstanceSpec forges the spec of the (stopped) copy of the source Instance, owned by the recipient.
+func TransferredInstanceSpec(source *clv1alpha2.Instance, recipient string) clv1alpha2.InstanceSpec {
+	return clv1alpha2.InstanceSpec{
+		Template:   source.Spec.Template,
+		Tenant:     clv1alpha2.GenericRef{Name: recipient},
+		Running:    false,
+		PrettyName: source.Spec.PrettyName,
+		// The copied disks preserve the size of the source ones.
+		DiskSizes: source.DeepCopy().Spec.DiskSizes,
+	}
+}
+
+// TransferDataVolumeSpec forges the spec of a DataVolume cloning the one of the given environment of the source Instance.
+func TransferDataVolumeSpec(environment *clv1alpha2.Environment, source *cdiv1beta1.DataVolume) (cdiv1beta1.DataVolumeSpec, error) {
+	spec, err := DataVolumeSpec(environment)
+	if err != nil {
+		return cdiv1beta1.DataVolumeSpec{}, err
+	}
+	return cloneDataVolumeSpec(spec, source), nil
+}
+
+// TransferAdditionalDiskDataVolumeSpec forges the spec of a DataVolume cloning the one of the given persistent additional disk of the source Instance.
+func TransferAdditionalDiskDataVolumeSpec(environment *clv1alpha2.Environment, disk *clv1alpha2.AdditionalDisk, source *cdiv1beta1.DataVolume) cdiv1beta1.DataVolumeSpec {
+	return cloneDataVolumeSpec(AdditionalDiskDataVolumeSpec(environment, disk), source)
+}
+
+// cloneDataVolumeSpec configures the given DataVolume spec to clone the source DataVolume, preserving its size and storage class.
+func cloneDataVolumeSpec(spec cdiv1beta1.DataVolumeSpec, source *cdiv1beta1.DataVolume) cdiv1beta1.DataVolumeSpec {
+	spec.Source = &cdiv1beta1.DataVolumeSource{
+		PVC: &cdiv1beta1.DataVolumeSourcePVC{
+			Namespace: source.Namespace,
+			Name:      source.Name,
+		},
+	}
+	if source.Spec.PVC != nil {
+		// Preserve the size of the source volume, which may have been expanded in the meanwhile
+		if size, ok := source.Spec.PVC.Resources.Requests[corev1.ResourceStorage]; ok {
+			spec.PVC.Resources.Requests[corev1.ResourceStorage] = size
+		}
+		spec.PVC.StorageClassName = source.Spec.PVC.StorageClassName
+	}
+	return spec
+}
+
+// TransferMirrorPVCSpec forges the spec of the read-only mirror of the persistent volume of the source Instance.
+func TransferMirrorPVCSpec(origin *corev1.PersistentVolumeClaim, mirrorStorageClassName string) corev1.PersistentVolumeClaimSpec {
+	spec := MirrorPVCSpec(origin, mirrorStorageClassName)
+	spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany}
+	return spec
+}
+
+// TransferPVCSpec forges the spec of the PVC receiving the copy of the persistent volume of the source Instance.
+func TransferPVCSpec(environment *clv1alpha2.Environment, source *corev1.PersistentVolumeClaim) corev1.PersistentVolumeClaimSpec {
+	spec := InstancePVCSpec(environment)
+	if size, ok := source.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: size}
+	}
+	return spec
+}
+
+// TransferMirrorPVCName returns the name of the mirror of the source volume, given the name of the target one.
+func TransferMirrorPVCName(targetName string) string {
+	return fmt.Sprintf("%s-source", targetName)
+}
+
+// PVCTransferJobSpec forges the spec for the job copying the content of the mirror of the source volume into the target one.
+func PVCTransferJobSpec(mirror, target *corev1.PersistentVolumeClaim) batchv1.JobSpec {
+	return batchv1.JobSpec{
+		BackoffLimit:            ptr.To[int32](TransferJobMaxRetries),
+		TTLSecondsAfterFinished: ptr.To[int32](TransferJobTTLSeconds),
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				RestartPolicy: corev1.RestartPolicyNever,
+				Containers: []corev1.Container{{
+					Name:    "transfer-container",
+					Image:   ProvisionJobBaseImage,
+					Command: []string{"cp", "-a", TransferJobSourcePath + "/.", TransferJobTargetPath},
+					VolumeMounts: []corev1.VolumeMount{{
+						Name:      "source",
+						MountPath: TransferJobSourcePath,
+						ReadOnly:  true,
+					}, {
+						Name:      "target",
+						MountPath: TransferJobTargetPath,
+					}},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							"cpu":    resource.MustParse("100m"),
+							"memory": resource.MustParse("128Mi"),
+						},
+						Limits: corev1.ResourceList{
+							"cpu":    resource.MustParse("500m"),
+							"memory": resource.MustParse("256Mi"),
+						},
+					},
+				}},
+				Volumes: []corev1.Volume{{
+					Name: "source",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: mirror.Name,
+							ReadOnly:  true,
+						},
+					},
+				}, {
+					Name: "target",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: target.Name,
+						},
+					},
+				}},
+			},
+		},
+	}
+}
@@ -51,6 +51,12 @@ const (
 	LabelNodeSelectorKey = "crownlabs.polito.it/has-node-selector"
 	// LabelEnvironmentKey is the key of the label identifying the environment name.
 	LabelEnvironmentKey = "crownlabs.polito.it/environment"
+	// LabelSharedVolumeKey is the key of the label identifying the shared volume name.
+	LabelSharedVolumeKey = "crownlabs.polito.it/shared-volume"
+	// LabelScheduledSnapshotKey is the key of the label identifying the snapshots created according to a schedule.
+	LabelScheduledSnapshotKey = "crownlabs.polito.it/scheduled-snapshot"
+	// LabelInstanceTransferKey is the key of the label identifying the InstanceTransfer a resource has been created by.
+	LabelInstanceTransferKey = "crownlabs.polito.it/instance-transfer"
 
 	// InstanceTerminationSelectorLabel -> label for Instances which have to be be checked for termination.
 	InstanceTerminationSelectorLabel = "crownlabs.polito.it/watch-for-instance-termination"
@@ -64,6 +70,8 @@ const (
 	InstanceInactivityIgnoreNamespace = "crownlabs.polito.it/instance-inactivity-ignore"
 	// ExpirationIgnoreNamespace -> label added to the Namespace to ignore expiration termination for Instances in it.
 	ExpirationIgnoreNamespace = "crownlabs.polito.it/expiration-ignore"
+	// AutomationDryRunLabel -> label added to a Template or a Namespace to only record the actions of the automation controllers, without performing them.
+	AutomationDryRunLabel = "crownlabs.polito.it/automation-dry-run"
 
 	// EnvironmentNameLabel -> Key of the label used to store the environment name.
 	EnvironmentNameLabel = "crownlabs.polito.it/environment-name"
@@ -127,10 +135,19 @@ const (
 	// ExpiringWarningNotificationTimestampAnnotation -> annotation to store the timestamp of the expiring warning notification.
 	ExpiringWarningNotificationTimestampAnnotation = "crownlabs.polito.it/expiring-warning-notification-timestamp"
 
+	// PreTerminationHookStartedAnnotation -> timestamp of the beginning of the pre-termination hooks of the instance.
+	PreTerminationHookStartedAnnotation = "crownlabs.polito.it/pre-termination-hook-started"
+
+	// ContentRevisionAnnotation -> revision of the content the volume has been populated with.
+	ContentRevisionAnnotation = "crownlabs.polito.it/content-revision"
+
 	// AuthorizationAnnotationKey is the key of the annotation that shows which labels are requested on the target namespace to mirror the PVC.
 	AuthorizationAnnotationKey = "pmp.crownlabs.polito.it/required-target-ns-labels"
 	// MyDriveAuthorizationAnnotationValue is the value of the annotation in case mirror origin is a MyDrive PVC.
 	MyDriveAuthorizationAnnotationValue = "crownlabs.polito.it/type=tenant,crownlabs.polito.it/name={tenant-id}"
+	// ReadOnlyMirrorAnnotationKey is the key of the annotation allowing read-only mirrors of a PVC accessible by a single node,
+	// which shall not be in use in the meanwhile (e.g., the volume of a stopped Instance being transferred).
+	ReadOnlyMirrorAnnotationKey = "pmp.crownlabs.polito.it/allow-read-only-mirrors"
 	// ShVolAuthorizationAnnotationValue is the value of the annotation in case mirror origin is a SharedVolume PVC.
 	ShVolAuthorizationAnnotationValue = "crownlabs.polito.it/type=tenant"
 )
@@ -263,6 +280,25 @@ func SharedVolumeObjectLabels(labels map[string]string) map[string]string {
 	return labels
 }
 
+// SharedVolumeSnapshotLabels receives in input a set of labels and returns the updated set depending on the specified shared volume snapshot.
+func SharedVolumeSnapshotLabels(labels map[string]string, snapshot *clv1alpha2.SharedVolumeSnapshot) (map[string]string, bool) {
+	labels = deepCopyLabels(labels)
+	update := false
+
+	update = updateLabel(labels, LabelManagedByKey, labelManagedByShVolValue) || update
+	update = updateLabel(labels, LabelSharedVolumeKey, snapshot.Spec.SharedVolumeName) || update
+
+	return labels, update
+}
+
+// ScheduledSnapshotSelectorLabels returns a set of labels to select the snapshots scheduled for the given shared volume.
+func ScheduledSnapshotSelectorLabels(shvol *clv1alpha2.SharedVolume) map[string]string {
+	return map[string]string{
+		LabelSharedVolumeKey:      shvol.Name,
+		LabelScheduledSnapshotKey: strconv.FormatBool(true),
+	}
+}
+
 // TenantLabels receives in input a set of labels and returns the updated set depending on the specified tenant.
 func TenantLabels(labels map[string]string, tenant *clv1alpha2.Tenant, targetLabel ctrlcommon.KVLabel) map[string]string {
 	labels = deepCopyLabels(labels)
@@ -706,6 +706,62 @@ var _ = Describe("Labels forging", func() {
 		})
 	})
 
+	Describe("The forge.SharedVolumeSnapshotLabels function", func() {
+		var (
+			snapshot clv1alpha2.SharedVolumeSnapshot
+			input    map[string]string
+			output   map[string]string
+			updated  bool
+		)
+
+		BeforeEach(func() {
+			snapshot = clv1alpha2.SharedVolumeSnapshot{Spec: clv1alpha2.SharedVolumeSnapshotSpec{SharedVolumeName: "shvol"}}
+		})
+
+		JustBeforeEach(func() {
+			output, updated = forge.SharedVolumeSnapshotLabels(input, &snapshot)
+		})
+
+		When("the labels are not yet configured", func() {
+			BeforeEach(func() {
+				input = map[string]string{"key": "value"}
+			})
+
+			It("Should add the managed-by and shared-volume labels", func() {
+				Expect(output).To(Equal(map[string]string{
+					"key":                               "value",
+					"crownlabs.polito.it/managed-by":    "sharedvolume",
+					"crownlabs.polito.it/shared-volume": "shvol",
+				}))
+				Expect(updated).To(BeTrue())
+			})
+		})
+
+		When("the labels are already configured", func() {
+			BeforeEach(func() {
+				input = map[string]string{
+					"crownlabs.polito.it/managed-by":    "sharedvolume",
+					"crownlabs.polito.it/shared-volume": "shvol",
+				}
+			})
+
+			It("Should not update the labels", func() {
+				Expect(output).To(Equal(input))
+				Expect(updated).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("The forge.ScheduledSnapshotSelectorLabels function", func() {
+		It("Should select the scheduled snapshots of the shared volume", func() {
+			shvol := clv1alpha2.SharedVolume{ObjectMeta: metav1.ObjectMeta{Name: "shvol"}}
+			Expect(forge.ScheduledSnapshotSelectorLabels(&shvol)).To(Equal(map[string]string{
+				"crownlabs.polito.it/shared-volume":      "shvol",
+				"crownlabs.polito.it/scheduled-snapshot": "true",
+			}))
+		})
+	})
+
 	Describe("UpdateWorkspaceResourceCommonLabels", func() {
 		It("Should add target label and managed-by label to existing labels", func() {
 			inputLabels := map[string]string{
@@ -50,8 +50,8 @@ func GetWorkspaceTenantsManagerBindingName(ws *clv1alpha1.Workspace) string {
 }
 
 // ConfigureWorkspaceInstancesManagerBinding configures the RoleRef and Subjects for a ClusterRoleBinding
-// that grants permissions to manage instances in a workspace.
-func ConfigureWorkspaceInstancesManagerBinding(ws *clv1alpha1.Workspace, crb *rbacv1.ClusterRoleBinding) {
+// that grants permissions to manage instances in a workspace. The managers of the given ancestors inherit the permissions.
+func ConfigureWorkspaceInstancesManagerBinding(ws *clv1alpha1.Workspace, crb *rbacv1.ClusterRoleBinding, ancestors ...string) {
 	// Configure the RoleRef for instances management
 	crb.RoleRef = rbacv1.RoleRef{
 		Kind:     "ClusterRole",
@@ -59,19 +59,13 @@ func ConfigureWorkspaceInstancesManagerBinding(ws *clv1alpha1.Workspace, crb *rb
 		APIGroup: rbacv1.GroupName,
 	}
 
-	// Set the subjects (Workspace Managers)
-	crb.Subjects = []rbacv1.Subject{
-		{
-			Kind:     rbacv1.GroupKind,
-			Name:     fmt.Sprintf("kubernetes:%s", WorkspaceRoleName(ws.Name, clv1alpha2.Manager)),
-			APIGroup: rbacv1.GroupName,
-		},
-	}
+	// Set the subjects (Workspace and ancestors Managers)
+	crb.Subjects = workspaceGroupSubjects(clv1alpha2.Manager, append([]string{ws.Name}, ancestors...)...)
 }
 
 // ConfigureWorkspaceTenantsManagerBinding configures the RoleRef and Subjects for a ClusterRoleBinding
-// that grants permissions to manage tenants in a workspace.
-func ConfigureWorkspaceTenantsManagerBinding(ws *clv1alpha1.Workspace, crb *rbacv1.ClusterRoleBinding) {
+// that grants permissions to manage tenants in a workspace. The managers of the given ancestors inherit the permissions.
+func ConfigureWorkspaceTenantsManagerBinding(ws *clv1alpha1.Workspace, crb *rbacv1.ClusterRoleBinding, ancestors ...string) {
 	// Configure the RoleRef for tenants management
 	crb.RoleRef = rbacv1.RoleRef{
 		Kind:     "ClusterRole",
@@ -79,14 +73,8 @@ func ConfigureWorkspaceTenantsManagerBinding(ws *clv1alpha1.Workspace, crb *rbac
 		APIGroup: rbacv1.GroupName,
 	}
 
-	// Set the subjects (Workspace Managers)
-	crb.Subjects = []rbacv1.Subject{
-		{
-			Kind:     rbacv1.GroupKind,
-			Name:     fmt.Sprintf("kubernetes:%s", WorkspaceRoleName(ws.Name, clv1alpha2.Manager)),
-			APIGroup: rbacv1.GroupName,
-		},
-	}
+	// Set the subjects (Workspace and ancestors Managers)
+	crb.Subjects = workspaceGroupSubjects(clv1alpha2.Manager, append([]string{ws.Name}, ancestors...)...)
 }
 
 // ResourceObjectMeta returns a generic ObjectMeta for a resource.
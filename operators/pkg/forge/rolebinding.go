@@ -15,7 +15,6 @@
 package forge
 
 import (
-	"fmt"
 	"maps"
 
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -36,7 +35,8 @@ const (
 )
 
 // ConfigureWorkspaceUserViewTemplatesBinding configures a RoleBinding for a workspace user to view templates.
-func ConfigureWorkspaceUserViewTemplatesBinding(ws *clv1alpha1.Workspace, rb *rbacv1.RoleBinding, labels map[string]string) {
+// The users of the given descendants inherit the visibility of the templates.
+func ConfigureWorkspaceUserViewTemplatesBinding(ws *clv1alpha1.Workspace, rb *rbacv1.RoleBinding, labels map[string]string, descendants ...string) {
 	// Set labels
 	if rb.Labels == nil {
 		rb.Labels = make(map[string]string)
@@ -51,17 +51,12 @@ func ConfigureWorkspaceUserViewTemplatesBinding(ws *clv1alpha1.Workspace, rb *rb
 	}
 
 	// Configure Subjects
-	rb.Subjects = []rbacv1.Subject{
-		{
-			Kind:     rbacv1.GroupKind,
-			Name:     fmt.Sprintf("kubernetes:%s", WorkspaceRoleName(ws.Name, clv1alpha2.User)),
-			APIGroup: rbacv1.GroupName,
-		},
-	}
+	rb.Subjects = workspaceGroupSubjects(clv1alpha2.User, append([]string{ws.Name}, descendants...)...)
 }
 
 // ConfigureWorkspaceManagerManageTemplatesBinding configures a RoleBinding for a workspace manager to manage templates.
-func ConfigureWorkspaceManagerManageTemplatesBinding(ws *clv1alpha1.Workspace, rb *rbacv1.RoleBinding, labels map[string]string) {
+// The managers of the given ancestors inherit the permissions.
+func ConfigureWorkspaceManagerManageTemplatesBinding(ws *clv1alpha1.Workspace, rb *rbacv1.RoleBinding, labels map[string]string, ancestors ...string) {
 	// Set labels
 	if rb.Labels == nil {
 		rb.Labels = make(map[string]string)
@@ -78,17 +73,12 @@ func ConfigureWorkspaceManagerManageTemplatesBinding(ws *clv1alpha1.Workspace, r
 	}
 
 	// Configure Subjects
-	rb.Subjects = []rbacv1.Subject{
-		{
-			Kind:     rbacv1.GroupKind,
-			Name:     fmt.Sprintf("kubernetes:%s", WorkspaceRoleName(ws.Name, clv1alpha2.Manager)),
-			APIGroup: rbacv1.GroupName,
-		},
-	}
+	rb.Subjects = workspaceGroupSubjects(clv1alpha2.Manager, append([]string{ws.Name}, ancestors...)...)
 }
 
 // ConfigureWorkspaceManagerManageSharedVolumesBinding configures a RoleBinding for a workspace manager to manage shared volumes.
-func ConfigureWorkspaceManagerManageSharedVolumesBinding(ws *clv1alpha1.Workspace, rb *rbacv1.RoleBinding, labels map[string]string) {
+// The managers of the given ancestors inherit the permissions.
+func ConfigureWorkspaceManagerManageSharedVolumesBinding(ws *clv1alpha1.Workspace, rb *rbacv1.RoleBinding, labels map[string]string, ancestors ...string) {
 	// Set labels
 	if rb.Labels == nil {
 		rb.Labels = make(map[string]string)
@@ -103,13 +93,7 @@ func ConfigureWorkspaceManagerManageSharedVolumesBinding(ws *clv1alpha1.Workspac
 	}
 
 	// Configure Subjects
-	rb.Subjects = []rbacv1.Subject{
-		{
-			Kind:     rbacv1.GroupKind,
-			Name:     fmt.Sprintf("kubernetes:%s", WorkspaceRoleName(ws.Name, clv1alpha2.Manager)),
-			APIGroup: rbacv1.GroupName,
-		},
-	}
+	rb.Subjects = workspaceGroupSubjects(clv1alpha2.Manager, append([]string{ws.Name}, ancestors...)...)
 }
 
 // ConfigurePersonalWorkspaceManageTemplatesBinding configures a RoleBinding for a tenant to manage templates.
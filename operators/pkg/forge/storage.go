@@ -19,8 +19,12 @@ package forge
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -39,6 +43,18 @@ const (
 	ProvisionJobMaxRetries = 3
 	// ProvisionJobTTLSeconds -> Seconds for Provision jobs before deletion (either failure or success).
 	ProvisionJobTTLSeconds = 3600 * 24 * 7
+
+	// UsageJobMountPath -> Path where the volume is mounted by the Usage jobs.
+	UsageJobMountPath = "/volume"
+	// UsageJobMaxRetries -> Maximum number of retries for Usage jobs.
+	UsageJobMaxRetries = 1
+	// UsageJobTTLSeconds -> Seconds for Usage jobs before deletion, in case they are not collected.
+	UsageJobTTLSeconds = 3600
+
+	// ContentSyncJobMaxRetries -> Maximum number of retries for Content Sync jobs.
+	ContentSyncJobMaxRetries = 2
+	// ContentSyncJobTTLSeconds -> Seconds for Content Sync jobs before deletion, in case they are not collected.
+	ContentSyncJobTTLSeconds = 3600
 )
 
 var (
@@ -100,15 +116,83 @@ func PVCMountInfosFromEnvironment(ctx context.Context, c client.Client) ([]corev
 			), err
 		}
 
+		access := SharedVolumeAccess(&shvol, tenant, &clv1alpha2.GenericRef{Name: template.Name, Namespace: template.Namespace})
+		if access == clv1alpha2.SharedVolumeAccessNone {
+			return nil, fmt.Sprintf(
+				"access to SharedVolume %q in namespace %q denied for environment %%s",
+				mount.SharedVolumeRef.Name,
+				mount.SharedVolumeRef.Namespace,
+			), fmt.Errorf("tenant %s is not allowed to mount SharedVolume %s/%s", tenant.Name, shvol.Namespace, shvol.Name)
+		}
+
 		if isManager {
 			mount.ReadOnly = false
 		}
+		if access == clv1alpha2.SharedVolumeAccessReadOnly {
+			mount.ReadOnly = true
+		}
 		mountInfos = append(mountInfos, ShVolMountInfo(mount, instance.Name))
 	}
 
 	return mountInfos, "", nil
 }
 
+// SharedVolumeAccess returns the access granted on the shared volume to the given tenant, when mounting it
+// through an instance of the given template (nil if unknown, hence matching only the rules not specifying it).
+// Shared volumes without access control rules are accessible in read-write mode by any tenant, while the most
+// permissive access among the matching rules is granted otherwise.
+func SharedVolumeAccess(shvol *clv1alpha2.SharedVolume, tenant *clv1alpha2.Tenant, template *clv1alpha2.GenericRef) clv1alpha2.SharedVolumeAccessMode {
+	if len(shvol.Spec.AccessControl) == 0 {
+		return clv1alpha2.SharedVolumeAccessReadWrite
+	}
+
+	access := clv1alpha2.SharedVolumeAccessNone
+	for i := range shvol.Spec.AccessControl {
+		rule := &shvol.Spec.AccessControl[i]
+		if !sharedVolumeAccessRuleMatches(rule, shvol.Namespace, tenant, template) {
+			continue
+		}
+		if rule.Access == clv1alpha2.SharedVolumeAccessReadWrite {
+			return clv1alpha2.SharedVolumeAccessReadWrite
+		}
+		access = rule.Access
+	}
+	return access
+}
+
+// sharedVolumeAccessRuleMatches returns whether the tenant and the template match all the criteria of the rule.
+func sharedVolumeAccessRuleMatches(rule *clv1alpha2.SharedVolumeAccessRule, namespace string,
+	tenant *clv1alpha2.Tenant, template *clv1alpha2.GenericRef) bool {
+	if rule.Tenant != "" && rule.Tenant != tenant.Name {
+		return false
+	}
+
+	if rule.Workspace != "" {
+		if slices.Contains(tenant.Status.InactiveWorkspaces, rule.Workspace) {
+			return false
+		}
+		if !slices.ContainsFunc(tenant.Spec.Workspaces, func(ws clv1alpha2.TenantWorkspaceEntry) bool {
+			return ws.Name == rule.Workspace && ws.Role != clv1alpha2.Candidate && (rule.Role == "" || ws.Role == rule.Role)
+		}) {
+			return false
+		}
+	}
+
+	if rule.Template != nil {
+		if template == nil || rule.Template.Name != template.Name {
+			return false
+		}
+		if templateNamespace := rule.Template.Namespace; templateNamespace != "" {
+			namespace = templateNamespace
+		}
+		if namespace != template.Namespace {
+			return false
+		}
+	}
+
+	return true
+}
+
 // PVCProvisioningJobSpec forges the spec for the PVC Provisioning job.
 func PVCProvisioningJobSpec(pvc *corev1.PersistentVolumeClaim) batchv1.JobSpec {
 	return batchv1.JobSpec{
@@ -149,6 +233,83 @@ func PVCProvisioningJobSpec(pvc *corev1.PersistentVolumeClaim) batchv1.JobSpec {
 	}
 }
 
+// PVCUsageJobSpec forges the spec for the job measuring the usage of a PVC.
+// The output of df (in POSIX format, with 1024-byte blocks) is reported as termination message of the container.
+func PVCUsageJobSpec(pvc *corev1.PersistentVolumeClaim) batchv1.JobSpec {
+	return batchv1.JobSpec{
+		BackoffLimit:            ptr.To[int32](UsageJobMaxRetries),
+		TTLSecondsAfterFinished: ptr.To[int32](UsageJobTTLSeconds),
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				RestartPolicy: corev1.RestartPolicyNever,
+				Containers: []corev1.Container{{
+					Name:    "usage-container",
+					Image:   ProvisionJobBaseImage,
+					Command: []string{"sh", "-c", fmt.Sprintf("df -Pk %s | tail -n 1 > %s", UsageJobMountPath, corev1.TerminationMessagePathDefault)},
+					VolumeMounts: []corev1.VolumeMount{{
+						Name:      "volume",
+						MountPath: UsageJobMountPath,
+						ReadOnly:  true,
+					}},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							"cpu":    resource.MustParse("10m"),
+							"memory": resource.MustParse("16Mi"),
+						},
+						Limits: corev1.ResourceList{
+							"cpu":    resource.MustParse("100m"),
+							"memory": resource.MustParse("32Mi"),
+						},
+					},
+				}},
+				Volumes: []corev1.Volume{{
+					Name: "volume",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvc.Name,
+							ReadOnly:  true,
+						},
+					},
+				}},
+			},
+		},
+	}
+}
+
+// PVCContentSyncJobSpec forges the spec for the job populating a PVC with the content retrieved from the given source,
+// optionally overwriting the files already present. The revision of the content is reported as termination message of the container.
+func PVCContentSyncJobSpec(pvc *corev1.PersistentVolumeClaim, source *clv1alpha2.ContentSource, overwrite bool, opts *ContainerEnvOpts) batchv1.JobSpec {
+	var container corev1.Container
+	if source.Git != nil {
+		container = ContentGitSyncContainer(source.Git, opts)
+	} else {
+		container = ContentDownloaderInitContainer(source.ArchiveURL, opts)
+		SetContainerArgs(&container, "download")
+	}
+	AddEnvVariableToContainer(&container, "OVERWRITE", strconv.FormatBool(overwrite))
+	AddEnvVariableToContainer(&container, "REVISION_PATH", corev1.TerminationMessagePathDefault)
+
+	return batchv1.JobSpec{
+		BackoffLimit:            ptr.To[int32](ContentSyncJobMaxRetries),
+		TTLSecondsAfterFinished: ptr.To[int32](ContentSyncJobTTLSeconds),
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				RestartPolicy:   corev1.RestartPolicyNever,
+				SecurityContext: PodSecurityContext(),
+				Containers:      []corev1.Container{container},
+				Volumes: []corev1.Volume{{
+					Name: PersistentVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvc.Name,
+						},
+					},
+				}},
+			},
+		},
+	}
+}
+
 // MyDrivePVCName returns the name for a tenant's MyDrive PVC.
 func MyDrivePVCName(tenantName string) string {
 	return fmt.Sprintf("%s-drive", strings.ReplaceAll(tenantName, ".", "-"))
@@ -179,6 +340,34 @@ func ShVolPVCMirrorName(shvolName, instanceName string) string {
 	return fmt.Sprintf("%s-%s-mirror", LastCharsOf(shvolName, 27), LastCharsOf(instanceName, 27))
 }
 
+// ShVolScheduledSnapshotName returns the name of the snapshot of a SharedVolume scheduled at the given time.
+func ShVolScheduledSnapshotName(shvolName string, timestamp time.Time) string {
+	// The timestamp is 15 characters long, and the name is also used as label value, hence limited to 63 characters.
+	return fmt.Sprintf("%s-%s", LastCharsOf(shvolName, 47), timestamp.UTC().Format("20060102-150405"))
+}
+
+// ShVolVolumeSnapshotSpec forges the spec of the CSI VolumeSnapshot of the PVC of a SharedVolume.
+func ShVolVolumeSnapshotSpec(shvolName, snapshotClassName string) snapshotv1.VolumeSnapshotSpec {
+	spec := snapshotv1.VolumeSnapshotSpec{
+		Source: snapshotv1.VolumeSnapshotSource{
+			PersistentVolumeClaimName: ptr.To(ShVolPVCName(shvolName)),
+		},
+	}
+	if snapshotClassName != "" {
+		spec.VolumeSnapshotClassName = ptr.To(snapshotClassName)
+	}
+	return spec
+}
+
+// ShVolRestoreDataSource forges the data source to restore the PVC of a SharedVolume from the given CSI VolumeSnapshot.
+func ShVolRestoreDataSource(volumeSnapshotName string) *corev1.TypedLocalObjectReference {
+	return &corev1.TypedLocalObjectReference{
+		APIGroup: ptr.To(snapshotv1.GroupName),
+		Kind:     "VolumeSnapshot",
+		Name:     volumeSnapshotName,
+	}
+}
+
 // MyDrivePVCSpec forges the spec for the PVC for tenant's MyDrive storage.
 func MyDrivePVCSpec(storageClassName string, storageSize resource.Quantity) corev1.PersistentVolumeClaimSpec {
 	return corev1.PersistentVolumeClaimSpec{
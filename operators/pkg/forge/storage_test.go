@@ -18,10 +18,13 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -137,6 +140,136 @@ var _ = Describe("External Volume Mounts and Provisioning Job forging", func() {
 		})
 	})
 
+	Describe("The forge.PVCUsageJobSpec function", func() {
+		It("Should have the right spec", func() {
+			pvc := corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "shvol-0000"}}
+			actual := forge.PVCUsageJobSpec(&pvc)
+			Expect(actual.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+			Expect(actual.Template.Spec.Containers[0].Command).To(ContainElement(ContainSubstring("df -Pk " + forge.UsageJobMountPath)))
+			Expect(actual.Template.Spec.Containers[0].VolumeMounts[0].ReadOnly).To(BeTrue())
+			Expect(actual.Template.Spec.Volumes[0].VolumeSource.PersistentVolumeClaim.ClaimName).To(Equal("shvol-0000"))
+		})
+	})
+
+	Describe("The forge.PVCContentSyncJobSpec function", func() {
+		var (
+			pvc    = corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "shvol-0000"}}
+			opts   = forge.ContainerEnvOpts{ContentToolsImg: "crownlabs/content-tools", ImagesTag: "v1"}
+			source clv1alpha2.ContentSource
+			actual batchv1.JobSpec
+		)
+
+		JustBeforeEach(func() {
+			actual = forge.PVCContentSyncJobSpec(&pvc, &source, true, &opts)
+		})
+
+		When("the source is an archive", func() {
+			BeforeEach(func() {
+				source = clv1alpha2.ContentSource{ArchiveURL: "https://example.com/course.zip"}
+			})
+
+			It("Should download the archive, overwriting the existing files", func() {
+				container := actual.Template.Spec.Containers[0]
+				Expect(container.Name).To(Equal(forge.ContentDownloaderName))
+				Expect(container.Image).To(Equal("crownlabs/content-tools:v1"))
+				Expect(container.Args).To(ConsistOf("download"))
+				Expect(container.Env).To(ContainElements(
+					corev1.EnvVar{Name: "SOURCE_ARCHIVE", Value: "https://example.com/course.zip"},
+					corev1.EnvVar{Name: "OVERWRITE", Value: "true"},
+					corev1.EnvVar{Name: "REVISION_PATH", Value: corev1.TerminationMessagePathDefault},
+				))
+				Expect(actual.Template.Spec.Volumes[0].VolumeSource.PersistentVolumeClaim.ClaimName).To(Equal("shvol-0000"))
+			})
+		})
+
+		When("the source is a Git repository", func() {
+			BeforeEach(func() {
+				source = clv1alpha2.ContentSource{Git: &clv1alpha2.GitContentSource{Repository: "https://example.com/course.git", Ref: "v2"}}
+			})
+
+			It("Should check out the repository, overwriting the existing files", func() {
+				container := actual.Template.Spec.Containers[0]
+				Expect(container.Name).To(Equal(forge.ContentGitSyncName))
+				Expect(container.Args).To(ConsistOf("git-sync"))
+				Expect(container.Env).To(ContainElements(
+					corev1.EnvVar{Name: "GIT_REPOSITORY", Value: "https://example.com/course.git"},
+					corev1.EnvVar{Name: "GIT_REF", Value: "v2"},
+					corev1.EnvVar{Name: "DESTINATION_PATH", Value: forge.PersistentDefaultMountPath},
+					corev1.EnvVar{Name: "OVERWRITE", Value: "true"},
+					corev1.EnvVar{Name: "REVISION_PATH", Value: corev1.TerminationMessagePathDefault},
+				))
+				Expect(container.VolumeMounts[0].Name).To(Equal(actual.Template.Spec.Volumes[0].Name))
+				Expect(actual.Template.Spec.SecurityContext.RunAsUser).To(PointTo(Equal(forge.CrownLabsUserID)))
+			})
+		})
+	})
+
+	Describe("The forge.SharedVolumeAccess function", func() {
+		var (
+			shvol    clv1alpha2.SharedVolume
+			tenant   clv1alpha2.Tenant
+			template *clv1alpha2.GenericRef
+		)
+
+		BeforeEach(func() {
+			shvol = clv1alpha2.SharedVolume{ObjectMeta: metav1.ObjectMeta{Name: "shvol", Namespace: "workspace-netgroup"}}
+			tenant = clv1alpha2.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: "tester"},
+				Spec: clv1alpha2.TenantSpec{Workspaces: []clv1alpha2.TenantWorkspaceEntry{
+					{Name: "netgroup", Role: clv1alpha2.User},
+					{Name: "sandbox", Role: clv1alpha2.Candidate},
+				}},
+			}
+			template = &clv1alpha2.GenericRef{Name: "kubernetes", Namespace: "workspace-netgroup"}
+		})
+
+		access := func() clv1alpha2.SharedVolumeAccessMode {
+			return forge.SharedVolumeAccess(&shvol, &tenant, template)
+		}
+
+		It("Should grant read-write access if no rules are present", func() {
+			Expect(access()).To(Equal(clv1alpha2.SharedVolumeAccessReadWrite))
+		})
+
+		It("Should deny access if no rule matches", func() {
+			shvol.Spec.AccessControl = []clv1alpha2.SharedVolumeAccessRule{
+				{Tenant: "other", Access: clv1alpha2.SharedVolumeAccessReadWrite},
+				{Workspace: "netgroup", Role: clv1alpha2.Manager, Access: clv1alpha2.SharedVolumeAccessReadWrite},
+				{Workspace: "sandbox", Access: clv1alpha2.SharedVolumeAccessReadOnly},
+			}
+			Expect(access()).To(Equal(clv1alpha2.SharedVolumeAccessNone))
+		})
+
+		It("Should grant the most permissive access among the matching rules", func() {
+			shvol.Spec.AccessControl = []clv1alpha2.SharedVolumeAccessRule{
+				{Workspace: "netgroup", Access: clv1alpha2.SharedVolumeAccessReadOnly},
+				{Tenant: "tester", Workspace: "netgroup", Role: clv1alpha2.User, Access: clv1alpha2.SharedVolumeAccessReadWrite},
+			}
+			Expect(access()).To(Equal(clv1alpha2.SharedVolumeAccessReadWrite))
+		})
+
+		It("Should not match the workspaces whose membership is inactive", func() {
+			shvol.Spec.AccessControl = []clv1alpha2.SharedVolumeAccessRule{
+				{Workspace: "netgroup", Access: clv1alpha2.SharedVolumeAccessReadOnly},
+			}
+			tenant.Status.InactiveWorkspaces = []string{"netgroup"}
+			Expect(access()).To(Equal(clv1alpha2.SharedVolumeAccessNone))
+		})
+
+		It("Should match the template, defaulting to the namespace of the shared volume", func() {
+			shvol.Spec.AccessControl = []clv1alpha2.SharedVolumeAccessRule{
+				{Template: &clv1alpha2.GenericRef{Name: "kubernetes"}, Access: clv1alpha2.SharedVolumeAccessReadOnly},
+			}
+			Expect(access()).To(Equal(clv1alpha2.SharedVolumeAccessReadOnly))
+
+			template = &clv1alpha2.GenericRef{Name: "kubernetes", Namespace: "workspace-other"}
+			Expect(access()).To(Equal(clv1alpha2.SharedVolumeAccessNone))
+
+			template = nil
+			Expect(access()).To(Equal(clv1alpha2.SharedVolumeAccessNone))
+		})
+	})
+
 	Context("Functions that interact with k8s", func() {
 		var (
 			ctx           context.Context
@@ -351,6 +484,50 @@ var _ = Describe("External Volume Mounts and Provisioning Job forging", func() {
 				})
 			})
 
+			Context("The shared volume has access control rules", func() {
+				BeforeEach(func() {
+					environment = clv1alpha2.Environment{
+						Name: environmentName,
+						SharedVolumeMounts: []clv1alpha2.SharedVolumeMountInfo{
+							{
+								SharedVolumeRef: shVolRef,
+								MountPath:       shVolMountPath,
+								ReadOnly:        false,
+							},
+						},
+					}
+					ctx, _ = clctx.EnvironmentInto(ctx, &environment)
+				})
+
+				When("The tenant is granted read-only access", func() {
+					BeforeEach(func() {
+						tenant.Spec.Workspaces = []clv1alpha2.TenantWorkspaceEntry{{Name: workspaceName, Role: clv1alpha2.User}}
+						shvol.Spec.AccessControl = []clv1alpha2.SharedVolumeAccessRule{
+							{Workspace: workspaceName, Access: clv1alpha2.SharedVolumeAccessReadOnly},
+						}
+					})
+
+					It("Should mount the shvol as read-only", func() {
+						Expect(err).ToNot(HaveOccurred())
+						Expect(mountInfos).To(HaveLen(1))
+						Expect(mountInfos[0].ReadOnly).To(BeTrue())
+					})
+				})
+
+				When("The tenant is not matched by any rule", func() {
+					BeforeEach(func() {
+						shvol.Spec.AccessControl = []clv1alpha2.SharedVolumeAccessRule{
+							{Tenant: tenantMgrName, Access: clv1alpha2.SharedVolumeAccessReadWrite},
+						}
+					})
+
+					It("Should deny the mount", func() {
+						Expect(err).To(HaveOccurred())
+						Expect(mountInfos).To(BeNil())
+					})
+				})
+			})
+
 			When("The environment requires both personal and shared volumes", func() {
 				BeforeEach(func() {
 					environment = clv1alpha2.Environment{
@@ -527,4 +704,39 @@ var _ = Describe("External Volume Mounts and Provisioning Job forging", func() {
 
 		})
 	})
+
+	Describe("ShVolScheduledSnapshotName", func() {
+		It("Should append the UTC timestamp to the shared volume name", func() {
+			timestamp := time.Date(2024, time.March, 5, 14, 30, 15, 0, time.FixedZone("CET", 3600))
+			Expect(forge.ShVolScheduledSnapshotName("shvol", timestamp)).To(Equal("shvol-20240305-133015"))
+		})
+
+		It("Should truncate long shared volume names to fit a label value", func() {
+			name := forge.ShVolScheduledSnapshotName(strings.Repeat("a", 100), time.Now())
+			Expect(len(name)).To(BeNumerically("<=", 63))
+		})
+	})
+
+	Describe("ShVolVolumeSnapshotSpec", func() {
+		It("Should snapshot the PVC of the shared volume with the default class", func() {
+			spec := forge.ShVolVolumeSnapshotSpec("shvol", "")
+			Expect(spec.Source.PersistentVolumeClaimName).To(PointTo(Equal(forge.ShVolPVCName("shvol"))))
+			Expect(spec.Source.VolumeSnapshotContentName).To(BeNil())
+			Expect(spec.VolumeSnapshotClassName).To(BeNil())
+		})
+
+		It("Should use the specified snapshot class", func() {
+			spec := forge.ShVolVolumeSnapshotSpec("shvol", "csi-snapclass")
+			Expect(spec.VolumeSnapshotClassName).To(PointTo(Equal("csi-snapclass")))
+		})
+	})
+
+	Describe("ShVolRestoreDataSource", func() {
+		It("Should reference the given VolumeSnapshot", func() {
+			source := forge.ShVolRestoreDataSource("snapshot")
+			Expect(source.APIGroup).To(PointTo(Equal("snapshot.storage.k8s.io")))
+			Expect(source.Kind).To(Equal("VolumeSnapshot"))
+			Expect(source.Name).To(Equal("snapshot"))
+		})
+	})
 })
@@ -19,12 +19,15 @@ import (
 	"maps"
 	"regexp"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apicommon "github.com/netgroup-polito/CrownLabs/operators/api/common"
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
 	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
 	ctrlcommon "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/common"
 )
@@ -64,6 +67,50 @@ func UpdateMyDrivePVCAnnotations(annotations map[string]string, tenantName strin
 	return annotations
 }
 
+// UpdateMyDriveContentRevisionAnnotation updates the annotations of the MyDrive PVC with the revision of its initial content.
+func UpdateMyDriveContentRevisionAnnotation(annotations map[string]string, revision string) map[string]string {
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[ContentRevisionAnnotation] = revision
+
+	return annotations
+}
+
+// MyDriveSize returns the size of the MyDrive of a tenant: the one configured in the tenant itself if any,
+// otherwise the largest between the default size and the ones configured in the given workspaces.
+func MyDriveSize(defaultSize resource.Quantity, tenantSize *resource.Quantity, workspaces []clv1alpha1.Workspace) resource.Quantity {
+	if tenantSize != nil {
+		return tenantSize.DeepCopy()
+	}
+
+	size := defaultSize.DeepCopy()
+	for i := range workspaces {
+		if wsSize := workspaces[i].Spec.MyDriveSize; wsSize != nil && wsSize.Cmp(size) > 0 {
+			size = wsSize.DeepCopy()
+		}
+	}
+	return size
+}
+
+// MyDriveUsageThreshold returns the highest of the given thresholds (in percentage)
+// crossed by the usage of the MyDrive, or zero if none is crossed.
+func MyDriveUsageThreshold(usage *clv1alpha2.MyDriveUsage, thresholds []int) int {
+	total := usage.Used.Value() + usage.Available.Value()
+	if total <= 0 {
+		return 0
+	}
+
+	crossed := 0
+	percentage := float64(usage.Used.Value()) * 100 / float64(total)
+	for _, threshold := range thresholds {
+		if percentage >= float64(threshold) && threshold > crossed {
+			crossed = threshold
+		}
+	}
+	return crossed
+}
+
 // CleanTenantName sanitizes a tenant name by replacing spaces with underscores and removing
 // any characters that are not alphanumeric or underscores. It also trims leading
 // and trailing underscores.
@@ -139,3 +186,51 @@ func ConfigureTenantAllowNetworkPolicy(policy *netv1.NetworkPolicy, labels map[s
 		}},
 	}}
 }
+
+// GetEnrollmentRequestName returns the name of the EnrollmentRequest of a tenant for the given workspace.
+func GetEnrollmentRequestName(tenantName, workspaceName string) string {
+	return fmt.Sprintf("%s.%s", tenantName, workspaceName)
+}
+
+// EnrollmentRequestLabels returns the labels identifying the tenant and the workspace of an EnrollmentRequest.
+func EnrollmentRequestLabels(labels map[string]string, tenantName, workspaceName string) map[string]string {
+	labels = deepCopyLabels(labels)
+	labels[LabelTenantKey] = tenantName
+	labels[LabelWorkspaceKey] = workspaceName
+
+	return labels
+}
+
+// IsWorkspaceMembershipActive returns whether the membership in the workspace is valid at the given time.
+func IsWorkspaceMembershipActive(entry *clv1alpha2.TenantWorkspaceEntry, now time.Time) bool {
+	if entry.ValidFrom != nil && now.Before(entry.ValidFrom.Time) {
+		return false
+	}
+	return entry.ValidUntil == nil || now.Before(entry.ValidUntil.Time)
+}
+
+// NextWorkspaceMembershipChange returns the first instant after now at which the validity of any of the
+// memberships changes, or at which the expiration notice period of an active membership starts. The zero
+// value is returned if no change is expected.
+func NextWorkspaceMembershipChange(entries []clv1alpha2.TenantWorkspaceEntry, now time.Time, notice time.Duration) time.Time {
+	var next time.Time
+	consider := func(t time.Time) {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+
+	for i := range entries {
+		if entries[i].ValidFrom != nil {
+			consider(entries[i].ValidFrom.Time)
+		}
+		if entries[i].ValidUntil != nil {
+			consider(entries[i].ValidUntil.Time)
+			if notice > 0 {
+				consider(entries[i].ValidUntil.Add(-notice))
+			}
+		}
+	}
+
+	return next
+}
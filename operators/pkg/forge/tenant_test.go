@@ -16,14 +16,18 @@ package forge_test
 
 import (
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	apicommon "github.com/netgroup-polito/CrownLabs/operators/api/common"
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
 	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
 	ctrlcommon "github.com/netgroup-polito/CrownLabs/operators/pkg/controller/common"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
@@ -264,6 +268,58 @@ var _ = Describe("Tenant forging", func() {
 		})
 	})
 
+	Describe("The forge.MyDriveSize function", func() {
+		var workspaces []clv1alpha1.Workspace
+
+		BeforeEach(func() {
+			workspaces = []clv1alpha1.Workspace{
+				{Spec: clv1alpha1.WorkspaceSpec{MyDriveSize: ptr.To(resource.MustParse("10Gi"))}},
+				{Spec: clv1alpha1.WorkspaceSpec{MyDriveSize: ptr.To(resource.MustParse("20Gi"))}},
+				{Spec: clv1alpha1.WorkspaceSpec{}},
+			}
+		})
+
+		It("Should return the default size when not configured in the workspaces", func() {
+			size := forge.MyDriveSize(resource.MustParse("5Gi"), nil, workspaces[2:])
+			Expect(size.String()).To(Equal("5Gi"))
+		})
+
+		It("Should return the largest size among the workspaces", func() {
+			size := forge.MyDriveSize(resource.MustParse("5Gi"), nil, workspaces)
+			Expect(size.String()).To(Equal("20Gi"))
+		})
+
+		It("Should not return a size lower than the default one", func() {
+			size := forge.MyDriveSize(resource.MustParse("50Gi"), nil, workspaces)
+			Expect(size.String()).To(Equal("50Gi"))
+		})
+
+		It("Should return the size configured in the tenant, if any", func() {
+			size := forge.MyDriveSize(resource.MustParse("5Gi"), ptr.To(resource.MustParse("2Gi")), workspaces)
+			Expect(size.String()).To(Equal("2Gi"))
+		})
+	})
+
+	Describe("The forge.MyDriveUsageThreshold function", func() {
+		usage := func(used, available string) *clv1alpha2.MyDriveUsage {
+			return &clv1alpha2.MyDriveUsage{Used: resource.MustParse(used), Available: resource.MustParse(available)}
+		}
+		thresholds := []int{95, 80}
+
+		It("Should return zero if no threshold is crossed", func() {
+			Expect(forge.MyDriveUsageThreshold(usage("1Gi", "9Gi"), thresholds)).To(Equal(0))
+		})
+
+		It("Should return the highest threshold crossed", func() {
+			Expect(forge.MyDriveUsageThreshold(usage("8Gi", "2Gi"), thresholds)).To(Equal(80))
+			Expect(forge.MyDriveUsageThreshold(usage("10Gi", "0"), thresholds)).To(Equal(95))
+		})
+
+		It("Should return zero if the volume is empty", func() {
+			Expect(forge.MyDriveUsageThreshold(usage("0", "0"), thresholds)).To(Equal(0))
+		})
+	})
+
 	Describe("The forge.StaticTenantNamespaceLabels function", func() {
 		It("Should append static labels to empty map", func() {
 			resultLabels := forge.StaticTenantNamespaceLabels(map[string]string{})
@@ -294,4 +350,56 @@ var _ = Describe("Tenant forging", func() {
 			Expect(resultLabels).To(HaveKeyWithValue("crownlabs.polito.it/managed-by", "tenant"))
 		})
 	})
+
+	Describe("The forge.EnrollmentRequestLabels function", func() {
+		It("Should add the tenant and workspace labels, preserving the existing ones", func() {
+			resultLabels := forge.EnrollmentRequestLabels(map[string]string{"custom-label": "custom-value"}, "tester", "ws")
+
+			Expect(forge.GetEnrollmentRequestName("tester", "ws")).To(Equal("tester.ws"))
+			Expect(resultLabels).To(HaveLen(3))
+			Expect(resultLabels).To(HaveKeyWithValue("custom-label", "custom-value"))
+			Expect(resultLabels).To(HaveKeyWithValue("crownlabs.polito.it/tenant", "tester"))
+			Expect(resultLabels).To(HaveKeyWithValue("crownlabs.polito.it/workspace", "ws"))
+		})
+	})
+
+	Describe("The forge.IsWorkspaceMembershipActive function", func() {
+		now := time.Now()
+		at := func(d time.Duration) *metav1.Time { return &metav1.Time{Time: now.Add(d)} }
+
+		type MembershipCase struct {
+			ValidFrom, ValidUntil *metav1.Time
+			Expected              bool
+		}
+
+		DescribeTable("Correctly determines whether the membership is active",
+			func(c MembershipCase) {
+				entry := clv1alpha2.TenantWorkspaceEntry{Name: "ws", Role: clv1alpha2.User, ValidFrom: c.ValidFrom, ValidUntil: c.ValidUntil}
+				Expect(forge.IsWorkspaceMembershipActive(&entry, now)).To(Equal(c.Expected))
+			},
+			Entry("Without validity period", MembershipCase{Expected: true}),
+			Entry("Within the validity period", MembershipCase{ValidFrom: at(-time.Hour), ValidUntil: at(time.Hour), Expected: true}),
+			Entry("Not yet started", MembershipCase{ValidFrom: at(time.Hour), Expected: false}),
+			Entry("Already expired", MembershipCase{ValidUntil: at(-time.Hour), Expected: false}),
+		)
+	})
+
+	Describe("The forge.NextWorkspaceMembershipChange function", func() {
+		now := time.Now()
+		at := func(d time.Duration) *metav1.Time { return &metav1.Time{Time: now.Add(d)} }
+
+		It("Should return the zero time if no change is expected", func() {
+			entries := []clv1alpha2.TenantWorkspaceEntry{{Name: "ws1"}, {Name: "ws2", ValidUntil: at(-time.Hour)}}
+			Expect(forge.NextWorkspaceMembershipChange(entries, now, time.Hour).IsZero()).To(BeTrue())
+		})
+
+		It("Should return the first upcoming boundary, including the start of the notice period", func() {
+			entries := []clv1alpha2.TenantWorkspaceEntry{
+				{Name: "ws1", ValidFrom: at(5 * time.Hour)},
+				{Name: "ws2", ValidUntil: at(3 * time.Hour)},
+			}
+			Expect(forge.NextWorkspaceMembershipChange(entries, now, time.Hour)).To(BeTemporally("==", now.Add(2*time.Hour)))
+			Expect(forge.NextWorkspaceMembershipChange(entries, now, 0)).To(BeTemporally("==", now.Add(3*time.Hour)))
+		})
+	})
 })
@@ -41,6 +41,9 @@ const (
 	volumeCloudInitName = "cloud-init"
 	virtioDiskType      = "virtio"
 
+	// volumeAdditionalDiskPrefix -> the prefix of the names of the volumes corresponding to additional disks.
+	volumeAdditionalDiskPrefix = "data-"
+
 	// terminationGracePeriod -> the amount of seconds before a terminating VM is forcefully deleted.
 	terminationGracePeriod = 60
 
@@ -82,10 +85,14 @@ func VirtualMachineInstanceSpec(instance *clv1alpha2.Instance, template *clv1alp
 
 // Volumes forges the array of volumes to be mounted onto the VMI specification.
 func Volumes(instance *clv1alpha2.Instance, environment *clv1alpha2.Environment, mountInfos []corev1.VolumeMount) []virtv1.Volume {
-	return append([]virtv1.Volume{
+	volumes := []virtv1.Volume{
 		VolumeRootDisk(instance, environment),
 		VolumeCloudInit(CanonicalName(instance.GetName())),
-	}, AttachableVolumes(mountInfos)...)
+	}
+	for i := range environment.AdditionalDisks {
+		volumes = append(volumes, VolumeAdditionalDisk(instance, environment, &environment.AdditionalDisks[i]))
+	}
+	return append(volumes, AttachableVolumes(mountInfos)...)
 }
 
 // VolumeCloudInit forges the specification of a volume mapping to a secret containing the cloud-init configuration.
@@ -174,20 +181,83 @@ func VolumeContainerDisk(image string) virtv1.Volume {
 	}
 }
 
+// VolumeAdditionalDisk forges the specification of the volume backing an additional disk: a DataVolume if the
+// disk is persistent, and either an ephemeral container disk or an empty disk, depending on the image, otherwise.
+func VolumeAdditionalDisk(instance *clv1alpha2.Instance, environment *clv1alpha2.Environment, disk *clv1alpha2.AdditionalDisk) virtv1.Volume {
+	volume := virtv1.Volume{Name: AdditionalDiskVolumeName(disk)}
+	switch {
+	case disk.Persistent:
+		volume.DataVolume = &virtv1.DataVolumeSource{Name: AdditionalDiskDataVolumeName(instance, environment, disk)}
+	case disk.Image != "":
+		volume.ContainerDisk = &virtv1.ContainerDiskSource{
+			Image:           disk.Image,
+			ImagePullSecret: registryCredentialsSecretName,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+		}
+	default:
+		volume.EmptyDisk = &virtv1.EmptyDiskSource{Capacity: disk.Size}
+	}
+	return volume
+}
+
+// AdditionalDiskVolumeName returns the name of the VMI volume corresponding to the given additional disk.
+func AdditionalDiskVolumeName(disk *clv1alpha2.AdditionalDisk) string {
+	return volumeAdditionalDiskPrefix + disk.Name
+}
+
+// AdditionalDiskDataVolumeName returns the name of the DataVolume backing the given persistent additional disk.
+func AdditionalDiskDataVolumeName(instance *clv1alpha2.Instance, environment *clv1alpha2.Environment, disk *clv1alpha2.AdditionalDisk) string {
+	return NamespacedNameWithSuffix(instance, environment.Name+StringSeparator+disk.Name).Name
+}
+
+// DataVolumeSuffixes returns the name suffixes of the DataVolumes of the given VM environment,
+// i.e., the one of the root disk followed by the ones of the persistent additional disks.
+func DataVolumeSuffixes(environment *clv1alpha2.Environment) []string {
+	suffixes := []string{environment.Name}
+	for i := range environment.AdditionalDisks {
+		if environment.AdditionalDisks[i].Persistent {
+			suffixes = append(suffixes, environment.Name+StringSeparator+environment.AdditionalDisks[i].Name)
+		}
+	}
+	return suffixes
+}
+
+// AdditionalDisksSize returns the overall size of the additional disks of the given environment.
+func AdditionalDisksSize(environment *clv1alpha2.Environment) resource.Quantity {
+	size := resource.MustParse("0")
+	for i := range environment.AdditionalDisks {
+		size.Add(environment.AdditionalDisks[i].Size)
+	}
+	return size
+}
+
 // VolumeDiskTargets forges the array of disks to be attached to the VM Domain.
-func VolumeDiskTargets(_ *clv1alpha2.Environment) []virtv1.Disk {
+func VolumeDiskTargets(environment *clv1alpha2.Environment) []virtv1.Disk {
 	disks := []virtv1.Disk{VolumeDiskTarget(volumeRootName)}
 	disks = append(disks, VolumeDiskTarget(volumeCloudInitName))
+	for i := range environment.AdditionalDisks {
+		disk := &environment.AdditionalDisks[i]
+		disks = append(disks, VolumeDiskTargetWithBus(AdditionalDiskVolumeName(disk), disk.Bus))
+	}
 	return disks
 }
 
 // VolumeDiskTarget forges the specification of a KVM disk attached to volume.
 func VolumeDiskTarget(name string) virtv1.Disk {
+	return VolumeDiskTargetWithBus(name, virtioDiskType)
+}
+
+// VolumeDiskTargetWithBus forges the specification of a KVM disk attached to volume through the given bus,
+// defaulting to virtio if not specified.
+func VolumeDiskTargetWithBus(name string, bus clv1alpha2.DiskBus) virtv1.Disk {
+	if bus == "" {
+		bus = virtioDiskType
+	}
 	return virtv1.Disk{
 		Name: name,
 		DiskDevice: virtv1.DiskDevice{
 			Disk: &virtv1.DiskTarget{
-				Bus: virtioDiskType,
+				Bus: virtv1.DiskBus(bus),
 			},
 		},
 	}
@@ -333,3 +403,31 @@ func DataVolumeSpec(environment *clv1alpha2.Environment) (cdiv1beta1.DataVolumeS
 		},
 	}, nil
 }
+
+// AdditionalDiskDataVolumeSpec forges the spec of the DataVolume backing a persistent additional disk,
+// which is either blank or populated from the given registry image.
+func AdditionalDiskDataVolumeSpec(environment *clv1alpha2.Environment, disk *clv1alpha2.AdditionalDisk) cdiv1beta1.DataVolumeSpec {
+	source := &cdiv1beta1.DataVolumeSource{Blank: &cdiv1beta1.DataVolumeBlankImage{}}
+	if disk.Image != "" {
+		source = &cdiv1beta1.DataVolumeSource{
+			Registry: &cdiv1beta1.DataVolumeSourceRegistry{
+				URL:       ptr.To(urlDockerPrefix + disk.Image),
+				SecretRef: ptr.To(cdiSecretName),
+			},
+		}
+	}
+
+	return cdiv1beta1.DataVolumeSpec{
+		Source: source,
+		PVC: &corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			VolumeMode:       ptr.To(corev1.PersistentVolumeFilesystem),
+			StorageClassName: InstancePVCStorageClassName(environment),
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: disk.Size,
+				},
+			},
+		},
+	}
+}
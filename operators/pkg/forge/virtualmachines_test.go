@@ -17,6 +17,7 @@ package forge_test
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -257,6 +258,107 @@ var _ = Describe("VirtualMachines and VirtualMachineInstances forging", func() {
 		})
 	})
 
+	Describe("The forge.Volumes function with additional disks", func() {
+		BeforeEach(func() {
+			environment.AdditionalDisks = []clv1alpha2.AdditionalDisk{
+				{Name: "db", Size: resource.MustParse("10Gi"), Persistent: true},
+				{Name: "scratch", Size: resource.MustParse("5Gi")},
+			}
+		})
+
+		It("Correctly appends the additional disk volumes", func() {
+			actual := forge.Volumes(&instance, &environment, nil)
+			expected := []virtv1.Volume{
+				forge.VolumeRootDisk(&instance, &environment),
+				forge.VolumeCloudInit(forge.CanonicalName(instance.GetName())),
+				forge.VolumeAdditionalDisk(&instance, &environment, &environment.AdditionalDisks[0]),
+				forge.VolumeAdditionalDisk(&instance, &environment, &environment.AdditionalDisks[1]),
+			}
+			Expect(actual).To(Equal(expected))
+		})
+	})
+
+	Describe("The forge.VolumeAdditionalDisk function", func() {
+		var (
+			disk   clv1alpha2.AdditionalDisk
+			volume virtv1.Volume
+		)
+
+		BeforeEach(func() {
+			environment.Name = "env"
+			disk = clv1alpha2.AdditionalDisk{Name: "db", Size: resource.MustParse("10Gi")}
+		})
+
+		JustBeforeEach(func() {
+			volume = forge.VolumeAdditionalDisk(&instance, &environment, &disk)
+		})
+
+		It("Should set the correct volume name", func() { Expect(volume.Name).To(BeIdenticalTo("data-db")) })
+
+		When("the disk is persistent", func() {
+			BeforeEach(func() { disk.Persistent = true })
+			It("Should forge the data-volume volume", func() {
+				Expect(volume.DataVolume).To(PointTo(Equal(virtv1.DataVolumeSource{Name: instanceName + "-env-db"})))
+			})
+		})
+
+		When("the disk is ephemeral and populated from an image", func() {
+			BeforeEach(func() { disk.Image = image })
+			It("Should forge the container-disk volume", func() {
+				Expect(volume.ContainerDisk).ToNot(BeNil())
+				Expect(volume.ContainerDisk.Image).To(BeIdenticalTo(image))
+			})
+		})
+
+		When("the disk is ephemeral and blank", func() {
+			It("Should forge the empty-disk volume", func() {
+				Expect(volume.EmptyDisk).To(PointTo(Equal(virtv1.EmptyDiskSource{Capacity: resource.MustParse("10Gi")})))
+			})
+		})
+	})
+
+	Describe("The forge.AdditionalDiskDataVolumeSpec function", func() {
+		var (
+			disk clv1alpha2.AdditionalDisk
+			spec cdiv1beta1.DataVolumeSpec
+		)
+
+		BeforeEach(func() {
+			disk = clv1alpha2.AdditionalDisk{Name: "db", Size: resource.MustParse("10Gi"), Persistent: true}
+		})
+
+		JustBeforeEach(func() {
+			spec = forge.AdditionalDiskDataVolumeSpec(&environment, &disk)
+		})
+
+		It("Should request the size of the disk", func() {
+			Expect(spec.PVC.Resources.Requests).To(HaveKeyWithValue(corev1.ResourceStorage, resource.MustParse("10Gi")))
+		})
+
+		When("no image is specified", func() {
+			It("Should forge a blank source", func() { Expect(spec.Source.Blank).ToNot(BeNil()) })
+		})
+
+		When("an image is specified", func() {
+			BeforeEach(func() { disk.Image = image })
+			It("Should forge a registry source", func() {
+				Expect(spec.Source.Registry).ToNot(BeNil())
+				Expect(spec.Source.Registry.URL).To(PointTo(Equal("docker://" + image)))
+			})
+		})
+	})
+
+	Describe("The forge.AdditionalDisksSize function", func() {
+		It("Should sum the sizes of the additional disks", func() {
+			environment.AdditionalDisks = []clv1alpha2.AdditionalDisk{
+				{Name: "db", Size: resource.MustParse("10Gi"), Persistent: true},
+				{Name: "scratch", Size: resource.MustParse("5Gi")},
+			}
+			size := forge.AdditionalDisksSize(&environment)
+			Expect(size.Cmp(resource.MustParse("15Gi"))).To(BeZero())
+		})
+	})
+
 	Describe("The forge.VolumeRootDisk function", func() {
 		var volume virtv1.Volume
 
@@ -332,6 +434,23 @@ var _ = Describe("VirtualMachines and VirtualMachineInstances forging", func() {
 		})
 	})
 
+	Describe("The forge.VolumeDiskTargets function with additional disks", func() {
+		It("Correctly appends the additional disks with the requested bus", func() {
+			environment.AdditionalDisks = []clv1alpha2.AdditionalDisk{
+				{Name: "db", Size: resource.MustParse("10Gi"), Bus: clv1alpha2.DiskBusSata},
+				{Name: "scratch", Size: resource.MustParse("5Gi")},
+			}
+			actual := forge.VolumeDiskTargets(&environment)
+			expected := []virtv1.Disk{
+				forge.VolumeDiskTarget("root"),
+				forge.VolumeDiskTarget("cloud-init"),
+				forge.VolumeDiskTargetWithBus("data-db", clv1alpha2.DiskBusSata),
+				forge.VolumeDiskTarget("data-scratch"),
+			}
+			Expect(actual).To(Equal(expected))
+		})
+	})
+
 	Describe("The forge.VolumeDiskTarget function", func() {
 		var disk virtv1.Disk
 		const name = "disk-name"
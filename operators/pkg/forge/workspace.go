@@ -0,0 +1,163 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge
+
+import (
+	"fmt"
+	"sort"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	apicommon "github.com/netgroup-polito/CrownLabs/operators/api/common"
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+)
+
+// WorkspaceParentName returns the name of the parent of the given Workspace, or an empty string if not set.
+func WorkspaceParentName(ws *clv1alpha1.Workspace) string {
+	if ws.Spec.Parent == nil {
+		return ""
+	}
+	return ws.Spec.Parent.Name
+}
+
+// WorkspaceAncestors returns the names of the ancestors of the given Workspace, starting from the direct parent.
+// The walk stops at the first parent not included in the given list, while an error is returned in case of cycles.
+func WorkspaceAncestors(ws *clv1alpha1.Workspace, workspaces []clv1alpha1.Workspace) ([]string, error) {
+	byName := make(map[string]*clv1alpha1.Workspace, len(workspaces))
+	for i := range workspaces {
+		byName[workspaces[i].Name] = &workspaces[i]
+	}
+	byName[ws.Name] = ws
+
+	var ancestors []string
+	visited := map[string]bool{ws.Name: true}
+	for current := ws; WorkspaceParentName(current) != ""; {
+		parent := WorkspaceParentName(current)
+		if visited[parent] {
+			return ancestors, fmt.Errorf("cycle detected in the hierarchy of workspace %s, through workspace %s", ws.Name, parent)
+		}
+		visited[parent] = true
+		ancestors = append(ancestors, parent)
+
+		next, found := byName[parent]
+		if !found {
+			break
+		}
+		current = next
+	}
+
+	return ancestors, nil
+}
+
+// WorkspaceChildren returns the direct children of the Workspace with the given name, sorted by name.
+func WorkspaceChildren(name string, workspaces []clv1alpha1.Workspace) []*clv1alpha1.Workspace {
+	var children []*clv1alpha1.Workspace
+	for i := range workspaces {
+		if workspaces[i].Name != name && WorkspaceParentName(&workspaces[i]) == name {
+			children = append(children, &workspaces[i])
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+	return children
+}
+
+// WorkspaceDescendants returns the names of all the descendants of the Workspace with the given name, sorted by name.
+func WorkspaceDescendants(name string, workspaces []clv1alpha1.Workspace) []string {
+	var descendants []string
+	visited := map[string]bool{name: true}
+	for queue := []string{name}; len(queue) > 0; queue = queue[1:] {
+		for _, child := range WorkspaceChildren(queue[0], workspaces) {
+			if !visited[child.Name] {
+				visited[child.Name] = true
+				descendants = append(descendants, child.Name)
+				queue = append(queue, child.Name)
+			}
+		}
+	}
+	sort.Strings(descendants)
+	return descendants
+}
+
+// WorkspaceQuotaUsage converts a WorkspaceResourceQuota to the corresponding WorkspaceResourceUsage.
+func WorkspaceQuotaUsage(quota *apicommon.WorkspaceResourceQuota) clv1alpha1.WorkspaceResourceUsage {
+	return clv1alpha1.WorkspaceResourceUsage{
+		Instances: quota.Instances,
+		CPU:       quota.CPU,
+		Memory:    quota.Memory.DeepCopy(),
+		Disk:      quota.Disk.DeepCopy(),
+	}
+}
+
+// AccumulateWorkspaceUsage adds the resources of the second WorkspaceResourceUsage to the first one.
+func AccumulateWorkspaceUsage(usage, other *clv1alpha1.WorkspaceResourceUsage) {
+	usage.Instances += other.Instances
+	usage.CPU += other.CPU
+	usage.Memory.Add(other.Memory)
+	usage.Disk.Add(other.Disk)
+}
+
+// WorkspacesQuotaSum returns the sum of the quotas of the given Workspaces.
+func WorkspacesQuotaSum(workspaces []*clv1alpha1.Workspace) clv1alpha1.WorkspaceResourceUsage {
+	var total clv1alpha1.WorkspaceResourceUsage
+	for _, ws := range workspaces {
+		quota := WorkspaceQuotaUsage(&ws.Spec.Quota)
+		AccumulateWorkspaceUsage(&total, &quota)
+	}
+	return total
+}
+
+// InstanceWorkspaceUsage returns the resources requested by an Instance of the given Template.
+func InstanceWorkspaceUsage(template *clv1alpha2.Template) clv1alpha1.WorkspaceResourceUsage {
+	usage := clv1alpha1.WorkspaceResourceUsage{Instances: 1}
+	for i := range template.Spec.EnvironmentList {
+		resources := &template.Spec.EnvironmentList[i].Resources
+		usage.CPU += resources.CPU
+		usage.Memory.Add(resources.Memory)
+		usage.Disk.Add(resources.Disk)
+	}
+	return usage
+}
+
+// ExceededWorkspaceQuota returns the description of the resources of the given usage exceeding the quota, if any.
+func ExceededWorkspaceQuota(usage *clv1alpha1.WorkspaceResourceUsage, quota *apicommon.WorkspaceResourceQuota) []string {
+	var exceeded []string
+	if usage.Instances > quota.Instances {
+		exceeded = append(exceeded, fmt.Sprintf("Instances (%d > %d)", usage.Instances, quota.Instances))
+	}
+	if usage.CPU > quota.CPU {
+		exceeded = append(exceeded, fmt.Sprintf("CPU (%d > %d)", usage.CPU, quota.CPU))
+	}
+	if usage.Memory.Cmp(quota.Memory) > 0 {
+		exceeded = append(exceeded, fmt.Sprintf("Memory (%s > %s)", usage.Memory.String(), quota.Memory.String()))
+	}
+	if !quota.Disk.IsZero() && usage.Disk.Cmp(quota.Disk) > 0 {
+		exceeded = append(exceeded, fmt.Sprintf("Disk (%s > %s)", usage.Disk.String(), quota.Disk.String()))
+	}
+	return exceeded
+}
+
+// workspaceGroupSubjects returns the subjects corresponding to the given role of the given Workspaces.
+func workspaceGroupSubjects(role clv1alpha2.WorkspaceUserRole, workspaces ...string) []rbacv1.Subject {
+	subjects := make([]rbacv1.Subject, 0, len(workspaces))
+	for _, ws := range workspaces {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:     rbacv1.GroupKind,
+			Name:     fmt.Sprintf("kubernetes:%s", WorkspaceRoleName(ws, role)),
+			APIGroup: rbacv1.GroupName,
+		})
+	}
+	return subjects
+}
@@ -0,0 +1,142 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apicommon "github.com/netgroup-polito/CrownLabs/operators/api/common"
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+)
+
+var _ = Describe("Workspace hierarchy forging", func() {
+	workspace := func(name, parent string, cpu int64, memory string, instances int64) clv1alpha1.Workspace {
+		ws := clv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: clv1alpha1.WorkspaceSpec{
+				Quota: apicommon.WorkspaceResourceQuota{
+					ResourceSpec: apicommon.ResourceSpec{CPU: cpu, Memory: resource.MustParse(memory)},
+					Instances:    instances,
+				},
+			},
+		}
+		if parent != "" {
+			ws.Spec.Parent = &clv1alpha1.GenericRef{Name: parent}
+		}
+		return ws
+	}
+
+	var workspaces []clv1alpha1.Workspace
+
+	BeforeEach(func() {
+		workspaces = []clv1alpha1.Workspace{
+			workspace("department", "", 16, "32Gi", 20),
+			workspace("course-b", "department", 4, "8Gi", 5),
+			workspace("course-a", "department", 8, "16Gi", 10),
+			workspace("lab", "course-a", 2, "4Gi", 2),
+			workspace("standalone", "", 2, "4Gi", 2),
+		}
+	})
+
+	Describe("The forge.WorkspaceAncestors function", func() {
+		It("Should return the ancestors starting from the direct parent", func() {
+			ancestors, err := forge.WorkspaceAncestors(&workspaces[3], workspaces)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ancestors).To(Equal([]string{"course-a", "department"}))
+		})
+
+		It("Should return no ancestors for a root workspace", func() {
+			ancestors, err := forge.WorkspaceAncestors(&workspaces[0], workspaces)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ancestors).To(BeEmpty())
+		})
+
+		It("Should stop at the first missing parent", func() {
+			orphan := workspace("orphan", "missing", 1, "1Gi", 1)
+			ancestors, err := forge.WorkspaceAncestors(&orphan, workspaces)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ancestors).To(Equal([]string{"missing"}))
+		})
+
+		It("Should detect cycles, considering the given version of the workspace", func() {
+			department := workspace("department", "lab", 16, "32Gi", 20)
+			_, err := forge.WorkspaceAncestors(&department, workspaces)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("The forge.WorkspaceChildren and forge.WorkspaceDescendants functions", func() {
+		It("Should return the direct children sorted by name", func() {
+			children := forge.WorkspaceChildren("department", workspaces)
+			Expect(children).To(HaveLen(2))
+			Expect(children[0].Name).To(Equal("course-a"))
+			Expect(children[1].Name).To(Equal("course-b"))
+		})
+
+		It("Should return all the descendants sorted by name", func() {
+			Expect(forge.WorkspaceDescendants("department", workspaces)).To(Equal([]string{"course-a", "course-b", "lab"}))
+			Expect(forge.WorkspaceDescendants("standalone", workspaces)).To(BeEmpty())
+		})
+	})
+
+	Describe("The quota sub-allocation functions", func() {
+		It("Should sum the quotas of the children", func() {
+			allocated := forge.WorkspacesQuotaSum(forge.WorkspaceChildren("department", workspaces))
+			Expect(allocated.CPU).To(BeNumerically("==", 12))
+			Expect(allocated.Memory.Cmp(resource.MustParse("24Gi"))).To(BeZero())
+			Expect(allocated.Instances).To(BeNumerically("==", 15))
+			Expect(forge.ExceededWorkspaceQuota(&allocated, &workspaces[0].Spec.Quota)).To(BeEmpty())
+		})
+
+		It("Should report the resources exceeding the quota", func() {
+			allocated := forge.WorkspacesQuotaSum(forge.WorkspaceChildren("department", workspaces))
+			Expect(forge.ExceededWorkspaceQuota(&allocated, &workspaces[1].Spec.Quota)).To(ConsistOf(
+				"Instances (15 > 5)", "CPU (12 > 4)", "Memory (24Gi > 8Gi)"))
+		})
+
+		It("Should compute the usage of an instance from its template", func() {
+			template := clv1alpha2.Template{Spec: clv1alpha2.TemplateSpec{EnvironmentList: []clv1alpha2.Environment{
+				{Resources: clv1alpha2.EnvironmentResources{ResourceSpec: apicommon.ResourceSpec{CPU: 2, Memory: resource.MustParse("2Gi")}}},
+				{Resources: clv1alpha2.EnvironmentResources{ResourceSpec: apicommon.ResourceSpec{CPU: 1, Memory: resource.MustParse("1Gi")}}},
+			}}}
+			usage := forge.InstanceWorkspaceUsage(&template)
+			Expect(usage.Instances).To(BeNumerically("==", 1))
+			Expect(usage.CPU).To(BeNumerically("==", 3))
+			Expect(usage.Memory.Cmp(resource.MustParse("3Gi"))).To(BeZero())
+		})
+	})
+
+	Describe("The workspace binding functions", func() {
+		It("Should grant the manager permissions to the managers of the ancestors", func() {
+			crb := &rbacv1.ClusterRoleBinding{}
+			forge.ConfigureWorkspaceInstancesManagerBinding(&workspaces[3], crb, "course-a", "department")
+			Expect(crb.Subjects).To(HaveLen(3))
+			Expect(crb.Subjects[2].Name).To(Equal("kubernetes:workspace-department:manager"))
+		})
+
+		It("Should grant the visibility of the templates to the users of the descendants", func() {
+			rb := &rbacv1.RoleBinding{}
+			forge.ConfigureWorkspaceUserViewTemplatesBinding(&workspaces[0], rb, nil, "course-a")
+			Expect(rb.Subjects).To(HaveLen(2))
+			Expect(rb.Subjects[1].Name).To(Equal("kubernetes:workspace-course-a:user"))
+		})
+	})
+})
@@ -16,11 +16,16 @@ package imagelist
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"sync"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
@@ -36,7 +41,17 @@ type RegistryConfig struct {
 	Username      string `json:"username"`
 	Password      string `json:"password"`
 	ImageListName string `json:"imageListName"`
-	Project       string `json:"project,omitempty"` // Only for Harbor
+	Project       string `json:"project,omitempty"`       // Only for Harbor
+	Platform      string `json:"platform,omitempty"`      // Only for OCI, the platform described for multi-platform images (e.g., linux/amd64)
+	WebhookSecret string `json:"webhookSecret,omitempty"` // The secret expected in the Authorization header of the webhooks; if empty, webhooks are rejected
+
+	// CredentialsSecret references the Secret containing the credentials, taking precedence over the inline ones.
+	CredentialsSecret *clv1alpha1.GenericRef `json:"credentialsSecret,omitempty"`
+	// DeprecationPolicy defines the criteria to mark the versions as deprecated, and possibly delete them when unused.
+	DeprecationPolicy *clv1alpha1.ImageDeprecationPolicy `json:"deprecationPolicy,omitempty"`
+
+	// registry is the ImageRegistry resource the configuration originates from, if any.
+	registry *clv1alpha1.ImageRegistry
 }
 
 // UpdateResult represents the result of updating a single image list.
@@ -49,7 +64,8 @@ type UpdateResult struct {
 // UpdaterOptions holds configuration for the image list updater.
 type UpdaterOptions struct {
 	ConfigFilePath string
-	Interval       int // interval in seconds
+	Interval       int    // interval in seconds
+	WebhookAddress string // address the registry webhook receiver listens on; if empty, the receiver is disabled
 }
 
 // BackgroundUpdater manages periodic image list updates.
@@ -64,6 +80,10 @@ type BackgroundUpdater struct {
 
 var globalUpdater *BackgroundUpdater
 
+// requestorsMu serializes the initialization of the requestors, which rely on the global RequestersSharedData,
+// as registries may be concurrently processed by the periodic update and the ImageRegistry controller.
+var requestorsMu sync.Mutex
+
 // LoadRegistriesConfig loads registry configuration from file.
 func LoadRegistriesConfig(filePath string) ([]RegistryConfig, error) {
 	configData, err := os.ReadFile(filePath) // #nosec G304: path is from controlled configuration
@@ -83,6 +103,81 @@ func LoadRegistriesConfig(filePath string) ([]RegistryConfig, error) {
 	return config, nil
 }
 
+// RegistryConfigFromResource converts an ImageRegistry resource into the corresponding registry configuration.
+func RegistryConfigFromResource(registry *clv1alpha1.ImageRegistry) RegistryConfig {
+	return RegistryConfig{
+		Name:              registry.Name,
+		Type:              string(registry.Spec.Type),
+		URL:               registry.Spec.URL,
+		RegistryName:      registry.Spec.RegistryName,
+		ImageListName:     registry.Spec.ImageListName,
+		Project:           registry.Spec.Project,
+		Platform:          registry.Spec.Platform,
+		CredentialsSecret: registry.Spec.CredentialsSecretRef,
+		DeprecationPolicy: registry.Spec.DeprecationPolicy,
+		registry:          registry,
+	}
+}
+
+// LoadRegistries loads the registry configurations from both the configuration file, if present, and the ImageRegistry resources.
+// In case of name clashes, the configurations from the file take precedence.
+func LoadRegistries(ctx context.Context, k8sClient client.Client, filePath string, log logr.Logger) ([]RegistryConfig, error) {
+	var config []RegistryConfig
+	if filePath != "" {
+		fileConfig, err := LoadRegistriesConfig(filePath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		config = fileConfig
+	}
+
+	var registries clv1alpha1.ImageRegistryList
+	if err := k8sClient.List(ctx, &registries); err != nil {
+		// Tolerate the absence of the ImageRegistry CRD, falling back to the configuration file only.
+		if !meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("failed to list ImageRegistry resources: %w", err)
+		}
+		log.V(1).Info("ImageRegistry resources not available", "error", err.Error())
+	}
+
+	for i := range registries.Items {
+		regConfig := RegistryConfigFromResource(&registries.Items[i])
+		if slices.ContainsFunc(config, func(c RegistryConfig) bool { return c.Name == regConfig.Name }) {
+			log.Info("ignoring ImageRegistry clashing with the configuration file", "registry_name", regConfig.Name)
+			continue
+		}
+		config = append(config, regConfig)
+	}
+
+	if len(config) == 0 {
+		return nil, fmt.Errorf("no registries configured")
+	}
+
+	return config, nil
+}
+
+// UpdateRegistryStatus records the outcome of an update in the status of the ImageRegistry the configuration originates from, if any.
+func UpdateRegistryStatus(ctx context.Context, k8sClient client.Client, regConfig *RegistryConfig, imageCount int, updateErr error) error {
+	if regConfig.registry == nil {
+		return nil
+	}
+
+	registry := regConfig.registry.DeepCopy()
+	registry.Status.Ready = updateErr == nil
+	registry.Status.LastUpdateTime = ptr.To(metav1.Now())
+	registry.Status.Error = ""
+	if updateErr != nil {
+		registry.Status.Error = updateErr.Error()
+	} else {
+		registry.Status.ImageCount = imageCount
+	}
+
+	if err := k8sClient.Status().Patch(ctx, registry, client.MergeFrom(regConfig.registry)); err != nil {
+		return fmt.Errorf("failed to update the status of ImageRegistry %s: %w", registry.Name, err)
+	}
+	return nil
+}
+
 // Initialize initializes the image list updater with the given configuration.
 func Initialize(k8sClient client.Client, log logr.Logger, options UpdaterOptions) error {
 	if globalUpdater != nil {
@@ -119,7 +214,7 @@ func (u *BackgroundUpdater) Update(ctx context.Context) error {
 
 	log.Info("starting image list update")
 
-	config, err := LoadRegistriesConfig(u.options.ConfigFilePath)
+	config, err := LoadRegistries(ctx, u.k8sClient, u.options.ConfigFilePath, log)
 	if err != nil {
 		log.Error(err, "failed to load registries configuration")
 		return err
@@ -130,17 +225,26 @@ func (u *BackgroundUpdater) Update(ctx context.Context) error {
 
 	for i := range config {
 		result, err := ProcessSingleRegistryConfigWithItems(ctx, &config[i], u.k8sClient, log)
+		if statusErr := UpdateRegistryStatus(ctx, u.k8sClient, &config[i], len(result), err); statusErr != nil {
+			log.Error(statusErr, "failed to update registry status", "registry_name", config[i].Name)
+		}
 		if err != nil {
 			log.Error(err, "failed to process registry", "registry_name", config[i].Name, "imagelist_name", config[i].ImageListName)
 			errorCount++
 		} else {
 			log.Info("successfully updated image list", "registry_name", config[i].Name, "imagelist_name", config[i].ImageListName, "items_count", len(result))
+			metricLastUpdate.WithLabelValues(config[i].Name, metricSourceCrawl).SetToCurrentTime()
 			successCount++
 		}
 	}
 
 	log.Info("image list update completed", "success_count", successCount, "error_count", errorCount)
 
+	// Compute the usage of the images once the ImageLists are up to date
+	if err := u.updateUsage(ctx, config); err != nil {
+		log.Error(err, "failed to update the usage of the images")
+	}
+
 	if errorCount > 0 {
 		return fmt.Errorf("update completed with %d errors out of %d registries", errorCount, len(config))
 	}
@@ -150,23 +254,9 @@ func (u *BackgroundUpdater) Update(ctx context.Context) error {
 
 // ProcessSingleRegistryConfig processes a single registry configuration.
 func ProcessSingleRegistryConfig(ctx context.Context, regConfig *RegistryConfig, k8sClient client.Client, log logr.Logger) error {
-	var requestor Requestor
-
-	switch regConfig.Type {
-	case "docker":
-		requestor = NewDockerImageListRequestor(log.WithName(regConfig.Name).WithName("dockerRequestor"))
-	case "harbor":
-		if regConfig.Project == "" {
-			return fmt.Errorf("project is required for Harbor registry")
-		}
-		RequestersSharedData["harbor_project_name"] = regConfig.Project
-		requestor = NewHarborImageListRequestor(log.WithName(regConfig.Name).WithName("harborRequestor"))
-	default:
-		return fmt.Errorf("unsupported registry type: %s", regConfig.Type)
-	}
-
-	if initResult, err := requestor.Initialize(regConfig.Username, regConfig.Password, regConfig.URL); !initResult || err != nil {
-		return fmt.Errorf("failed to initialize %s image list requestor: %w", regConfig.Type, err)
+	requestor, err := initializeRequestor(ctx, regConfig, k8sClient, log)
+	if err != nil {
+		return err
 	}
 
 	log.Info("updating ImageList CR", "name", regConfig.ImageListName, "registry", regConfig.Name)
@@ -186,8 +276,26 @@ func ProcessSingleRegistryConfig(ctx context.Context, regConfig *RegistryConfig,
 
 // ProcessSingleRegistryConfigWithItems processes a single registry configuration and returns the updated items.
 func ProcessSingleRegistryConfigWithItems(ctx context.Context, regConfig *RegistryConfig, k8sClient client.Client, log logr.Logger) ([]clv1alpha1.ImageListItem, error) {
+	if err := ProcessSingleRegistryConfig(ctx, regConfig, k8sClient, log); err != nil {
+		return nil, err
+	}
+
+	// Return the items persisted by the updater to avoid querying the registry twice.
+	var imageList clv1alpha1.ImageList
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: regConfig.ImageListName}, &imageList); err != nil {
+		return nil, fmt.Errorf("failed to retrieve updated ImageList resource: %w", err)
+	}
+
+	return imageList.Spec.Images, nil
+}
+
+// initializeRequestor creates the requestor corresponding to the registry type, and initializes it with the registry credentials.
+func initializeRequestor(ctx context.Context, regConfig *RegistryConfig, k8sClient client.Client, log logr.Logger) (Requestor, error) {
 	var requestor Requestor
 
+	requestorsMu.Lock()
+	defer requestorsMu.Unlock()
+
 	switch regConfig.Type {
 	case "docker":
 		requestor = NewDockerImageListRequestor(log.WithName(regConfig.Name).WithName("dockerRequestor"))
@@ -197,31 +305,21 @@ func ProcessSingleRegistryConfigWithItems(ctx context.Context, regConfig *Regist
 		}
 		RequestersSharedData["harbor_project_name"] = regConfig.Project
 		requestor = NewHarborImageListRequestor(log.WithName(regConfig.Name).WithName("harborRequestor"))
+	case "oci":
+		RequestersSharedData["oci_platform"] = regConfig.Platform
+		requestor = NewOCIImageListRequestor(log.WithName(regConfig.Name).WithName("ociRequestor"))
 	default:
 		return nil, fmt.Errorf("unsupported registry type: %s", regConfig.Type)
 	}
 
-	if initResult, err := requestor.Initialize(regConfig.Username, regConfig.Password, regConfig.URL); !initResult || err != nil {
-		return nil, fmt.Errorf("failed to initialize %s image list requestor: %w", regConfig.Type, err)
-	}
-
-	log.Info("updating ImageList CR", "name", regConfig.ImageListName, "registry", regConfig.Name)
-
-	imageListSaver, err := NewDefaultImageListSaver(ctx, regConfig.ImageListName, k8sClient, log.WithName(regConfig.Name).WithName("saver"))
+	credentials, err := ResolveRegistryCredentials(ctx, k8sClient, regConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize the image list saver: %w", err)
+		return nil, fmt.Errorf("failed to resolve the credentials of registry %s: %w", regConfig.Name, err)
 	}
 
-	imageListUpdater := NewUpdater([]Requestor{requestor}, regConfig.ImageListName, regConfig.Project, imageListSaver, regConfig.RegistryName, log.WithName(regConfig.Name).WithName("updater"))
-	if err := imageListUpdater.Update(ctx); err != nil {
-		return nil, fmt.Errorf("failed to update the ImageList resource: %w", err)
-	}
-
-	// Return the items persisted by the updater to avoid querying the registry twice.
-	var imageList clv1alpha1.ImageList
-	if err := k8sClient.Get(ctx, client.ObjectKey{Name: regConfig.ImageListName}, &imageList); err != nil {
-		return nil, fmt.Errorf("failed to retrieve updated ImageList resource: %w", err)
+	if initResult, err := requestor.Initialize(credentials, regConfig.URL); !initResult || err != nil {
+		return nil, fmt.Errorf("failed to initialize %s image list requestor: %w", regConfig.Type, err)
 	}
 
-	return imageList.Spec.Images, nil
+	return requestor, nil
 }
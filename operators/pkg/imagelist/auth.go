@@ -0,0 +1,356 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagelist
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SecretTokenKey -> the key of the generic Secrets containing a bearer token.
+	SecretTokenKey = "token"
+	// SecretUsernameKey -> the key of the generic Secrets containing the username.
+	SecretUsernameKey = "username"
+	// SecretPasswordKey -> the key of the generic Secrets containing the password.
+	SecretPasswordKey = "password"
+	// SecretWebhookSecretKey -> the key of the credentials Secrets containing the secret expected from the registry webhooks.
+	SecretWebhookSecretKey = "webhookSecret"
+
+	// defaultTokenLifetime -> the lifetime assumed for the tokens issued without expiration by the token services.
+	defaultTokenLifetime = 60 * time.Second
+)
+
+var challengeParamsRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// RegistryCredentials contains the credentials used to authenticate with a registry.
+type RegistryCredentials struct {
+	Username string
+	Password string
+	// Token is a static bearer token, taking precedence over the username and password.
+	Token string
+}
+
+// dockerConfigJSON is the content of the Secrets of type kubernetes.io/dockerconfigjson.
+type dockerConfigJSON struct {
+	Auths map[string]struct {
+		Username      string `json:"username"`
+		Password      string `json:"password"`
+		Auth          string `json:"auth"`
+		RegistryToken string `json:"registrytoken"`
+	} `json:"auths"`
+}
+
+// ResolveRegistryCredentials retrieves the credentials to access the given registry,
+// either from the referenced Secret, if any, or from the inline configuration.
+func ResolveRegistryCredentials(ctx context.Context, k8sClient client.Client, regConfig *RegistryConfig) (RegistryCredentials, error) {
+	if regConfig.CredentialsSecret == nil {
+		return RegistryCredentials{Username: regConfig.Username, Password: regConfig.Password}, nil
+	}
+
+	secret, err := getCredentialsSecret(ctx, k8sClient, regConfig)
+	if err != nil {
+		return RegistryCredentials{}, err
+	}
+
+	if secret.Type == corev1.SecretTypeDockerConfigJson {
+		return credentialsFromDockerConfig(secret.Data[corev1.DockerConfigJsonKey], regConfig)
+	}
+
+	return RegistryCredentials{
+		Username: string(secret.Data[SecretUsernameKey]),
+		Password: string(secret.Data[SecretPasswordKey]),
+		Token:    string(secret.Data[SecretTokenKey]),
+	}, nil
+}
+
+// ResolveWebhookSecret retrieves the secret expected from the webhooks of the given registry,
+// either from the inline configuration, or from the referenced credentials Secret.
+func ResolveWebhookSecret(ctx context.Context, k8sClient client.Client, regConfig *RegistryConfig) (string, error) {
+	if regConfig.WebhookSecret != "" || regConfig.CredentialsSecret == nil {
+		return regConfig.WebhookSecret, nil
+	}
+
+	secret, err := getCredentialsSecret(ctx, k8sClient, regConfig)
+	if err != nil {
+		return "", err
+	}
+	return string(secret.Data[SecretWebhookSecretKey]), nil
+}
+
+// getCredentialsSecret retrieves the credentials Secret referenced by the given registry configuration.
+func getCredentialsSecret(ctx context.Context, k8sClient client.Client, regConfig *RegistryConfig) (*corev1.Secret, error) {
+	var secret corev1.Secret
+	key := client.ObjectKey{Name: regConfig.CredentialsSecret.Name, Namespace: regConfig.CredentialsSecret.Namespace}
+	if err := k8sClient.Get(ctx, key, &secret); err != nil {
+		return nil, fmt.Errorf("failed to retrieve credentials secret %s: %w", key, err)
+	}
+	return &secret, nil
+}
+
+// credentialsFromDockerConfig extracts the credentials of the given registry from a dockerconfigjson document,
+// matching the entries with the host of either the registry URL or the registry name.
+func credentialsFromDockerConfig(data []byte, regConfig *RegistryConfig) (RegistryCredentials, error) {
+	var config dockerConfigJSON
+	if err := json.Unmarshal(data, &config); err != nil {
+		return RegistryCredentials{}, fmt.Errorf("failed to parse dockerconfigjson: %w", err)
+	}
+
+	hosts := []string{registryHost(regConfig.URL), registryHost(regConfig.RegistryName)}
+	for server, entry := range config.Auths {
+		host := registryHost(server)
+		if host == "" || (host != hosts[0] && host != hosts[1]) {
+			continue
+		}
+
+		credentials := RegistryCredentials{Username: entry.Username, Password: entry.Password, Token: entry.RegistryToken}
+		if entry.Auth != "" && credentials.Username == "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return RegistryCredentials{}, fmt.Errorf("failed to decode the auth field of %s: %w", server, err)
+			}
+			credentials.Username, credentials.Password, _ = strings.Cut(string(decoded), ":")
+		}
+		return credentials, nil
+	}
+
+	return RegistryCredentials{}, fmt.Errorf("no credentials found in dockerconfigjson for registry %s", regConfig.RegistryName)
+}
+
+// registryHost returns the host (including the port, if any) of a registry address, with or without scheme.
+func registryHost(address string) string {
+	if !strings.Contains(address, "://") {
+		address = "https://" + address
+	}
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return ""
+	}
+	return parsed.Host
+}
+
+// NewRegistryHTTPClient returns an HTTP client authenticating the requests with the given credentials.
+func NewRegistryHTTPClient(credentials RegistryCredentials, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: NewRegistryTransport(credentials, http.DefaultTransport),
+	}
+}
+
+// registryTransport is an http.RoundTripper authenticating the requests to a registry.
+// Static bearer tokens take precedence; otherwise, basic auth is used, and the bearer token
+// flow of the Docker registry token service is performed whenever requested by the registry
+// through the WWW-Authenticate header. The tokens obtained are cached by scope.
+type registryTransport struct {
+	base        http.RoundTripper
+	credentials RegistryCredentials
+
+	mu     sync.Mutex
+	tokens map[string]cachedToken
+}
+
+// cachedToken is a token issued by a registry token service.
+type cachedToken struct {
+	token   string
+	expires time.Time
+}
+
+// bearerChallenge represents the parameters of a Bearer WWW-Authenticate challenge.
+type bearerChallenge struct {
+	realm   string
+	service string
+	scope   string
+}
+
+// tokenResponse is the response of a registry token service.
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// NewRegistryTransport returns an http.RoundTripper authenticating the requests with the given credentials.
+func NewRegistryTransport(credentials RegistryCredentials, base http.RoundTripper) http.RoundTripper {
+	return &registryTransport{base: base, credentials: credentials, tokens: map[string]cachedToken{}}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *registryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.credentials.Token != "" {
+		return t.base.RoundTrip(withBearerToken(req, t.credentials.Token))
+	}
+
+	scope := scopeFromRequest(req)
+	authenticated := req.Clone(req.Context())
+	if token, ok := t.cachedToken(scope); ok {
+		authenticated.Header.Set("Authorization", "Bearer "+token)
+	} else if t.credentials.Username != "" || t.credentials.Password != "" {
+		authenticated.SetBasicAuth(t.credentials.Username, t.credentials.Password)
+	}
+
+	resp, err := t.base.RoundTrip(authenticated)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	challenge, ok := parseBearerChallenge(resp.Header.Get("WWW-Authenticate"))
+	if !ok {
+		return resp, nil
+	}
+	if challenge.scope == "" {
+		challenge.scope = scope
+	}
+
+	token, err := t.fetchToken(req.Context(), challenge)
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	_ = resp.Body.Close()
+
+	t.mu.Lock()
+	t.tokens[scope] = token
+	t.mu.Unlock()
+
+	return t.base.RoundTrip(withBearerToken(req, token.token))
+}
+
+// cachedToken returns the token cached for the given scope, if still valid.
+func (t *registryTransport) cachedToken(scope string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	token, ok := t.tokens[scope]
+	if !ok || time.Now().After(token.expires) {
+		return "", false
+	}
+	return token.token, true
+}
+
+// fetchToken retrieves a token from the token service advertised by the given challenge.
+func (t *registryTransport) fetchToken(ctx context.Context, challenge bearerChallenge) (cachedToken, error) {
+	realm, err := url.Parse(challenge.realm)
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("invalid token service realm %q: %w", challenge.realm, err)
+	}
+
+	query := realm.Query()
+	if challenge.service != "" {
+		query.Set("service", challenge.service)
+	}
+	for _, scope := range strings.Fields(challenge.scope) {
+		query.Add("scope", scope)
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), http.NoBody)
+	if err != nil {
+		return cachedToken{}, err
+	}
+	if t.credentials.Username != "" || t.credentials.Password != "" {
+		req.SetBasicAuth(t.credentials.Username, t.credentials.Password)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("failed to contact the token service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("failed to read the token service response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return cachedToken{}, fmt.Errorf("token service returned status code %d", resp.StatusCode)
+	}
+
+	var parsed tokenResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return cachedToken{}, fmt.Errorf("failed to parse the token service response: %w", err)
+	}
+
+	token := cachedToken{token: parsed.Token, expires: time.Now().Add(defaultTokenLifetime)}
+	if token.token == "" {
+		token.token = parsed.AccessToken
+	}
+	if token.token == "" {
+		return cachedToken{}, fmt.Errorf("token service returned an empty token")
+	}
+	if parsed.ExpiresIn > 0 {
+		token.expires = time.Now().Add(time.Duration(parsed.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// parseBearerChallenge parses a Bearer WWW-Authenticate header, returning false if a different scheme is requested.
+func parseBearerChallenge(header string) (bearerChallenge, bool) {
+	scheme, params, _ := strings.Cut(header, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return bearerChallenge{}, false
+	}
+
+	var challenge bearerChallenge
+	for _, match := range challengeParamsRegex.FindAllStringSubmatch(params, -1) {
+		switch strings.ToLower(match[1]) {
+		case "realm":
+			challenge.realm = match[2]
+		case "service":
+			challenge.service = match[2]
+		case "scope":
+			challenge.scope = match[2]
+		}
+	}
+	return challenge, challenge.realm != ""
+}
+
+// scopeFromRequest returns the token scope required to perform the given request to the registry API.
+func scopeFromRequest(req *http.Request) string {
+	rest, ok := strings.CutPrefix(req.URL.Path, "/v2/")
+	if !ok {
+		return ""
+	}
+	if rest == "_catalog" {
+		return "registry:catalog:*"
+	}
+	for _, marker := range []string{"/tags/", "/manifests/", "/blobs/"} {
+		if idx := strings.Index(rest, marker); idx > 0 {
+			action := "pull"
+			if req.Method == http.MethodDelete {
+				action = "delete"
+			}
+			return "repository:" + rest[:idx] + ":" + action
+		}
+	}
+	return ""
+}
+
+// withBearerToken returns a copy of the given request, authenticated with the given bearer token.
+func withBearerToken(req *http.Request, token string) *http.Request {
+	authenticated := req.Clone(req.Context())
+	authenticated.Header.Set("Authorization", "Bearer "+token)
+	return authenticated
+}
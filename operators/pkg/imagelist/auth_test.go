@@ -0,0 +1,242 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagelist_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	imagelist "github.com/netgroup-polito/CrownLabs/operators/pkg/imagelist"
+)
+
+func newImageListTestClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(clv1alpha1.AddToScheme(scheme)).To(Succeed())
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithStatusSubresource(&clv1alpha1.ImageRegistry{}, &clv1alpha1.ImageList{}).Build()
+}
+
+var _ = Describe("ResolveRegistryCredentials", func() {
+	var regConfig imagelist.RegistryConfig
+
+	BeforeEach(func() {
+		regConfig = imagelist.RegistryConfig{
+			Name:              "registry",
+			URL:               "http://registry.registry.svc:5000",
+			RegistryName:      "registry.example.com",
+			Username:          "inline",
+			Password:          "inline",
+			CredentialsSecret: &clv1alpha1.GenericRef{Name: "credentials", Namespace: "crownlabs"},
+		}
+	})
+
+	resolve := func(secret *corev1.Secret) (imagelist.RegistryCredentials, error) {
+		secret.ObjectMeta = metav1.ObjectMeta{Name: "credentials", Namespace: "crownlabs"}
+		return imagelist.ResolveRegistryCredentials(context.Background(), newImageListTestClient(secret), &regConfig)
+	}
+
+	It("uses the inline credentials if no secret is referenced", func() {
+		regConfig.CredentialsSecret = nil
+		Expect(imagelist.ResolveRegistryCredentials(context.Background(), newImageListTestClient(), &regConfig)).
+			To(Equal(imagelist.RegistryCredentials{Username: "inline", Password: "inline"}))
+	})
+
+	It("extracts the credentials matching the registry from a dockerconfigjson secret", func() {
+		auth := base64.StdEncoding.EncodeToString([]byte("robot$crownlabs:p4ss:word"))
+		Expect(resolve(&corev1.Secret{
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths": {
+				"https://index.docker.io/v1/": {"username": "other", "password": "other"},
+				"https://registry.example.com": {"auth": "` + auth + `"}
+			}}`)},
+		})).To(Equal(imagelist.RegistryCredentials{Username: "robot$crownlabs", Password: "p4ss:word"}))
+	})
+
+	It("fails if the dockerconfigjson secret does not contain the registry", func() {
+		_, err := resolve(&corev1.Secret{
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths": {"ghcr.io": {"username": "other"}}}`)},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("extracts the token from a generic secret", func() {
+		Expect(resolve(&corev1.Secret{Data: map[string][]byte{imagelist.SecretTokenKey: []byte("t0k3n")}})).
+			To(Equal(imagelist.RegistryCredentials{Token: "t0k3n"}))
+	})
+
+	It("fails if the secret does not exist", func() {
+		_, err := imagelist.ResolveRegistryCredentials(context.Background(), newImageListTestClient(), &regConfig)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Registry authentication", func() {
+	var (
+		server         *httptest.Server
+		tokenRequests  int
+		tokenScopes    []string
+		authorizations []string
+	)
+
+	BeforeEach(func() {
+		tokenRequests = 0
+		tokenScopes = nil
+		authorizations = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/token" {
+				tokenRequests++
+				if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				Expect(r.URL.Query().Get("service")).To(Equal("registry.example.com"))
+				tokenScopes = append(tokenScopes, r.URL.Query()["scope"]...)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"token": "issued", "expires_in": 300})
+				return
+			}
+
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+			if r.Header.Get("Authorization") != "Bearer issued" && r.Header.Get("Authorization") != "Bearer static" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="registry.example.com"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"repositories": []string{}})
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	get := func(httpClient *http.Client, path string) int {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+path, http.NoBody)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := httpClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	It("performs the token service flow when challenged, caching the token", func() {
+		httpClient := imagelist.NewRegistryHTTPClient(imagelist.RegistryCredentials{Username: "user", Password: "pass"}, 10*time.Second)
+
+		Expect(get(httpClient, "/v2/_catalog")).To(Equal(http.StatusOK))
+		Expect(get(httpClient, "/v2/_catalog")).To(Equal(http.StatusOK))
+		Expect(get(httpClient, "/v2/crownlabs/desktop/tags/list")).To(Equal(http.StatusOK))
+
+		Expect(tokenRequests).To(Equal(2))
+		Expect(tokenScopes).To(Equal([]string{"registry:catalog:*", "repository:crownlabs/desktop:pull"}))
+		Expect(authorizations[0]).To(HavePrefix("Basic "))
+		Expect(authorizations[1:3]).To(Equal([]string{"Bearer issued", "Bearer issued"}))
+	})
+
+	It("fails if the token service rejects the credentials", func() {
+		httpClient := imagelist.NewRegistryHTTPClient(imagelist.RegistryCredentials{Username: "user", Password: "wrong"}, 10*time.Second)
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/v2/_catalog", http.NoBody)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = httpClient.Do(req) //nolint:bodyclose // The response is nil in case of error.
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("uses the static token, if configured", func() {
+		httpClient := imagelist.NewRegistryHTTPClient(imagelist.RegistryCredentials{Username: "user", Token: "static"}, 10*time.Second)
+		Expect(get(httpClient, "/v2/_catalog")).To(Equal(http.StatusOK))
+		Expect(tokenRequests).To(BeZero())
+		Expect(authorizations).To(Equal([]string{"Bearer static"}))
+	})
+})
+
+var _ = Describe("LoadRegistries", func() {
+	var (
+		ctx        context.Context
+		configFile string
+		k8sClient  client.Client
+	)
+
+	registry := func(name string) *clv1alpha1.ImageRegistry {
+		return &clv1alpha1.ImageRegistry{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: clv1alpha1.ImageRegistrySpec{
+				Type:                 clv1alpha1.ImageRegistryTypeOCI,
+				URL:                  "https://ghcr.io",
+				RegistryName:         "ghcr.io",
+				ImageListName:        name,
+				CredentialsSecretRef: &clv1alpha1.GenericRef{Name: "credentials", Namespace: "crownlabs"},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		configFile = filepath.Join(GinkgoT().TempDir(), "registries.yaml")
+		Expect(os.WriteFile(configFile, []byte(`
+- name: docker
+  type: docker
+  url: http://docker.example.com
+  registryName: docker.example.com
+  imageListName: docker
+`), 0o600)).To(Succeed())
+		k8sClient = newImageListTestClient(registry("ghcr"), registry("docker"))
+	})
+
+	It("merges the configuration file with the ImageRegistry resources, giving precedence to the former", func() {
+		config, err := imagelist.LoadRegistries(ctx, k8sClient, configFile, logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config).To(HaveLen(2))
+		Expect(config[0].Type).To(Equal("docker"))
+		Expect(config[1].Name).To(Equal("ghcr"))
+		Expect(config[1].Type).To(Equal("oci"))
+		Expect(config[1].CredentialsSecret).To(Equal(&clv1alpha1.GenericRef{Name: "credentials", Namespace: "crownlabs"}))
+	})
+
+	It("tolerates the absence of the configuration file", func() {
+		config, err := imagelist.LoadRegistries(ctx, k8sClient, filepath.Join(filepath.Dir(configFile), "missing.yaml"), logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config).To(HaveLen(2))
+	})
+
+	It("records the outcome of the updates in the ImageRegistry status", func() {
+		config, err := imagelist.LoadRegistries(ctx, k8sClient, "", logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(imagelist.UpdateRegistryStatus(ctx, k8sClient, &config[0], 3, errors.New("unreachable"))).To(Succeed())
+		var updated clv1alpha1.ImageRegistry
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: config[0].Name}, &updated)).To(Succeed())
+		Expect(updated.Status.Ready).To(BeFalse())
+		Expect(updated.Status.Error).To(Equal("unreachable"))
+		Expect(updated.Status.LastUpdateTime).NotTo(BeNil())
+	})
+})
@@ -108,9 +108,20 @@ func ProcessImageList(images []map[string]interface{}) []clv1alpha1.ImageListIte
 			continue
 		}
 
+		// Extract the details of the versions, if provided by the requestor
+		var details []clv1alpha1.ImageVersionDetails
+		if detailsIface, ok := image["details"].([]clv1alpha1.ImageVersionDetails); ok {
+			for i := range detailsIface {
+				if detailsIface[i].Version != "latest" {
+					details = append(details, detailsIface[i])
+				}
+			}
+		}
+
 		out = append(out, clv1alpha1.ImageListItem{
 			Name:     name,
 			Versions: versions,
+			Details:  details,
 		})
 	}
 
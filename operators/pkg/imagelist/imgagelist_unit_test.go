@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
@@ -54,6 +55,21 @@ var _ = Describe("ProcessImageList", func() {
 		Expect(res[0].Versions).To(Equal([]string{"v1.1"}))
 	})
 
+	It("keeps the details of non-latest versions", func() {
+		images := []map[string]interface{}{{
+			"name": "prova",
+			"tags": []string{"v1.1", "latest"},
+			"details": []clv1alpha1.ImageVersionDetails{
+				{Version: "v1.1", Digest: "sha256:1111"},
+				{Version: "latest", Digest: "sha256:1111"},
+			},
+		}}
+
+		res := imagelist.ProcessImageList(images)
+		Expect(res).To(HaveLen(1))
+		Expect(res[0].Details).To(Equal([]clv1alpha1.ImageVersionDetails{{Version: "v1.1", Digest: "sha256:1111"}}))
+	})
+
 	It("skips images with no tags field", func() {
 		images := []map[string]interface{}{{
 			"name": "no-tags",
@@ -112,7 +128,7 @@ var _ = Describe("Requestor", func() {
 		}()
 
 		requestor := imagelist.NewHarborImageListRequestor(logr.Discard())
-		initialized, err := requestor.Initialize("user", "pass", server.URL)
+		initialized, err := requestor.Initialize(imagelist.RegistryCredentials{Username: "user", Password: "pass"}, server.URL)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(initialized).To(BeTrue())
 
@@ -125,6 +141,136 @@ var _ = Describe("Requestor", func() {
 	})
 })
 
+var _ = Describe("OCIImageListRequestor", func() {
+	const (
+		configDigest   = "sha256:c0ff1e"
+		indexDigest    = "sha256:1dea"
+		amd64Digest    = "sha256:a4d64"
+		manifestDigest = "sha256:5e1f"
+	)
+
+	var (
+		server   *httptest.Server
+		requests []string
+	)
+
+	writeJSON := func(w http.ResponseWriter, mediaType, digest string, body interface{}) {
+		w.Header().Set("Content-Type", mediaType)
+		if digest != "" {
+			w.Header().Set("Docker-Content-Digest", digest)
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}
+
+	BeforeEach(func() {
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.RequestURI())
+			if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			switch r.URL.RequestURI() {
+			case "/v2/_catalog":
+				w.Header().Set("Link", `</v2/_catalog?last=crownlabs%2Fdesktop&n=1>; rel="next"`)
+				writeJSON(w, "application/json", "", map[string]interface{}{"repositories": []string{"crownlabs/desktop"}})
+			case "/v2/_catalog?last=crownlabs%2Fdesktop&n=1":
+				writeJSON(w, "application/json", "", map[string]interface{}{"repositories": []string{"crownlabs/server"}})
+			case "/v2/crownlabs/desktop/tags/list":
+				writeJSON(w, "application/json", "", map[string]interface{}{"name": "crownlabs/desktop", "tags": []string{"v1", "latest"}})
+			case "/v2/crownlabs/server/tags/list":
+				writeJSON(w, "application/json", "", map[string]interface{}{"name": "crownlabs/server", "tags": []string{"v2"}})
+			case "/v2/crownlabs/desktop/manifests/v1":
+				Expect(r.Header.Get("Accept")).To(ContainSubstring(imagelist.MediaTypeOCIIndex))
+				writeJSON(w, imagelist.MediaTypeOCIIndex, indexDigest, map[string]interface{}{
+					"mediaType":   imagelist.MediaTypeOCIIndex,
+					"annotations": map[string]string{"org.opencontainers.image.description": "Desktop environment"},
+					"manifests": []map[string]interface{}{
+						{"digest": "sha256:a4m64", "platform": map[string]string{"os": "linux", "architecture": "arm64"}},
+						{"digest": amd64Digest, "platform": map[string]string{"os": "linux", "architecture": "amd64"}},
+					},
+				})
+			case "/v2/crownlabs/desktop/manifests/" + amd64Digest, "/v2/crownlabs/server/manifests/v2":
+				writeJSON(w, imagelist.MediaTypeOCIManifest, manifestDigest, map[string]interface{}{
+					"mediaType": imagelist.MediaTypeOCIManifest,
+					"config":    map[string]interface{}{"digest": configDigest, "size": 100},
+					"layers":    []map[string]interface{}{{"digest": "sha256:1a7e1", "size": 1000}, {"digest": "sha256:1a7e2", "size": 2000}},
+				})
+			case "/v2/crownlabs/desktop/blobs/" + configDigest, "/v2/crownlabs/server/blobs/" + configDigest:
+				writeJSON(w, "application/json", "", map[string]interface{}{
+					"created":      "2026-05-06T13:33:48Z",
+					"architecture": "amd64",
+					"os":           "linux",
+					"config":       map[string]interface{}{"Labels": map[string]string{"org.opencontainers.image.description": "Overridden", "maintainer": "crownlabs"}},
+				})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("retrieves the images following the pagination, along with the details of each tag", func() {
+		requestor := imagelist.NewOCIImageListRequestor(logr.Discard())
+		initialized, err := requestor.Initialize(imagelist.RegistryCredentials{Username: "user", Password: "pass"}, server.URL+"/")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(initialized).To(BeTrue())
+
+		res, err := requestor.GetImageList(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+
+		items := imagelist.ProcessImageList(res)
+		Expect(items).To(HaveLen(2))
+		Expect(items[0].Name).To(Equal("crownlabs/desktop"))
+		Expect(items[0].Versions).To(Equal([]string{"v1"}))
+		Expect(items[0].Details).To(HaveLen(1))
+
+		created, err := time.Parse(time.RFC3339, "2026-05-06T13:33:48Z")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items[0].Details[0].Version).To(Equal("v1"))
+		Expect(items[0].Details[0].Digest).To(Equal(indexDigest))
+		Expect(items[0].Details[0].Size).To(Equal(int64(3100)))
+		Expect(items[0].Details[0].Architecture).To(Equal("amd64"))
+		Expect(items[0].Details[0].Created.Time.Equal(created)).To(BeTrue())
+		Expect(items[0].Details[0].Annotations).To(Equal(map[string]string{
+			"org.opencontainers.image.description": "Desktop environment",
+			"maintainer":                           "crownlabs",
+		}))
+
+		Expect(items[1].Name).To(Equal("crownlabs/server"))
+		Expect(items[1].Details).To(HaveLen(1))
+		Expect(items[1].Details[0].Digest).To(Equal(manifestDigest))
+		Expect(requests).NotTo(ContainElement("/v2/crownlabs/desktop/manifests/latest"))
+	})
+
+	It("describes the manifest of the configured platform", func() {
+		imagelist.RequestersSharedData["oci_platform"] = "linux/arm64"
+		defer delete(imagelist.RequestersSharedData, "oci_platform")
+
+		requestor := imagelist.NewOCIImageListRequestor(logr.Discard())
+		_, err := requestor.Initialize(imagelist.RegistryCredentials{Username: "user", Password: "pass"}, server.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		// The manifest of the arm64 platform is not available, hence the details cannot be retrieved.
+		_, err = requestor.GetVersionDetails(context.Background(), "crownlabs/desktop", "v1")
+		Expect(err).To(HaveOccurred())
+		Expect(requests).To(ContainElement("/v2/crownlabs/desktop/manifests/sha256:a4m64"))
+	})
+
+	It("fails if the catalog cannot be retrieved", func() {
+		requestor := imagelist.NewOCIImageListRequestor(logr.Discard())
+		_, err := requestor.Initialize(imagelist.RegistryCredentials{Username: "user", Password: "wrong"}, server.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = requestor.GetImageList(context.Background())
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("DefaultImageListSaver", func() {
 	It("creates an ImageList with empty images while keeping registry and project base name", func() {
 		scheme := runtime.NewScheme()
@@ -0,0 +1,58 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagelist
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricLabelRegistry = "registry"
+	metricLabelAction   = "action"
+	metricLabelResult   = "result"
+	metricLabelSource   = "source"
+
+	metricResultSuccess = "success"
+	metricResultError   = "error"
+	metricResultIgnored = "ignored"
+
+	metricSourceCrawl   = "crawl"
+	metricSourceWebhook = "webhook"
+)
+
+var (
+	metricWebhookEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "imagelist_webhook_events_total",
+		Help: "The number of image events received from the registry webhooks, by outcome",
+	}, []string{metricLabelRegistry, metricLabelAction, metricLabelResult})
+
+	metricWebhookEventLag = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "imagelist_webhook_event_lag_seconds",
+		Help: "The number of seconds elapsed between the generation of an image event and its application to the ImageList",
+		// Buckets (upper bound in seconds): 0.5, 1, 2, 4, .., 256
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 10),
+	}, []string{metricLabelRegistry})
+
+	metricLastUpdate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "imagelist_last_update_timestamp_seconds",
+		Help: "The timestamp of the last successful update of the ImageList associated with each registry, by source",
+	}, []string{metricLabelRegistry, metricLabelSource})
+)
+
+func init() {
+	// Register custom metrics with the global prometheus registry
+	metrics.Registry.MustRegister(metricWebhookEvents, metricWebhookEventLag, metricLastUpdate)
+}
@@ -0,0 +1,81 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagelist
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
+)
+
+// RegistryReconciler updates the ImageList associated with an ImageRegistry as soon as the latter is created or
+// modified, without waiting for the next periodic update, which subsequently keeps it up to date.
+type RegistryReconciler struct {
+	client.Client
+	ConfigFilePath string
+}
+
+// Reconcile reconciles the state of an ImageRegistry resource.
+func (r *RegistryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	var registry clv1alpha1.ImageRegistry
+	if err := r.Get(ctx, req.NamespacedName, &registry); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// The configurations from the file take precedence, hence the registry is not processed in case of clashes.
+	if r.ConfigFilePath != "" {
+		config, err := LoadRegistriesConfig(r.ConfigFilePath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return ctrl.Result{}, err
+		}
+		if slices.ContainsFunc(config, func(c RegistryConfig) bool { return c.Name == registry.Name }) {
+			log.Info("ImageRegistry clashing with the configuration file, skipping")
+			return ctrl.Result{}, nil
+		}
+	}
+
+	regConfig := RegistryConfigFromResource(&registry)
+	items, err := ProcessSingleRegistryConfigWithItems(ctx, &regConfig, r.Client, log)
+	if statusErr := UpdateRegistryStatus(ctx, r.Client, &regConfig, len(items), err); statusErr != nil {
+		log.Error(statusErr, "failed to update registry status")
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	metricLastUpdate.WithLabelValues(regConfig.Name, metricSourceCrawl).SetToCurrentTime()
+	log.Info("successfully updated image list", "imagelist_name", regConfig.ImageListName, "items_count", len(items))
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager registers a new controller for ImageRegistry resources.
+func (r *RegistryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// The generation changed predicate allows to avoid reconciling on the status changes of the ImageRegistry
+		For(&clv1alpha1.ImageRegistry{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithLogConstructor(utils.LogConstructor(mgr.GetLogger(), "ImageRegistry")).
+		Complete(r)
+}
@@ -17,16 +17,23 @@ package imagelist
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2/textlogger"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
 )
 
 // Requestor defines the interface for objects responsible to retrieve the list of images from upstream sources.
@@ -35,7 +42,13 @@ type Requestor interface {
 	// GetImageList retrieves the list of images from the upstream registry.
 	GetImageList(ctx context.Context) ([]map[string]interface{}, error)
 	// Initialize initializes the requestor with configuration data.
-	Initialize(username, password, registryURL string) (bool, error)
+	Initialize(credentials RegistryCredentials, registryURL string) (bool, error)
+}
+
+// VersionDeleter is implemented by the requestors able to delete the versions of the images from the registry.
+type VersionDeleter interface {
+	// DeleteVersion deletes the given version (i.e., tag) of an image from the registry.
+	DeleteVersion(ctx context.Context, image, version string) error
 }
 
 // RegisteredRequestors holds the list of all registered image list requestors.
@@ -47,8 +60,6 @@ var RequestersSharedData = map[string]string{}
 // DockerImageListRequestor interacts with a Docker registry to retrieve the list of images currently available.
 type DockerImageListRequestor struct {
 	url         string
-	username    string
-	password    string
 	client      *http.Client
 	initialized bool
 	log         logr.Logger
@@ -58,8 +69,6 @@ type DockerImageListRequestor struct {
 func NewDockerImageListRequestor(log logr.Logger) *DockerImageListRequestor {
 	return &DockerImageListRequestor{
 		url:         "",
-		username:    "",
-		password:    "",
 		client:      &http.Client{Timeout: 10 * time.Second},
 		initialized: false,
 		log:         log,
@@ -68,10 +77,9 @@ func NewDockerImageListRequestor(log logr.Logger) *DockerImageListRequestor {
 
 // Initialize initializes the requestor with configuration from shared data.
 // Returns true if initialization was successful, false otherwise.
-func (r *DockerImageListRequestor) Initialize(username, password, registryURL string) (bool, error) {
+func (r *DockerImageListRequestor) Initialize(credentials RegistryCredentials, registryURL string) (bool, error) {
 	r.url = registryURL
-	r.username = username
-	r.password = password
+	r.client = NewRegistryHTTPClient(credentials, r.client.Timeout)
 	r.initialized = true
 	return true, nil
 }
@@ -107,8 +115,6 @@ func (r *DockerImageListRequestor) doSingleGet(ctx context.Context, path string)
 		return nil, err
 	}
 
-	req.SetBasicAuth(r.username, r.password)
-
 	resp, err := r.client.Do(req)
 	if err != nil {
 		r.log.Error(err, "failed to perform HTTP request", "path", path)
@@ -189,8 +195,6 @@ func (r *DockerImageListRequestor) mapRepositoriesToPaths(repositories []interfa
 // Harbor uses different API endpoints compared to Docker registry V2.
 type HarborImageListRequestor struct {
 	url         string
-	username    string
-	password    string
 	projectName string
 	client      *http.Client
 	initialized bool
@@ -201,8 +205,6 @@ type HarborImageListRequestor struct {
 func NewHarborImageListRequestor(log logr.Logger) *HarborImageListRequestor {
 	return &HarborImageListRequestor{
 		url:         "",
-		username:    "",
-		password:    "",
 		projectName: "",
 		client:      &http.Client{},
 		initialized: false,
@@ -213,10 +215,9 @@ func NewHarborImageListRequestor(log logr.Logger) *HarborImageListRequestor {
 // Initialize initializes the requestor with configuration from shared data.
 // For Harbor, the projectName should be provided in RequestersSharedData["harbor_project_name"]
 // Returns true if initialization was successful, false otherwise.
-func (r *HarborImageListRequestor) Initialize(username, password, registryURL string) (bool, error) {
+func (r *HarborImageListRequestor) Initialize(credentials RegistryCredentials, registryURL string) (bool, error) {
 	r.url = registryURL
-	r.username = username
-	r.password = password
+	r.client = NewRegistryHTTPClient(credentials, r.client.Timeout)
 
 	// Try to get project name from shared data
 	projectName, ok := RequestersSharedData["harbor_project_name"]
@@ -410,8 +411,6 @@ func (r *HarborImageListRequestor) doSingleGetAsList(ctx context.Context, path s
 		return nil, err
 	}
 
-	req.SetBasicAuth(r.username, r.password)
-
 	resp, err := r.client.Do(req)
 	if err != nil {
 		r.log.Error(err, "failed to perform HTTP request", "path", path)
@@ -498,8 +497,6 @@ func (r *HarborImageListRequestor) doSingleGet(ctx context.Context, path string)
 		return nil, err
 	}
 
-	req.SetBasicAuth(r.username, r.password)
-
 	resp, err := r.client.Do(req)
 	if err != nil {
 		r.log.Error(err, "failed to perform HTTP request", "path", path)
@@ -611,9 +608,393 @@ func GetMapKeys(m map[string]interface{}) []string {
 	return keys
 }
 
+const (
+	// MediaTypeOCIIndex is the media type of the OCI image indexes.
+	MediaTypeOCIIndex = "application/vnd.oci.image.index.v1+json"
+	// MediaTypeOCIManifest is the media type of the OCI image manifests.
+	MediaTypeOCIManifest = "application/vnd.oci.image.manifest.v1+json"
+	// MediaTypeDockerManifestList is the media type of the Docker manifest lists.
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	// MediaTypeDockerManifest is the media type of the Docker image manifests.
+	MediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+
+	// DefaultOCIPlatform is the platform whose manifest is described in case of multi-platform images.
+	DefaultOCIPlatform = "linux/amd64"
+
+	// ociMaxConcurrentRequests is the maximum number of concurrent requests performed towards the registry.
+	ociMaxConcurrentRequests = 8
+)
+
+// nextLinkRegex matches the URL of the next page in the Link header of paginated responses.
+var nextLinkRegex = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+// ociDescriptor is a reference to a content stored in the registry.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+		Variant      string `json:"variant,omitempty"`
+	} `json:"platform,omitempty"`
+}
+
+// ociManifest represents both image manifests and indexes (i.e., manifest lists).
+type ociManifest struct {
+	MediaType   string            `json:"mediaType"`
+	Config      *ociDescriptor    `json:"config,omitempty"`
+	Layers      []ociDescriptor   `json:"layers,omitempty"`
+	Manifests   []ociDescriptor   `json:"manifests,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociImageConfig is the subset of the image configuration relevant for the image list.
+type ociImageConfig struct {
+	Created      *time.Time `json:"created,omitempty"`
+	Architecture string     `json:"architecture"`
+	OS           string     `json:"os"`
+	Config       struct {
+		Labels map[string]string `json:"Labels,omitempty"`
+	} `json:"config"`
+}
+
+// OCIImageListRequestor interacts with a registry implementing the OCI distribution specification to retrieve
+// the list of images currently available, along with the digest and the metadata of each tag.
+type OCIImageListRequestor struct {
+	url         string
+	platform    string
+	client      *http.Client
+	initialized bool
+	log         logr.Logger
+}
+
+// NewOCIImageListRequestor creates a new OCIImageListRequestor instance.
+func NewOCIImageListRequestor(log logr.Logger) *OCIImageListRequestor {
+	return &OCIImageListRequestor{
+		platform:    DefaultOCIPlatform,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		initialized: false,
+		log:         log,
+	}
+}
+
+// Initialize initializes the requestor with configuration from shared data.
+// The platform described for multi-platform images can be provided in RequestersSharedData["oci_platform"].
+// Returns true if initialization was successful, false otherwise.
+func (r *OCIImageListRequestor) Initialize(credentials RegistryCredentials, registryURL string) (bool, error) {
+	r.url = strings.TrimSuffix(registryURL, "/")
+	r.client = NewRegistryHTTPClient(credentials, r.client.Timeout)
+	if platform, ok := RequestersSharedData["oci_platform"]; ok && platform != "" {
+		r.platform = platform
+	}
+	r.initialized = true
+	return true, nil
+}
+
+// GetImageList retrieves the list of images from the upstream registry.
+// It fetches the catalog first, then retrieves the tags and the manifest of each tag for each repository.
+// Returns data in the format expected by processImageList: {"name": "repo", "tags": ["tag1"], "details": [...]}.
+func (r *OCIImageListRequestor) GetImageList(ctx context.Context) ([]map[string]interface{}, error) {
+	if !r.initialized {
+		return nil, fmt.Errorf("OCI image list requestor not initialized")
+	}
+
+	r.log.V(1).Info("requesting registry catalog upstream")
+	repositories, err := r.getPaginatedList(ctx, "/v2/_catalog", "repositories")
+	if err != nil {
+		r.log.Error(err, "failed to retrieve catalog")
+		return nil, err
+	}
+
+	r.log.V(1).Info("requesting image details upstream", "repository_count", len(repositories))
+	results := make([]map[string]interface{}, len(repositories))
+	errs := make([]error, len(repositories))
+	semaphore := make(chan struct{}, ociMaxConcurrentRequests)
+	var wg sync.WaitGroup
+
+	for i, repository := range repositories {
+		wg.Add(1)
+		go func(i int, repository string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i], errs[i] = r.getRepositoryImage(ctx, repository)
+		}(i, repository)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("repository %s: %w", repositories[i], err)
+		}
+	}
+	return results, nil
+}
+
+// getRepositoryImage retrieves the tags of the given repository, along with the details of each of them.
+func (r *OCIImageListRequestor) getRepositoryImage(ctx context.Context, repository string) (map[string]interface{}, error) {
+	tags, err := r.getPaginatedList(ctx, fmt.Sprintf("/v2/%s/tags/list", repository), "tags")
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]clv1alpha1.ImageVersionDetails, 0, len(tags))
+	for _, tag := range tags {
+		if tag == "latest" {
+			continue
+		}
+
+		detail, err := r.GetVersionDetails(ctx, repository, tag)
+		if err != nil {
+			// The tag is still listed, although without details (e.g., it has been deleted in the meanwhile).
+			r.log.Error(err, "failed to retrieve image details", "repository", repository, "tag", tag)
+			continue
+		}
+		details = append(details, *detail)
+	}
+
+	return map[string]interface{}{
+		"name":    repository,
+		"tags":    tags,
+		"details": details,
+	}, nil
+}
+
+// GetVersionDetails retrieves the digest and the metadata of the given tag of a repository.
+func (r *OCIImageListRequestor) GetVersionDetails(ctx context.Context, repository, tag string) (*clv1alpha1.ImageVersionDetails, error) {
+	manifest, digest, err := r.getManifest(ctx, repository, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &clv1alpha1.ImageVersionDetails{Version: tag, Digest: digest, Annotations: map[string]string{}}
+	for key, value := range manifest.Annotations {
+		detail.Annotations[key] = value
+	}
+
+	// In case of multi-platform images, describe the manifest of the configured platform.
+	if len(manifest.Manifests) > 0 {
+		selected := r.selectPlatformManifest(manifest.Manifests)
+		if manifest, _, err = r.getManifest(ctx, repository, selected.Digest); err != nil {
+			return nil, err
+		}
+		for key, value := range manifest.Annotations {
+			if _, found := detail.Annotations[key]; !found {
+				detail.Annotations[key] = value
+			}
+		}
+		if selected.Platform != nil {
+			detail.Architecture = selected.Platform.Architecture
+		}
+	}
+
+	for i := range manifest.Layers {
+		detail.Size += manifest.Layers[i].Size
+	}
+
+	if manifest.Config != nil {
+		detail.Size += manifest.Config.Size
+
+		var config ociImageConfig
+		if err := r.getJSON(ctx, fmt.Sprintf("/v2/%s/blobs/%s", repository, manifest.Config.Digest), &config); err != nil {
+			return nil, fmt.Errorf("failed to retrieve the configuration of %s:%s: %w", repository, tag, err)
+		}
+		if config.Created != nil {
+			detail.Created = &metav1.Time{Time: *config.Created}
+		}
+		if config.Architecture != "" {
+			detail.Architecture = config.Architecture
+		}
+		// The manifest annotations take precedence over the labels of the configuration.
+		for key, value := range config.Config.Labels {
+			if _, found := detail.Annotations[key]; !found {
+				detail.Annotations[key] = value
+			}
+		}
+	}
+
+	if len(detail.Annotations) == 0 {
+		detail.Annotations = nil
+	}
+	return detail, nil
+}
+
+// selectPlatformManifest returns the manifest matching the configured platform, or the first one if none matches.
+func (r *OCIImageListRequestor) selectPlatformManifest(manifests []ociDescriptor) *ociDescriptor {
+	osName, arch, _ := strings.Cut(r.platform, "/")
+	for i := range manifests {
+		if platform := manifests[i].Platform; platform != nil && platform.OS == osName && platform.Architecture == arch {
+			return &manifests[i]
+		}
+	}
+	return &manifests[0]
+}
+
+// getManifest retrieves the manifest identified by the given reference (either a tag or a digest), along with its digest.
+func (r *OCIImageListRequestor) getManifest(ctx context.Context, repository, reference string) (*ociManifest, string, error) {
+	accept := strings.Join([]string{MediaTypeOCIIndex, MediaTypeOCIManifest, MediaTypeDockerManifestList, MediaTypeDockerManifest}, ", ")
+	body, header, err := r.doGet(ctx, fmt.Sprintf("/v2/%s/manifests/%s", repository, reference), accept)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to parse the manifest of %s:%s: %w", repository, reference, err)
+	}
+
+	digest := header.Get("Docker-Content-Digest")
+	if digest == "" {
+		sum := sha256.Sum256(body)
+		digest = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return &manifest, digest, nil
+}
+
+// getPaginatedList retrieves a list of strings from a paginated endpoint, following the Link headers.
+func (r *OCIImageListRequestor) getPaginatedList(ctx context.Context, path, key string) ([]string, error) {
+	var items []string
+	for path != "" {
+		var page map[string]json.RawMessage
+		var pageItems []string
+		body, header, err := r.doGet(ctx, path, "application/json")
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse the response of %s: %w", path, err)
+		}
+		if raw, ok := page[key]; ok {
+			if err := json.Unmarshal(raw, &pageItems); err != nil {
+				return nil, fmt.Errorf("failed to parse the %q field of %s: %w", key, path, err)
+			}
+		}
+		items = append(items, pageItems...)
+		path = r.nextPagePath(header)
+	}
+	return items, nil
+}
+
+// nextPagePath returns the path of the next page advertised by the Link header, if any.
+func (r *OCIImageListRequestor) nextPagePath(header http.Header) string {
+	matches := nextLinkRegex.FindStringSubmatch(header.Get("Link"))
+	if len(matches) < 2 {
+		return ""
+	}
+	next, err := url.Parse(matches[1])
+	if err != nil {
+		r.log.Error(err, "failed to parse the next page link", "link", matches[1])
+		return ""
+	}
+	return next.RequestURI()
+}
+
+// getJSON performs a GET request to the target path and parses the JSON result into the given target.
+func (r *OCIImageListRequestor) getJSON(ctx context.Context, path string, target interface{}) error {
+	body, _, err := r.doGet(ctx, path, "")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, target)
+}
+
+// doGet performs a single GET request to the target path, returning the body and the headers of the response.
+func (r *OCIImageListRequestor) doGet(ctx context.Context, path, accept string) ([]byte, http.Header, error) {
+	r.log.V(1).Info("performing GET request to registry", "url", r.url+path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url+path, http.NoBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create HTTP request for %s: %w", path, err)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to perform HTTP request for %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected HTTP status code for %s: %d", path, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the response body of %s: %w", path, err)
+	}
+	return body, resp.Header, nil
+}
+
+// DeleteVersion deletes the manifest the given version refers to from the registry.
+// Note that the other tags referring to the same manifest, if any, are deleted as well.
+func (r *DockerImageListRequestor) DeleteVersion(ctx context.Context, image, version string) error {
+	return deleteManifest(ctx, r.client, r.url, image, version)
+}
+
+// DeleteVersion deletes the manifest the given version refers to from the registry.
+// Note that the other tags referring to the same manifest, if any, are deleted as well.
+func (r *OCIImageListRequestor) DeleteVersion(ctx context.Context, image, version string) error {
+	return deleteManifest(ctx, r.client, r.url, image, version)
+}
+
+// DeleteVersion deletes the artifact the given version refers to from the Harbor project.
+func (r *HarborImageListRequestor) DeleteVersion(ctx context.Context, image, version string) error {
+	path := fmt.Sprintf("/api/v2.0/projects/%s/repositories/%s/artifacts/%s",
+		url.PathEscape(r.projectName), url.PathEscape(url.PathEscape(image)), url.PathEscape(version))
+	return doDelete(ctx, r.client, r.url+path)
+}
+
+// deleteManifest deletes a manifest through the distribution API, which requires resolving the tag into the digest first.
+func deleteManifest(ctx context.Context, httpClient *http.Client, baseURL, image, version string) error {
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/", strings.TrimSuffix(baseURL, "/"), image)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL+version, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Accept", strings.Join([]string{MediaTypeOCIIndex, MediaTypeOCIManifest, MediaTypeDockerManifestList, MediaTypeDockerManifest}, ", "))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s:%s: %w", image, version, err)
+	}
+	resp.Body.Close()
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if resp.StatusCode != http.StatusOK || digest == "" {
+		return fmt.Errorf("failed to resolve the digest of %s:%s: status code %d", image, version, resp.StatusCode)
+	}
+
+	return doDelete(ctx, httpClient, manifestURL+digest)
+}
+
+// doDelete performs a DELETE request to the given URL, expecting a successful status code.
+func doDelete(ctx context.Context, httpClient *http.Client, target string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, target, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform the deletion: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("unexpected HTTP status code for the deletion: %d", resp.StatusCode)
+	}
+	return nil
+}
+
 func init() {
 	dockerLog := textlogger.NewLogger(textlogger.NewConfig()).WithName("imageList").WithName("dockerRequestor")
 	RegisteredRequestors = append(RegisteredRequestors, NewDockerImageListRequestor(dockerLog))
 	harborLog := textlogger.NewLogger(textlogger.NewConfig()).WithName("imageList").WithName("harborRequestor")
 	RegisteredRequestors = append(RegisteredRequestors, NewHarborImageListRequestor(harborLog))
+	ociLog := textlogger.NewLogger(textlogger.NewConfig()).WithName("imageList").WithName("ociRequestor")
+	RegisteredRequestors = append(RegisteredRequestors, NewOCIImageListRequestor(ociLog))
 }
@@ -18,11 +18,13 @@ package imagelist
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/go-logr/logr"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
@@ -32,6 +34,8 @@ import (
 type Saver interface {
 	// CreateOrUpdateImageList creates a new ImageList resource or updates an existing one with the provided images from a specific registry.
 	CreateOrUpdateImageList(registryName, projectBaseName string, images []clv1alpha1.ImageListItem) error
+	// ApplyImageEvents incrementally updates the ImageList resource according to the events notified by a specific registry.
+	ApplyImageEvents(registryName, projectBaseName string, events []ImageEvent) error
 }
 
 // RegisteredSavers holds the list of all registered image list savers.
@@ -111,3 +115,118 @@ func (s *DefaultImageListSaver) CreateOrUpdateImageList(registryName, projectBas
 	s.log.Info("ImageList updated successfully", "name", s.name, "registryName", registryName, "projectBaseName", projectBaseName, "imageCount", len(images))
 	return nil
 }
+
+// ApplyImageEvents incrementally updates the ImageList (creating it if necessary) according to the given events.
+func (s *DefaultImageListSaver) ApplyImageEvents(registryName, projectBaseName string, events []ImageEvent) error {
+	s.log.V(1).Info("applying image events to ImageList", "registryName", registryName, "projectBaseName", projectBaseName, "eventCount", len(events))
+
+	// Retry in case of conflicts, as the ImageList may be concurrently modified by the periodic update.
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		imageList := &clv1alpha1.ImageList{}
+		if err := s.client.Get(s.ctx, types.NamespacedName{Name: s.name}, imageList); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return fmt.Errorf("failed to get ImageList: %w", err)
+			}
+
+			imageList.Name = s.name
+			imageList.Spec = clv1alpha1.ImageListSpec{
+				RegistryName:    registryName,
+				ProjectBaseName: projectBaseName,
+				Images:          ApplyImageEventsToItems(nil, events),
+			}
+			return s.client.Create(s.ctx, imageList)
+		}
+
+		imageList.Spec.Images = ApplyImageEventsToItems(imageList.Spec.Images, events)
+		return s.client.Update(s.ctx, imageList)
+	})
+	if err != nil {
+		s.log.Error(err, "failed to apply image events to ImageList", "name", s.name)
+		return fmt.Errorf("failed to apply image events to ImageList: %w", err)
+	}
+
+	s.log.Info("ImageList incrementally updated", "name", s.name, "registryName", registryName, "eventCount", len(events))
+	return nil
+}
+
+// ApplyImageEventsToItems returns the list of images resulting from the application of the given events.
+// Pushed versions are added (or their digest updated), while deleted versions are removed, together with
+// the images left without any version. Deletions referring to a digest only remove the versions whose
+// details report that digest, since the corresponding tags cannot be determined otherwise.
+func ApplyImageEventsToItems(images []clv1alpha1.ImageListItem, events []ImageEvent) []clv1alpha1.ImageListItem {
+	out := make([]clv1alpha1.ImageListItem, 0, len(images))
+	for i := range images {
+		out = append(out, *images[i].DeepCopy())
+	}
+
+	for i := range events {
+		event := &events[i]
+		if event.Version == "latest" {
+			continue
+		}
+
+		idx := slices.IndexFunc(out, func(item clv1alpha1.ImageListItem) bool { return item.Name == event.Image })
+		switch event.Action {
+		case ImageEventPush:
+			if event.Version == "" {
+				continue
+			}
+			if idx < 0 {
+				out = append(out, clv1alpha1.ImageListItem{Name: event.Image})
+				idx = len(out) - 1
+			}
+			applyPushEvent(&out[idx], event)
+		case ImageEventDelete:
+			if idx >= 0 {
+				applyDeleteEvent(&out[idx], event)
+			}
+		}
+	}
+
+	// Drop the images left without any version, consistently with the full update.
+	return slices.DeleteFunc(out, func(item clv1alpha1.ImageListItem) bool { return len(item.Versions) == 0 })
+}
+
+// applyPushEvent adds the pushed version to the given image, refreshing its details if tracked.
+func applyPushEvent(item *clv1alpha1.ImageListItem, event *ImageEvent) {
+	if !slices.Contains(item.Versions, event.Version) {
+		item.Versions = append(item.Versions, event.Version)
+	}
+
+	// The details are managed only when already tracked for the image (i.e., the requestor provides them),
+	// and are replaced since the remaining metadata of an overwritten tag is no longer valid.
+	if len(item.Details) == 0 || event.Digest == "" {
+		return
+	}
+	details := clv1alpha1.ImageVersionDetails{Version: event.Version, Digest: event.Digest}
+	if idx := slices.IndexFunc(item.Details, func(d clv1alpha1.ImageVersionDetails) bool { return d.Version == event.Version }); idx >= 0 {
+		if item.Details[idx].Digest != event.Digest {
+			item.Details[idx] = details
+		}
+		return
+	}
+	item.Details = append(item.Details, details)
+}
+
+// applyDeleteEvent removes the deleted version(s) from the given image.
+func applyDeleteEvent(item *clv1alpha1.ImageListItem, event *ImageEvent) {
+	versions := []string{event.Version}
+	if event.Version == "" {
+		if event.Digest == "" {
+			return
+		}
+		versions = nil
+		for i := range item.Details {
+			if item.Details[i].Digest == event.Digest {
+				versions = append(versions, item.Details[i].Version)
+			}
+		}
+	}
+
+	deleted := func(version string) bool { return slices.Contains(versions, version) }
+	item.Versions = slices.DeleteFunc(item.Versions, deleted)
+	item.Details = slices.DeleteFunc(item.Details, func(d clv1alpha1.ImageVersionDetails) bool { return deleted(d.Version) })
+	if len(item.Details) == 0 {
+		item.Details = nil
+	}
+}
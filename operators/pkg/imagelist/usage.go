@@ -0,0 +1,490 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagelist
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+)
+
+// ImageReference is a parsed reference to a container image (e.g., registry.example.com/project/image:tag).
+type ImageReference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ImageUsageSources groups the resources possibly using the images of the ImageLists.
+type ImageUsageSources struct {
+	Templates         []clv1alpha2.Template
+	Instances         []clv1alpha2.Instance
+	InstanceSnapshots []clv1alpha2.InstanceSnapshot
+}
+
+// ParseImageReference parses a container image reference, possibly prefixed by the docker:// scheme.
+// The registry is identified by the first path segment, if it looks like a host name.
+func ParseImageReference(ref string) ImageReference {
+	var parsed ImageReference
+	ref = strings.TrimPrefix(ref, "docker://")
+
+	if name, digest, found := strings.Cut(ref, "@"); found {
+		ref, parsed.Digest = name, digest
+	}
+	if idx := strings.LastIndex(ref, ":"); idx > strings.LastIndex(ref, "/") {
+		ref, parsed.Tag = ref[:idx], ref[idx+1:]
+	}
+
+	if first, rest, found := strings.Cut(ref, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		parsed.Registry, ref = first, rest
+	}
+	parsed.Repository = ref
+	return parsed
+}
+
+// MatchImageReference returns the image and the version of the ImageList the given reference refers to, if any.
+// References not specifying the registry are resolved against the registry of the ImageList.
+func MatchImageReference(imageList *clv1alpha1.ImageList, ref ImageReference) (image, imageVersion string, found bool) {
+	item := matchImageItem(imageList, ref)
+	if item == nil {
+		return "", "", false
+	}
+
+	if ref.Digest != "" {
+		for j := range item.Details {
+			if item.Details[j].Digest == ref.Digest {
+				return item.Name, item.Details[j].Version, true
+			}
+		}
+	}
+	if ref.Tag != "" && slices.Contains(item.Versions, ref.Tag) {
+		return item.Name, ref.Tag, true
+	}
+	return "", "", false
+}
+
+// UnresolvedImageReferences returns the images used by the given Templates which refer to an image of the ImageList,
+// but cannot be resolved to any of its versions (e.g., unknown tags or digests). Since the versions they use are
+// unknown, none of the versions of the ImageList can be safely considered unused as long as they exist.
+func UnresolvedImageReferences(imageList *clv1alpha1.ImageList, templates []clv1alpha2.Template) []string {
+	var unresolved []string
+	for i := range templates {
+		for _, image := range templateImages(&templates[i]) {
+			ref := ParseImageReference(image)
+			if matchImageItem(imageList, ref) == nil {
+				continue
+			}
+			if _, _, found := MatchImageReference(imageList, ref); !found && !slices.Contains(unresolved, image) {
+				unresolved = append(unresolved, image)
+			}
+		}
+	}
+	return unresolved
+}
+
+// matchImageItem returns the image of the ImageList the given reference refers to, regardless of the version, if any.
+func matchImageItem(imageList *clv1alpha1.ImageList, ref ImageReference) *clv1alpha1.ImageListItem {
+	if ref.Registry != "" && registryHost(ref.Registry) != registryHost(imageList.Spec.RegistryName) {
+		return nil
+	}
+
+	for i := range imageList.Spec.Images {
+		item := &imageList.Spec.Images[i]
+		if item.Name == ref.Repository || imageList.Spec.ProjectBaseName+"/"+item.Name == ref.Repository {
+			return item
+		}
+	}
+	return nil
+}
+
+// ComputeImageListStatus computes the usage and deprecation status of the images of the given ImageList.
+// The deprecation timestamps are preserved from the current status of the ImageList, if already deprecated.
+func ComputeImageListStatus(imageList *clv1alpha1.ImageList, policy *clv1alpha1.ImageDeprecationPolicy,
+	sources *ImageUsageSources, now time.Time) (clv1alpha1.ImageListStatus, error) {
+	status := clv1alpha1.ImageListStatus{LastUsageUpdateTime: ptr.To(metav1.NewTime(now))}
+
+	patterns, err := compilePatterns(policy)
+	if err != nil {
+		return status, err
+	}
+
+	// Index the status of the versions, to be filled while scanning the sources.
+	versions := map[string]*clv1alpha1.ImageVersionStatus{}
+	images := make([]clv1alpha1.ImageStatus, len(imageList.Spec.Images))
+	for i := range imageList.Spec.Images {
+		item := &imageList.Spec.Images[i]
+		images[i] = clv1alpha1.ImageStatus{Name: item.Name, Versions: make([]clv1alpha1.ImageVersionStatus, len(item.Versions))}
+		for j := range item.Versions {
+			images[i].Versions[j].Version = item.Versions[j]
+			versions[item.Name+":"+item.Versions[j]] = &images[i].Versions[j]
+		}
+	}
+
+	templates := map[string]*clv1alpha2.Template{}
+	for i := range sources.Templates {
+		template := &sources.Templates[i]
+		templates[template.Namespace+"/"+template.Name] = template
+		for _, key := range templateImageVersions(imageList, template) {
+			ref := clv1alpha1.GenericRef{Name: template.Name, Namespace: template.Namespace}
+			if !slices.Contains(versions[key].Templates, ref) {
+				versions[key].Templates = append(versions[key].Templates, ref)
+			}
+		}
+	}
+
+	for i := range sources.Instances {
+		instance := &sources.Instances[i]
+		template, ok := templates[instance.Spec.Template.Namespace+"/"+instance.Spec.Template.Name]
+		if !instance.Spec.Running || !ok {
+			continue
+		}
+		for _, key := range templateImageVersions(imageList, template) {
+			versions[key].RunningInstances++
+		}
+	}
+
+	for i := range images {
+		for j := range sources.InstanceSnapshots {
+			snapshot := &sources.InstanceSnapshots[j]
+			if images[i].Name == snapshot.Spec.ImageName || strings.HasSuffix(images[i].Name, "/"+snapshot.Spec.ImageName) {
+				images[i].InstanceSnapshots = append(images[i].InstanceSnapshots, clv1alpha1.GenericRef{Name: snapshot.Name, Namespace: snapshot.Namespace})
+			}
+		}
+	}
+
+	if policy != nil {
+		previous := previouslyDeprecated(imageList)
+		for i := range imageList.Spec.Images {
+			for v, reason := range deprecatedVersions(&imageList.Spec.Images[i], policy, patterns, now) {
+				key := imageList.Spec.Images[i].Name + ":" + v
+				versions[key].Deprecated = true
+				versions[key].DeprecationReason = reason
+				versions[key].DeprecatedSince = ptr.To(metav1.NewTime(now))
+				if since, ok := previous[key]; ok {
+					versions[key].DeprecatedSince = since
+				}
+			}
+		}
+	}
+
+	status.Images = images
+	return status, nil
+}
+
+// IsImageVersionUnused returns whether the given version is neither used by Templates and running Instances,
+// nor belongs to an image produced by InstanceSnapshots.
+func IsImageVersionUnused(image *clv1alpha1.ImageStatus, versionStatus *clv1alpha1.ImageVersionStatus) bool {
+	return len(versionStatus.Templates) == 0 && versionStatus.RunningInstances == 0 && len(image.InstanceSnapshots) == 0
+}
+
+// FindImageVersionStatus returns the status of the version of the ImageList the given reference refers to, if any.
+func FindImageVersionStatus(imageList *clv1alpha1.ImageList, ref ImageReference) *clv1alpha1.ImageVersionStatus {
+	image, imageVersion, found := MatchImageReference(imageList, ref)
+	if !found {
+		return nil
+	}
+
+	for i := range imageList.Status.Images {
+		if imageList.Status.Images[i].Name != image {
+			continue
+		}
+		for j := range imageList.Status.Images[i].Versions {
+			if imageList.Status.Images[i].Versions[j].Version == imageVersion {
+				return &imageList.Status.Images[i].Versions[j]
+			}
+		}
+	}
+	return nil
+}
+
+// templateImageVersions returns the keys (image:version) of the versions of the ImageList used by the given Template.
+func templateImageVersions(imageList *clv1alpha1.ImageList, template *clv1alpha2.Template) []string {
+	var keys []string
+	for _, ref := range templateImages(template) {
+		image, imageVersion, found := MatchImageReference(imageList, ParseImageReference(ref))
+		if found && !slices.Contains(keys, image+":"+imageVersion) {
+			keys = append(keys, image+":"+imageVersion)
+		}
+	}
+	return keys
+}
+
+// templateImages returns the references of the images used by the given Template, including the ones of the additional disks.
+func templateImages(template *clv1alpha2.Template) []string {
+	images := make([]string, 0, len(template.Spec.EnvironmentList))
+	for i := range template.Spec.EnvironmentList {
+		environment := &template.Spec.EnvironmentList[i]
+		images = append(images, environment.Image)
+		for j := range environment.AdditionalDisks {
+			if environment.AdditionalDisks[j].Image != "" {
+				images = append(images, environment.AdditionalDisks[j].Image)
+			}
+		}
+	}
+	return images
+}
+
+// compilePatterns compiles the regular expressions of the deprecation policy.
+func compilePatterns(policy *clv1alpha1.ImageDeprecationPolicy) ([]*regexp.Regexp, error) {
+	if policy == nil {
+		return nil, nil
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(policy.Patterns))
+	for _, pattern := range policy.Patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deprecation pattern %q: %w", pattern, err)
+		}
+		patterns = append(patterns, compiled)
+	}
+	return patterns, nil
+}
+
+// previouslyDeprecated returns the deprecation timestamps of the versions already deprecated in the ImageList status.
+func previouslyDeprecated(imageList *clv1alpha1.ImageList) map[string]*metav1.Time {
+	previous := map[string]*metav1.Time{}
+	for i := range imageList.Status.Images {
+		for j := range imageList.Status.Images[i].Versions {
+			versionStatus := &imageList.Status.Images[i].Versions[j]
+			if versionStatus.Deprecated && versionStatus.DeprecatedSince != nil {
+				previous[imageList.Status.Images[i].Name+":"+versionStatus.Version] = versionStatus.DeprecatedSince
+			}
+		}
+	}
+	return previous
+}
+
+// deprecatedVersions returns the versions of the given image deprecated according to the policy, with the corresponding reason.
+func deprecatedVersions(item *clv1alpha1.ImageListItem, policy *clv1alpha1.ImageDeprecationPolicy,
+	patterns []*regexp.Regexp, now time.Time) map[string]string {
+	created := map[string]time.Time{}
+	for i := range item.Details {
+		if item.Details[i].Created != nil {
+			created[item.Details[i].Version] = item.Details[i].Created.Time
+		}
+	}
+
+	// Sort the versions from the most recent one.
+	sorted := slices.Clone(item.Versions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ci, iok := created[sorted[i]]
+		cj, jok := created[sorted[j]]
+		if iok && jok && !ci.Equal(cj) {
+			return ci.After(cj)
+		}
+		return compareVersions(sorted[i], sorted[j]) > 0
+	})
+
+	deprecated := map[string]string{}
+	for i, v := range sorted {
+		for _, pattern := range patterns {
+			if pattern.MatchString(item.Name + ":" + v) {
+				deprecated[v] = fmt.Sprintf("matches the deprecation pattern %q", pattern.String())
+				break
+			}
+		}
+		if _, ok := deprecated[v]; ok || i < policy.KeepLatestVersions {
+			continue
+		}
+
+		if policy.DeprecateOlderVersions && policy.KeepLatestVersions > 0 {
+			deprecated[v] = fmt.Sprintf("superseded by the %d most recent versions", policy.KeepLatestVersions)
+		} else if c, ok := created[v]; ok && policy.MaxAge != nil && now.Sub(c) > policy.MaxAge.Duration {
+			deprecated[v] = fmt.Sprintf("older than %s", policy.MaxAge.Duration)
+		}
+	}
+	return deprecated
+}
+
+// compareVersions compares two versions, semantically if both are valid versions, and lexicographically otherwise.
+func compareVersions(a, b string) int {
+	va, erra := version.ParseGeneric(a)
+	vb, errb := version.ParseGeneric(b)
+	if erra != nil || errb != nil {
+		return strings.Compare(a, b)
+	}
+	if va.LessThan(vb) {
+		return -1
+	}
+	if vb.LessThan(va) {
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// updateUsage computes the usage and deprecation status of the ImageLists associated with the given registries,
+// deleting the unused deprecated versions from the registry according to the deprecation policy.
+func (u *BackgroundUpdater) updateUsage(ctx context.Context, config []RegistryConfig) error {
+	var sources ImageUsageSources
+	var templates clv1alpha2.TemplateList
+	var instances clv1alpha2.InstanceList
+	var snapshots clv1alpha2.InstanceSnapshotList
+	if err := u.k8sClient.List(ctx, &templates); err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+	if err := u.k8sClient.List(ctx, &instances); err != nil {
+		return fmt.Errorf("failed to list instances: %w", err)
+	}
+	if err := u.k8sClient.List(ctx, &snapshots); err != nil {
+		return fmt.Errorf("failed to list instance snapshots: %w", err)
+	}
+	sources.Templates, sources.Instances, sources.InstanceSnapshots = templates.Items, instances.Items, snapshots.Items
+
+	var errs []error
+	for i := range config {
+		if err := UpdateImageListUsage(ctx, u.k8sClient, &config[i], &sources, u.log); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// UpdateImageListUsage updates the usage and deprecation status of the ImageList associated with the given registry,
+// deleting the unused deprecated versions from the registry according to the deprecation policy.
+func UpdateImageListUsage(ctx context.Context, k8sClient client.Client, regConfig *RegistryConfig,
+	sources *ImageUsageSources, log logr.Logger) error {
+	log = log.WithValues("registry_name", regConfig.Name, "imagelist_name", regConfig.ImageListName)
+
+	var imageList clv1alpha1.ImageList
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: regConfig.ImageListName}, &imageList); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	status, err := ComputeImageListStatus(&imageList, regConfig.DeprecationPolicy, sources, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to compute the usage of ImageList %s: %w", imageList.Name, err)
+	}
+
+	imageList.Status = status
+	if err := k8sClient.Status().Update(ctx, &imageList); err != nil {
+		return fmt.Errorf("failed to update the status of ImageList %s: %w", imageList.Name, err)
+	}
+
+	if regConfig.DeprecationPolicy == nil || regConfig.DeprecationPolicy.DeleteUnusedAfter == nil {
+		return nil
+	}
+	if unresolved := UnresolvedImageReferences(&imageList, sources.Templates); len(unresolved) > 0 {
+		log.Info("skipping the deletion of unused versions, as some templates use unresolved image references", "images", unresolved)
+		return nil
+	}
+	return deleteUnusedVersions(ctx, k8sClient, regConfig, &imageList, log)
+}
+
+// deleteUnusedVersions deletes from the registry the versions deprecated for longer than the configured duration and no longer used.
+func deleteUnusedVersions(ctx context.Context, k8sClient client.Client, regConfig *RegistryConfig,
+	imageList *clv1alpha1.ImageList, log logr.Logger) error {
+	threshold := time.Now().Add(-regConfig.DeprecationPolicy.DeleteUnusedAfter.Duration)
+
+	var events []ImageEvent
+	for i := range imageList.Status.Images {
+		image := &imageList.Status.Images[i]
+		for j := range image.Versions {
+			versionStatus := &image.Versions[j]
+			if versionStatus.Deprecated && versionStatus.DeprecatedSince.Time.Before(threshold) && IsImageVersionUnused(image, versionStatus) {
+				events = append(events, ImageEvent{Action: ImageEventDelete, Image: image.Name, Version: versionStatus.Version})
+			}
+		}
+	}
+
+	// Deleting a manifest also deletes all the tags referring to it: skip the versions possibly sharing
+	// the manifest with other versions which are not going to be deleted as well.
+	candidates := events
+	events = nil
+	for i := range candidates {
+		if shared := sharedManifestVersions(imageList, &candidates[i], candidates); len(shared) > 0 {
+			log.Info("skipping the deletion of a version sharing the manifest with other versions",
+				"image", candidates[i].Image, "version", candidates[i].Version, "shared", shared)
+			continue
+		}
+		events = append(events, candidates[i])
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	requestor, err := initializeRequestor(ctx, regConfig, k8sClient, log)
+	if err != nil {
+		return err
+	}
+	deleter, ok := requestor.(VersionDeleter)
+	if !ok {
+		return fmt.Errorf("registry type %s does not support the deletion of versions", regConfig.Type)
+	}
+
+	deleted := events[:0]
+	var errs []error
+	for i := range events {
+		if err := deleter.DeleteVersion(ctx, events[i].Image, events[i].Version); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s:%s: %w", events[i].Image, events[i].Version, err))
+			continue
+		}
+		log.Info("deleted unused deprecated version from the registry", "image", events[i].Image, "version", events[i].Version)
+		deleted = append(deleted, events[i])
+	}
+
+	if len(deleted) > 0 {
+		saver, err := NewDefaultImageListSaver(ctx, regConfig.ImageListName, k8sClient, log.WithName("saver"))
+		if err != nil {
+			return fmt.Errorf("failed to initialize the image list saver: %w", err)
+		}
+		if err := saver.ApplyImageEvents(regConfig.RegistryName, regConfig.Project, deleted); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// sharedManifestVersions returns the versions of the image which (possibly) refer to the same manifest of the one to be deleted,
+// and are not part of the given deletion candidates. Versions whose digest is unknown are conservatively considered as shared.
+func sharedManifestVersions(imageList *clv1alpha1.ImageList, event *ImageEvent, candidates []ImageEvent) []string {
+	image, imageVersion := event.Image, event.Version
+	idx := slices.IndexFunc(imageList.Spec.Images, func(item clv1alpha1.ImageListItem) bool { return item.Name == image })
+	if idx < 0 {
+		return nil
+	}
+	item := &imageList.Spec.Images[idx]
+
+	digests := map[string]string{}
+	for i := range item.Details {
+		digests[item.Details[i].Version] = item.Details[i].Digest
+	}
+
+	var shared []string
+	for _, other := range item.Versions {
+		if other == imageVersion || slices.ContainsFunc(candidates, func(c ImageEvent) bool { return c.Image == image && c.Version == other }) {
+			continue
+		}
+		if digests[imageVersion] == "" || digests[other] == "" || digests[imageVersion] == digests[other] {
+			shared = append(shared, other)
+		}
+	}
+	return shared
+}
@@ -0,0 +1,311 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagelist_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	imagelist "github.com/netgroup-polito/CrownLabs/operators/pkg/imagelist"
+)
+
+var _ = Describe("ParseImageReference", func() {
+	DescribeTable("parses the image references",
+		func(ref string, expected imagelist.ImageReference) {
+			Expect(imagelist.ParseImageReference(ref)).To(Equal(expected))
+		},
+		Entry("with registry and tag", "registry.example.com/crownlabs/desktop:v1",
+			imagelist.ImageReference{Registry: "registry.example.com", Repository: "crownlabs/desktop", Tag: "v1"}),
+		Entry("with registry port and digest", "docker://localhost:5000/desktop@sha256:abc",
+			imagelist.ImageReference{Registry: "localhost:5000", Repository: "desktop", Digest: "sha256:abc"}),
+		Entry("without registry", "crownlabs/desktop:v1",
+			imagelist.ImageReference{Repository: "crownlabs/desktop", Tag: "v1"}),
+	)
+})
+
+var _ = Describe("Image usage and deprecation", func() {
+	const (
+		registryName = "registry.example.com"
+		namespace    = "workspace-test"
+	)
+
+	var (
+		imageList *clv1alpha1.ImageList
+		sources   imagelist.ImageUsageSources
+		now       time.Time
+	)
+
+	created := func(age time.Duration) *metav1.Time {
+		return ptr.To(metav1.NewTime(now.Add(-age)))
+	}
+
+	template := func(name string, images ...string) clv1alpha2.Template {
+		tmpl := clv1alpha2.Template{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		for _, image := range images {
+			tmpl.Spec.EnvironmentList = append(tmpl.Spec.EnvironmentList, clv1alpha2.Environment{Name: "env", Image: image})
+		}
+		return tmpl
+	}
+
+	versionStatus := func(status *clv1alpha1.ImageListStatus, image, version string) *clv1alpha1.ImageVersionStatus {
+		for i := range status.Images {
+			for j := range status.Images[i].Versions {
+				if status.Images[i].Name == image && status.Images[i].Versions[j].Version == version {
+					return &status.Images[i].Versions[j]
+				}
+			}
+		}
+		Fail("version " + image + ":" + version + " not found")
+		return nil
+	}
+
+	BeforeEach(func() {
+		now = time.Now()
+		imageList = &clv1alpha1.ImageList{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry"},
+			Spec: clv1alpha1.ImageListSpec{
+				RegistryName:    registryName,
+				ProjectBaseName: "crownlabs",
+				Images: []clv1alpha1.ImageListItem{{
+					Name:     "desktop",
+					Versions: []string{"v1", "v2", "v3", "v3-rc1"},
+					Details: []clv1alpha1.ImageVersionDetails{
+						{Version: "v1", Digest: "sha256:one", Created: created(400 * 24 * time.Hour)},
+						{Version: "v2", Digest: "sha256:two", Created: created(30 * 24 * time.Hour)},
+						{Version: "v3", Digest: "sha256:three", Created: created(24 * time.Hour)},
+						{Version: "v3-rc1", Digest: "sha256:rc", Created: created(48 * time.Hour)},
+					},
+				}, {
+					Name:     "snapshot",
+					Versions: []string{"latest"},
+				}},
+			},
+		}
+		sources = imagelist.ImageUsageSources{}
+	})
+
+	It("matches the image references by tag and by digest", func() {
+		image, version, found := imagelist.MatchImageReference(imageList, imagelist.ParseImageReference(registryName+"/crownlabs/desktop:v2"))
+		Expect(found).To(BeTrue())
+		Expect(image).To(Equal("desktop"))
+		Expect(version).To(Equal("v2"))
+
+		_, version, found = imagelist.MatchImageReference(imageList, imagelist.ParseImageReference(registryName+"/desktop@sha256:three"))
+		Expect(found).To(BeTrue())
+		Expect(version).To(Equal("v3"))
+
+		_, _, found = imagelist.MatchImageReference(imageList, imagelist.ParseImageReference("ghcr.io/crownlabs/desktop:v2"))
+		Expect(found).To(BeFalse())
+	})
+
+	It("resolves the image references without registry against the registry of the image list", func() {
+		image, version, found := imagelist.MatchImageReference(imageList, imagelist.ParseImageReference("crownlabs/desktop:v2"))
+		Expect(found).To(BeTrue())
+		Expect(image).To(Equal("desktop"))
+		Expect(version).To(Equal("v2"))
+
+		_, version, found = imagelist.MatchImageReference(imageList, imagelist.ParseImageReference("desktop:v3"))
+		Expect(found).To(BeTrue())
+		Expect(version).To(Equal("v3"))
+	})
+
+	It("reports the image references which cannot be resolved to any version", func() {
+		templates := []clv1alpha2.Template{
+			template("tmpl1", registryName+"/crownlabs/desktop:v2", "crownlabs/desktop:latest"),
+			template("tmpl2", "ghcr.io/crownlabs/desktop:v9", "ubuntu:22.04", registryName+"/crownlabs/desktop@sha256:unknown"),
+		}
+		Expect(imagelist.UnresolvedImageReferences(imageList, templates)).To(ConsistOf(
+			"crownlabs/desktop:latest", registryName+"/crownlabs/desktop@sha256:unknown"))
+	})
+
+	It("reports the templates, running instances and snapshots using the images", func() {
+		sources.Templates = []clv1alpha2.Template{
+			template("tmpl1", registryName+"/crownlabs/desktop:v2"),
+			template("tmpl2", registryName+"/crownlabs/desktop@sha256:two", registryName+"/crownlabs/desktop:v3"),
+		}
+		sources.Instances = []clv1alpha2.Instance{
+			{Spec: clv1alpha2.InstanceSpec{Running: true, Template: clv1alpha2.GenericRef{Name: "tmpl1", Namespace: namespace}}},
+			{Spec: clv1alpha2.InstanceSpec{Running: true, Template: clv1alpha2.GenericRef{Name: "tmpl2", Namespace: namespace}}},
+			{Spec: clv1alpha2.InstanceSpec{Running: false, Template: clv1alpha2.GenericRef{Name: "tmpl2", Namespace: namespace}}},
+		}
+		sources.InstanceSnapshots = []clv1alpha2.InstanceSnapshot{{
+			ObjectMeta: metav1.ObjectMeta{Name: "snap", Namespace: "tenant-pippo"},
+			Spec:       clv1alpha2.InstanceSnapshotSpec{ImageName: "snapshot"},
+		}}
+
+		status, err := imagelist.ComputeImageListStatus(imageList, nil, &sources, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.LastUsageUpdateTime).NotTo(BeNil())
+
+		v2 := versionStatus(&status, "desktop", "v2")
+		Expect(v2.Templates).To(ConsistOf(
+			clv1alpha1.GenericRef{Name: "tmpl1", Namespace: namespace},
+			clv1alpha1.GenericRef{Name: "tmpl2", Namespace: namespace},
+		))
+		Expect(v2.RunningInstances).To(Equal(2))
+		Expect(versionStatus(&status, "desktop", "v3").RunningInstances).To(Equal(1))
+		Expect(versionStatus(&status, "desktop", "v1").Templates).To(BeEmpty())
+		Expect(status.Images[1].InstanceSnapshots).To(ConsistOf(clv1alpha1.GenericRef{Name: "snap", Namespace: "tenant-pippo"}))
+		Expect(versionStatus(&status, "desktop", "v1").Deprecated).To(BeFalse())
+	})
+
+	It("reports the templates using the images through additional disks", func() {
+		tmpl := template("tmpl", registryName+"/crownlabs/desktop:v3")
+		tmpl.Spec.EnvironmentList[0].AdditionalDisks = []clv1alpha2.AdditionalDisk{
+			{Name: "data", Image: registryName + "/crownlabs/desktop:v1", Persistent: true},
+			{Name: "scratch"},
+		}
+		sources.Templates = []clv1alpha2.Template{tmpl}
+
+		status, err := imagelist.ComputeImageListStatus(imageList, nil, &sources, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versionStatus(&status, "desktop", "v1").Templates).To(ConsistOf(clv1alpha1.GenericRef{Name: "tmpl", Namespace: namespace}))
+		Expect(versionStatus(&status, "desktop", "v3").Templates).To(ConsistOf(clv1alpha1.GenericRef{Name: "tmpl", Namespace: namespace}))
+	})
+
+	It("deprecates the versions matching the patterns and exceeding the most recent ones", func() {
+		policy := &clv1alpha1.ImageDeprecationPolicy{KeepLatestVersions: 2, DeprecateOlderVersions: true, Patterns: []string{"-rc[0-9]*$"}}
+		status, err := imagelist.ComputeImageListStatus(imageList, policy, &sources, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(versionStatus(&status, "desktop", "v3-rc1").Deprecated).To(BeTrue())
+		Expect(versionStatus(&status, "desktop", "v3-rc1").DeprecationReason).To(ContainSubstring("pattern"))
+		Expect(versionStatus(&status, "desktop", "v3").Deprecated).To(BeFalse())
+		Expect(versionStatus(&status, "desktop", "v2").Deprecated).To(BeTrue())
+		Expect(versionStatus(&status, "desktop", "v1").Deprecated).To(BeTrue())
+		Expect(versionStatus(&status, "snapshot", "latest").Deprecated).To(BeFalse())
+	})
+
+	It("deprecates the versions older than the maximum age, preserving the deprecation timestamp", func() {
+		since := metav1.NewTime(now.Add(-72 * time.Hour).Truncate(time.Second))
+		imageList.Status.Images = []clv1alpha1.ImageStatus{{Name: "desktop", Versions: []clv1alpha1.ImageVersionStatus{
+			{Version: "v1", Deprecated: true, DeprecatedSince: &since},
+		}}}
+
+		policy := &clv1alpha1.ImageDeprecationPolicy{MaxAge: &metav1.Duration{Duration: 365 * 24 * time.Hour}}
+		status, err := imagelist.ComputeImageListStatus(imageList, policy, &sources, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(versionStatus(&status, "desktop", "v1").Deprecated).To(BeTrue())
+		Expect(versionStatus(&status, "desktop", "v1").DeprecatedSince).To(Equal(&since))
+		Expect(versionStatus(&status, "desktop", "v2").Deprecated).To(BeFalse())
+	})
+
+	It("fails if a deprecation pattern is invalid", func() {
+		_, err := imagelist.ComputeImageListStatus(imageList, &clv1alpha1.ImageDeprecationPolicy{Patterns: []string{"("}}, &sources, now)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("finds the status of the version referenced by an image", func() {
+		status, err := imagelist.ComputeImageListStatus(imageList, &clv1alpha1.ImageDeprecationPolicy{Patterns: []string{"v1$"}}, &sources, now)
+		Expect(err).NotTo(HaveOccurred())
+		imageList.Status = status
+
+		found := imagelist.FindImageVersionStatus(imageList, imagelist.ParseImageReference(registryName+"/crownlabs/desktop:v1"))
+		Expect(found).NotTo(BeNil())
+		Expect(found.Deprecated).To(BeTrue())
+		Expect(imagelist.FindImageVersionStatus(imageList, imagelist.ParseImageReference(registryName+"/crownlabs/desktop:v9"))).To(BeNil())
+	})
+
+	Context("UpdateImageListUsage", func() {
+		var (
+			ctx       context.Context
+			server    *httptest.Server
+			deleted   []string
+			k8sClient client.Client
+			regConfig imagelist.RegistryConfig
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			deleted = nil
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodHead:
+					w.Header().Set("Docker-Content-Digest", "sha256:"+r.URL.Path[len(r.URL.Path)-2:])
+					w.WriteHeader(http.StatusOK)
+				case http.MethodDelete:
+					deleted = append(deleted, r.URL.Path)
+					w.WriteHeader(http.StatusAccepted)
+				default:
+					w.WriteHeader(http.StatusMethodNotAllowed)
+				}
+			}))
+
+			since := metav1.NewTime(now.Add(-48 * time.Hour))
+			imageList.Status.Images = []clv1alpha1.ImageStatus{{Name: "desktop", Versions: []clv1alpha1.ImageVersionStatus{
+				{Version: "v1", Deprecated: true, DeprecatedSince: &since},
+				{Version: "v2", Deprecated: true, DeprecatedSince: &since},
+			}}}
+			k8sClient = newImageListTestClient(imageList)
+			regConfig = imagelist.RegistryConfig{
+				Name:          "registry",
+				Type:          "docker",
+				URL:           server.URL,
+				RegistryName:  registryName,
+				ImageListName: imageList.Name,
+				DeprecationPolicy: &clv1alpha1.ImageDeprecationPolicy{
+					Patterns:          []string{"desktop:v[12]$"},
+					DeleteUnusedAfter: &metav1.Duration{Duration: 24 * time.Hour},
+				},
+			}
+			sources.Templates = []clv1alpha2.Template{template("tmpl", registryName+"/crownlabs/desktop:v2")}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("deletes the unused versions deprecated for longer than the configured duration", func() {
+			Expect(imagelist.UpdateImageListUsage(ctx, k8sClient, &regConfig, &sources, logr.Discard())).To(Succeed())
+			Expect(deleted).To(Equal([]string{"/v2/desktop/manifests/sha256:v1"}))
+
+			var updated clv1alpha1.ImageList
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: imageList.Name}, &updated)).To(Succeed())
+			Expect(updated.Spec.Images[0].Versions).To(ConsistOf("v2", "v3", "v3-rc1"))
+			Expect(updated.Status.Images[0].Versions[1].Templates).To(HaveLen(1))
+		})
+
+		It("does not delete any version while some template images cannot be resolved", func() {
+			sources.Templates = append(sources.Templates, template("other", "crownlabs/desktop:latest"))
+			Expect(imagelist.UpdateImageListUsage(ctx, k8sClient, &regConfig, &sources, logr.Discard())).To(Succeed())
+			Expect(deleted).To(BeEmpty())
+		})
+
+		It("does not delete the versions sharing the manifest with versions still in use", func() {
+			imageList.Spec.Images[0].Details[0].Digest = "sha256:three"
+			k8sClient = newImageListTestClient(imageList)
+			Expect(imagelist.UpdateImageListUsage(ctx, k8sClient, &regConfig, &sources, logr.Discard())).To(Succeed())
+			Expect(deleted).To(BeEmpty())
+		})
+
+		It("does not delete any version if not configured", func() {
+			regConfig.DeprecationPolicy.DeleteUnusedAfter = nil
+			Expect(imagelist.UpdateImageListUsage(ctx, k8sClient, &regConfig, &sources, logr.Discard())).To(Succeed())
+			Expect(deleted).To(BeEmpty())
+		})
+	})
+})
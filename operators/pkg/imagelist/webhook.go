@@ -0,0 +1,371 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagelist
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ImageEventAction is the type of change notified by a registry.
+type ImageEventAction string
+
+const (
+	// ImageEventPush -> a version of an image has been pushed.
+	ImageEventPush ImageEventAction = "push"
+	// ImageEventDelete -> a version of an image has been deleted.
+	ImageEventDelete ImageEventAction = "delete"
+)
+
+const (
+	// WebhookPathPrefix -> the path prefix of the registry webhooks, followed by the name of the registry configuration.
+	WebhookPathPrefix = "/registries/"
+
+	// maxWebhookBodySize -> the maximum size of the webhook payloads accepted by the receiver.
+	maxWebhookBodySize = 1 << 20
+
+	harborEventPushArtifact   = "PUSH_ARTIFACT"
+	harborEventDeleteArtifact = "DELETE_ARTIFACT"
+)
+
+// ImageEvent describes a single change of an image version, as notified by a registry.
+type ImageEvent struct {
+	Action ImageEventAction
+	// Image is the name of the image, consistently with the one reported by the corresponding requestor.
+	Image string
+	// Version is the affected tag, possibly empty for deletions identified by digest only.
+	Version string
+	Digest  string
+	// Timestamp is the time the event has been generated by the registry, if known.
+	Timestamp time.Time
+}
+
+// distributionEnvelope is the payload of the Docker distribution notifications.
+type distributionEnvelope struct {
+	Events []struct {
+		Action    string    `json:"action"`
+		Timestamp time.Time `json:"timestamp"`
+		Target    struct {
+			Repository string `json:"repository"`
+			Tag        string `json:"tag"`
+			Digest     string `json:"digest"`
+		} `json:"target"`
+	} `json:"events"`
+}
+
+// harborPayload is the payload of the Harbor webhooks.
+type harborPayload struct {
+	Type      string `json:"type"`
+	OccurAt   int64  `json:"occur_at"`
+	EventData struct {
+		Resources []struct {
+			Tag    string `json:"tag"`
+			Digest string `json:"digest"`
+		} `json:"resources"`
+		Repository struct {
+			Namespace    string `json:"namespace"`
+			RepoFullName string `json:"repo_full_name"`
+		} `json:"repository"`
+	} `json:"event_data"`
+}
+
+// ParseDistributionEvents extracts the image events from a Docker distribution notification envelope.
+// Pushes not associated with a tag (e.g., blob uploads) and the other actions (e.g., pulls) are discarded.
+func ParseDistributionEvents(body []byte) ([]ImageEvent, error) {
+	var envelope distributionEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse the distribution notification: %w", err)
+	}
+
+	var events []ImageEvent
+	for i := range envelope.Events {
+		ev := &envelope.Events[i]
+		event := ImageEvent{
+			Image:     ev.Target.Repository,
+			Version:   ev.Target.Tag,
+			Digest:    ev.Target.Digest,
+			Timestamp: ev.Timestamp,
+		}
+
+		switch ImageEventAction(ev.Action) {
+		case ImageEventPush:
+			if event.Version == "" {
+				continue
+			}
+		case ImageEventDelete:
+			if event.Version == "" && event.Digest == "" {
+				continue
+			}
+		default:
+			continue
+		}
+
+		event.Action = ImageEventAction(ev.Action)
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// ParseHarborEvents extracts the image events from a Harbor webhook payload.
+// Events concerning projects other than the given one are discarded, as not part of the corresponding ImageList.
+func ParseHarborEvents(body []byte, project string) ([]ImageEvent, error) {
+	var payload harborPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse the Harbor webhook: %w", err)
+	}
+
+	var action ImageEventAction
+	switch payload.Type {
+	case harborEventPushArtifact:
+		action = ImageEventPush
+	case harborEventDeleteArtifact:
+		action = ImageEventDelete
+	default:
+		return nil, nil
+	}
+
+	repository := &payload.EventData.Repository
+	if project != "" && repository.Namespace != project {
+		return nil, nil
+	}
+
+	// The Harbor requestor names the images with the last segment of the repository only.
+	image := repository.RepoFullName[strings.LastIndex(repository.RepoFullName, "/")+1:]
+
+	var timestamp time.Time
+	if payload.OccurAt > 0 {
+		timestamp = time.Unix(payload.OccurAt, 0)
+	}
+
+	var events []ImageEvent
+	for _, resource := range payload.EventData.Resources {
+		if resource.Tag == "" && (action == ImageEventPush || resource.Digest == "") {
+			continue
+		}
+		events = append(events, ImageEvent{
+			Action:    action,
+			Image:     image,
+			Version:   resource.Tag,
+			Digest:    resource.Digest,
+			Timestamp: timestamp,
+		})
+	}
+	return events, nil
+}
+
+// WebhookReceiver handles the notifications sent by the registries, incrementally updating the corresponding ImageList.
+// The periodic update keeps acting as a reconciliation fallback, e.g., in case of lost notifications.
+type WebhookReceiver struct {
+	k8sClient      client.Client
+	log            logr.Logger
+	configFilePath string
+}
+
+// NewWebhookReceiver creates a new WebhookReceiver instance.
+func NewWebhookReceiver(k8sClient client.Client, log logr.Logger, configFilePath string) *WebhookReceiver {
+	return &WebhookReceiver{
+		k8sClient:      k8sClient,
+		log:            log,
+		configFilePath: configFilePath,
+	}
+}
+
+// ServeHTTP handles a single webhook, addressed to the registry configuration named by the last path segment.
+func (wr *WebhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, WebhookPathPrefix)
+	log := wr.log.WithValues("registry_name", name)
+
+	// The configuration is loaded every time, consistently with the periodic update.
+	config, err := LoadRegistries(r.Context(), wr.k8sClient, wr.configFilePath, log)
+	if err != nil {
+		log.Error(err, "failed to load registries configuration")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var regConfig *RegistryConfig
+	for i := range config {
+		if config[i].Name == name {
+			regConfig = &config[i]
+			break
+		}
+	}
+	if regConfig == nil {
+		log.Info("received webhook for unknown registry")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	secret, err := ResolveWebhookSecret(r.Context(), wr.k8sClient, regConfig)
+	if err != nil {
+		log.Error(err, "failed to resolve the webhook secret")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if !authorizeWebhook(r, secret) {
+		log.Info("received unauthorized webhook", "remote_addr", r.RemoteAddr)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var events []ImageEvent
+	switch regConfig.Type {
+	case "harbor":
+		events, err = ParseHarborEvents(body, regConfig.Project)
+	case "docker", "oci":
+		events, err = ParseDistributionEvents(body)
+	default:
+		err = fmt.Errorf("unsupported registry type: %s", regConfig.Type)
+	}
+	if err != nil {
+		log.Error(err, "failed to parse webhook")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := wr.apply(r.Context(), regConfig, events, log); err != nil {
+		// Let the registry retry the delivery; the periodic update eventually reconciles the ImageList anyhow.
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// apply applies the given events to the ImageList associated with the registry, recording the corresponding metrics.
+func (wr *WebhookReceiver) apply(ctx context.Context, regConfig *RegistryConfig, events []ImageEvent, log logr.Logger) error {
+	var relevant []ImageEvent
+	for i := range events {
+		if events[i].Version == "latest" {
+			metricWebhookEvents.WithLabelValues(regConfig.Name, string(events[i].Action), metricResultIgnored).Inc()
+			continue
+		}
+		relevant = append(relevant, events[i])
+	}
+	if len(relevant) == 0 {
+		return nil
+	}
+
+	saver, err := NewDefaultImageListSaver(ctx, regConfig.ImageListName, wr.k8sClient, log.WithName("saver"))
+	if err != nil {
+		return fmt.Errorf("failed to initialize the image list saver: %w", err)
+	}
+
+	result := metricResultSuccess
+	err = saver.ApplyImageEvents(regConfig.RegistryName, regConfig.Project, relevant)
+	if err != nil {
+		result = metricResultError
+	}
+
+	now := time.Now()
+	for i := range relevant {
+		metricWebhookEvents.WithLabelValues(regConfig.Name, string(relevant[i].Action), result).Inc()
+		if err == nil && !relevant[i].Timestamp.IsZero() {
+			metricWebhookEventLag.WithLabelValues(regConfig.Name).Observe(now.Sub(relevant[i].Timestamp).Seconds())
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	metricLastUpdate.WithLabelValues(regConfig.Name, metricSourceWebhook).SetToCurrentTime()
+	log.Info("applied webhook events", "imagelist_name", regConfig.ImageListName, "event_count", len(relevant))
+	return nil
+}
+
+// authorizeWebhook checks whether the Authorization header matches the configured secret, either as is or as bearer token.
+// Webhooks are rejected if no secret is configured, to prevent unauthenticated modifications of the ImageList.
+func authorizeWebhook(r *http.Request, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	header := r.Header.Get("Authorization")
+	token := strings.TrimPrefix(header, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(header), []byte(secret)) == 1 ||
+		subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+}
+
+// WebhookReceiverRunnable wraps the receiver of the registry webhooks, to be added as a runnable to the manager.
+type WebhookReceiverRunnable struct{}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, to receive the webhooks on every replica,
+// as they are spread by the Service across all of them. The incremental updates of the ImageList are retried on
+// conflict, hence they can be safely performed concurrently with the (leader-only) periodic update.
+func (WebhookReceiverRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements the Runnable interface, starting the receiver of the registry webhooks.
+func (WebhookReceiverRunnable) Start(ctx context.Context) error {
+	return StartWebhookReceiver(ctx)
+}
+
+// StartWebhookReceiver starts the HTTP server receiving the registry webhooks, until the context is canceled.
+// It is a no-op if the updater is not initialized or the receiver is not enabled.
+func StartWebhookReceiver(ctx context.Context) error {
+	if globalUpdater == nil || globalUpdater.options.WebhookAddress == "" {
+		return nil
+	}
+
+	log := globalUpdater.log.WithName("webhook-receiver")
+
+	mux := http.NewServeMux()
+	mux.Handle(WebhookPathPrefix, NewWebhookReceiver(globalUpdater.k8sClient, log, globalUpdater.options.ConfigFilePath))
+
+	srv := &http.Server{
+		Addr:              globalUpdater.options.WebhookAddress,
+		Handler:           mux,
+		ReadTimeout:       10 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Error(err, "error during webhook receiver shutdown")
+		}
+	}()
+
+	log.Info("imagelist webhook receiver listening", "address", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("imagelist webhook receiver failed: %w", err)
+	}
+	return nil
+}
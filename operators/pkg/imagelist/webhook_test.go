@@ -0,0 +1,260 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagelist_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	imagelist "github.com/netgroup-polito/CrownLabs/operators/pkg/imagelist"
+)
+
+const distributionNotification = `{"events": [
+	{"action": "push", "timestamp": "2026-05-06T13:33:48Z", "target": {"repository": "crownlabs/desktop", "tag": "v2", "digest": "sha256:2222"}},
+	{"action": "push", "timestamp": "2026-05-06T13:33:47Z", "target": {"repository": "crownlabs/desktop", "digest": "sha256:b10b"}},
+	{"action": "pull", "timestamp": "2026-05-06T13:33:49Z", "target": {"repository": "crownlabs/desktop", "tag": "v1"}},
+	{"action": "delete", "timestamp": "2026-05-06T13:33:50Z", "target": {"repository": "crownlabs/server", "digest": "sha256:1111"}}
+]}`
+
+const harborWebhook = `{
+	"type": "DELETE_ARTIFACT",
+	"occur_at": 1778074428,
+	"event_data": {
+		"resources": [{"digest": "sha256:1111", "tag": "v1"}],
+		"repository": {"name": "desktop", "namespace": "crownlabs-standalone", "repo_full_name": "crownlabs-standalone/desktop"}
+	}
+}`
+
+var _ = Describe("Registry events parsing", func() {
+	It("extracts the tag pushes and the deletions from the distribution notifications", func() {
+		events, err := imagelist.ParseDistributionEvents([]byte(distributionNotification))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(HaveLen(2))
+
+		Expect(events[0].Action).To(Equal(imagelist.ImageEventPush))
+		Expect(events[0].Image).To(Equal("crownlabs/desktop"))
+		Expect(events[0].Version).To(Equal("v2"))
+		Expect(events[0].Digest).To(Equal("sha256:2222"))
+		Expect(events[0].Timestamp.Equal(time.Date(2026, 5, 6, 13, 33, 48, 0, time.UTC))).To(BeTrue())
+
+		Expect(events[1].Action).To(Equal(imagelist.ImageEventDelete))
+		Expect(events[1].Image).To(Equal("crownlabs/server"))
+		Expect(events[1].Version).To(BeEmpty())
+		Expect(events[1].Digest).To(Equal("sha256:1111"))
+	})
+
+	It("extracts the events of the configured project from the Harbor webhooks", func() {
+		events, err := imagelist.ParseHarborEvents([]byte(harborWebhook), "crownlabs-standalone")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(ConsistOf(imagelist.ImageEvent{
+			Action:    imagelist.ImageEventDelete,
+			Image:     "desktop",
+			Version:   "v1",
+			Digest:    "sha256:1111",
+			Timestamp: time.Unix(1778074428, 0),
+		}))
+	})
+
+	It("discards the Harbor events of other projects", func() {
+		events, err := imagelist.ParseHarborEvents([]byte(harborWebhook), "crownlabs-containerdisks")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(BeEmpty())
+	})
+
+	It("fails with malformed payloads", func() {
+		_, err := imagelist.ParseDistributionEvents([]byte("{"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ApplyImageEventsToItems", func() {
+	var images []clv1alpha1.ImageListItem
+
+	BeforeEach(func() {
+		images = []clv1alpha1.ImageListItem{{
+			Name:     "desktop",
+			Versions: []string{"v1", "v2"},
+			Details: []clv1alpha1.ImageVersionDetails{
+				{Version: "v1", Digest: "sha256:1111", Size: 10},
+				{Version: "v2", Digest: "sha256:2222", Size: 20},
+			},
+		}, {
+			Name:     "server",
+			Versions: []string{"v1"},
+		}}
+	})
+
+	It("adds the pushed versions, refreshing the tracked details", func() {
+		res := imagelist.ApplyImageEventsToItems(images, []imagelist.ImageEvent{
+			{Action: imagelist.ImageEventPush, Image: "desktop", Version: "v2", Digest: "sha256:2b2b"},
+			{Action: imagelist.ImageEventPush, Image: "desktop", Version: "v3", Digest: "sha256:3333"},
+			{Action: imagelist.ImageEventPush, Image: "server", Version: "v2", Digest: "sha256:4444"},
+			{Action: imagelist.ImageEventPush, Image: "client", Version: "v1"},
+			{Action: imagelist.ImageEventPush, Image: "client", Version: "latest"},
+		})
+
+		Expect(res).To(HaveLen(3))
+		Expect(res[0].Versions).To(Equal([]string{"v1", "v2", "v3"}))
+		Expect(res[0].Details).To(Equal([]clv1alpha1.ImageVersionDetails{
+			{Version: "v1", Digest: "sha256:1111", Size: 10},
+			{Version: "v2", Digest: "sha256:2b2b"},
+			{Version: "v3", Digest: "sha256:3333"},
+		}))
+		Expect(res[1].Versions).To(Equal([]string{"v1", "v2"}))
+		Expect(res[1].Details).To(BeEmpty())
+		Expect(res[2]).To(Equal(clv1alpha1.ImageListItem{Name: "client", Versions: []string{"v1"}}))
+
+		// The original list is not modified.
+		Expect(images[0].Versions).To(HaveLen(2))
+	})
+
+	It("removes the deleted versions, by tag or by digest, and the images left empty", func() {
+		res := imagelist.ApplyImageEventsToItems(images, []imagelist.ImageEvent{
+			{Action: imagelist.ImageEventDelete, Image: "desktop", Digest: "sha256:1111"},
+			{Action: imagelist.ImageEventDelete, Image: "server", Version: "v1"},
+			{Action: imagelist.ImageEventDelete, Image: "missing", Version: "v1"},
+		})
+
+		Expect(res).To(Equal([]clv1alpha1.ImageListItem{{
+			Name:     "desktop",
+			Versions: []string{"v2"},
+			Details:  []clv1alpha1.ImageVersionDetails{{Version: "v2", Digest: "sha256:2222", Size: 20}},
+		}}))
+	})
+})
+
+var _ = Describe("WebhookReceiver", func() {
+	const secret = "s3cr3t"
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		receiver   *imagelist.WebhookReceiver
+		recorder   *httptest.ResponseRecorder
+		request    *http.Request
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(clv1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&clv1alpha1.ImageList{
+			ObjectMeta: metav1.ObjectMeta{Name: "harbor-standalone"},
+			Spec: clv1alpha1.ImageListSpec{
+				RegistryName:    "harbor.example.com",
+				ProjectBaseName: "crownlabs-standalone",
+				Images:          []clv1alpha1.ImageListItem{{Name: "desktop", Versions: []string{"v1", "v2"}}},
+			},
+		}).Build()
+
+		configFile := filepath.Join(GinkgoT().TempDir(), "registries.yaml")
+		Expect(os.WriteFile(configFile, []byte(`
+- name: harbor
+  type: harbor
+  url: http://harbor.example.com
+  registryName: harbor.example.com
+  imageListName: harbor-standalone
+  project: crownlabs-standalone
+  webhookSecret: `+secret+`
+- name: docker
+  type: docker
+  url: http://docker.example.com
+  registryName: docker.example.com
+  imageListName: docker
+`), 0o600)).To(Succeed())
+
+		receiver = imagelist.NewWebhookReceiver(fakeClient, logr.Discard(), configFile)
+		recorder = httptest.NewRecorder()
+		request = httptest.NewRequest(http.MethodPost, imagelist.WebhookPathPrefix+"harbor", strings.NewReader(harborWebhook))
+		request.Header.Set("Authorization", "Bearer "+secret)
+	})
+
+	JustBeforeEach(func() {
+		receiver.ServeHTTP(recorder, request)
+	})
+
+	imageList := func() *clv1alpha1.ImageList {
+		il := &clv1alpha1.ImageList{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Name: "harbor-standalone"}, il)).To(Succeed())
+		return il
+	}
+
+	It("incrementally updates the ImageList associated with the registry", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(imageList().Spec.Images).To(Equal([]clv1alpha1.ImageListItem{{Name: "desktop", Versions: []string{"v2"}}}))
+	})
+
+	When("the secret does not match", func() {
+		BeforeEach(func() { request.Header.Set("Authorization", "wrong") })
+
+		It("rejects the webhook", func() {
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(imageList().Spec.Images[0].Versions).To(HaveLen(2))
+		})
+	})
+
+	When("the registry does not configure a secret", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest(http.MethodPost, imagelist.WebhookPathPrefix+"docker", strings.NewReader(distributionNotification))
+		})
+
+		It("rejects the webhook", func() {
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	When("the registry is unknown", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest(http.MethodPost, imagelist.WebhookPathPrefix+"unknown", strings.NewReader(harborWebhook))
+		})
+
+		It("returns not found", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	When("the payload is malformed", func() {
+		BeforeEach(func() {
+			request = httptest.NewRequest(http.MethodPost, imagelist.WebhookPathPrefix+"harbor", strings.NewReader("{"))
+			request.Header.Set("Authorization", secret)
+		})
+
+		It("returns bad request", func() {
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
+
+var _ = Describe("WebhookReceiverRunnable", func() {
+	It("runs on every replica, regardless of the leader election", func() {
+		var runnable manager.Runnable = imagelist.WebhookReceiverRunnable{}
+		Expect(runnable.(manager.LeaderElectionRunnable).NeedLeaderElection()).To(BeFalse())
+	})
+})
@@ -0,0 +1,245 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instautoctrl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+)
+
+const (
+	icalDateFormat          = "20060102"
+	icalDateTimeFormat      = "20060102T150405"
+	icalDateTimeFormatUTC   = "20060102T150405Z"
+	holidayPeriodDateFormat = "2006-01-02"
+)
+
+// Holiday is a period of time during which the automated actions are suspended.
+// All-day holidays are stored as dates, and they are evaluated in the time zone of the automation policy.
+type Holiday struct {
+	Name   string
+	Start  time.Time
+	End    time.Time
+	AllDay bool
+}
+
+// HolidayCalendar is a set of holidays, loaded from an iCalendar file or from the Workspace automation policy.
+type HolidayCalendar struct {
+	Holidays []Holiday
+}
+
+// bounds returns the beginning and the end of the holiday, evaluated in the given location.
+func (h *Holiday) bounds(location *time.Location) (start, end time.Time) {
+	if !h.AllDay {
+		return h.Start, h.End
+	}
+	return time.Date(h.Start.Year(), h.Start.Month(), h.Start.Day(), 0, 0, 0, 0, location),
+		time.Date(h.End.Year(), h.End.Month(), h.End.Day(), 0, 0, 0, 0, location)
+}
+
+// Covering returns the holiday covering the given time (if any), evaluating all-day holidays in the given location.
+// If multiple holidays overlap, the one ending later is returned.
+func (c *HolidayCalendar) Covering(t time.Time, location *time.Location) (holiday *Holiday, end time.Time, found bool) {
+	if c == nil {
+		return nil, time.Time{}, false
+	}
+
+	for i := range c.Holidays {
+		start, stop := c.Holidays[i].bounds(location)
+		if !t.Before(start) && t.Before(stop) && stop.After(end) {
+			holiday, end, found = &c.Holidays[i], stop, true
+		}
+	}
+	return holiday, end, found
+}
+
+// Merge returns a new calendar containing the holidays of both calendars.
+func (c *HolidayCalendar) Merge(other *HolidayCalendar) *HolidayCalendar {
+	merged := &HolidayCalendar{}
+	if c != nil {
+		merged.Holidays = append(merged.Holidays, c.Holidays...)
+	}
+	if other != nil {
+		merged.Holidays = append(merged.Holidays, other.Holidays...)
+	}
+	return merged
+}
+
+// HolidayCalendarFromPeriods converts the holiday periods of a Workspace automation policy into a calendar.
+func HolidayCalendarFromPeriods(periods []clv1alpha1.HolidayPeriod) (*HolidayCalendar, error) {
+	calendar := &HolidayCalendar{}
+	for i := range periods {
+		period := &periods[i]
+		start, err := time.Parse(holidayPeriodDateFormat, period.Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date %q of holiday period %q: %w", period.Start, period.Name, err)
+		}
+
+		end := start
+		if period.End != "" {
+			if end, err = time.Parse(holidayPeriodDateFormat, period.End); err != nil {
+				return nil, fmt.Errorf("invalid end date %q of holiday period %q: %w", period.End, period.Name, err)
+			}
+		}
+		if end.Before(start) {
+			return nil, fmt.Errorf("holiday period %q ends before its beginning", period.Name)
+		}
+
+		// The end date of the period is included, while the one of the holiday is excluded.
+		calendar.Holidays = append(calendar.Holidays, Holiday{Name: period.Name, Start: start, End: end.AddDate(0, 0, 1), AllDay: true})
+	}
+	return calendar, nil
+}
+
+// LoadHolidayCalendar loads the holidays from the iCalendar file at the given path.
+func LoadHolidayCalendar(path string) (*HolidayCalendar, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open holiday calendar %s: %w", path, err)
+	}
+	defer file.Close()
+
+	calendar, err := ParseHolidayCalendar(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse holiday calendar %s: %w", path, err)
+	}
+	return calendar, nil
+}
+
+// ParseHolidayCalendar parses the events of an iCalendar (RFC 5545) stream as holidays.
+// Only the DTSTART, DTEND and SUMMARY properties are considered, while recurrence rules are not supported.
+func ParseHolidayCalendar(reader io.Reader) (*HolidayCalendar, error) {
+	lines, err := unfoldICalLines(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &HolidayCalendar{}
+	var current *Holiday
+	var hasEnd bool
+	for _, line := range lines {
+		name, params, value, ok := splitICalLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current, hasEnd = &Holiday{}, false
+		case current == nil:
+			continue
+		case name == "END" && value == "VEVENT":
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("event %q without start date", current.Name)
+			}
+			if !hasEnd {
+				// Events without an end date last one day if all-day events, and are instantaneous otherwise.
+				current.End = current.Start
+				if current.AllDay {
+					current.End = current.Start.AddDate(0, 0, 1)
+				}
+			}
+			if current.End.After(current.Start) {
+				calendar.Holidays = append(calendar.Holidays, *current)
+			}
+			current = nil
+		case name == "SUMMARY":
+			current.Name = unescapeICalText(value)
+		case name == "DTSTART":
+			if current.Start, current.AllDay, err = parseICalTime(params, value); err != nil {
+				return nil, fmt.Errorf("invalid start date of event %q: %w", current.Name, err)
+			}
+		case name == "DTEND":
+			if current.End, _, err = parseICalTime(params, value); err != nil {
+				return nil, fmt.Errorf("invalid end date of event %q: %w", current.Name, err)
+			}
+			hasEnd = true
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("unterminated event %q", current.Name)
+	}
+	return calendar, nil
+}
+
+// unfoldICalLines splits the iCalendar stream in lines, joining the ones folded across multiple lines.
+func unfoldICalLines(reader io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// splitICalLine splits a content line in its name, parameters and value.
+func splitICalLine(line string) (name string, params map[string]string, value string, ok bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(head, ";")
+	params = make(map[string]string, len(parts)-1)
+	for _, param := range parts[1:] {
+		if key, val, found := strings.Cut(param, "="); found {
+			params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, strings.TrimSpace(value), true
+}
+
+// parseICalTime parses a DATE or DATE-TIME value, returning whether it refers to a whole day.
+func parseICalTime(params map[string]string, value string) (t time.Time, allDay bool, err error) {
+	if params["VALUE"] == "DATE" || len(value) == len(icalDateFormat) {
+		t, err = time.Parse(icalDateFormat, value)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(icalDateTimeFormatUTC, value)
+		return t, false, err
+	}
+
+	location := time.UTC
+	if tzid, ok := params["TZID"]; ok {
+		if location, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown time zone %q: %w", tzid, err)
+		}
+	}
+	t, err = time.ParseInLocation(icalDateTimeFormat, value, location)
+	return t, false, err
+}
+
+// unescapeICalText reverts the escaping of the iCalendar TEXT values.
+func unescapeICalText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
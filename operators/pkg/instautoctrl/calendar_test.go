@@ -0,0 +1,125 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instautoctrl_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/instautoctrl"
+)
+
+var _ = Describe("Holiday calendar", func() {
+	const ical = "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Christmas\r\n" +
+		"  break\r\n" +
+		"DTSTART;VALUE=DATE:20261224\r\n" +
+		"DTEND;VALUE=DATE:20270107\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Maintenance\r\n" +
+		"DTSTART:20261031T080000Z\r\n" +
+		"DTEND:20261031T120000Z\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Liberation Day\r\n" +
+		"DTSTART;VALUE=DATE:20270425\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	var rome *time.Location
+
+	BeforeEach(func() {
+		var err error
+		rome, err = time.LoadLocation("Europe/Rome")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("The ParseHolidayCalendar function", func() {
+		It("Should parse all-day and timed events", func() {
+			calendar, err := instautoctrl.ParseHolidayCalendar(strings.NewReader(ical))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(calendar.Holidays).To(HaveLen(3))
+
+			Expect(calendar.Holidays[0].Name).To(Equal("Christmas break"))
+			Expect(calendar.Holidays[0].AllDay).To(BeTrue())
+			Expect(calendar.Holidays[1].AllDay).To(BeFalse())
+			Expect(calendar.Holidays[1].End.Sub(calendar.Holidays[1].Start)).To(Equal(4 * time.Hour))
+			// All-day events without an end date last one day.
+			Expect(calendar.Holidays[2].End.Sub(calendar.Holidays[2].Start)).To(Equal(24 * time.Hour))
+		})
+
+		It("Should fail in case of unterminated events", func() {
+			_, err := instautoctrl.ParseHolidayCalendar(strings.NewReader("BEGIN:VEVENT\nDTSTART:20261224\n"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("The Covering function", func() {
+		var calendar *instautoctrl.HolidayCalendar
+
+		BeforeEach(func() {
+			var err error
+			calendar, err = instautoctrl.ParseHolidayCalendar(strings.NewReader(ical))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should evaluate all-day holidays in the given location", func() {
+			holiday, end, found := calendar.Covering(time.Date(2026, 12, 23, 23, 30, 0, 0, time.UTC), rome)
+			Expect(found).To(BeTrue())
+			Expect(holiday.Name).To(Equal("Christmas break"))
+			Expect(end).To(BeTemporally("==", time.Date(2027, 1, 7, 0, 0, 0, 0, rome)))
+
+			_, _, found = calendar.Covering(time.Date(2026, 12, 23, 23, 30, 0, 0, time.UTC), time.UTC)
+			Expect(found).To(BeFalse())
+		})
+
+		It("Should consider timed holidays", func() {
+			_, end, found := calendar.Covering(time.Date(2026, 10, 31, 9, 0, 0, 0, time.UTC), rome)
+			Expect(found).To(BeTrue())
+			Expect(end).To(BeTemporally("==", time.Date(2026, 10, 31, 12, 0, 0, 0, time.UTC)))
+		})
+
+		It("Should handle nil calendars", func() {
+			var empty *instautoctrl.HolidayCalendar
+			_, _, found := empty.Covering(time.Now(), time.UTC)
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("The HolidayCalendarFromPeriods function", func() {
+		It("Should include the end date of the periods", func() {
+			calendar, err := instautoctrl.HolidayCalendarFromPeriods([]clv1alpha1.HolidayPeriod{
+				{Name: "Exams", Start: "2027-01-20", End: "2027-01-22"},
+				{Start: "2027-02-01"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(calendar.Holidays).To(HaveLen(2))
+			Expect(calendar.Holidays[0].End).To(Equal(time.Date(2027, 1, 23, 0, 0, 0, 0, time.UTC)))
+			Expect(calendar.Holidays[1].End).To(Equal(time.Date(2027, 2, 2, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("Should fail if a period ends before its beginning", func() {
+			_, err := instautoctrl.HolidayCalendarFromPeriods([]clv1alpha1.HolidayPeriod{{Start: "2027-01-20", End: "2027-01-10"}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -18,6 +18,7 @@ package instautoctrl
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"regexp"
 	"strconv"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
 	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
 	clctx "github.com/netgroup-polito/CrownLabs/operators/pkg/clcontext"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
@@ -93,41 +95,48 @@ func ParseDurationWithDays(ctx context.Context, input string) (time.Duration, er
 }
 
 // SendInactivityDetectionNotification sends notification about instance inactivity detection.
-func SendInactivityDetectionNotification(ctx context.Context, mc *mail.Client, remainingTime time.Duration) error {
-	return sendNotification(ctx, mc, InactivityDetectedMailTemplatePath, remainingTime)
+func SendInactivityDetectionNotification(ctx context.Context, mc *mail.Client, ob *NotificationOutbox, remainingTime time.Duration) error {
+	return sendNotification(ctx, mc, ob, InactivityDetectedMailTemplatePath, remainingTime)
 }
 
 // SendInactivityTerminationNotification sends notification about instance inactivity termination.
-func SendInactivityTerminationNotification(ctx context.Context, mc *mail.Client, remainingTime time.Duration) error {
-	return sendNotification(ctx, mc, InactivityTerminatedMailTemplatePath, remainingTime)
+func SendInactivityTerminationNotification(ctx context.Context, mc *mail.Client, ob *NotificationOutbox, remainingTime time.Duration) error {
+	return sendNotification(ctx, mc, ob, InactivityTerminatedMailTemplatePath, remainingTime)
 }
 
 // SendExpiringWarningNotification sends expiration warning notification.
-func SendExpiringWarningNotification(ctx context.Context, mc *mail.Client, remainingTime time.Duration) error {
-	return sendNotification(ctx, mc, WarningExpirationMailTemplatePath, remainingTime)
+func SendExpiringWarningNotification(ctx context.Context, mc *mail.Client, ob *NotificationOutbox, remainingTime time.Duration) error {
+	return sendNotification(ctx, mc, ob, WarningExpirationMailTemplatePath, remainingTime)
 }
 
 // SendDestructionWarningNotification sends a destruction warning notification when a powered-off instance is about to be destroyed.
-func SendDestructionWarningNotification(ctx context.Context, mc *mail.Client, remainingTime time.Duration) error {
-	return sendNotification(ctx, mc, WarningDestructionMailTemplatePath, remainingTime)
+func SendDestructionWarningNotification(ctx context.Context, mc *mail.Client, ob *NotificationOutbox, remainingTime time.Duration) error {
+	return sendNotification(ctx, mc, ob, WarningDestructionMailTemplatePath, remainingTime)
 }
 
 // SendDestructionNotification sends a deletion notification when an instance is deleted.
-func SendDestructionNotification(ctx context.Context, mc *mail.Client) error {
-	return sendNotification(ctx, mc, DestructionMailTemplatePath, 0)
+func SendDestructionNotification(ctx context.Context, mc *mail.Client, ob *NotificationOutbox) error {
+	return sendNotification(ctx, mc, ob, DestructionMailTemplatePath, 0)
 }
 
 // SendExpiringNotification sends expiration warning notification.
-func SendExpiringNotification(ctx context.Context, mc *mail.Client) error {
-	return sendNotification(ctx, mc, ExpirationMailTemplatePath, 0)
+func SendExpiringNotification(ctx context.Context, mc *mail.Client, ob *NotificationOutbox) error {
+	return sendNotification(ctx, mc, ob, ExpirationMailTemplatePath, 0)
 }
 
-func sendNotification(ctx context.Context, mc *mail.Client, mailTemplatePath string, remainingTime time.Duration) error {
-	log := ctrl.LoggerFrom(ctx).WithName("notification-email-instance")
-
-	if mc == nil {
-		return fmt.Errorf("mail client is not configured")
+// notificationOutcome returns the message logging the outcome of the given kind of notification, which is either
+// sent to the user or enqueued in the outbox, to be delivered with the next digest.
+func notificationOutcome(kind string, ob *NotificationOutbox) string {
+	if ob != nil {
+		return kind + " enqueued"
 	}
+	return kind + " email sent to user"
+}
+
+// sendNotification sends the given notification to the tenant, or enqueues it in the outbox
+// (to be later delivered as part of a digest) if configured.
+func sendNotification(ctx context.Context, mc *mail.Client, ob *NotificationOutbox, mailTemplatePath string, remainingTime time.Duration) error {
+	log := ctrl.LoggerFrom(ctx).WithName("notification-email-instance")
 
 	instance := clctx.InstanceFrom(ctx)
 	if instance == nil {
@@ -137,14 +146,30 @@ func sendNotification(ctx context.Context, mc *mail.Client, mailTemplatePath str
 	if tenant == nil {
 		return fmt.Errorf("tenant not found in context")
 	}
+
+	// Reflect the deferral of the automated action (if any) due to quiet hours and holidays.
+	var deferralNote string
+	if policy := PolicyFrom(ctx); policy != nil && remainingTime > 0 {
+		remainingTime, deferralNote = policy.DeferAction(time.Now(), remainingTime)
+	}
+
+	if ob != nil {
+		return ob.Enqueue(ctx, tenant, instance, mailTemplatePath, remainingTime, deferralNote)
+	}
+
+	if mc == nil {
+		return fmt.Errorf("mail client is not configured")
+	}
 	log.Info("sending email notification to user", "instance", instance.Name, "email", tenant.Spec.Email)
 
 	ph := mail.Placeholders{
-		TenantName:    tenant.Name,
-		TenantEmail:   tenant.Spec.Email,
-		PrettyName:    instance.Spec.PrettyName,
-		InstanceName:  instance.Name,
-		RemainingTime: remainingTime.String(),
+		TenantName:       tenant.Name,
+		TenantEmail:      tenant.Spec.Email,
+		PrettyName:       instance.Spec.PrettyName,
+		InstanceName:     instance.Name,
+		RemainingTime:    remainingTime.String(),
+		DeferralNote:     deferralNote,
+		DeferralNoteHTML: mail.HTMLParagraph(deferralNote),
 	}
 	err := mc.SendCrownLabsMail(ctx, mailTemplatePath, &ph)
 	if err != nil {
@@ -287,6 +312,28 @@ var stopAfterInactivityChanged = predicate.Funcs{
 	},
 }
 
+var automationPolicyChanged = predicate.Funcs{
+	CreateFunc: func(_ event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldWorkspace, oldOk := e.ObjectOld.(*clv1alpha1.Workspace)
+		newWorkspace, newOk := e.ObjectNew.(*clv1alpha1.Workspace)
+		if !oldOk || !newOk {
+			return false
+		}
+
+		// Requeue only if the automation policy of the workspace has changed
+		return !reflect.DeepEqual(oldWorkspace.Spec.AutomationPolicy, newWorkspace.Spec.AutomationPolicy)
+	},
+	DeleteFunc: func(_ event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(_ event.GenericEvent) bool {
+		return false
+	},
+}
+
 var inactivityIgnoreNamespace = predicate.Funcs{
 	UpdateFunc: func(e event.UpdateEvent) bool {
 		oldNs, oldOk := e.ObjectOld.(*corev1.Namespace)
@@ -0,0 +1,240 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instautoctrl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
+)
+
+// AutomationAction identifies an action performed by the automation controllers.
+type AutomationAction string
+
+const (
+	// ActionStop -> the Instance is stopped.
+	ActionStop AutomationAction = "stop"
+	// ActionDelete -> the Instance is deleted.
+	ActionDelete AutomationAction = "delete"
+	// ActionSubmit -> the content of the Instance is submitted.
+	ActionSubmit AutomationAction = "submit"
+
+	// InactiveTerminationControllerName is the name of the Instance Inactive Termination controller.
+	InactiveTerminationControllerName = "instance-inactive-termination"
+	// ExpirationControllerName is the name of the Instance Expiration controller.
+	ExpirationControllerName = "instance-expiration-termination"
+	// TerminationControllerName is the name of the Instance Termination controller.
+	TerminationControllerName = "instance-termination"
+	// SubmissionControllerName is the name of the Instance Submission controller.
+	SubmissionControllerName = "instance-submission"
+
+	// DryRunReportPath is the path of the endpoint listing the actions recorded in dry-run mode.
+	DryRunReportPath = "/automation/dry-run"
+
+	metricDryRunLabelController = "controller"
+	metricDryRunLabelAction     = "action"
+)
+
+var (
+	metricDryRunActions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "instance_automation_dry_run_actions_total",
+		Help: "The number of actions the automation controllers would have performed if not in dry-run mode",
+	},
+		[]string{metricDryRunLabelController, metricDryRunLabelAction},
+	)
+	metricDryRunPendingActions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "instance_automation_dry_run_pending_actions",
+		Help: "The number of actions currently pending in dry-run mode",
+	},
+		[]string{metricDryRunLabelController, metricDryRunLabelAction},
+	)
+)
+
+func init() {
+	// Register custom metrics with the global prometheus registry
+	metrics.Registry.MustRegister(metricDryRunActions, metricDryRunPendingActions)
+}
+
+// PendingAction is an action the automation controllers would have performed if not in dry-run mode.
+type PendingAction struct {
+	Controller  string           `json:"controller"`
+	Action      AutomationAction `json:"action"`
+	Namespace   string           `json:"namespace"`
+	Instance    string           `json:"instance"`
+	Template    string           `json:"template,omitempty"`
+	Reason      string           `json:"reason"`
+	ScheduledAt time.Time        `json:"scheduledAt"`
+	RecordedAt  time.Time        `json:"recordedAt"`
+}
+
+// DryRunAuditor determines whether the automation controllers operate in dry-run mode, and keeps track
+// of the actions they would have performed, recording them as Events and Prometheus metrics.
+// A nil DryRunAuditor is valid, and corresponds to dry-run mode always disabled.
+type DryRunAuditor struct {
+	// Global enables the dry-run mode for all the Instances, independently of the labels.
+	Global bool
+
+	mu      sync.RWMutex
+	pending map[string]PendingAction
+}
+
+// pendingActionKey returns the key identifying the actions of a given controller on a given instance.
+func pendingActionKey(controller string, instance types.NamespacedName) string {
+	return controller + "/" + instance.String()
+}
+
+// Enabled returns whether the dry-run mode is enabled for the given instance, either globally, or through
+// the corresponding label set on its Template or Namespace. The template is retrieved if not provided.
+func (a *DryRunAuditor) Enabled(ctx context.Context, c client.Client, instance *clv1alpha2.Instance, template *clv1alpha2.Template) (bool, error) {
+	if a == nil {
+		return false, nil
+	}
+	if a.Global {
+		return true, nil
+	}
+
+	dryRunValue := strconv.FormatBool(true)
+	if template == nil {
+		template = &clv1alpha2.Template{}
+		if err := c.Get(ctx, forge.NamespacedNameFromGenericRef(instance.Spec.Template), template); err != nil && !kerrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed retrieving instance template: %w", err)
+		}
+	}
+	if utils.CheckSingleLabel(template, forge.AutomationDryRunLabel, dryRunValue) {
+		return true, nil
+	}
+
+	var namespace corev1.Namespace
+	if err := c.Get(ctx, types.NamespacedName{Name: instance.Namespace}, &namespace); err != nil {
+		return false, fmt.Errorf("failed retrieving namespace %q: %w", instance.Namespace, err)
+	}
+	return utils.CheckSingleLabel(&namespace, forge.AutomationDryRunLabel, dryRunValue), nil
+}
+
+// Record keeps track of an action which would have been performed at the given time, emitting the corresponding Event.
+func (a *DryRunAuditor) Record(ctx context.Context, recorder record.EventRecorder, controller string,
+	instance *clv1alpha2.Instance, action AutomationAction, scheduledAt time.Time, reason string) {
+	if a == nil {
+		return
+	}
+
+	entry := PendingAction{
+		Controller:  controller,
+		Action:      action,
+		Namespace:   instance.Namespace,
+		Instance:    instance.Name,
+		Template:    instance.Spec.Template.Name,
+		Reason:      reason,
+		ScheduledAt: scheduledAt.UTC().Truncate(time.Second),
+		RecordedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+
+	a.mu.Lock()
+	if a.pending == nil {
+		a.pending = make(map[string]PendingAction)
+	}
+	key := pendingActionKey(controller, forge.NamespacedNameFromObject(instance))
+	if previous, ok := a.pending[key]; ok {
+		metricDryRunPendingActions.WithLabelValues(controller, string(previous.Action)).Dec()
+	}
+	a.pending[key] = entry
+	metricDryRunPendingActions.WithLabelValues(controller, string(action)).Inc()
+	a.mu.Unlock()
+
+	metricDryRunActions.WithLabelValues(controller, string(action)).Inc()
+
+	if recorder != nil {
+		recorder.Eventf(instance, corev1.EventTypeNormal, "DryRun",
+			"Dry-run: action %q would be performed at %s (%s)", action, entry.ScheduledAt.Format(time.RFC3339), reason)
+	}
+	ctrl.LoggerFrom(ctx).Info("dry-run: action not performed", "action", action, "scheduledAt", entry.ScheduledAt, "reason", reason)
+}
+
+// Forget removes the action of the given controller on the given instance (if any), as no longer pending.
+func (a *DryRunAuditor) Forget(controller string, instance types.NamespacedName) {
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	key := pendingActionKey(controller, instance)
+	if previous, ok := a.pending[key]; ok {
+		metricDryRunPendingActions.WithLabelValues(controller, string(previous.Action)).Dec()
+		delete(a.pending, key)
+	}
+}
+
+// PendingActions returns the actions currently pending, sorted by scheduled time.
+func (a *DryRunAuditor) PendingActions() []PendingAction {
+	if a == nil {
+		return nil
+	}
+
+	a.mu.RLock()
+	actions := make([]PendingAction, 0, len(a.pending))
+	for key := range a.pending {
+		actions = append(actions, a.pending[key])
+	}
+	a.mu.RUnlock()
+
+	sort.Slice(actions, func(i, j int) bool {
+		if !actions[i].ScheduledAt.Equal(actions[j].ScheduledAt) {
+			return actions[i].ScheduledAt.Before(actions[j].ScheduledAt)
+		}
+		return pendingActionKey(actions[i].Controller, types.NamespacedName{Namespace: actions[i].Namespace, Name: actions[i].Instance}) <
+			pendingActionKey(actions[j].Controller, types.NamespacedName{Namespace: actions[j].Namespace, Name: actions[j].Instance})
+	})
+	return actions
+}
+
+// ServeHTTP serves the report of the pending actions in JSON format, optionally filtered by namespace.
+func (a *DryRunAuditor) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	namespace := req.URL.Query().Get("namespace")
+	actions := []PendingAction{}
+	for _, action := range a.PendingActions() {
+		if namespace == "" || action.Namespace == namespace {
+			actions = append(actions, action)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(actions); err != nil {
+		ctrl.Log.WithName("dry-run-report").Error(err, "failed encoding the dry-run report")
+	}
+}
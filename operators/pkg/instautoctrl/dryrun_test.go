@@ -0,0 +1,149 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instautoctrl_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/instautoctrl"
+)
+
+var _ = Describe("Dry-run auditor", func() {
+	const (
+		namespaceName = "workspace-test"
+		templateName  = "template"
+	)
+
+	var (
+		ctx       context.Context
+		namespace *corev1.Namespace
+		template  *clv1alpha2.Template
+		instance  *clv1alpha2.Instance
+		auditor   *instautoctrl.DryRunAuditor
+		recorder  *record.FakeRecorder
+	)
+
+	newClient := func() client.Client {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(clv1alpha2.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespace, template).Build()
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespaceName}}
+		template = &clv1alpha2.Template{ObjectMeta: metav1.ObjectMeta{Name: templateName, Namespace: namespaceName}}
+		instance = &clv1alpha2.Instance{
+			ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: namespaceName},
+			Spec: clv1alpha2.InstanceSpec{
+				Template: clv1alpha2.GenericRef{Name: templateName, Namespace: namespaceName},
+			},
+		}
+		auditor = &instautoctrl.DryRunAuditor{}
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	Describe("Checking whether the dry-run mode is enabled", func() {
+		It("Should be disabled for a nil auditor", func() {
+			var nilAuditor *instautoctrl.DryRunAuditor
+			Expect(nilAuditor.Enabled(ctx, newClient(), instance, nil)).To(BeFalse())
+		})
+
+		It("Should be disabled by default", func() {
+			Expect(auditor.Enabled(ctx, newClient(), instance, nil)).To(BeFalse())
+		})
+
+		It("Should be enabled globally", func() {
+			auditor.Global = true
+			Expect(auditor.Enabled(ctx, newClient(), instance, nil)).To(BeTrue())
+		})
+
+		It("Should be enabled through the template label", func() {
+			template.Labels = map[string]string{forge.AutomationDryRunLabel: "true"}
+			Expect(auditor.Enabled(ctx, newClient(), instance, nil)).To(BeTrue())
+		})
+
+		It("Should be enabled through the namespace label", func() {
+			namespace.Labels = map[string]string{forge.AutomationDryRunLabel: "true"}
+			Expect(auditor.Enabled(ctx, newClient(), instance, template)).To(BeTrue())
+		})
+	})
+
+	Describe("Recording the pending actions", func() {
+		var scheduledAt time.Time
+
+		BeforeEach(func() {
+			scheduledAt = time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+			auditor.Record(ctx, recorder, instautoctrl.ExpirationControllerName, instance, instautoctrl.ActionDelete, scheduledAt, "maximum lifetime reached")
+		})
+
+		It("Should emit an event", func() {
+			Expect(recorder.Events).To(Receive(ContainSubstring("DryRun")))
+		})
+
+		It("Should list the pending action", func() {
+			actions := auditor.PendingActions()
+			Expect(actions).To(HaveLen(1))
+			Expect(actions[0].Controller).To(Equal(instautoctrl.ExpirationControllerName))
+			Expect(actions[0].Action).To(Equal(instautoctrl.ActionDelete))
+			Expect(actions[0].Template).To(Equal(templateName))
+			Expect(actions[0].ScheduledAt).To(Equal(scheduledAt))
+		})
+
+		It("Should replace the action previously recorded by the same controller", func() {
+			auditor.Record(ctx, recorder, instautoctrl.ExpirationControllerName, instance, instautoctrl.ActionStop, scheduledAt, "other")
+			Expect(auditor.PendingActions()).To(HaveLen(1))
+			Expect(auditor.PendingActions()[0].Action).To(Equal(instautoctrl.ActionStop))
+		})
+
+		It("Should forget the action no longer pending", func() {
+			auditor.Forget(instautoctrl.ExpirationControllerName, types.NamespacedName{Namespace: namespaceName, Name: "instance"})
+			Expect(auditor.PendingActions()).To(BeEmpty())
+		})
+
+		It("Should serve the report, filtered by namespace", func() {
+			var actions []instautoctrl.PendingAction
+
+			response := httptest.NewRecorder()
+			auditor.ServeHTTP(response, httptest.NewRequest(http.MethodGet, instautoctrl.DryRunReportPath, http.NoBody))
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(json.Unmarshal(response.Body.Bytes(), &actions)).To(Succeed())
+			Expect(actions).To(HaveLen(1))
+
+			response = httptest.NewRecorder()
+			auditor.ServeHTTP(response, httptest.NewRequest(http.MethodGet, instautoctrl.DryRunReportPath+"?namespace=other", http.NoBody))
+			Expect(json.Unmarshal(response.Body.Bytes(), &actions)).To(Succeed())
+			Expect(actions).To(BeEmpty())
+		})
+	})
+})
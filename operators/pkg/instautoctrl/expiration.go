@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
 	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
 	clctx "github.com/netgroup-polito/CrownLabs/operators/pkg/clcontext"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
@@ -48,6 +49,10 @@ type InstanceExpirationReconciler struct {
 	NamespaceWhitelist            metav1.LabelSelector
 	EnableExpirationNotifications bool
 	MailClient                    *mail.Client
+	Outbox                        *NotificationOutbox
+	Schedule                      AutomationSchedule
+	DryRun                        *DryRunAuditor
+	PreTerminationHooks           *PreTerminationHookRunner
 	NotificationInterval          time.Duration
 	MarginTime                    time.Duration
 	// This function, if configured, is deferred at the beginning of the Reconcile.
@@ -73,7 +78,11 @@ func (r *InstanceExpirationReconciler) SetupWithManager(mgr ctrl.Manager, concur
 			createNamespaceWatchHandlerWithIgnore(r.Client, forge.ExpirationIgnoreNamespace),
 			builder.WithPredicates(expirationIgnoreNamespace),
 		).
-		Named("instance-expiration-termination").
+		Watches(&clv1alpha1.Workspace{},
+			createWorkspaceWatchHandler(r.Client),
+			builder.WithPredicates(automationPolicyChanged),
+		).
+		Named(ExpirationControllerName).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: concurrency,
 		}).
@@ -101,6 +110,7 @@ func (r *InstanceExpirationReconciler) Reconcile(ctx context.Context, req ctrl.R
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			log.Info("instance not found", "name", req.NamespacedName, "namespace", req.Namespace)
+			r.DryRun.Forget(ExpirationControllerName, req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "failed to retrieve instance/template/tenant")
@@ -108,14 +118,33 @@ func (r *InstanceExpirationReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 	tracer.Step("instance, template and tenant retrieved")
 
-	// Get lifespan from template's deleteAfterCreation field
-	deleteAfterCreation := template.Spec.Cleanup.DeleteAfterCreation
+	workspace, err := GetTemplateWorkspace(ctx, r.Client, template)
+	if err != nil {
+		log.Error(err, "failed to retrieve the template workspace")
+		return ctrl.Result{}, err
+	}
+	policy, err := ResolveAutomationPolicy(template, workspace, r.DefaultPolicy())
+	if err != nil {
+		log.Error(err, "failed to resolve the automation policy")
+		return ctrl.Result{}, err
+	}
+	if policy.DryRun, err = r.DryRun.Enabled(ctx, r.Client, instance, template); err != nil {
+		log.Error(err, "failed to check the dry-run mode")
+		return ctrl.Result{}, err
+	}
+
+	// Get lifespan from the deleteAfterCreation field of the resolved policy
+	deleteAfterCreation := policy.DeleteAfterCreation
 
 	ctx, _ = clctx.TemplateInto(ctx, template)
 	ctx, _ = clctx.InstanceInto(ctx, instance)
 	ctx, _ = clctx.TenantInto(ctx, tenant)
+	ctx = PolicyInto(ctx, &policy)
 
 	// If the template's deleteAfterCreation field is set to neverTimeoutValue , never delete
+	if deleteAfterCreation == NeverTimeoutValue || !policy.DryRun {
+		r.DryRun.Forget(ExpirationControllerName, req.NamespacedName)
+	}
 	if deleteAfterCreation == NeverTimeoutValue {
 		dbgLog.Info("Instance marked as never expiring", "instance", instance.GetName(), "namespace", instance.GetNamespace())
 		return ctrl.Result{}, nil
@@ -128,6 +157,12 @@ func (r *InstanceExpirationReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	tracer.Step("expiration checked")
+
+	// In dry-run mode, only record the action which would be performed
+	if policy.DryRun {
+		return r.RecordDryRunDeletion(ctx, instance, remainingTime), nil
+	}
+
 	if remainingTime <= 0 {
 		tenant := clctx.TenantFrom(ctx)
 		if tenant == nil {
@@ -135,17 +170,23 @@ func (r *InstanceExpirationReconciler) Reconcile(ctx context.Context, req ctrl.R
 		}
 
 		if r.EnableExpirationNotifications {
+			// Postpone the warning notification (and consequently the deletion) during quiet hours
+			if _, warned := instance.Annotations[forge.ExpiringWarningNotificationTimestampAnnotation]; !warned {
+				if quiet, requeueAfter := r.CheckQuietHours(ctx); quiet {
+					return ctrl.Result{RequeueAfter: requeueAfter}, nil
+				}
+			}
 			shouldSendWarning, err := r.ShouldSendWarningNotification(ctx)
 			if err != nil {
 				log.Error(err, "failed to check if warning notification should be sent")
 				return ctrl.Result{}, err
 			}
 			if shouldSendWarning {
-				if err := SendExpiringWarningNotification(ctx, r.MailClient, r.NotificationInterval); err != nil {
+				if err := SendExpiringWarningNotification(ctx, r.MailClient, r.Outbox, policy.ExpirationNotificationInterval); err != nil {
 					log.Error(err, "failed sending expiring warning notification email")
 					return ctrl.Result{}, err
 				}
-				return ctrl.Result{RequeueAfter: r.NotificationInterval}, nil
+				return ctrl.Result{RequeueAfter: policy.ExpirationNotificationInterval}, nil
 			}
 			// If all notifications have been sent (or simply disabled), terminate the instance
 			shouldTerminate, newRemainingTime, err := r.ShouldTerminateInstance(ctx)
@@ -154,6 +195,9 @@ func (r *InstanceExpirationReconciler) Reconcile(ctx context.Context, req ctrl.R
 				return ctrl.Result{}, err
 			}
 			if shouldTerminate {
+				if proceed, res, err := r.RunPreTerminationHooks(ctx); !proceed {
+					return res, err
+				}
 				if err := r.DeleteInstance(ctx); err != nil {
 					log.Error(err, "failed to delete expired instance")
 					return ctrl.Result{}, err
@@ -170,6 +214,12 @@ func (r *InstanceExpirationReconciler) Reconcile(ctx context.Context, req ctrl.R
 				return ctrl.Result{RequeueAfter: requeueTime}, nil
 			}
 		} else {
+			if deferred, requeueAfter := r.CheckActionDeferral(ctx); deferred {
+				return ctrl.Result{RequeueAfter: requeueAfter}, nil
+			}
+			if proceed, res, err := r.RunPreTerminationHooks(ctx); !proceed {
+				return res, err
+			}
 			if err := r.DeleteInstance(ctx); err != nil {
 				log.Error(err, "failed to delete expired instance")
 				return ctrl.Result{}, err
@@ -186,16 +236,18 @@ func (r *InstanceExpirationReconciler) Reconcile(ctx context.Context, req ctrl.R
 	return ctrl.Result{RequeueAfter: requeueTime}, nil
 }
 
-// ShouldTerminateInstance checks if the instance should be terminated.
+// ShouldTerminateInstance checks if the instance should be terminated, i.e., if the notification interval elapsed
+// since the warning notification and the automated actions are not currently deferred by the policy.
 func (r *InstanceExpirationReconciler) ShouldTerminateInstance(ctx context.Context) (bool, time.Duration, error) {
+	notificationInterval := r.Policy(ctx).ExpirationNotificationInterval
 	instance := clctx.InstanceFrom(ctx)
 	if instance == nil {
-		return false, r.NotificationInterval, fmt.Errorf("instance not found in context")
+		return false, notificationInterval, fmt.Errorf("instance not found in context")
 	}
 
 	if r.EnableExpirationNotifications {
 		if _, ok := instance.Annotations[forge.ExpiringWarningNotificationTimestampAnnotation]; !ok {
-			return false, r.NotificationInterval, nil
+			return false, notificationInterval, nil
 		}
 	}
 
@@ -203,16 +255,21 @@ func (r *InstanceExpirationReconciler) ShouldTerminateInstance(ctx context.Conte
 	if timestampStr, ok := instance.Annotations[forge.ExpiringWarningNotificationTimestampAnnotation]; ok {
 		timestampWarning, err := time.Parse(time.RFC3339, timestampStr)
 		if err != nil {
-			return false, r.NotificationInterval, err
+			return false, notificationInterval, err
 		}
 		elapsed := time.Since(timestampWarning)
-		if elapsed < r.NotificationInterval {
+		if elapsed < notificationInterval {
 			// evaluate the remaining time to reach the notification interval
-			remainingTime := r.NotificationInterval - elapsed + r.MarginTime
+			remainingTime := notificationInterval - elapsed + r.MarginTime
 			return false, remainingTime, nil
 		}
 	} else {
-		return false, r.NotificationInterval, nil
+		return false, notificationInterval, nil
+	}
+
+	// Do not delete the instance during quiet hours and holidays
+	if deferred, requeueAfter := r.CheckActionDeferral(ctx); deferred {
+		return false, requeueAfter - r.MarginTime, nil
 	}
 
 	return true, 0, nil
@@ -262,6 +319,26 @@ func (r *InstanceExpirationReconciler) DeleteInstance(ctx context.Context) error
 	return nil
 }
 
+// RunPreTerminationHooks archives the content of the instance environments configuring a pre-termination hook,
+// returning whether the instance can be deleted, or the result to wait for the hooks completion otherwise.
+func (r *InstanceExpirationReconciler) RunPreTerminationHooks(ctx context.Context) (bool, ctrl.Result, error) {
+	instance := clctx.InstanceFrom(ctx)
+	if instance == nil {
+		return false, ctrl.Result{}, fmt.Errorf("instance not found in context")
+	}
+
+	done, requeueAfter, err := r.PreTerminationHooks.Run(ctx, instance, clctx.TemplateFrom(ctx))
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed running pre-termination hooks")
+		return false, ctrl.Result{}, err
+	}
+	if !done {
+		ctrl.LoggerFrom(ctx).V(utils.LogDebugLevel).Info("waiting for pre-termination hooks completion")
+		return false, ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	return true, ctrl.Result{}, nil
+}
+
 // NotifyInstanceDeletion handles sending notification emails when an instance is deleted.
 func (r *InstanceExpirationReconciler) NotifyInstanceDeletion(ctx context.Context) error {
 	log := ctrl.LoggerFrom(ctx).WithName("notify-instance-deletion")
@@ -277,10 +354,10 @@ func (r *InstanceExpirationReconciler) NotifyInstanceDeletion(ctx context.Contex
 
 	// Send the notification email
 	if r.EnableExpirationNotifications {
-		if err := SendExpiringNotification(ctx, r.MailClient); err != nil {
+		if err := SendExpiringNotification(ctx, r.MailClient, r.Outbox); err != nil {
 			return fmt.Errorf("failed sending notification email: %w", err)
 		}
-		log.Info("Notification email sent to user", "instance", instance.Name, "email", tenant.Spec.Email)
+		log.Info(notificationOutcome("Notification", r.Outbox), "instance", instance.Name, "email", tenant.Spec.Email)
 	} else {
 		log.Info("Expiration notifications are disabled, skipping email notification", "instance", instance.Name, "email", tenant.Spec.Email)
 	}
@@ -338,3 +415,63 @@ func (r *InstanceExpirationReconciler) CheckSkipReconciliation(ctx context.Conte
 
 	return false, nil
 }
+
+// DefaultPolicy returns the automation policy derived from the global configuration of the reconciler.
+func (r *InstanceExpirationReconciler) DefaultPolicy() AutomationPolicy {
+	return AutomationPolicy{
+		ExpirationNotificationInterval: r.NotificationInterval,
+		QuietHours:                     r.Schedule.QuietHours,
+		Holidays:                       r.Schedule.Holidays,
+		Location:                       r.Schedule.Location,
+	}
+}
+
+// Policy returns the automation policy embedded in the context, falling back to the one derived from the global configuration.
+func (r *InstanceExpirationReconciler) Policy(ctx context.Context) *AutomationPolicy {
+	if policy := PolicyFrom(ctx); policy != nil {
+		return policy
+	}
+	policy, _ := ResolveAutomationPolicy(nil, nil, r.DefaultPolicy())
+	return &policy
+}
+
+// CheckQuietHours returns whether notifications are currently suspended due to the quiet hours of the policy,
+// along with the time after which the instance should be requeued.
+func (r *InstanceExpirationReconciler) CheckQuietHours(ctx context.Context) (bool, time.Duration) {
+	quiet, end := r.Policy(ctx).InQuietHours(time.Now())
+	if !quiet {
+		return false, 0
+	}
+	ctrl.LoggerFrom(ctx).Info("quiet hours in progress, postponing the notification", "until", end)
+	return true, time.Until(end) + r.MarginTime
+}
+
+// CheckActionDeferral returns whether the automated actions are currently deferred due to the quiet hours or the
+// holidays of the policy, along with the time after which the instance should be requeued.
+func (r *InstanceExpirationReconciler) CheckActionDeferral(ctx context.Context) (bool, time.Duration) {
+	now := time.Now()
+	allowed, reason := r.Policy(ctx).NextAllowedTime(now)
+	if !allowed.After(now) {
+		return false, 0
+	}
+	ctrl.LoggerFrom(ctx).Info("automated actions deferred", "reason", reason, "until", allowed)
+	return true, allowed.Sub(now) + r.MarginTime
+}
+
+// RecordDryRunDeletion records the deletion the instance would be subject to after the given remaining lifetime
+// and the following notification interval, without performing it nor notifying the tenant.
+func (r *InstanceExpirationReconciler) RecordDryRunDeletion(ctx context.Context, instance *clv1alpha2.Instance, remainingTime time.Duration) ctrl.Result {
+	policy := r.Policy(ctx)
+	delay := max(remainingTime, 0)
+	if r.EnableExpirationNotifications {
+		delay += policy.ExpirationNotificationInterval
+	}
+
+	scheduledAt, _ := policy.NextAllowedTime(time.Now().Add(delay))
+	r.DryRun.Record(ctx, r.EventsRecorder, ExpirationControllerName, instance, ActionDelete, scheduledAt, "maximum lifetime reached")
+
+	if remainingTime > 0 {
+		return ctrl.Result{RequeueAfter: remainingTime + r.MarginTime}
+	}
+	return ctrl.Result{RequeueAfter: policy.ExpirationNotificationInterval}
+}
@@ -36,6 +36,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
 	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
 	clctx "github.com/netgroup-polito/CrownLabs/operators/pkg/clcontext"
 	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
@@ -55,6 +56,10 @@ type InstanceInactiveTerminationReconciler struct {
 	NotificationInterval            time.Duration
 	DestructionNotificationInterval time.Duration
 	MailClient                      *mail.Client
+	Outbox                          *NotificationOutbox
+	Schedule                        AutomationSchedule
+	DryRun                          *DryRunAuditor
+	PreTerminationHooks             *PreTerminationHookRunner
 	Prometheus                      PrometheusClientInterface
 	MarginTime                      time.Duration
 	MinLastActivityRequeueTime      time.Duration
@@ -89,7 +94,11 @@ func (r *InstanceInactiveTerminationReconciler) SetupWithManager(mgr ctrl.Manage
 			createNamespaceWatchHandlerWithIgnore(r.Client, forge.InstanceInactivityIgnoreNamespace),
 			builder.WithPredicates(inactivityIgnoreNamespace),
 		).
-		Named("instance-inactive-termination").
+		Watches(&clv1alpha1.Workspace{},
+			createWorkspaceWatchHandler(r.Client),
+			builder.WithPredicates(automationPolicyChanged),
+		).
+		Named(InactiveTerminationControllerName).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: concurrency,
 		}).
@@ -121,6 +130,8 @@ func (r *InstanceInactiveTerminationReconciler) Reconcile(ctx context.Context, r
 	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
 		if !kerrors.IsNotFound(err) {
 			log.Error(err, "failed retrieving instance")
+		} else {
+			r.DryRun.Forget(InactiveTerminationControllerName, req.NamespacedName)
 		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
@@ -138,6 +149,25 @@ func (r *InstanceInactiveTerminationReconciler) Reconcile(ctx context.Context, r
 	ctx, _ = clctx.InstanceInto(ctx, &instance)
 	ctx, _ = clctx.TemplateInto(ctx, &template)
 
+	workspace, err := GetTemplateWorkspace(ctx, r.Client, &template)
+	if err != nil {
+		log.Error(err, "failed to retrieve the template workspace")
+		return ctrl.Result{}, err
+	}
+	policy, err := ResolveAutomationPolicy(&template, workspace, r.DefaultPolicy())
+	if err != nil {
+		log.Error(err, "failed to resolve the automation policy")
+		return ctrl.Result{}, err
+	}
+	if policy.DryRun, err = r.DryRun.Enabled(ctx, r.Client, &instance, &template); err != nil {
+		log.Error(err, "failed to check the dry-run mode")
+		return ctrl.Result{}, err
+	}
+	if !policy.DryRun {
+		r.DryRun.Forget(InactiveTerminationControllerName, req.NamespacedName)
+	}
+	ctx = PolicyInto(ctx, &policy)
+
 	// ── 3. Defer: patch instance object + delete if flagged ──
 	var deleteInstance bool
 	defer func(original *clv1alpha2.Instance) {
@@ -197,9 +227,10 @@ func (r *InstanceInactiveTerminationReconciler) Reconcile(ctx context.Context, r
 		return r.RequeueAfterRandom(), skipErr
 	}
 
-	stopAfterInactivity := template.Spec.Cleanup.StopAfterInactivity
+	stopAfterInactivity := policy.StopAfterInactivity
 	// If set to NeverTimeoutValue, return but schedule a requeue to keep refreshing activity
 	if stopAfterInactivity == NeverTimeoutValue {
+		r.DryRun.Forget(InactiveTerminationControllerName, req.NamespacedName)
 		dbgLog.Info("Instance marked as never stop", "name", instance.GetName(), "namespace", instance.GetNamespace())
 		return r.RequeueAfterRandom(), nil
 	}
@@ -219,6 +250,11 @@ func (r *InstanceInactiveTerminationReconciler) Reconcile(ctx context.Context, r
 	dbgLog.Info("instance termination check", "remainingTime", remainingTime.String(), "instance", instance.Name)
 	tracer.Step("inactive termination check done")
 
+	// In dry-run mode, only record the action which would be performed
+	if policy.DryRun {
+		return r.RecordDryRunTermination(ctx, &instance, remainingTime)
+	}
+
 	// Check if the instance has expired
 	if remainingTime <= 0 {
 		res, terminateEarly, handleErr := r.HandleInactivityInstance(ctx, &instance, &deleteInstance)
@@ -253,9 +289,15 @@ func (r *InstanceInactiveTerminationReconciler) HandlePoweredOffInstance(ctx con
 
 	if !isActive {
 		// No delete-after-inactivity configured, nothing to do for a powered-off instance.
+		r.DryRun.Forget(InactiveTerminationControllerName, forge.NamespacedNameFromObject(instance))
 		return ctrl.Result{}, nil
 	}
 
+	// In dry-run mode, only record the action which would be performed
+	if r.Policy(ctx).DryRun {
+		return r.RecordDryRunDestruction(ctx, instance, remainingPauseTime)
+	}
+
 	// Destruction timer still has time left — requeue.
 	if remainingPauseTime > 0 {
 		dbgLog.Info("requeueing paused instance for destruction check")
@@ -271,6 +313,9 @@ func (r *InstanceInactiveTerminationReconciler) HandlePoweredOffInstance(ctx con
 			return ctrl.Result{}, err
 		}
 		if shouldSendWarning {
+			if quiet, requeueAfter := r.CheckQuietHours(ctx); quiet {
+				return ctrl.Result{RequeueAfter: requeueAfter}, nil
+			}
 			window, err := r.GetDestructionNotificationWindow(ctx, instance)
 			if err != nil {
 				log.Error(err, "failed getting destruction notification window")
@@ -280,7 +325,7 @@ func (r *InstanceInactiveTerminationReconciler) HandlePoweredOffInstance(ctx con
 				log.Error(err, "failed sending destruction warning email")
 				return ctrl.Result{}, err
 			}
-			return ctrl.Result{RequeueAfter: r.DestructionNotificationInterval}, nil
+			return ctrl.Result{RequeueAfter: r.Policy(ctx).DestructionNotificationInterval}, nil
 		}
 
 		// Check if all notifications have been sent and instance should be deleted.
@@ -290,10 +335,13 @@ func (r *InstanceInactiveTerminationReconciler) HandlePoweredOffInstance(ctx con
 			return ctrl.Result{}, err
 		}
 		if !shouldDelete {
+			if deferred, requeueAfter := r.CheckActionDeferral(ctx); deferred {
+				return ctrl.Result{RequeueAfter: requeueAfter}, nil
+			}
 			// Still waiting for the next notification interval.
 			lastNotificationTimeStr := instance.Annotations[forge.LastDestructionNotificationTimestampAnnotation]
 			lastNotificationTime, _ := time.Parse(time.RFC3339, lastNotificationTimeStr)
-			requeueTime := r.DestructionNotificationInterval - time.Since(lastNotificationTime) + r.MarginTime
+			requeueTime := r.Policy(ctx).DestructionNotificationInterval - time.Since(lastNotificationTime) + r.MarginTime
 			if requeueTime < 0 {
 				requeueTime = r.MarginTime
 			}
@@ -301,7 +349,10 @@ func (r *InstanceInactiveTerminationReconciler) HandlePoweredOffInstance(ctx con
 			return ctrl.Result{RequeueAfter: requeueTime}, nil
 		}
 
-		// All notifications sent — notify deletion then flag for deletion.
+		// All notifications sent — archive the content, notify deletion then flag for deletion.
+		if proceed, res, err := r.RunPreTerminationHooks(ctx, instance); !proceed {
+			return res, err
+		}
 		log.Info("Deleting paused persistent instance due to prolonged inactivity...")
 		if err := r.NotifyInstanceDeletion(ctx); err != nil {
 			log.Error(err, "failed to send deletion notification")
@@ -311,7 +362,19 @@ func (r *InstanceInactiveTerminationReconciler) HandlePoweredOffInstance(ctx con
 		return ctrl.Result{}, nil
 	}
 
-	// Notifications disabled — flag for immediate deletion.
+	// Notifications disabled — flag for immediate deletion, unless deferred.
+	shouldDelete, err := r.ShouldDeleteInstance(ctx, instance)
+	if err != nil {
+		log.Error(err, "failed checking if should delete instance")
+		return ctrl.Result{}, err
+	}
+	if !shouldDelete {
+		_, requeueAfter := r.CheckActionDeferral(ctx)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	if proceed, res, err := r.RunPreTerminationHooks(ctx, instance); !proceed {
+		return res, err
+	}
 	log.Info("Deleting paused persistent instance due to prolonged inactivity...")
 	*deleteInstance = true
 	return ctrl.Result{}, nil
@@ -330,11 +393,14 @@ func (r *InstanceInactiveTerminationReconciler) HandleInactivityInstance(ctx con
 		}
 
 		if shouldSendWarning {
+			if quiet, requeueAfter := r.CheckQuietHours(ctx); quiet {
+				return ctrl.Result{RequeueAfter: requeueAfter}, true, nil
+			}
 			if err := r.SendInactivityWarning(ctx, instance); err != nil {
 				log.Error(err, "failed sending inactivity warning email", "instance", instance.Name, "namespace", instance.Namespace)
 				return ctrl.Result{}, true, err
 			}
-			return ctrl.Result{RequeueAfter: r.NotificationInterval}, true, nil
+			return ctrl.Result{RequeueAfter: r.Policy(ctx).InactivityNotificationInterval}, true, nil
 		}
 
 		// Check if all notifications have been sent and instance should be terminated.
@@ -344,6 +410,9 @@ func (r *InstanceInactiveTerminationReconciler) HandleInactivityInstance(ctx con
 			return ctrl.Result{}, true, err
 		}
 		if shouldTerminate {
+			if proceed, res, err := r.RunPreTerminationHooks(ctx, instance); !proceed {
+				return res, true, err
+			}
 			if err := r.TerminateInstance(ctx, deleteInstance); err != nil {
 				log.Error(err, "failed terminating inactive instance", "instance", instance.Name, "namespace", instance.Namespace)
 				return ctrl.Result{}, true, err
@@ -355,8 +424,17 @@ func (r *InstanceInactiveTerminationReconciler) HandleInactivityInstance(ctx con
 			}
 			return ctrl.Result{}, true, nil
 		}
+		if deferred, requeueAfter := r.CheckActionDeferral(ctx); deferred {
+			return r.deferralResult(requeueAfter), true, nil
+		}
 	} else {
-		// Notifications disabled — terminate immediately.
+		// Notifications disabled — terminate immediately, unless deferred.
+		if deferred, requeueAfter := r.CheckActionDeferral(ctx); deferred {
+			return r.deferralResult(requeueAfter), true, nil
+		}
+		if proceed, res, err := r.RunPreTerminationHooks(ctx, instance); !proceed {
+			return res, true, err
+		}
 		if err := r.TerminateInstance(ctx, deleteInstance); err != nil {
 			log.Error(err, "failed terminating inactive instance", "instance", instance.Name, "namespace", instance.Namespace)
 			return ctrl.Result{}, true, err
@@ -533,7 +611,7 @@ func (r *InstanceInactiveTerminationReconciler) GetInactivityNotificationWindow(
 	template := clctx.TemplateFrom(ctx)
 
 	// Calculate the remaining number of alerts that should be sent
-	NumAlerts := r.InstanceMaxNumberOfAlerts
+	NumAlerts := r.Policy(ctx).MaxNumberOfAlerts
 
 	if template != nil {
 		if customMaxAlertsStr, ok := template.Annotations[forge.CustomNumberOfAlertsAnnotation]; ok {
@@ -556,7 +634,7 @@ func (r *InstanceInactiveTerminationReconciler) GetInactivityNotificationWindow(
 	}
 
 	// Calculate the remaining time before reaching the maximum number of alerts
-	return time.Duration(remainingAlerts) * r.NotificationInterval, nil
+	return time.Duration(remainingAlerts) * r.Policy(ctx).InactivityNotificationInterval, nil
 }
 
 // IsTemplatePersistent checks if the instance template has at least one persistent environment.
@@ -604,6 +682,21 @@ func (r *InstanceInactiveTerminationReconciler) TerminateInstance(ctx context.Co
 	return nil
 }
 
+// RunPreTerminationHooks archives the content of the instance environments configuring a pre-termination hook,
+// returning whether the instance can be stopped or deleted, or the result to wait for the hooks completion otherwise.
+func (r *InstanceInactiveTerminationReconciler) RunPreTerminationHooks(ctx context.Context, instance *clv1alpha2.Instance) (bool, ctrl.Result, error) {
+	done, requeueAfter, err := r.PreTerminationHooks.Run(ctx, instance, clctx.TemplateFrom(ctx))
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed running pre-termination hooks")
+		return false, ctrl.Result{}, err
+	}
+	if !done {
+		ctrl.LoggerFrom(ctx).V(utils.LogDebugLevel).Info("waiting for pre-termination hooks completion")
+		return false, ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	return true, ctrl.Result{}, nil
+}
+
 // IncrementAnnotation increments the value of the annotation string by 1.
 func (r *InstanceInactiveTerminationReconciler) IncrementAnnotation(ctx context.Context, annotationString string) (string, error) {
 	log := ctrl.LoggerFrom(ctx).WithName("string-to-int-annotation")
@@ -717,7 +810,7 @@ func (r *InstanceInactiveTerminationReconciler) GetAlertCounts(ctx context.Conte
 		return 0, 0, err
 	}
 
-	maxAlerts = r.InstanceMaxNumberOfAlerts
+	maxAlerts = r.Policy(ctx).MaxNumberOfAlerts
 	template := clctx.TemplateFrom(ctx)
 	if template != nil {
 		// if the CustomNumberOfAlertsAnnotation is set, override the default max alerts
@@ -733,7 +826,8 @@ func (r *InstanceInactiveTerminationReconciler) GetAlertCounts(ctx context.Conte
 	return numAlerts, maxAlerts, nil
 }
 
-// ShouldTerminateInstance checks if the instance should be terminated based on its running state and the number of alerts sent.
+// ShouldTerminateInstance checks if the instance should be terminated based on its running state, the number of alerts sent,
+// and on whether the automated actions are currently deferred by the policy.
 func (r *InstanceInactiveTerminationReconciler) ShouldTerminateInstance(ctx context.Context, instance *clv1alpha2.Instance) (bool, error) {
 	if !instance.Spec.Running {
 		return false, nil
@@ -745,13 +839,30 @@ func (r *InstanceInactiveTerminationReconciler) ShouldTerminateInstance(ctx cont
 		if err != nil {
 			return false, err
 		}
-		return numAlerts >= maxAlerts, nil
+		if numAlerts < maxAlerts {
+			return false, nil
+		}
+	}
+
+	// Do not terminate the instance during quiet hours and holidays
+	if deferred, _ := r.CheckActionDeferral(ctx); deferred {
+		return false, nil
 	}
 
 	// If notifications are disabled, terminate the instance immediately
 	return true, nil
 }
 
+// deferralResult returns the result to requeue an instance whose termination has been deferred,
+// still ensuring the periodic refresh of its last activity.
+func (r *InstanceInactiveTerminationReconciler) deferralResult(requeueAfter time.Duration) ctrl.Result {
+	activityResult := r.RequeueAfterRandom()
+	if activityResult.RequeueAfter < requeueAfter {
+		return activityResult
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}
+}
+
 // ShouldSendWarningNotification checks if the notification should be sent based on the number of alerts sent and the last notification time.
 func (r *InstanceInactiveTerminationReconciler) ShouldSendWarningNotification(ctx context.Context, instance *clv1alpha2.Instance) (bool, error) {
 	log := ctrl.LoggerFrom(ctx).WithName("ShouldSendWarningNotification")
@@ -786,7 +897,7 @@ func (r *InstanceInactiveTerminationReconciler) ShouldSendWarningNotification(ct
 		return false, err
 	}
 	if numAlerts > 0 {
-		if time.Since(lastNotificationTime) < r.NotificationInterval-r.MarginTime {
+		if time.Since(lastNotificationTime) < r.Policy(ctx).InactivityNotificationInterval-r.MarginTime {
 			log.Info("Last notification sent within the notification interval, skipping email notification", "instance", instance.Name)
 			return false, nil
 		}
@@ -812,11 +923,11 @@ func (r *InstanceInactiveTerminationReconciler) SendInactivityWarning(ctx contex
 
 	if r.EnableInactivityNotifications {
 		ctx, _ = clctx.TenantInto(ctx, tenant)
-		if err := SendInactivityDetectionNotification(ctx, r.MailClient, remainingTime); err != nil {
+		if err := SendInactivityDetectionNotification(ctx, r.MailClient, r.Outbox, remainingTime); err != nil {
 			log.Error(err, "failed sending notification email to user", "email", tenant.Spec.Email)
 			return err
 		}
-		log.Info("Inactivity notification email sent to user", "instance", instance.Name, "email", tenant.Spec.Email)
+		log.Info(notificationOutcome("Inactivity notification", r.Outbox), "instance", instance.Name, "email", tenant.Spec.Email)
 	} else {
 		log.Info("Inactivity notifications are disabled, skipping email notification", "instance", instance.Name, "email", tenant.Spec.Email)
 	}
@@ -849,10 +960,10 @@ func (r *InstanceInactiveTerminationReconciler) SendTerminationNotification(ctx
 
 	if r.EnableInactivityNotifications {
 		ctx, _ = clctx.TenantInto(ctx, tenant)
-		if err := SendInactivityTerminationNotification(ctx, r.MailClient, 0); err != nil {
+		if err := SendInactivityTerminationNotification(ctx, r.MailClient, r.Outbox, 0); err != nil {
 			return fmt.Errorf("failed sending termination notification email: %w", err)
 		}
-		log.Info("Termination notification email sent to user", "instance", instance.Name, "email", tenant.Spec.Email)
+		log.Info(notificationOutcome("Termination notification", r.Outbox), "instance", instance.Name, "email", tenant.Spec.Email)
 	} else {
 		log.Info("Inactivity notifications are disabled, skipping email notification", "instance", instance.Name, "email", tenant.Spec.Email)
 	}
@@ -875,12 +986,12 @@ func (r *InstanceInactiveTerminationReconciler) GetDestructionNotificationWindow
 		}
 	}
 
-	maxAlerts := r.InstanceMaxNumberOfAlerts
-	remainingAlerts := maxAlerts - numAlerts
+	policy := r.Policy(ctx)
+	remainingAlerts := policy.MaxNumberOfAlerts - numAlerts
 	if remainingAlerts <= 0 {
 		return 0, nil
 	}
-	return time.Duration(remainingAlerts) * r.DestructionNotificationInterval, nil
+	return time.Duration(remainingAlerts) * policy.DestructionNotificationInterval, nil
 }
 
 // ShouldSendDestructionWarningNotification checks if the notification should be sent based on the number of alerts sent and the last notification time.
@@ -898,7 +1009,8 @@ func (r *InstanceInactiveTerminationReconciler) ShouldSendDestructionWarningNoti
 		}
 	}
 
-	maxAlerts := r.InstanceMaxNumberOfAlerts
+	policy := r.Policy(ctx)
+	maxAlerts := policy.MaxNumberOfAlerts
 
 	lastNotificationTimeStr := instance.Annotations[forge.LastDestructionNotificationTimestampAnnotation]
 	if lastNotificationTimeStr == "" {
@@ -912,7 +1024,7 @@ func (r *InstanceInactiveTerminationReconciler) ShouldSendDestructionWarningNoti
 		return false, err
 	}
 
-	if numAlerts > 0 && time.Since(lastNotificationTime) < r.DestructionNotificationInterval-r.MarginTime {
+	if numAlerts > 0 && time.Since(lastNotificationTime) < policy.DestructionNotificationInterval-r.MarginTime {
 		log.Info("Last destruction notification sent within the notification interval, skipping email notification", "instance", instance.Name)
 		return false, nil // The interval has not yet passed
 	}
@@ -930,11 +1042,11 @@ func (r *InstanceInactiveTerminationReconciler) SendDestructionWarning(ctx conte
 
 	// 1. Call the function to send the email that is in common.go.
 	ctx, _ = clctx.TenantInto(ctx, tenant)
-	if err := SendDestructionWarningNotification(ctx, r.MailClient, remainingTime); err != nil {
+	if err := SendDestructionWarningNotification(ctx, r.MailClient, r.Outbox, remainingTime); err != nil {
 		log.Error(err, "failed sending destruction notification email to user", "email", tenant.Spec.Email)
 		return fmt.Errorf("failed to send destruction warning email: %w", err)
 	}
-	log.Info("Destruction notification email sent to user", "instance", instance.Name, "email", tenant.Spec.Email)
+	log.Info(notificationOutcome("Destruction notification", r.Outbox), "instance", instance.Name, "email", tenant.Spec.Email)
 
 	// 2. Update the annotations to count how many emails we have sent.
 	numAlertsStr := instance.Annotations[forge.DestructionAlertsSentAnnotation]
@@ -962,7 +1074,7 @@ func (r *InstanceInactiveTerminationReconciler) GetRemainingInactivityDestructio
 		return 0, false, fmt.Errorf("template not found in context")
 	}
 
-	deleteAfterInactivity := template.Spec.Cleanup.DeleteAfterInactivity
+	deleteAfterInactivity := r.Policy(ctx).DeleteAfterInactivity
 	if deleteAfterInactivity == NeverTimeoutValue || deleteAfterInactivity == "" {
 		return 0, false, nil
 	}
@@ -992,8 +1104,9 @@ func (r *InstanceInactiveTerminationReconciler) GetRemainingInactivityDestructio
 	return remainingTime, true, nil
 }
 
-// ShouldDeleteInstance checks if the instance should be deleted based on the number of destruction alerts sent.
-func (r *InstanceInactiveTerminationReconciler) ShouldDeleteInstance(_ context.Context, instance *clv1alpha2.Instance) (bool, error) {
+// ShouldDeleteInstance checks if the instance should be deleted based on the number of destruction alerts sent,
+// and on whether the automated actions are currently deferred by the policy.
+func (r *InstanceInactiveTerminationReconciler) ShouldDeleteInstance(ctx context.Context, instance *clv1alpha2.Instance) (bool, error) {
 	if r.EnableInactivityNotifications {
 		numAlertsStr := instance.Annotations[forge.DestructionAlertsSentAnnotation]
 		numAlerts := 0
@@ -1004,7 +1117,14 @@ func (r *InstanceInactiveTerminationReconciler) ShouldDeleteInstance(_ context.C
 				return false, err
 			}
 		}
-		return numAlerts >= r.InstanceMaxNumberOfAlerts, nil
+		if numAlerts < r.Policy(ctx).MaxNumberOfAlerts {
+			return false, nil
+		}
+	}
+
+	// Do not delete the instance during quiet hours and holidays
+	if deferred, _ := r.CheckActionDeferral(ctx); deferred {
+		return false, nil
 	}
 	return true, nil
 }
@@ -1026,13 +1146,104 @@ func (r *InstanceInactiveTerminationReconciler) NotifyInstanceDeletion(ctx conte
 	// Send the notification email
 	if r.EnableInactivityNotifications {
 		ctx, _ = clctx.TenantInto(ctx, tenant)
-		if err := SendDestructionNotification(ctx, r.MailClient); err != nil {
+		if err := SendDestructionNotification(ctx, r.MailClient, r.Outbox); err != nil {
 			return fmt.Errorf("failed sending notification email: %w", err)
 		}
-		log.Info("Notification email sent to user", "instance", instance.Name, "email", tenant.Spec.Email)
+		log.Info(notificationOutcome("Notification", r.Outbox), "instance", instance.Name, "email", tenant.Spec.Email)
 	} else {
 		log.Info("Destruction notifications are disabled, skipping email notification", "instance", instance.Name, "email", tenant.Spec.Email)
 	}
 
 	return nil
 }
+
+// DefaultPolicy returns the automation policy derived from the global configuration of the reconciler.
+func (r *InstanceInactiveTerminationReconciler) DefaultPolicy() AutomationPolicy {
+	return AutomationPolicy{
+		MaxNumberOfAlerts:               r.InstanceMaxNumberOfAlerts,
+		InactivityNotificationInterval:  r.NotificationInterval,
+		DestructionNotificationInterval: r.DestructionNotificationInterval,
+		QuietHours:                      r.Schedule.QuietHours,
+		Holidays:                        r.Schedule.Holidays,
+		Location:                        r.Schedule.Location,
+	}
+}
+
+// Policy returns the automation policy embedded in the context, falling back to the one derived from the global configuration.
+func (r *InstanceInactiveTerminationReconciler) Policy(ctx context.Context) *AutomationPolicy {
+	if policy := PolicyFrom(ctx); policy != nil {
+		return policy
+	}
+	policy, _ := ResolveAutomationPolicy(nil, nil, r.DefaultPolicy())
+	return &policy
+}
+
+// CheckQuietHours returns whether notifications are currently suspended due to the quiet hours of the policy,
+// along with the time after which the instance should be requeued.
+func (r *InstanceInactiveTerminationReconciler) CheckQuietHours(ctx context.Context) (bool, time.Duration) {
+	quiet, end := r.Policy(ctx).InQuietHours(time.Now())
+	if !quiet {
+		return false, 0
+	}
+	ctrl.LoggerFrom(ctx).Info("quiet hours in progress, postponing the notification", "until", end)
+	return true, time.Until(end) + r.MarginTime
+}
+
+// CheckActionDeferral returns whether the automated actions are currently deferred due to the quiet hours or the
+// holidays of the policy, along with the time after which the instance should be requeued.
+func (r *InstanceInactiveTerminationReconciler) CheckActionDeferral(ctx context.Context) (bool, time.Duration) {
+	now := time.Now()
+	allowed, reason := r.Policy(ctx).NextAllowedTime(now)
+	if !allowed.After(now) {
+		return false, 0
+	}
+	ctrl.LoggerFrom(ctx).Info("automated actions deferred", "reason", reason, "until", allowed)
+	return true, allowed.Sub(now) + r.MarginTime
+}
+
+// RecordDryRunTermination records the termination the instance would be subject to after the given remaining
+// inactivity time and the following notification window, without performing it nor notifying the tenant.
+func (r *InstanceInactiveTerminationReconciler) RecordDryRunTermination(ctx context.Context, instance *clv1alpha2.Instance, remainingTime time.Duration) (ctrl.Result, error) {
+	delay := max(remainingTime, 0)
+	if r.EnableInactivityNotifications {
+		window, err := r.GetInactivityNotificationWindow(ctx, instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		delay += window
+	}
+
+	action, reason := ActionDelete, "inactivity of a non-persistent instance"
+	if IsTemplatePersistent(clctx.TemplateFrom(ctx)) {
+		action, reason = ActionStop, "inactivity of a persistent instance"
+	}
+	scheduledAt, _ := r.Policy(ctx).NextAllowedTime(time.Now().Add(delay))
+	r.DryRun.Record(ctx, r.EventsRecorder, InactiveTerminationControllerName, instance, action, scheduledAt, reason)
+
+	requeueTime := r.Policy(ctx).InactivityNotificationInterval
+	if remainingTime > 0 {
+		requeueTime = remainingTime + r.MarginTime
+	}
+	return r.deferralResult(requeueTime), nil
+}
+
+// RecordDryRunDestruction records the deletion the powered off instance would be subject to after the given remaining
+// time and the following notification window, without performing it nor notifying the tenant.
+func (r *InstanceInactiveTerminationReconciler) RecordDryRunDestruction(ctx context.Context, instance *clv1alpha2.Instance, remainingPauseTime time.Duration) (ctrl.Result, error) {
+	delay := max(remainingPauseTime, 0)
+	if r.EnableInactivityNotifications {
+		window, err := r.GetDestructionNotificationWindow(ctx, instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		delay += window
+	}
+
+	scheduledAt, _ := r.Policy(ctx).NextAllowedTime(time.Now().Add(delay))
+	r.DryRun.Record(ctx, r.EventsRecorder, InactiveTerminationControllerName, instance, ActionDelete, scheduledAt, "prolonged inactivity of a powered off instance")
+
+	if remainingPauseTime > 0 {
+		return ctrl.Result{RequeueAfter: remainingPauseTime + r.MarginTime}, nil
+	}
+	return ctrl.Result{RequeueAfter: r.Policy(ctx).DestructionNotificationInterval}, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instautoctrl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+)
+
+// MigrateFormerCleanupDefaults clears the cleanup options set to "never" in the Templates created before the given
+// time, when "never" was the default value of those fields, so that they are inherited from the Workspace automation
+// policy. The Templates created afterwards are left untouched, as "never" is an explicit opt-out there.
+// If apply is false, the Templates to be migrated are only returned, without modifying them.
+func MigrateFormerCleanupDefaults(ctx context.Context, c client.Client, createdBefore time.Time, apply bool) ([]types.NamespacedName, error) {
+	log := ctrl.LoggerFrom(ctx).WithName("cleanup-defaults-migration")
+
+	var templates clv1alpha2.TemplateList
+	if err := c.List(ctx, &templates); err != nil {
+		return nil, fmt.Errorf("failed listing templates: %w", err)
+	}
+
+	var migrated []types.NamespacedName
+	for i := range templates.Items {
+		template := &templates.Items[i]
+		if !template.CreationTimestamp.Time.Before(createdBefore) {
+			continue
+		}
+
+		original := template.DeepCopy()
+		cleanup := &template.Spec.Cleanup
+		for _, field := range []*string{&cleanup.DeleteAfterCreation, &cleanup.StopAfterInactivity, &cleanup.DeleteAfterInactivity} {
+			if *field == NeverTimeoutValue {
+				*field = ""
+			}
+		}
+		if *cleanup == original.Spec.Cleanup {
+			continue
+		}
+
+		migrated = append(migrated, client.ObjectKeyFromObject(template))
+		if !apply {
+			continue
+		}
+		if err := c.Patch(ctx, template, client.MergeFrom(original)); err != nil {
+			return migrated, fmt.Errorf("failed migrating template %s/%s: %w", template.Namespace, template.Name, err)
+		}
+		log.Info("template cleanup options migrated", "template", client.ObjectKeyFromObject(template))
+	}
+
+	return migrated, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instautoctrl_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/instautoctrl"
+)
+
+var _ = Describe("The MigrateFormerCleanupDefaults function", func() {
+	var (
+		ctx     context.Context
+		c       client.Client
+		upgrade time.Time
+	)
+
+	template := func(name string, created time.Time, cleanup clv1alpha2.CleanupOptions) *clv1alpha2.Template {
+		return &clv1alpha2.Template{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "workspace-test", CreationTimestamp: metav1.NewTime(created)},
+			Spec:       clv1alpha2.TemplateSpec{Cleanup: cleanup},
+		}
+	}
+
+	getCleanup := func(name string) clv1alpha2.CleanupOptions {
+		var tmpl clv1alpha2.Template
+		Expect(c.Get(ctx, types.NamespacedName{Name: name, Namespace: "workspace-test"}, &tmpl)).To(Succeed())
+		return tmpl.Spec.Cleanup
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		upgrade = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+		scheme := runtime.NewScheme()
+		Expect(clv1alpha2.AddToScheme(scheme)).To(Succeed())
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			template("former", upgrade.Add(-time.Hour), clv1alpha2.CleanupOptions{
+				DeleteAfterCreation:   instautoctrl.NeverTimeoutValue,
+				StopAfterInactivity:   "2h",
+				DeleteAfterInactivity: instautoctrl.NeverTimeoutValue,
+			}),
+			template("customized", upgrade.Add(-time.Hour), clv1alpha2.CleanupOptions{DeleteAfterCreation: "7d"}),
+			template("recent", upgrade.Add(time.Hour), clv1alpha2.CleanupOptions{StopAfterInactivity: instautoctrl.NeverTimeoutValue}),
+		).Build()
+	})
+
+	It("Should only report the templates to be migrated in dry-run mode", func() {
+		migrated, err := instautoctrl.MigrateFormerCleanupDefaults(ctx, c, upgrade, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(migrated).To(ConsistOf(types.NamespacedName{Name: "former", Namespace: "workspace-test"}))
+		Expect(getCleanup("former").DeleteAfterCreation).To(Equal(instautoctrl.NeverTimeoutValue))
+	})
+
+	It("Should clear the former never default, preserving the explicit opt-outs created afterwards", func() {
+		migrated, err := instautoctrl.MigrateFormerCleanupDefaults(ctx, c, upgrade, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(migrated).To(HaveLen(1))
+		Expect(getCleanup("former")).To(Equal(clv1alpha2.CleanupOptions{StopAfterInactivity: "2h"}))
+		Expect(getCleanup("customized")).To(Equal(clv1alpha2.CleanupOptions{DeleteAfterCreation: "7d"}))
+		Expect(getCleanup("recent")).To(Equal(clv1alpha2.CleanupOptions{StopAfterInactivity: instautoctrl.NeverTimeoutValue}))
+	})
+})
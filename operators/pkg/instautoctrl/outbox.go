@@ -0,0 +1,424 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instautoctrl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils/mail"
+)
+
+const (
+	// DigestMailTemplatePath is the path to the email template for the notification digests.
+	DigestMailTemplatePath = "instautoctrl_notification_digest.yaml"
+
+	// OutboxComponentValue is the value of the component label identifying the notification outbox ConfigMaps.
+	OutboxComponentValue = "notification-outbox"
+	// OutboxAttemptsAnnotation -> the number of consecutive failed attempts to deliver the digest of an outbox.
+	OutboxAttemptsAnnotation = "crownlabs.polito.it/outbox-delivery-attempts"
+	// OutboxNextAttemptAnnotation -> timestamp before which the delivery of the digest of an outbox is not retried.
+	OutboxNextAttemptAnnotation = "crownlabs.polito.it/outbox-next-attempt"
+
+	outboxNamePrefix = "crownlabs-outbox-"
+
+	// outboxDeadlineCheckInterval is the interval at which the outboxes are checked for notifications that cannot wait
+	// for the next digest, as the corresponding automated action would be performed in the meanwhile.
+	outboxDeadlineCheckInterval = time.Minute
+)
+
+// OutboxEntry is a notification waiting in the outbox to be delivered as part of a digest.
+type OutboxEntry struct {
+	Template      string    `json:"template"`
+	Namespace     string    `json:"namespace"`
+	InstanceName  string    `json:"instanceName"`
+	PrettyName    string    `json:"prettyName,omitempty"`
+	RemainingTime string    `json:"remainingTime,omitempty"`
+	DeferralNote  string    `json:"deferralNote,omitempty"`
+	Occurrences   int       `json:"occurrences"`
+	FirstQueued   time.Time `json:"firstQueued"`
+	LastQueued    time.Time `json:"lastQueued"`
+	// Deadline is the time at which the notified action is performed, before which the notification must be delivered.
+	Deadline time.Time `json:"deadline,omitzero"`
+}
+
+// NotificationOutbox is a ConfigMap-backed queue of the notifications generated by the automation controllers.
+// Notifications are grouped in one ConfigMap per tenant, and repeated notifications of the same kind for the
+// same instance are deduplicated into a single entry, which is then delivered by the NotificationDispatcher.
+type NotificationOutbox struct {
+	client.Client
+	Namespace string
+}
+
+// DigestSender is the interface used by the NotificationDispatcher to deliver the digests.
+type DigestSender interface {
+	SendCrownLabsMail(ctx context.Context, emailContentTemplatePath string, ph *mail.Placeholders) error
+}
+
+// NotificationDispatcher periodically delivers the content of the notification outboxes as per-tenant digests.
+type NotificationDispatcher struct {
+	client.Client
+	Sender         DigestSender
+	Namespace      string
+	DigestInterval time.Duration
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+// OutboxName returns the name of the outbox ConfigMap associated with the given tenant.
+func OutboxName(tenantName string) string {
+	return outboxNamePrefix + tenantName
+}
+
+// outboxEntryKey returns the key identifying a notification in the outbox, which is used for deduplication.
+func outboxEntryKey(template, namespace, instanceName string) string {
+	return fmt.Sprintf("%s.%s.%s", strings.TrimSuffix(template, ".yaml"), namespace, instanceName)
+}
+
+// Enqueue adds the notification to the outbox of the given tenant, merging it with an already queued
+// notification of the same kind for the same instance, if any.
+func (o *NotificationOutbox) Enqueue(ctx context.Context, tenant *clv1alpha2.Tenant, instance *clv1alpha2.Instance,
+	mailTemplatePath string, remainingTime time.Duration, deferralNote string) error {
+	log := ctrl.LoggerFrom(ctx).WithName("notification-outbox")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	key := outboxEntryKey(mailTemplatePath, instance.Namespace, instance.Name)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var cm corev1.ConfigMap
+		cm.SetName(OutboxName(tenant.Name))
+		cm.SetNamespace(o.Namespace)
+
+		err := o.Get(ctx, client.ObjectKeyFromObject(&cm), &cm)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+		create := kerrors.IsNotFound(err)
+
+		if cm.Labels == nil {
+			cm.Labels = make(map[string]string)
+		}
+		cm.Labels[forge.LabelComponentKey] = OutboxComponentValue
+		cm.Labels[forge.LabelTenantKey] = tenant.Name
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+
+		entry := OutboxEntry{
+			Template:     mailTemplatePath,
+			Namespace:    instance.Namespace,
+			InstanceName: instance.Name,
+			FirstQueued:  now,
+		}
+		if raw, ok := cm.Data[key]; ok {
+			if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+				log.Error(err, "discarding malformed outbox entry", "key", key)
+				entry.FirstQueued = now
+				entry.Occurrences = 0
+			}
+		}
+		entry.PrettyName = instance.Spec.PrettyName
+		entry.RemainingTime, entry.Deadline = "", time.Time{}
+		if remainingTime > 0 {
+			entry.RemainingTime = remainingTime.String()
+			entry.Deadline = now.Add(remainingTime)
+		}
+		entry.DeferralNote = deferralNote
+		entry.Occurrences++
+		entry.LastQueued = now
+
+		raw, err := json.Marshal(&entry)
+		if err != nil {
+			return err
+		}
+		cm.Data[key] = string(raw)
+
+		if create {
+			return o.Create(ctx, &cm)
+		}
+		return o.Update(ctx, &cm)
+	})
+	if err != nil {
+		log.Error(err, "failed enqueueing notification", "tenant", tenant.Name, "key", key)
+		return fmt.Errorf("failed enqueueing notification for tenant %s: %w", tenant.Name, err)
+	}
+
+	log.Info("notification enqueued", "tenant", tenant.Name, "key", key)
+	return nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, to avoid sending duplicated digests.
+func (d *NotificationDispatcher) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements the Runnable interface, delivering the pending digests every DigestInterval.
+// In the meanwhile, the digests including notifications whose deadline precedes the next delivery are
+// sent in advance, so that an action is never notified after having been performed.
+func (d *NotificationDispatcher) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("notification-dispatcher")
+	log.Info("starting the notification dispatcher", "interval", d.DigestInterval)
+
+	ticker := time.NewTicker(min(d.DigestInterval, outboxDeadlineCheckInterval))
+	defer ticker.Stop()
+	nextDigest := time.Now().Add(d.DigestInterval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("stopping the notification dispatcher")
+			return nil
+		case now := <-ticker.C:
+			if now.Before(nextDigest) {
+				if err := d.DispatchExpiring(ctx, nextDigest); err != nil {
+					log.Error(err, "failed dispatching expiring notification digests")
+				}
+				continue
+			}
+
+			nextDigest = now.Add(d.DigestInterval)
+			if err := d.Dispatch(ctx); err != nil {
+				log.Error(err, "failed dispatching notification digests")
+			}
+		}
+	}
+}
+
+// Dispatch delivers a digest for each tenant with pending notifications.
+func (d *NotificationDispatcher) Dispatch(ctx context.Context) error {
+	return d.dispatch(ctx, func(*corev1.ConfigMap) bool { return true })
+}
+
+// DispatchExpiring delivers a digest for each tenant with pending notifications whose deadline precedes the given time.
+func (d *NotificationDispatcher) DispatchExpiring(ctx context.Context, before time.Time) error {
+	return d.dispatch(ctx, func(outbox *corev1.ConfigMap) bool {
+		return slices.ContainsFunc(ParseOutboxEntries(ctx, outbox), func(entry OutboxEntry) bool {
+			return !entry.Deadline.IsZero() && entry.Deadline.Before(before)
+		})
+	})
+}
+
+// dispatch delivers the digest of each outbox matching the given filter.
+func (d *NotificationDispatcher) dispatch(ctx context.Context, filter func(*corev1.ConfigMap) bool) error {
+	var outboxes corev1.ConfigMapList
+	if err := d.List(ctx, &outboxes, client.InNamespace(d.Namespace),
+		client.MatchingLabels{forge.LabelComponentKey: OutboxComponentValue}); err != nil {
+		return fmt.Errorf("failed listing notification outboxes: %w", err)
+	}
+
+	var errs []error
+	for i := range outboxes.Items {
+		if !filter(&outboxes.Items[i]) {
+			continue
+		}
+		if err := d.DispatchOutbox(ctx, &outboxes.Items[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DispatchOutbox delivers the digest of a single outbox, applying an exponential backoff in case of failures.
+func (d *NotificationDispatcher) DispatchOutbox(ctx context.Context, outbox *corev1.ConfigMap) error {
+	tenantName := outbox.Labels[forge.LabelTenantKey]
+	log := ctrl.LoggerFrom(ctx).WithName("notification-dispatcher").WithValues("tenant", tenantName)
+	dbgLog := log.V(utils.LogDebugLevel)
+
+	if len(outbox.Data) == 0 {
+		return client.IgnoreNotFound(d.Delete(ctx, outbox))
+	}
+
+	if nextAttempt, err := time.Parse(time.RFC3339, outbox.Annotations[OutboxNextAttemptAnnotation]); err == nil && time.Now().Before(nextAttempt) {
+		dbgLog.Info("digest delivery postponed due to previous failures", "nextAttempt", nextAttempt)
+		return nil
+	}
+
+	var tenant clv1alpha2.Tenant
+	if err := d.Get(ctx, types.NamespacedName{Name: tenantName}, &tenant); err != nil {
+		if kerrors.IsNotFound(err) {
+			log.Info("tenant not found, dropping the pending notifications")
+			return client.IgnoreNotFound(d.Delete(ctx, outbox))
+		}
+		return fmt.Errorf("failed retrieving tenant %s: %w", tenantName, err)
+	}
+
+	entries := ParseOutboxEntries(ctx, outbox)
+	if len(entries) == 0 {
+		return client.IgnoreNotFound(d.Delete(ctx, outbox))
+	}
+
+	plaintext, htmlContent := FormatDigest(entries)
+	ph := mail.Placeholders{
+		TenantName:      tenant.Name,
+		TenantEmail:     tenant.Spec.Email,
+		DigestPlaintext: plaintext,
+		DigestHTML:      htmlContent,
+		DigestCount:     strconv.Itoa(len(entries)),
+	}
+
+	if err := d.Sender.SendCrownLabsMail(ctx, DigestMailTemplatePath, &ph); err != nil {
+		log.Error(err, "failed sending notification digest")
+		if markErr := d.markFailure(ctx, outbox); markErr != nil {
+			log.Error(markErr, "failed recording the digest delivery failure")
+		}
+		return fmt.Errorf("failed sending digest to tenant %s: %w", tenantName, err)
+	}
+
+	log.Info("notification digest sent", "email", tenant.Spec.Email, "notifications", len(entries))
+	return d.removeDelivered(ctx, outbox, entries)
+}
+
+// markFailure records a failed delivery attempt and schedules the next one with an exponential backoff.
+func (d *NotificationDispatcher) markFailure(ctx context.Context, outbox *corev1.ConfigMap) error {
+	attempts, _ := strconv.Atoi(outbox.Annotations[OutboxAttemptsAnnotation])
+	attempts++
+
+	patch := client.MergeFrom(outbox.DeepCopy())
+	if outbox.Annotations == nil {
+		outbox.Annotations = make(map[string]string)
+	}
+	outbox.Annotations[OutboxAttemptsAnnotation] = strconv.Itoa(attempts)
+	outbox.Annotations[OutboxNextAttemptAnnotation] = time.Now().Add(d.RetryDelay(attempts)).Format(time.RFC3339)
+	return d.Patch(ctx, outbox, patch)
+}
+
+// RetryDelay returns the delay before the next delivery attempt, given the number of failed attempts.
+func (d *NotificationDispatcher) RetryDelay(attempts int) time.Duration {
+	delay := d.RetryBaseDelay
+	for i := 1; i < attempts && delay < d.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > d.RetryMaxDelay {
+		delay = d.RetryMaxDelay
+	}
+	return delay
+}
+
+// removeDelivered removes the delivered entries from the outbox, preserving those updated in the meanwhile.
+func (d *NotificationDispatcher) removeDelivered(ctx context.Context, outbox *corev1.ConfigMap, delivered []OutboxEntry) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var current corev1.ConfigMap
+		if err := d.Get(ctx, client.ObjectKeyFromObject(outbox), &current); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+
+		for i := range delivered {
+			entry := &delivered[i]
+			key := outboxEntryKey(entry.Template, entry.Namespace, entry.InstanceName)
+			var stored OutboxEntry
+			if err := json.Unmarshal([]byte(current.Data[key]), &stored); err != nil || !stored.LastQueued.After(entry.LastQueued) {
+				delete(current.Data, key)
+			}
+		}
+
+		if len(current.Data) == 0 {
+			return client.IgnoreNotFound(d.Delete(ctx, &current, client.Preconditions{ResourceVersion: &current.ResourceVersion}))
+		}
+
+		delete(current.Annotations, OutboxAttemptsAnnotation)
+		delete(current.Annotations, OutboxNextAttemptAnnotation)
+		return d.Update(ctx, &current)
+	})
+}
+
+// ParseOutboxEntries returns the entries stored in the outbox, sorted by namespace, instance and queuing time.
+func ParseOutboxEntries(ctx context.Context, outbox *corev1.ConfigMap) []OutboxEntry {
+	log := ctrl.LoggerFrom(ctx)
+
+	entries := make([]OutboxEntry, 0, len(outbox.Data))
+	for key, raw := range outbox.Data {
+		var entry OutboxEntry
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			log.Error(err, "skipping malformed outbox entry", "outbox", outbox.Name, "key", key)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Namespace != entries[j].Namespace {
+			return entries[i].Namespace < entries[j].Namespace
+		}
+		if entries[i].InstanceName != entries[j].InstanceName {
+			return entries[i].InstanceName < entries[j].InstanceName
+		}
+		return entries[i].FirstQueued.Before(entries[j].FirstQueued)
+	})
+	return entries
+}
+
+// FormatDigest renders the given entries as a plaintext and an HTML list.
+func FormatDigest(entries []OutboxEntry) (plaintext, htmlContent string) {
+	var pt, ht strings.Builder
+	ht.WriteString("<ul>")
+	for i := range entries {
+		entry := &entries[i]
+		name := entry.PrettyName
+		if name == "" {
+			name = entry.InstanceName
+		}
+		description := describeNotification(entry)
+		if entry.Occurrences > 1 {
+			description += fmt.Sprintf(" (reported %d times)", entry.Occurrences)
+		}
+		if entry.DeferralNote != "" {
+			description += ". " + entry.DeferralNote
+		}
+
+		fmt.Fprintf(&pt, "- %s: %s\n", name, description)
+		fmt.Fprintf(&ht, "<li><strong>%s</strong>: %s</li>", html.EscapeString(name), html.EscapeString(description))
+	}
+	ht.WriteString("</ul>")
+	return strings.TrimSuffix(pt.String(), "\n"), ht.String()
+}
+
+// describeNotification returns a human-readable description of the given notification.
+func describeNotification(entry *OutboxEntry) string {
+	switch entry.Template {
+	case InactivityDetectedMailTemplatePath:
+		return fmt.Sprintf("inactive, it will be paused/deleted if no activity is detected in the next %s", entry.RemainingTime)
+	case InactivityTerminatedMailTemplatePath:
+		return "paused/deleted due to prolonged inactivity"
+	case WarningExpirationMailTemplatePath:
+		return fmt.Sprintf("expiring, it will be deleted in %s", entry.RemainingTime)
+	case ExpirationMailTemplatePath:
+		return "deleted because it reached its maximum lifetime"
+	case WarningDestructionMailTemplatePath:
+		return fmt.Sprintf("powered off for a long time, it will be deleted in %s", entry.RemainingTime)
+	case DestructionMailTemplatePath:
+		return "deleted after being powered off for a long time"
+	default:
+		return strings.TrimSuffix(entry.Template, ".yaml")
+	}
+}
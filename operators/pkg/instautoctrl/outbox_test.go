@@ -0,0 +1,176 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instautoctrl_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/instautoctrl"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils/mail"
+)
+
+type fakeDigestSender struct {
+	err  error
+	sent []mail.Placeholders
+}
+
+func (s *fakeDigestSender) SendCrownLabsMail(_ context.Context, _ string, ph *mail.Placeholders) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, *ph)
+	return nil
+}
+
+var _ = Describe("Notification outbox", func() {
+	const (
+		outboxNamespace = "crownlabs-outbox"
+		tenantName      = "tester"
+	)
+
+	var (
+		ctx        context.Context
+		c          client.Client
+		outbox     *instautoctrl.NotificationOutbox
+		dispatcher *instautoctrl.NotificationDispatcher
+		sender     *fakeDigestSender
+		tenant     *clv1alpha2.Tenant
+		instances  []*clv1alpha2.Instance
+	)
+
+	getOutbox := func() (*corev1.ConfigMap, error) {
+		var cm corev1.ConfigMap
+		err := c.Get(ctx, types.NamespacedName{Namespace: outboxNamespace, Name: instautoctrl.OutboxName(tenantName)}, &cm)
+		return &cm, err
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(clv1alpha2.AddToScheme(scheme)).To(Succeed())
+
+		tenant = &clv1alpha2.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: tenantName},
+			Spec:       clv1alpha2.TenantSpec{Email: "tester@example.com"},
+		}
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(tenant).Build()
+
+		instances = nil
+		for _, name := range []string{"first", "second"} {
+			instances = append(instances, &clv1alpha2.Instance{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenant-" + tenantName},
+				Spec:       clv1alpha2.InstanceSpec{PrettyName: "Pretty " + name},
+			})
+		}
+
+		sender = &fakeDigestSender{}
+		outbox = &instautoctrl.NotificationOutbox{Client: c, Namespace: outboxNamespace}
+		dispatcher = &instautoctrl.NotificationDispatcher{
+			Client:         c,
+			Sender:         sender,
+			Namespace:      outboxNamespace,
+			DigestInterval: time.Hour,
+			RetryBaseDelay: time.Minute,
+			RetryMaxDelay:  10 * time.Minute,
+		}
+	})
+
+	It("Should deduplicate repeated notifications for the same instance", func() {
+		Expect(outbox.Enqueue(ctx, tenant, instances[0], instautoctrl.InactivityDetectedMailTemplatePath, 2*time.Hour, "")).To(Succeed())
+		Expect(outbox.Enqueue(ctx, tenant, instances[0], instautoctrl.InactivityDetectedMailTemplatePath, time.Hour, "")).To(Succeed())
+		Expect(outbox.Enqueue(ctx, tenant, instances[1], instautoctrl.WarningExpirationMailTemplatePath, time.Hour, "")).To(Succeed())
+
+		cm, err := getOutbox()
+		Expect(err).ToNot(HaveOccurred())
+
+		entries := instautoctrl.ParseOutboxEntries(ctx, cm)
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].InstanceName).To(Equal("first"))
+		Expect(entries[0].Occurrences).To(Equal(2))
+		Expect(entries[0].RemainingTime).To(Equal(time.Hour.String()))
+		Expect(entries[1].InstanceName).To(Equal("second"))
+		Expect(entries[1].Occurrences).To(Equal(1))
+	})
+
+	It("Should send a single digest per tenant and empty the outbox", func() {
+		Expect(outbox.Enqueue(ctx, tenant, instances[0], instautoctrl.InactivityDetectedMailTemplatePath, time.Hour, "")).To(Succeed())
+		Expect(outbox.Enqueue(ctx, tenant, instances[1], instautoctrl.ExpirationMailTemplatePath, 0, "")).To(Succeed())
+
+		Expect(dispatcher.Dispatch(ctx)).To(Succeed())
+
+		Expect(sender.sent).To(HaveLen(1))
+		Expect(sender.sent[0].TenantEmail).To(Equal("tester@example.com"))
+		Expect(sender.sent[0].DigestCount).To(Equal("2"))
+		Expect(sender.sent[0].DigestPlaintext).To(ContainSubstring("Pretty first"))
+		Expect(sender.sent[0].DigestHTML).To(ContainSubstring("<strong>Pretty second</strong>"))
+
+		_, err := getOutbox()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should deliver in advance only the notifications whose action precedes the next digest", func() {
+		Expect(outbox.Enqueue(ctx, tenant, instances[0], instautoctrl.InactivityDetectedMailTemplatePath, 24*time.Hour, "")).To(Succeed())
+
+		Expect(dispatcher.DispatchExpiring(ctx, time.Now().Add(time.Hour))).To(Succeed())
+		Expect(sender.sent).To(BeEmpty())
+
+		Expect(outbox.Enqueue(ctx, tenant, instances[1], instautoctrl.WarningDestructionMailTemplatePath, 30*time.Minute, "")).To(Succeed())
+
+		Expect(dispatcher.DispatchExpiring(ctx, time.Now().Add(time.Hour))).To(Succeed())
+		Expect(sender.sent).To(HaveLen(1))
+		Expect(sender.sent[0].DigestCount).To(Equal("2"))
+
+		_, err := getOutbox()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should postpone the delivery with a backoff in case of failures", func() {
+		Expect(outbox.Enqueue(ctx, tenant, instances[0], instautoctrl.InactivityDetectedMailTemplatePath, time.Hour, "")).To(Succeed())
+
+		sender.err = errors.New("smtp unavailable")
+		Expect(dispatcher.Dispatch(ctx)).ToNot(Succeed())
+
+		cm, err := getOutbox()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cm.Annotations).To(HaveKeyWithValue(instautoctrl.OutboxAttemptsAnnotation, "1"))
+		Expect(cm.Annotations).To(HaveKey(instautoctrl.OutboxNextAttemptAnnotation))
+
+		// The next attempt is postponed, hence nothing is sent even if the SMTP server is back.
+		sender.err = nil
+		Expect(dispatcher.Dispatch(ctx)).To(Succeed())
+		Expect(sender.sent).To(BeEmpty())
+	})
+
+	It("Should compute an exponential and bounded retry delay", func() {
+		Expect(dispatcher.RetryDelay(1)).To(Equal(time.Minute))
+		Expect(dispatcher.RetryDelay(2)).To(Equal(2 * time.Minute))
+		Expect(dispatcher.RetryDelay(4)).To(Equal(8 * time.Minute))
+		Expect(dispatcher.RetryDelay(10)).To(Equal(10 * time.Minute))
+	})
+})
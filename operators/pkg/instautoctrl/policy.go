@@ -0,0 +1,314 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instautoctrl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+)
+
+type policyCtxKey struct{}
+
+// maxDeferralSteps is the maximum number of consecutive quiet windows and holidays an action can be deferred through.
+const maxDeferralSteps = 100
+
+// AutomationSchedule groups the global settings constraining when the automated actions can be performed.
+type AutomationSchedule struct {
+	QuietHours []clv1alpha1.QuietHoursWindow
+	Location   *time.Location
+	Holidays   *HolidayCalendar
+}
+
+// AutomationPolicy is the effective automation policy applied to an Instance, which is resolved
+// hierarchically from the Template, the automation policy of the Workspace and the global configuration.
+type AutomationPolicy struct {
+	DeleteAfterCreation             string
+	StopAfterInactivity             string
+	DeleteAfterInactivity           string
+	MaxNumberOfAlerts               int
+	InactivityNotificationInterval  time.Duration
+	DestructionNotificationInterval time.Duration
+	ExpirationNotificationInterval  time.Duration
+	QuietHours                      []clv1alpha1.QuietHoursWindow
+	Holidays                        *HolidayCalendar
+	Location                        *time.Location
+	DryRun                          bool
+}
+
+// ResolveAutomationPolicy computes the effective automation policy, giving precedence to the settings of the
+// Template, then to the ones of the Workspace automation policy (if any) and finally to the given defaults.
+func ResolveAutomationPolicy(template *clv1alpha2.Template, workspace *clv1alpha1.Workspace, defaults AutomationPolicy) (AutomationPolicy, error) {
+	policy := defaults
+	if policy.DeleteAfterCreation == "" {
+		policy.DeleteAfterCreation = NeverTimeoutValue
+	}
+	if policy.StopAfterInactivity == "" {
+		policy.StopAfterInactivity = NeverTimeoutValue
+	}
+	if policy.DeleteAfterInactivity == "" {
+		policy.DeleteAfterInactivity = NeverTimeoutValue
+	}
+	if policy.Location == nil {
+		policy.Location = time.UTC
+	}
+
+	if workspace != nil && workspace.Spec.AutomationPolicy != nil {
+		wsPolicy := workspace.Spec.AutomationPolicy
+		overrideString(&policy.DeleteAfterCreation, wsPolicy.DeleteAfterCreation)
+		overrideString(&policy.StopAfterInactivity, wsPolicy.StopAfterInactivity)
+		overrideString(&policy.DeleteAfterInactivity, wsPolicy.DeleteAfterInactivity)
+		if wsPolicy.MaxNumberOfAlerts != nil {
+			policy.MaxNumberOfAlerts = *wsPolicy.MaxNumberOfAlerts
+		}
+		if wsPolicy.InactivityNotificationInterval != nil {
+			policy.InactivityNotificationInterval = wsPolicy.InactivityNotificationInterval.Duration
+		}
+		if wsPolicy.DestructionNotificationInterval != nil {
+			policy.DestructionNotificationInterval = wsPolicy.DestructionNotificationInterval.Duration
+		}
+		if wsPolicy.ExpirationNotificationInterval != nil {
+			policy.ExpirationNotificationInterval = wsPolicy.ExpirationNotificationInterval.Duration
+		}
+		// The quiet hours and the holidays of the Workspace extend the global ones, rather than replacing them,
+		// as they can only further constrain the automated actions.
+		if len(wsPolicy.QuietHours) > 0 {
+			policy.QuietHours = slices.Concat(policy.QuietHours, wsPolicy.QuietHours)
+		}
+		if len(wsPolicy.Holidays) > 0 {
+			holidays, err := HolidayCalendarFromPeriods(wsPolicy.Holidays)
+			if err != nil {
+				return defaults, fmt.Errorf("invalid holidays in the automation policy of workspace %s: %w", workspace.Name, err)
+			}
+			policy.Holidays = policy.Holidays.Merge(holidays)
+		}
+		if wsPolicy.TimeZone != "" {
+			location, err := time.LoadLocation(wsPolicy.TimeZone)
+			if err != nil {
+				return defaults, fmt.Errorf("invalid time zone %q in the automation policy of workspace %s: %w", wsPolicy.TimeZone, workspace.Name, err)
+			}
+			policy.Location = location
+		}
+	}
+
+	if template != nil {
+		overrideString(&policy.DeleteAfterCreation, template.Spec.Cleanup.DeleteAfterCreation)
+		overrideString(&policy.StopAfterInactivity, template.Spec.Cleanup.StopAfterInactivity)
+		overrideString(&policy.DeleteAfterInactivity, template.Spec.Cleanup.DeleteAfterInactivity)
+		// if the CustomNumberOfAlertsAnnotation is set, override the max alerts
+		if customMaxAlertsStr, ok := template.Annotations[forge.CustomNumberOfAlertsAnnotation]; ok {
+			if customMaxAlerts, err := strconv.Atoi(customMaxAlertsStr); err == nil {
+				policy.MaxNumberOfAlerts = customMaxAlerts
+			}
+		}
+	}
+
+	return policy, nil
+}
+
+func overrideString(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
+
+// InQuietHours returns whether the given time falls within the quiet hours of the policy,
+// and if so, the time at which the current quiet window ends.
+func (p *AutomationPolicy) InQuietHours(t time.Time) (quiet bool, end time.Time) {
+	location := p.Location
+	if location == nil {
+		location = time.UTC
+	}
+	t = t.In(location)
+
+	for i := range p.QuietHours {
+		window := &p.QuietHours[i]
+		start, errStart := parseClock(window.Start)
+		stop, errStop := parseClock(window.End)
+		if errStart != nil || errStop != nil {
+			continue
+		}
+
+		// Check the window starting today, and the one started yesterday (if spanning midnight).
+		for _, offset := range []int{0, -1} {
+			day := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, location)
+			if !windowAppliesTo(window, day.Weekday()) {
+				continue
+			}
+
+			windowStart := day.Add(start)
+			windowEnd := day.Add(stop)
+			if stop <= start {
+				windowEnd = windowEnd.Add(24 * time.Hour)
+			}
+
+			if !t.Before(windowStart) && t.Before(windowEnd) && windowEnd.After(end) {
+				quiet, end = true, windowEnd
+			}
+		}
+	}
+
+	return quiet, end
+}
+
+// NextAllowedTime returns the first instant, not before the given one, at which the policy allows automated actions
+// (i.e., outside quiet hours and holidays), along with a human-readable reason if the action has been deferred.
+func (p *AutomationPolicy) NextAllowedTime(t time.Time) (allowed time.Time, reason string) {
+	location := p.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	for range maxDeferralSteps {
+		if quiet, end := p.InQuietHours(t); quiet {
+			t, reason = end, "quiet hours"
+			continue
+		}
+		if holiday, end, found := p.Holidays.Covering(t, location); found {
+			t, reason = end, "holidays"
+			if holiday.Name != "" {
+				reason = fmt.Sprintf("holidays (%s)", holiday.Name)
+			}
+			continue
+		}
+		break
+	}
+	return t, reason
+}
+
+// DeferAction returns the delay after which an automated action scheduled after the given delay can actually be
+// performed according to the policy, along with a note for the tenant if it has been deferred.
+func (p *AutomationPolicy) DeferAction(now time.Time, delay time.Duration) (time.Duration, string) {
+	scheduled := now.Add(delay)
+	allowed, reason := p.NextAllowedTime(scheduled)
+	if !allowed.After(scheduled) {
+		return delay, ""
+	}
+
+	location := p.Location
+	if location == nil {
+		location = time.UTC
+	}
+	note := fmt.Sprintf("The action has been postponed to %s due to %s.", allowed.In(location).Format(time.RFC1123), reason)
+	return allowed.Sub(now), note
+}
+
+// ParseQuietHours parses a comma-separated list of daily windows in the HH:MM-HH:MM format.
+func ParseQuietHours(value string) ([]clv1alpha1.QuietHoursWindow, error) {
+	var windows []clv1alpha1.QuietHoursWindow
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		start, end, found := strings.Cut(item, "-")
+		if !found {
+			return nil, fmt.Errorf("invalid quiet hours window %q: expected the HH:MM-HH:MM format", item)
+		}
+		window := clv1alpha1.QuietHoursWindow{Start: strings.TrimSpace(start), End: strings.TrimSpace(end)}
+		if _, err := parseClock(window.Start); err != nil {
+			return nil, err
+		}
+		if _, err := parseClock(window.End); err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// parseClock parses a time of the day in the HH:MM format, returning the corresponding offset from midnight.
+func parseClock(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of the day %q: %w", value, err)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+func windowAppliesTo(window *clv1alpha1.QuietHoursWindow, weekday time.Weekday) bool {
+	if len(window.Days) == 0 {
+		return true
+	}
+	for _, day := range window.Days {
+		if string(day) == weekday.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyInto returns a copy of the context with the given automation policy embedded.
+func PolicyInto(ctx context.Context, policy *AutomationPolicy) context.Context {
+	return context.WithValue(ctx, policyCtxKey{}, policy)
+}
+
+// PolicyFrom retrieves the automation policy from the given context, if any.
+func PolicyFrom(ctx context.Context) *AutomationPolicy {
+	policy, _ := ctx.Value(policyCtxKey{}).(*AutomationPolicy)
+	return policy
+}
+
+// GetTemplateWorkspace retrieves the Workspace the given Template belongs to, returning nil if not found.
+func GetTemplateWorkspace(ctx context.Context, c client.Client, template *clv1alpha2.Template) (*clv1alpha1.Workspace, error) {
+	if template.Spec.WorkspaceRef.Name == "" {
+		return nil, nil
+	}
+
+	var workspace clv1alpha1.Workspace
+	if err := c.Get(ctx, types.NamespacedName{Name: template.Spec.WorkspaceRef.Name}, &workspace); err != nil {
+		if kerrors.IsNotFound(err) {
+			ctrl.LoggerFrom(ctx).Info("workspace not found, ignoring the workspace automation policy", "workspace", template.Spec.WorkspaceRef.Name)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed retrieving workspace %s: %w", template.Spec.WorkspaceRef.Name, err)
+	}
+	return &workspace, nil
+}
+
+// createWorkspaceWatchHandler creates a workspace watch handler enqueuing all the instances belonging to the workspace.
+func createWorkspaceWatchHandler(c client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		var requests []reconcile.Request
+
+		var instances clv1alpha2.InstanceList
+		if err := c.List(ctx, &instances, client.MatchingLabels{forge.LabelWorkspaceKey: obj.GetName()}); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed listing instances for workspace", "workspace", obj.GetName())
+			return requests
+		}
+
+		for i := range instances.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: forge.NamespacedNameFromObject(&instances.Items[i]),
+			})
+		}
+		return requests
+	})
+}
@@ -0,0 +1,203 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instautoctrl_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/instautoctrl"
+)
+
+var _ = Describe("Automation policy", func() {
+	var (
+		template  *clv1alpha2.Template
+		workspace *clv1alpha1.Workspace
+		defaults  instautoctrl.AutomationPolicy
+	)
+
+	BeforeEach(func() {
+		template = &clv1alpha2.Template{ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "workspace-test"}}
+		workspace = &clv1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+		defaults = instautoctrl.AutomationPolicy{
+			MaxNumberOfAlerts:              3,
+			InactivityNotificationInterval: 24 * time.Hour,
+			ExpirationNotificationInterval: 24 * time.Hour,
+		}
+	})
+
+	Describe("The ResolveAutomationPolicy function", func() {
+		It("Should fall back to the defaults when no policy is set", func() {
+			policy, err := instautoctrl.ResolveAutomationPolicy(template, workspace, defaults)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.DeleteAfterCreation).To(Equal(instautoctrl.NeverTimeoutValue))
+			Expect(policy.StopAfterInactivity).To(Equal(instautoctrl.NeverTimeoutValue))
+			Expect(policy.DeleteAfterInactivity).To(Equal(instautoctrl.NeverTimeoutValue))
+			Expect(policy.MaxNumberOfAlerts).To(Equal(3))
+			Expect(policy.Location).To(Equal(time.UTC))
+		})
+
+		It("Should give precedence to the template over the workspace policy", func() {
+			workspace.Spec.AutomationPolicy = &clv1alpha1.WorkspaceAutomationPolicy{
+				DeleteAfterCreation:            "7d",
+				StopAfterInactivity:            "12h",
+				MaxNumberOfAlerts:              ptr.To(1),
+				InactivityNotificationInterval: &metav1.Duration{Duration: time.Hour},
+			}
+			template.Spec.Cleanup.StopAfterInactivity = "2h"
+			template.Annotations = map[string]string{forge.CustomNumberOfAlertsAnnotation: "5"}
+
+			policy, err := instautoctrl.ResolveAutomationPolicy(template, workspace, defaults)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.DeleteAfterCreation).To(Equal("7d"))
+			Expect(policy.StopAfterInactivity).To(Equal("2h"))
+			Expect(policy.DeleteAfterInactivity).To(Equal(instautoctrl.NeverTimeoutValue))
+			Expect(policy.MaxNumberOfAlerts).To(Equal(5))
+			Expect(policy.InactivityNotificationInterval).To(Equal(time.Hour))
+			Expect(policy.ExpirationNotificationInterval).To(Equal(24 * time.Hour))
+		})
+
+		It("Should let the template opt out of the workspace policy with never", func() {
+			workspace.Spec.AutomationPolicy = &clv1alpha1.WorkspaceAutomationPolicy{
+				DeleteAfterCreation:   "7d",
+				StopAfterInactivity:   "12h",
+				DeleteAfterInactivity: "2d",
+			}
+			template.Spec.Cleanup = clv1alpha2.CleanupOptions{
+				DeleteAfterCreation: instautoctrl.NeverTimeoutValue,
+				StopAfterInactivity: instautoctrl.NeverTimeoutValue,
+			}
+
+			policy, err := instautoctrl.ResolveAutomationPolicy(template, workspace, defaults)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.DeleteAfterCreation).To(Equal(instautoctrl.NeverTimeoutValue))
+			Expect(policy.StopAfterInactivity).To(Equal(instautoctrl.NeverTimeoutValue))
+			Expect(policy.DeleteAfterInactivity).To(Equal("2d"))
+		})
+
+		It("Should extend the global quiet hours and holidays with the workspace ones", func() {
+			defaults.QuietHours = []clv1alpha1.QuietHoursWindow{{Start: "22:00", End: "07:00"}}
+			defaults.Holidays = &instautoctrl.HolidayCalendar{Holidays: []instautoctrl.Holiday{{Name: "Global"}}}
+			workspace.Spec.AutomationPolicy = &clv1alpha1.WorkspaceAutomationPolicy{
+				QuietHours: []clv1alpha1.QuietHoursWindow{{Start: "12:00", End: "14:00"}},
+				Holidays:   []clv1alpha1.HolidayPeriod{{Name: "Workspace", Start: "2026-12-24", End: "2026-12-26"}},
+			}
+
+			policy, err := instautoctrl.ResolveAutomationPolicy(template, workspace, defaults)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.QuietHours).To(Equal([]clv1alpha1.QuietHoursWindow{{Start: "22:00", End: "07:00"}, {Start: "12:00", End: "14:00"}}))
+			Expect(policy.Holidays.Holidays).To(HaveLen(2))
+			Expect(defaults.QuietHours).To(HaveLen(1))
+		})
+
+		It("Should fail in case of an invalid time zone", func() {
+			workspace.Spec.AutomationPolicy = &clv1alpha1.WorkspaceAutomationPolicy{TimeZone: "Invalid/Zone"}
+			_, err := instautoctrl.ResolveAutomationPolicy(template, workspace, defaults)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("The InQuietHours function", func() {
+		var policy instautoctrl.AutomationPolicy
+
+		BeforeEach(func() {
+			policy = instautoctrl.AutomationPolicy{
+				Location: time.UTC,
+				QuietHours: []clv1alpha1.QuietHoursWindow{
+					{Start: "22:00", End: "07:00"},
+					{Start: "12:00", End: "14:00", Days: []clv1alpha1.Weekday{clv1alpha1.Saturday}},
+				},
+			}
+		})
+
+		DescribeTable("Checking the quiet hours",
+			func(now time.Time, expectedQuiet bool, expectedEnd time.Time) {
+				quiet, end := policy.InQuietHours(now)
+				Expect(quiet).To(Equal(expectedQuiet))
+				Expect(end).To(Equal(expectedEnd))
+			},
+			// 2026-10-17 is a Saturday.
+			Entry("Before midnight", time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC), true, time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC)),
+			Entry("After midnight", time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC), true, time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC)),
+			Entry("Outside the windows", time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC), false, time.Time{}),
+			Entry("Within a day-specific window", time.Date(2026, 10, 17, 13, 0, 0, 0, time.UTC), true, time.Date(2026, 10, 17, 14, 0, 0, 0, time.UTC)),
+		)
+	})
+
+	Describe("The deferral of automated actions", func() {
+		var policy instautoctrl.AutomationPolicy
+
+		BeforeEach(func() {
+			holidays, err := instautoctrl.HolidayCalendarFromPeriods([]clv1alpha1.HolidayPeriod{
+				{Name: "Christmas break", Start: "2026-12-24", End: "2027-01-06"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			policy = instautoctrl.AutomationPolicy{
+				Location:   time.UTC,
+				QuietHours: []clv1alpha1.QuietHoursWindow{{Start: "22:00", End: "07:00"}},
+				Holidays:   holidays,
+			}
+		})
+
+		It("Should not defer actions outside quiet hours and holidays", func() {
+			now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+			allowed, reason := policy.NextAllowedTime(now)
+			Expect(allowed).To(Equal(now))
+			Expect(reason).To(BeEmpty())
+		})
+
+		It("Should defer actions through consecutive quiet hours and holidays", func() {
+			allowed, reason := policy.NextAllowedTime(time.Date(2026, 12, 23, 23, 0, 0, 0, time.UTC))
+			// The quiet window ends at 07:00 of the first day of the holidays, and the quiet window
+			// started the evening of the last day of the holidays ends at 07:00 of the following day.
+			Expect(allowed).To(Equal(time.Date(2027, 1, 7, 7, 0, 0, 0, time.UTC)))
+			Expect(reason).To(Equal("quiet hours"))
+		})
+
+		It("Should report the deferral in the notification note", func() {
+			now := time.Date(2026, 12, 22, 12, 0, 0, 0, time.UTC)
+			delay, note := policy.DeferAction(now, 48*time.Hour)
+			Expect(now.Add(delay)).To(Equal(time.Date(2027, 1, 7, 7, 0, 0, 0, time.UTC)))
+			Expect(note).To(ContainSubstring("postponed"))
+
+			delay, note = policy.DeferAction(now, time.Hour)
+			Expect(delay).To(Equal(time.Hour))
+			Expect(note).To(BeEmpty())
+		})
+	})
+
+	Describe("The ParseQuietHours function", func() {
+		It("Should parse a list of windows", func() {
+			windows, err := instautoctrl.ParseQuietHours("22:00-07:00, 12:30-14:00")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(windows).To(Equal([]clv1alpha1.QuietHoursWindow{{Start: "22:00", End: "07:00"}, {Start: "12:30", End: "14:00"}}))
+		})
+
+		It("Should fail in case of malformed windows", func() {
+			_, err := instautoctrl.ParseQuietHours("22:00")
+			Expect(err).To(HaveOccurred())
+			_, err = instautoctrl.ParseQuietHours("25:00-07:00")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,301 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instautoctrl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+)
+
+const (
+	// preTerminationHookJobSuffix is the suffix of the jobs archiving the content of the persistent environments.
+	preTerminationHookJobSuffix = "pre-termination"
+	// preTerminationHookPollInterval is the interval after which the completion of the hooks is checked again.
+	preTerminationHookPollInterval = 10 * time.Second
+)
+
+// PreTerminationHookRunner executes the pre-termination hooks configured in the environments of an Instance,
+// archiving their content before the Instance is automatically stopped or deleted.
+// A nil PreTerminationHookRunner is valid, and corresponds to the hooks being disabled.
+type PreTerminationHookRunner struct {
+	client.Client
+	Scheme           *runtime.Scheme
+	EventsRecorder   record.EventRecorder
+	ContainerEnvOpts forge.ContainerEnvOpts
+	// DefaultTimeout is the maximum time to wait for the hooks, unless overridden by the environment.
+	DefaultTimeout time.Duration
+}
+
+// preTerminationHookState is the state of the hook of a single environment.
+type preTerminationHookState int
+
+const (
+	hookRunning preTerminationHookState = iota
+	hookSucceeded
+	hookFailed
+	hookSkipped
+)
+
+// Run executes the pre-termination hooks of the given instance, returning whether all of them completed
+// (either successfully, with failure or due to timeout), and thus the instance can be stopped or deleted.
+// Otherwise, the caller is expected to requeue the instance after the returned delay.
+func (h *PreTerminationHookRunner) Run(ctx context.Context, instance *clv1alpha2.Instance, template *clv1alpha2.Template) (done bool, requeueAfter time.Duration, err error) {
+	if h == nil || template == nil || !HasPreTerminationHooks(template) {
+		return true, 0, nil
+	}
+	log := ctrl.LoggerFrom(ctx).WithName("pre-termination-hook")
+
+	started, err := h.ensureStartTime(ctx, instance)
+	if err != nil {
+		return false, 0, err
+	}
+
+	done = true
+	timeout := time.Duration(0)
+	for i := range template.Spec.EnvironmentList {
+		environment := &template.Spec.EnvironmentList[i]
+		if environment.PreTerminationHook == nil {
+			continue
+		}
+
+		envTimeout := h.DefaultTimeout
+		if environment.PreTerminationHook.Timeout != nil {
+			envTimeout = environment.PreTerminationHook.Timeout.Duration
+		}
+		timeout = max(timeout, envTimeout)
+
+		state, err := h.runEnvironmentHook(ctx, instance, environment, started)
+		if err != nil {
+			return false, 0, err
+		}
+
+		switch state {
+		case hookRunning:
+			if time.Since(started) < envTimeout {
+				done = false
+				continue
+			}
+			log.Info("pre-termination hook timed out", "environment", environment.Name, "timeout", envTimeout)
+			h.recordEvent(instance, corev1.EventTypeWarning, "PreTerminationHookTimeout",
+				"Pre-termination hook of environment %s did not complete within %s, proceeding anyway", environment.Name, envTimeout)
+		case hookSucceeded:
+			log.Info("pre-termination hook completed", "environment", environment.Name)
+		case hookFailed:
+			h.recordEvent(instance, corev1.EventTypeWarning, "PreTerminationHookFailed",
+				"Pre-termination hook of environment %s failed, proceeding anyway", environment.Name)
+		case hookSkipped:
+			// The environment has nothing to be archived.
+		}
+	}
+
+	if !done {
+		remaining := timeout - time.Since(started)
+		return false, min(max(remaining, 0)+time.Second, preTerminationHookPollInterval), nil
+	}
+
+	// Reset the start time, so that the hooks are executed again in case of future terminations.
+	if err := h.clearStartTime(ctx, instance); err != nil {
+		return false, 0, err
+	}
+	return true, 0, nil
+}
+
+// runEnvironmentHook enforces the hook of a single environment, returning its current state.
+func (h *PreTerminationHookRunner) runEnvironmentHook(ctx context.Context, instance *clv1alpha2.Instance,
+	environment *clv1alpha2.Environment, started time.Time) (preTerminationHookState, error) {
+	log := ctrl.LoggerFrom(ctx).WithName("pre-termination-hook")
+
+	if environment.EnvironmentType != clv1alpha2.ClassContainer && environment.EnvironmentType != clv1alpha2.ClassStandalone {
+		log.Info("pre-termination hook not supported by the environment type", "environment", environment.Name, "type", environment.EnvironmentType)
+		return hookSkipped, nil
+	}
+
+	destination, mountInfos, err := PreTerminationHookDestination(instance, environment)
+	if err != nil {
+		h.recordEvent(instance, corev1.EventTypeWarning, "PreTerminationHookSkipped", "Pre-termination hook of environment %s skipped: %v", environment.Name, err)
+		return hookSkipped, nil
+	}
+
+	pod, err := h.runningPod(ctx, instance, environment)
+	if err != nil {
+		return hookRunning, err
+	}
+
+	switch {
+	case pod != nil:
+		return h.enforceEphemeralContainer(ctx, pod, instance, environment, destination, mountInfos, started)
+	case environment.Persistent:
+		return h.enforceJob(ctx, instance, environment, destination, mountInfos, started)
+	default:
+		// Non-persistent environments which are not running have nothing left to save.
+		log.Info("pre-termination hook skipped, environment not running", "environment", environment.Name)
+		return hookSkipped, nil
+	}
+}
+
+// runningPod returns the running pod of the given environment, if any.
+func (h *PreTerminationHookRunner) runningPod(ctx context.Context, instance *clv1alpha2.Instance, environment *clv1alpha2.Environment) (*corev1.Pod, error) {
+	var pods corev1.PodList
+	if err := h.List(ctx, &pods, client.InNamespace(instance.Namespace), client.MatchingLabels(forge.EnvironmentSelectorLabels(instance, environment))); err != nil {
+		return nil, fmt.Errorf("failed listing pods of environment %s: %w", environment.Name, err)
+	}
+
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning && pods.Items[i].DeletionTimestamp == nil {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// enforceEphemeralContainer ensures the ephemeral container archiving the content of the environment is attached
+// to the running pod, since the content of non-persistent environments is not accessible from other pods.
+func (h *PreTerminationHookRunner) enforceEphemeralContainer(ctx context.Context, pod *corev1.Pod, instance *clv1alpha2.Instance,
+	environment *clv1alpha2.Environment, destination string, mountInfos []corev1.VolumeMount, started time.Time) (preTerminationHookState, error) {
+	for i := range pod.Status.EphemeralContainerStatuses {
+		status := &pod.Status.EphemeralContainerStatuses[i]
+		if status.Name != forge.PreTerminationHookName || status.State.Terminated == nil {
+			continue
+		}
+		if status.State.Terminated.ExitCode == 0 {
+			return hookSucceeded, nil
+		}
+		return hookFailed, nil
+	}
+
+	for i := range pod.Spec.EphemeralContainers {
+		if pod.Spec.EphemeralContainers[i].Name == forge.PreTerminationHookName {
+			return hookRunning, nil
+		}
+	}
+
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers,
+		forge.PreTerminationHookEphemeralContainer(instance, environment, destination, mountInfos, started, &h.ContainerEnvOpts))
+	if err := h.SubResource("ephemeralcontainers").Update(ctx, pod); err != nil {
+		return hookRunning, fmt.Errorf("failed adding the pre-termination hook container to pod %s: %w", pod.Name, err)
+	}
+
+	ctrl.LoggerFrom(ctx).Info("pre-termination hook started", "environment", environment.Name, "pod", pod.Name)
+	h.recordEvent(instance, corev1.EventTypeNormal, "PreTerminationHookStarted", "Archiving the content of environment %s before termination", environment.Name)
+	return hookRunning, nil
+}
+
+// enforceJob ensures the job archiving the content of the persistent environment is present.
+func (h *PreTerminationHookRunner) enforceJob(ctx context.Context, instance *clv1alpha2.Instance,
+	environment *clv1alpha2.Environment, destination string, mountInfos []corev1.VolumeMount, started time.Time) (preTerminationHookState, error) {
+	job := batchv1.Job{ObjectMeta: forge.ObjectMetaWithSuffix(instance, environment.Name+"-"+preTerminationHookJobSuffix)}
+	jobSpec := forge.PreTerminationHookJobSpec(instance, environment, destination, mountInfos, started, &h.ContainerEnvOpts)
+
+	op, err := ctrl.CreateOrUpdate(ctx, h.Client, &job, func() error {
+		if job.CreationTimestamp.IsZero() {
+			job.Spec = jobSpec
+		}
+		job.SetLabels(forge.InstanceComponentLabels(instance, environment.Name+"-"+preTerminationHookJobSuffix))
+		return ctrl.SetControllerReference(instance, &job, h.Scheme)
+	})
+	if err != nil {
+		return hookRunning, fmt.Errorf("failed ensuring pre-termination hook job (operation=%s): %w", op, err)
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return hookFailed, nil
+		}
+	}
+	if job.Status.Succeeded > 0 {
+		return hookSucceeded, nil
+	}
+	return hookRunning, nil
+}
+
+// ensureStartTime returns the time the hooks of the instance were started at, initializing it if not yet set.
+func (h *PreTerminationHookRunner) ensureStartTime(ctx context.Context, instance *clv1alpha2.Instance) (time.Time, error) {
+	if value, ok := instance.Annotations[forge.PreTerminationHookStartedAnnotation]; ok {
+		if started, err := time.Parse(time.RFC3339, value); err == nil {
+			return started, nil
+		}
+	}
+
+	started := time.Now().Truncate(time.Second)
+	original := instance.DeepCopy()
+	if instance.Annotations == nil {
+		instance.Annotations = map[string]string{}
+	}
+	instance.Annotations[forge.PreTerminationHookStartedAnnotation] = started.Format(time.RFC3339)
+	if err := h.Patch(ctx, instance, client.MergeFrom(original)); err != nil {
+		return started, fmt.Errorf("failed setting the pre-termination hook start time: %w", err)
+	}
+	return started, nil
+}
+
+// clearStartTime removes the start time of the hooks from the instance.
+func (h *PreTerminationHookRunner) clearStartTime(ctx context.Context, instance *clv1alpha2.Instance) error {
+	if _, ok := instance.Annotations[forge.PreTerminationHookStartedAnnotation]; !ok {
+		return nil
+	}
+
+	original := instance.DeepCopy()
+	delete(instance.Annotations, forge.PreTerminationHookStartedAnnotation)
+	if err := h.Patch(ctx, instance, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed clearing the pre-termination hook start time: %w", err)
+	}
+	return nil
+}
+
+func (h *PreTerminationHookRunner) recordEvent(instance *clv1alpha2.Instance, eventType, reason, messageFmt string, args ...interface{}) {
+	if h.EventsRecorder != nil {
+		h.EventsRecorder.Eventf(instance, eventType, reason, messageFmt, args...)
+	}
+}
+
+// HasPreTerminationHooks returns whether any environment of the given template configures a pre-termination hook.
+func HasPreTerminationHooks(template *clv1alpha2.Template) bool {
+	for i := range template.Spec.EnvironmentList {
+		if template.Spec.EnvironmentList[i].PreTerminationHook != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// PreTerminationHookDestination returns the destination the content of the given environment is archived to,
+// along with the additional volumes to be mounted to reach it.
+func PreTerminationHookDestination(instance *clv1alpha2.Instance, environment *clv1alpha2.Environment) (string, []corev1.VolumeMount, error) {
+	switch environment.PreTerminationHook.Target {
+	case clv1alpha2.PreTerminationHookTargetContentDestination:
+		destination := instance.Spec.ContentUrls[environment.Name].Destination
+		if destination == "" {
+			return "", nil, fmt.Errorf("no content destination configured for the environment")
+		}
+		return destination, nil, nil
+	default:
+		// The MyDrive volume must be already part of the pod, as ephemeral containers cannot add new ones.
+		if !environment.MountMyDriveVolume {
+			return "", nil, fmt.Errorf("the MyDrive volume is not mounted in the environment")
+		}
+		return forge.PreTerminationHookMyDriveDestination, []corev1.VolumeMount{forge.MyDriveMountInfo(instance.Spec.Tenant.Name)}, nil
+	}
+}
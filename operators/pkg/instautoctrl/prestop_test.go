@@ -0,0 +1,270 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instautoctrl_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/instautoctrl"
+)
+
+var _ = Describe("Pre-termination hooks", func() {
+	const (
+		namespaceName = "workspace-test"
+		tenantName    = "tenant"
+		envName       = "env-1"
+	)
+
+	var (
+		ctx      context.Context
+		template *clv1alpha2.Template
+		instance *clv1alpha2.Instance
+		objects  []client.Object
+		c        client.Client
+		runner   *instautoctrl.PreTerminationHookRunner
+		recorder *record.FakeRecorder
+		attached []corev1.EphemeralContainer
+
+		done         bool
+		requeueAfter time.Duration
+		err          error
+	)
+
+	environment := func() *clv1alpha2.Environment {
+		return &template.Spec.EnvironmentList[0]
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		template = &clv1alpha2.Template{
+			ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: namespaceName},
+			Spec: clv1alpha2.TemplateSpec{
+				EnvironmentList: []clv1alpha2.Environment{{
+					Name:               envName,
+					EnvironmentType:    clv1alpha2.ClassContainer,
+					MountMyDriveVolume: true,
+					PreTerminationHook: &clv1alpha2.PreTerminationHook{Target: clv1alpha2.PreTerminationHookTargetMyDrive},
+				}},
+			},
+		}
+		instance = &clv1alpha2.Instance{
+			ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: namespaceName, UID: "instance-uid"},
+			Spec: clv1alpha2.InstanceSpec{
+				Template: clv1alpha2.GenericRef{Name: template.Name, Namespace: namespaceName},
+				Tenant:   clv1alpha2.GenericRef{Name: tenantName},
+			},
+		}
+		objects = nil
+		attached = nil
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	JustBeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(clv1alpha2.AddToScheme(scheme)).To(Succeed())
+		// The fake client does not support the ephemeralcontainers subresource, hence track the containers attached.
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, instance)...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceUpdate: func(_ context.Context, _ client.Client, subResource string, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+					Expect(subResource).To(Equal("ephemeralcontainers"))
+					attached = obj.(*corev1.Pod).Spec.EphemeralContainers
+					return nil
+				},
+			}).Build()
+		runner = &instautoctrl.PreTerminationHookRunner{
+			Client:           c,
+			Scheme:           scheme,
+			EventsRecorder:   recorder,
+			ContainerEnvOpts: forge.ContainerEnvOpts{ImagesTag: "tag", ContentToolsImg: "content-tools"},
+			DefaultTimeout:   time.Hour,
+		}
+		done, requeueAfter, err = runner.Run(ctx, instance, template)
+	})
+
+	runningPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "instance-pod", Namespace: namespaceName, Labels: forge.EnvironmentSelectorLabels(instance, environment())},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	When("the runner is nil", func() {
+		It("Should proceed immediately", func() {
+			var nilRunner *instautoctrl.PreTerminationHookRunner
+			Expect(nilRunner.Run(ctx, instance, template)).To(BeTrue())
+		})
+	})
+
+	When("no environment configures a pre-termination hook", func() {
+		BeforeEach(func() { environment().PreTerminationHook = nil })
+
+		It("Should proceed without setting the start annotation", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+			Expect(instance.Annotations).ToNot(HaveKey(forge.PreTerminationHookStartedAnnotation))
+		})
+	})
+
+	When("the environment is not running and not persistent", func() {
+		It("Should proceed, as there is nothing to save", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+			Expect(instance.Annotations).ToNot(HaveKey(forge.PreTerminationHookStartedAnnotation))
+		})
+	})
+
+	When("the MyDrive volume is not mounted in the environment", func() {
+		BeforeEach(func() {
+			environment().MountMyDriveVolume = false
+			objects = append(objects, runningPod())
+		})
+
+		It("Should skip the hook emitting a warning", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring("PreTerminationHookSkipped")))
+		})
+	})
+
+	When("the environment is running", func() {
+		var pod *corev1.Pod
+
+		BeforeEach(func() {
+			pod = runningPod()
+			objects = append(objects, pod)
+		})
+
+		Context("and the hook container has not been attached yet", func() {
+			It("Should wait for the hook completion", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(done).To(BeFalse())
+				Expect(requeueAfter).To(BeNumerically(">", 0))
+				Expect(instance.Annotations).To(HaveKey(forge.PreTerminationHookStartedAnnotation))
+			})
+
+			It("Should attach the ephemeral container archiving the content", func() {
+				Expect(attached).To(HaveLen(1))
+				Expect(attached[0].Name).To(Equal(forge.PreTerminationHookName))
+				Expect(attached[0].VolumeMounts).To(ContainElement(HaveField("Name", forge.MyDrivePVCMirrorName(tenantName))))
+				Expect(attached[0].Env).To(ContainElement(
+					corev1.EnvVar{Name: "DESTINATION_URL", Value: forge.PreTerminationHookMyDriveDestination}))
+			})
+
+			It("Should name the archive after the start time of the hook", func() {
+				started, err := time.Parse(time.RFC3339, instance.Annotations[forge.PreTerminationHookStartedAnnotation])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(attached).To(HaveLen(1))
+				Expect(attached[0].Env).To(ContainElement(
+					corev1.EnvVar{Name: "FILENAME", Value: "instance-" + envName + "-" + started.UTC().Format(forge.PreTerminationHookArchiveTimeFormat)}))
+			})
+		})
+
+		Context("and the hook container completed successfully", func() {
+			BeforeEach(func() {
+				instance.Annotations = map[string]string{forge.PreTerminationHookStartedAnnotation: time.Now().Format(time.RFC3339)}
+				pod.Spec.EphemeralContainers = []corev1.EphemeralContainer{{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: forge.PreTerminationHookName}}}
+				pod.Status.EphemeralContainerStatuses = []corev1.ContainerStatus{{
+					Name:  forge.PreTerminationHookName,
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+				}}
+			})
+
+			It("Should proceed and clear the start annotation", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(done).To(BeTrue())
+				Expect(instance.Annotations).ToNot(HaveKey(forge.PreTerminationHookStartedAnnotation))
+			})
+		})
+
+		Context("and the hook container failed", func() {
+			BeforeEach(func() {
+				instance.Annotations = map[string]string{forge.PreTerminationHookStartedAnnotation: time.Now().Format(time.RFC3339)}
+				pod.Spec.EphemeralContainers = []corev1.EphemeralContainer{{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: forge.PreTerminationHookName}}}
+				pod.Status.EphemeralContainerStatuses = []corev1.ContainerStatus{{
+					Name:  forge.PreTerminationHookName,
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+				}}
+			})
+
+			It("Should proceed anyway emitting a warning", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(done).To(BeTrue())
+				Expect(recorder.Events).To(Receive(ContainSubstring("PreTerminationHookFailed")))
+			})
+		})
+
+		Context("and the hook timed out", func() {
+			BeforeEach(func() {
+				environment().PreTerminationHook.Timeout = &metav1.Duration{Duration: time.Minute}
+				instance.Annotations = map[string]string{forge.PreTerminationHookStartedAnnotation: time.Now().Add(-time.Hour).Format(time.RFC3339)}
+				pod.Spec.EphemeralContainers = []corev1.EphemeralContainer{{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: forge.PreTerminationHookName}}}
+			})
+
+			It("Should proceed anyway emitting a warning", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(done).To(BeTrue())
+				Expect(recorder.Events).To(Receive(ContainSubstring("PreTerminationHookTimeout")))
+			})
+		})
+	})
+
+	When("the environment is persistent and not running", func() {
+		var started time.Time
+
+		BeforeEach(func() {
+			started = time.Now().Add(-time.Minute).Truncate(time.Second)
+			environment().Persistent = true
+			environment().PreTerminationHook.Target = clv1alpha2.PreTerminationHookTargetContentDestination
+			instance.Spec.ContentUrls = map[string]clv1alpha2.InstanceContentUrls{envName: {Destination: "https://example.com/upload"}}
+			instance.Annotations = map[string]string{forge.PreTerminationHookStartedAnnotation: started.Format(time.RFC3339)}
+		})
+
+		It("Should create the job archiving the content and wait for its completion", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeFalse())
+
+			var jobs batchv1.JobList
+			Expect(c.List(ctx, &jobs, client.InNamespace(namespaceName))).To(Succeed())
+			Expect(jobs.Items).To(HaveLen(1))
+			Expect(jobs.Items[0].Name).To(Equal("instance-" + envName + "-pre-termination"))
+			Expect(jobs.Items[0].Spec.Template.Spec.Containers[0].Env).To(ContainElement(
+				corev1.EnvVar{Name: "DESTINATION_URL", Value: "https://example.com/upload"}))
+		})
+
+		It("Should not overwrite the archives of the previous terminations", func() {
+			var jobs batchv1.JobList
+			Expect(c.List(ctx, &jobs, client.InNamespace(namespaceName))).To(Succeed())
+			Expect(jobs.Items).To(HaveLen(1))
+			Expect(jobs.Items[0].Spec.Template.Spec.Containers[0].Env).To(ContainElement(
+				corev1.EnvVar{Name: "FILENAME", Value: "instance-" + envName + "-" + started.UTC().Format(forge.PreTerminationHookArchiveTimeFormat)}))
+		})
+	})
+})
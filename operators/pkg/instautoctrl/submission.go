@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -42,6 +43,7 @@ type InstanceSubmissionReconciler struct {
 	Scheme             *runtime.Scheme
 	NamespaceWhitelist metav1.LabelSelector
 	ContainerEnvOpts   forge.ContainerEnvOpts
+	DryRun             *DryRunAuditor
 	// This function, if configured, is deferred at the beginning of the Reconcile.
 	// Specifically, it is meant to be set to GinkgoRecover during the tests,
 	// in order to lead to a controlled failure in case the Reconcile panics.
@@ -53,7 +55,7 @@ func (r *InstanceSubmissionReconciler) SetupWithManager(mgr ctrl.Manager, concur
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&clv1alpha2.Instance{}).
 		Owns(&batchv1.Job{}).
-		Named("instance-submission").
+		Named(SubmissionControllerName).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: concurrency,
 		}).
@@ -79,6 +81,8 @@ func (r *InstanceSubmissionReconciler) Reconcile(ctx context.Context, req ctrl.R
 	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
 		if !kerrors.IsNotFound(err) {
 			log.Error(err, "failed retrieving instance")
+		} else {
+			r.DryRun.Forget(SubmissionControllerName, req.NamespacedName)
 		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
@@ -93,6 +97,18 @@ func (r *InstanceSubmissionReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 	tracer.Step("labels checked")
 
+	dryRun, err := r.DryRun.Enabled(ctx, r.Client, &instance, nil)
+	if err != nil {
+		log.Error(err, "failed checking the dry-run mode")
+		return ctrl.Result{}, err
+	}
+	if dryRun {
+		// In dry-run mode, only record the action which would be performed
+		r.DryRun.Record(ctx, r.EventsRecorder, SubmissionControllerName, &instance, ActionSubmit, time.Now(), "submission requested")
+		return ctrl.Result{}, nil
+	}
+	r.DryRun.Forget(SubmissionControllerName, req.NamespacedName)
+
 	envList, err := RetrieveEnvironmentList(ctx, r.Client, &instance)
 	if err != nil {
 		log.Error(err, "failed retrieving environment")
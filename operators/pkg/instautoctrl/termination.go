@@ -45,6 +45,7 @@ type InstanceTerminationReconciler struct {
 	NamespaceWhitelist          metav1.LabelSelector
 	StatusCheckRequestTimeout   time.Duration
 	InstanceStatusCheckInterval time.Duration
+	DryRun                      *DryRunAuditor
 	// This function, if configured, is deferred at the beginning of the Reconcile.
 	// Specifically, it is meant to be set to GinkgoRecover during the tests,
 	// in order to lead to a controlled failure in case the Reconcile panics.
@@ -55,7 +56,7 @@ type InstanceTerminationReconciler struct {
 func (r *InstanceTerminationReconciler) SetupWithManager(mgr ctrl.Manager, concurrency int) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&clv1alpha2.Instance{}).
-		Named("instance-termination").
+		Named(TerminationControllerName).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: concurrency,
 		}).
@@ -85,6 +86,8 @@ func (r *InstanceTerminationReconciler) Reconcile(ctx context.Context, req ctrl.
 	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
 		if !kerrors.IsNotFound(err) {
 			log.Error(err, "failed retrieving instance")
+		} else {
+			r.DryRun.Forget(TerminationControllerName, req.NamespacedName)
 		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
@@ -115,6 +118,16 @@ func (r *InstanceTerminationReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	tracer.Step("status checked checked")
 
+	if !terminate {
+		r.DryRun.Forget(TerminationControllerName, req.NamespacedName)
+	} else if dryRun, err := r.DryRun.Enabled(ctx, r.Client, &instance, nil); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed checking the dry-run mode: %w", err)
+	} else if dryRun {
+		// In dry-run mode, only record the action which would be performed
+		r.DryRun.Record(ctx, r.EventsRecorder, TerminationControllerName, &instance, ActionStop, time.Now(), "status check reported the termination")
+		return ctrl.Result{RequeueAfter: r.InstanceStatusCheckInterval}, nil
+	}
+
 	if terminate {
 		err := r.TerminateInstance(ctrl.LoggerInto(ctx, dbgLog), &instance)
 		if err != nil {
@@ -34,4 +34,13 @@ const (
 	EvPublicExposureMultiEnv = "PublicExposureMultipleEnvironments"
 	// EvPublicExposureMultiEnvMsg -> the event message corresponding to public exposure blocked due to multiple environments.
 	EvPublicExposureMultiEnvMsg = "Public exposure is not allowed for templates with multiple environments"
+
+	// EvDiskResizing -> the event key corresponding to the expansion of a persistent disk.
+	EvDiskResizing = "DiskResizing"
+	// EvDiskResizingMsg -> the event message corresponding to the expansion of a persistent disk.
+	EvDiskResizingMsg = "Expanding the disk of environment %v from %v to %v"
+	// EvDiskResizeFailed -> the event key corresponding to a failed expansion of a persistent disk.
+	EvDiskResizeFailed = "DiskResizeFailed"
+	// EvDiskResizeFailedMsg -> the event message corresponding to a failed expansion of a persistent disk.
+	EvDiskResizeFailedMsg = "Failed to expand the disk of environment %v: %v"
 )
@@ -45,6 +45,9 @@ func (r *InstanceReconciler) EnforceContainerEnvironment(ctx context.Context) er
 		if err := r.enforcePVC(ctx); err != nil {
 			return err
 		}
+		if err := r.enforceDiskSize(ctx); err != nil {
+			return err
+		}
 	}
 
 	return r.enforceContainer(ctx)
@@ -63,6 +66,7 @@ func (r *InstanceReconciler) enforcePVC(ctx context.Context) error {
 		// PVC's spec is immutable, it has to be set at creation
 		if pvc.CreationTimestamp.IsZero() {
 			pvc.Spec = forge.InstancePVCSpec(environment)
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = forge.InstanceDiskSize(instance, environment)
 		}
 		pvc.SetLabels(forge.EnvironmentObjectLabels(pvc.GetLabels(), instance, environment))
 		return ctrl.SetControllerReference(instance, &pvc, r.Scheme)
@@ -32,9 +32,11 @@ import (
 	"k8s.io/utils/trace"
 	virtv1 "kubevirt.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
@@ -397,7 +399,12 @@ func (r *InstanceReconciler) SetupWithManager(mgr ctrl.Manager, concurrency int)
 		Owns(&corev1.PersistentVolumeClaim{}).
 		// Here, we use Watches instead of Owns since we need to react also in case a VMI generated from a VM is updated,
 		// to correctly update the instance phase in case of persistent VMs with resource quota exceeded.
-		Watches(&virtv1.VirtualMachineInstance{}, handler.EnqueueRequestsFromMapFunc(r.vmiToInstance))
+		Watches(&virtv1.VirtualMachineInstance{}, handler.EnqueueRequestsFromMapFunc(r.vmiToInstance)).
+		// The PVCs of persistent VMs are owned by the corresponding DataVolumes, and watched to track the expansion of the disks.
+		Watches(&corev1.PersistentVolumeClaim{}, handler.EnqueueRequestsFromMapFunc(r.pvcToInstance)).
+		// The persistent instances are reconciled when the template changes, to expand their disks if necessary.
+		Watches(&clv1alpha2.Template{}, handler.EnqueueRequestsFromMapFunc(r.templateToInstances),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}))
 
 	if r.ExpositionConfig.GatewayAPIMode {
 		bld = bld.Owns(&gatewayv1.HTTPRoute{})
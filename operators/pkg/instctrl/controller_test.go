@@ -613,6 +613,34 @@ var _ = Describe("The instance-controller Reconcile method", func() {
 				Expect(RunReconciler()).To(HaveOccurred())
 			})
 		})
+
+		When("an environment name matches the volume of an additional disk of another environment", func() {
+			BeforeEach(func() {
+				testName = "test-colliding-disks"
+			})
+
+			It("Should reject the template", func() {
+				environment := environmentList[0]
+				environment.EnvironmentType = clv1alpha2.ClassVM
+				environment.AdditionalDisks = []clv1alpha2.AdditionalDisk{{Name: "data", Size: resource.MustParse("1Gi"), Persistent: true}}
+				environment.Name = "disk"
+				colliding := environmentList[0]
+				colliding.EnvironmentType = clv1alpha2.ClassVM
+				colliding.Name = "disk-data"
+
+				conflicting := clv1alpha2.Template{
+					ObjectMeta: metav1.ObjectMeta{Name: testName + "-conflicting", Namespace: testName},
+					Spec: clv1alpha2.TemplateSpec{
+						WorkspaceRef:    clv1alpha2.GenericRef{Name: testName},
+						EnvironmentList: []clv1alpha2.Environment{environment, colliding},
+					},
+				}
+				Expect(k8sClient.Create(ctx, &conflicting)).To(MatchError(ContainSubstring("<environment>-<disk>")))
+
+				conflicting.Spec.EnvironmentList[1].Name = "disk-other"
+				Expect(k8sClient.Create(ctx, &conflicting)).To(Succeed())
+			})
+		})
 	})
 
 	Context("Public Exposure functionality", func() {
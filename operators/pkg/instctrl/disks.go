@@ -0,0 +1,208 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instctrl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	clctx "github.com/netgroup-polito/CrownLabs/operators/pkg/clcontext"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
+)
+
+// enforceDiskSize expands the PVC backing the persistent disk of the environment, in case a larger size
+// is requested (either by the template or by the instance), and reflects the progress in the instance status.
+// In case of VMs, the PVC is the one created by CDI for the DataVolume, which shares the same name.
+func (r *InstanceReconciler) enforceDiskSize(ctx context.Context) error {
+	instance := clctx.InstanceFrom(ctx)
+	environment := clctx.EnvironmentFrom(ctx)
+	status := &instance.Status.Environments[clctx.EnvironmentIndexFrom(ctx)]
+
+	disk := ptr.Deref(status.Disk, clv1alpha2.InstanceDiskStatus{})
+	found, err := r.expandDisk(ctx, forge.ObjectMetaWithSuffix(instance, environment.Name),
+		forge.InstanceDiskSize(instance, environment), environment.Name, &disk)
+	if found {
+		status.Disk = &disk
+	}
+	return err
+}
+
+// enforceAdditionalDiskSize expands the PVC backing the given persistent additional disk of the environment,
+// in case a larger size is requested by the template, and reflects the progress in the instance status.
+func (r *InstanceReconciler) enforceAdditionalDiskSize(ctx context.Context, disk *clv1alpha2.AdditionalDisk) error {
+	instance := clctx.InstanceFrom(ctx)
+	environment := clctx.EnvironmentFrom(ctx)
+	status := &instance.Status.Environments[clctx.EnvironmentIndexFrom(ctx)]
+
+	idx := slices.IndexFunc(status.AdditionalDisks, func(d clv1alpha2.InstanceDiskStatus) bool { return d.Name == disk.Name })
+	diskStatus := clv1alpha2.InstanceDiskStatus{Name: disk.Name}
+	if idx >= 0 {
+		diskStatus = status.AdditionalDisks[idx]
+	}
+
+	found, err := r.expandDisk(ctx, forge.ObjectMetaWithSuffix(instance, environment.Name+forge.StringSeparator+disk.Name),
+		disk.Size, fmt.Sprintf("%s (disk %s)", environment.Name, disk.Name), &diskStatus)
+	switch {
+	case found && idx >= 0:
+		status.AdditionalDisks[idx] = diskStatus
+	case found:
+		status.AdditionalDisks = append(status.AdditionalDisks, diskStatus)
+	}
+	return err
+}
+
+// expandDisk expands the given PVC to the requested size, if larger than the current one, and reflects the progress
+// in the given disk status. The returned flag is false in case the PVC does not exist (yet), and the status is meaningless.
+func (r *InstanceReconciler) expandDisk(ctx context.Context, meta metav1.ObjectMeta, requested resource.Quantity,
+	diskName string, diskStatus *clv1alpha2.InstanceDiskStatus) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+	instance := clctx.InstanceFrom(ctx)
+
+	pvc := corev1.PersistentVolumeClaim{ObjectMeta: meta}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(&pvc), &pvc); err != nil {
+		if kerrors.IsNotFound(err) {
+			// The PVC of the DataVolume has not been created yet.
+			return false, nil
+		}
+		log.Error(err, "failed to retrieve the pvc of the persistent disk", "pvc", klog.KObj(&pvc))
+		return false, err
+	}
+
+	diskStatus.Requested = requested.DeepCopy()
+	diskStatus.Capacity = pvc.Status.Capacity.Storage().DeepCopy()
+
+	// The expansion is not performed in case the current size is unknown.
+	current := pvc.Spec.Resources.Requests.Storage().DeepCopy()
+	if !current.IsZero() && requested.Cmp(current) > 0 {
+		patch := client.MergeFrom(pvc.DeepCopy())
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = requested
+		if err := r.Patch(ctx, &pvc, patch); err != nil {
+			if !kerrors.IsForbidden(err) && !kerrors.IsInvalid(err) {
+				log.Error(err, "failed to expand the pvc of the persistent disk", "pvc", klog.KObj(&pvc))
+				return true, err
+			}
+
+			// The expansion is not allowed, e.g., due to the storage class or the resource quota: there is no point in retrying.
+			if diskStatus.ResizePhase != clv1alpha2.DiskResizePhaseFailed {
+				r.EventsRecorder.Eventf(instance, corev1.EventTypeWarning, EvDiskResizeFailed, EvDiskResizeFailedMsg, diskName, err)
+			}
+			log.Info("failed to expand the persistent disk", "pvc", klog.KObj(&pvc), "reason", err.Error())
+			diskStatus.ResizePhase = clv1alpha2.DiskResizePhaseFailed
+			diskStatus.Message = err.Error()
+			return true, nil
+		}
+
+		r.EventsRecorder.Eventf(instance, corev1.EventTypeNormal, EvDiskResizing, EvDiskResizingMsg, diskName, current.String(), requested.String())
+		log.Info("persistent disk expansion requested", "pvc", klog.KObj(&pvc), "previous", current, "requested", requested)
+		diskStatus.ResizePhase = clv1alpha2.DiskResizePhaseResizing
+		diskStatus.Message = ""
+		return true, nil
+	}
+
+	phase, message := diskResizePhase(&pvc, diskStatus.ResizePhase)
+	if phase != diskStatus.ResizePhase {
+		log.V(utils.LogDebugLevel).Info("disk resize phase changed", "pvc", klog.KObj(&pvc),
+			"previous", string(diskStatus.ResizePhase), "current", string(phase))
+	}
+	diskStatus.ResizePhase = phase
+	diskStatus.Message = message
+	return true, nil
+}
+
+// diskResizePhase returns the phase of the expansion of the given PVC (and the possible error message), given the previous one.
+func diskResizePhase(pvc *corev1.PersistentVolumeClaim, previous clv1alpha2.DiskResizePhase) (clv1alpha2.DiskResizePhase, string) {
+	for i := range pvc.Status.Conditions {
+		condition := &pvc.Status.Conditions[i]
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case corev1.PersistentVolumeClaimControllerResizeError, corev1.PersistentVolumeClaimNodeResizeError:
+			return clv1alpha2.DiskResizePhaseFailed, condition.Message
+		case corev1.PersistentVolumeClaimFileSystemResizePending:
+			return clv1alpha2.DiskResizePhaseFileSystemResizePending, ""
+		case corev1.PersistentVolumeClaimResizing:
+			return clv1alpha2.DiskResizePhaseResizing, ""
+		}
+	}
+
+	if pvc.Status.Capacity.Storage().Cmp(*pvc.Spec.Resources.Requests.Storage()) < 0 {
+		if previous == clv1alpha2.DiskResizePhaseUnset {
+			// The volume is still being provisioned.
+			return previous, ""
+		}
+		return clv1alpha2.DiskResizePhaseResizing, ""
+	}
+
+	if previous == clv1alpha2.DiskResizePhaseUnset {
+		return previous, ""
+	}
+	return clv1alpha2.DiskResizePhaseCompleted, ""
+}
+
+// pvcToInstance returns a reconcile request for the instance owning the DataVolume the given PVC belongs to, if any,
+// in order to track the expansion of the persistent disks of VMs (the PVCs of containers are directly owned by the instances).
+func (r *InstanceReconciler) pvcToInstance(ctx context.Context, o client.Object) []reconcile.Request {
+	owner := metav1.GetControllerOf(o)
+	if owner == nil || owner.Kind != "DataVolume" {
+		return nil
+	}
+
+	var dv cdiv1beta1.DataVolume
+	if err := r.Get(ctx, types.NamespacedName{Namespace: o.GetNamespace(), Name: owner.Name}, &dv); err != nil {
+		return nil
+	}
+	if instance := metav1.GetControllerOf(&dv); instance != nil && instance.Kind == "Instance" {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: dv.Namespace, Name: instance.Name}}}
+	}
+	return nil
+}
+
+// templateToInstances returns the reconcile requests for the persistent instances of the given template,
+// in order to expand their disks in case a larger size is configured in the template.
+func (r *InstanceReconciler) templateToInstances(ctx context.Context, o client.Object) []reconcile.Request {
+	var instances clv1alpha2.InstanceList
+	if err := r.List(ctx, &instances, client.MatchingLabels{
+		forge.LabelTemplateKey:   o.GetName(),
+		forge.LabelPersistentKey: strconv.FormatBool(true),
+	}); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list the instances of template", "template", klog.KObj(o))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range instances.Items {
+		if instances.Items[i].Spec.Template.Namespace == o.GetNamespace() {
+			requests = append(requests, reconcile.Request{NamespacedName: forge.NamespacedNameFromObject(&instances.Items[i])})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,308 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instctrl_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	cdiv1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apicommon "github.com/netgroup-polito/CrownLabs/operators/api/common"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	clctx "github.com/netgroup-polito/CrownLabs/operators/pkg/clcontext"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/instctrl"
+)
+
+var _ = Describe("Expansion of the persistent disks", func() {
+	const (
+		instanceName      = "kubernetes-0000"
+		instanceNamespace = "tenant-tester"
+		environmentName   = "control-plane"
+		disk              = "20Gi"
+	)
+
+	var (
+		ctx        context.Context
+		reconciler instctrl.InstanceReconciler
+		recorder   *record.FakeRecorder
+
+		instance    clv1alpha2.Instance
+		template    clv1alpha2.Template
+		environment clv1alpha2.Environment
+		existing    corev1.PersistentVolumeClaim
+		pvc         corev1.PersistentVolumeClaim
+
+		err error
+	)
+
+	diskStatus := func() *clv1alpha2.InstanceDiskStatus { return instance.Status.Environments[0].Disk }
+
+	BeforeEach(func() {
+		ctx = ctrl.LoggerInto(context.Background(), logr.Discard())
+
+		environment = clv1alpha2.Environment{
+			Name:            environmentName,
+			EnvironmentType: clv1alpha2.ClassContainer,
+			Image:           "internal/registry/image:v1.0",
+			Persistent:      true,
+			Resources: clv1alpha2.EnvironmentResources{
+				ResourceSpec: apicommon.ResourceSpec{CPU: 1, Memory: resource.MustParse("1G"), Disk: resource.MustParse(disk)},
+			},
+		}
+		template = clv1alpha2.Template{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "workspace-netgroup"},
+			Spec: clv1alpha2.TemplateSpec{
+				EnvironmentList: []clv1alpha2.Environment{environment},
+				WorkspaceRef:    clv1alpha2.GenericRef{Name: "netgroup"},
+			},
+		}
+		instance = clv1alpha2.Instance{
+			ObjectMeta: metav1.ObjectMeta{Name: instanceName, Namespace: instanceNamespace},
+			Spec: clv1alpha2.InstanceSpec{
+				Running:  true,
+				Template: clv1alpha2.GenericRef{Name: template.Name, Namespace: template.Namespace},
+				Tenant:   clv1alpha2.GenericRef{Name: "tester"},
+			},
+			Status: clv1alpha2.InstanceStatus{Environments: []clv1alpha2.InstanceStatusEnv{{Name: environmentName}}},
+		}
+
+		existing = corev1.PersistentVolumeClaim{
+			ObjectMeta: forge.ObjectMetaWithSuffix(&instance, environmentName),
+			Spec:       forge.InstancePVCSpec(&environment),
+			Status: corev1.PersistentVolumeClaimStatus{
+				Phase:    corev1.ClaimBound,
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(disk)},
+			},
+		}
+		existing.SetCreationTimestamp(metav1.NewTime(time.Now()))
+		pvc = corev1.PersistentVolumeClaim{}
+	})
+
+	JustBeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		reconciler = instctrl.InstanceReconciler{
+			Client:         fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&template, &existing).Build(),
+			Scheme:         scheme.Scheme,
+			EventsRecorder: recorder,
+		}
+
+		ctx, _ = clctx.TenantInto(ctx, &clv1alpha2.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "tester"}})
+		ctx, _ = clctx.InstanceInto(ctx, &instance)
+		ctx, _ = clctx.TemplateInto(ctx, &template)
+		ctx, _ = clctx.EnvironmentInto(ctx, &environment)
+		ctx = clctx.EnvironmentIndexInto(ctx, 0)
+		ctx = clctx.VolumeMountInfosInto(ctx, nil)
+
+		err = reconciler.EnforceContainerEnvironment(ctx)
+	})
+
+	When("no larger disk is requested", func() {
+		It("Should not return an error", func() { Expect(err).ToNot(HaveOccurred()) })
+
+		It("Should report the size of the disk, without any expansion", func() {
+			Expect(diskStatus()).ToNot(BeNil())
+			Expect(diskStatus().Requested).To(Equal(resource.MustParse(disk)))
+			Expect(diskStatus().Capacity).To(Equal(resource.MustParse(disk)))
+			Expect(diskStatus().ResizePhase).To(Equal(clv1alpha2.DiskResizePhaseUnset))
+		})
+	})
+
+	When("a larger disk is requested for the instance", func() {
+		BeforeEach(func() {
+			instance.Spec.DiskSizes = map[string]resource.Quantity{environmentName: resource.MustParse("30Gi")}
+		})
+
+		It("Should not return an error", func() { Expect(err).ToNot(HaveOccurred()) })
+
+		It("Should expand the PVC", func() {
+			Expect(reconciler.Get(ctx, forge.NamespacedNameFromObject(&existing), &pvc)).To(Succeed())
+			Expect(pvc.Spec.Resources.Requests.Storage()).To(PointTo(Equal(resource.MustParse("30Gi"))))
+		})
+
+		It("Should report the expansion in progress", func() {
+			Expect(diskStatus().Requested).To(Equal(resource.MustParse("30Gi")))
+			Expect(diskStatus().ResizePhase).To(Equal(clv1alpha2.DiskResizePhaseResizing))
+			Expect(recorder.Events).To(Receive(ContainSubstring(instctrl.EvDiskResizing)))
+		})
+
+		When("the file system expansion is pending", func() {
+			BeforeEach(func() {
+				existing.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("30Gi")
+				existing.Status.Conditions = []corev1.PersistentVolumeClaimCondition{{
+					Type:   corev1.PersistentVolumeClaimFileSystemResizePending,
+					Status: corev1.ConditionTrue,
+				}}
+			})
+
+			It("Should report the pending phase", func() {
+				Expect(diskStatus().ResizePhase).To(Equal(clv1alpha2.DiskResizePhaseFileSystemResizePending))
+			})
+		})
+
+		When("the expansion failed", func() {
+			BeforeEach(func() {
+				existing.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("30Gi")
+				existing.Status.Conditions = []corev1.PersistentVolumeClaimCondition{{
+					Type:    corev1.PersistentVolumeClaimControllerResizeError,
+					Status:  corev1.ConditionTrue,
+					Message: "resize not supported",
+				}}
+			})
+
+			It("Should report the failure", func() {
+				Expect(diskStatus().ResizePhase).To(Equal(clv1alpha2.DiskResizePhaseFailed))
+				Expect(diskStatus().Message).To(Equal("resize not supported"))
+			})
+		})
+
+		When("the expansion completed", func() {
+			BeforeEach(func() {
+				existing.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("30Gi")
+				existing.Status.Capacity[corev1.ResourceStorage] = resource.MustParse("30Gi")
+				instance.Status.Environments[0].Disk = &clv1alpha2.InstanceDiskStatus{ResizePhase: clv1alpha2.DiskResizePhaseResizing}
+			})
+
+			It("Should report the completion", func() {
+				Expect(diskStatus().Capacity).To(Equal(resource.MustParse("30Gi")))
+				Expect(diskStatus().ResizePhase).To(Equal(clv1alpha2.DiskResizePhaseCompleted))
+			})
+		})
+	})
+
+	When("a smaller disk is requested for the instance", func() {
+		BeforeEach(func() {
+			instance.Spec.DiskSizes = map[string]resource.Quantity{environmentName: resource.MustParse("10Gi")}
+		})
+
+		It("Should not shrink the PVC", func() {
+			Expect(reconciler.Get(ctx, forge.NamespacedNameFromObject(&existing), &pvc)).To(Succeed())
+			Expect(pvc.Spec.Resources.Requests.Storage()).To(PointTo(Equal(resource.MustParse(disk))))
+		})
+	})
+})
+
+var _ = Describe("Additional disks of VM environments", func() {
+	const (
+		instanceName      = "kubernetes-0000"
+		instanceNamespace = "tenant-tester"
+		environmentName   = "vm"
+	)
+
+	var (
+		ctx        context.Context
+		reconciler instctrl.InstanceReconciler
+
+		instance    clv1alpha2.Instance
+		environment clv1alpha2.Environment
+		dv          cdiv1beta1.DataVolume
+		objects     []client.Object
+
+		err error
+	)
+
+	BeforeEach(func() {
+		ctx = ctrl.LoggerInto(context.Background(), logr.Discard())
+
+		environment = clv1alpha2.Environment{
+			Name:            environmentName,
+			EnvironmentType: clv1alpha2.ClassVM,
+			Image:           "internal/registry/image:v1.0",
+			AdditionalDisks: []clv1alpha2.AdditionalDisk{
+				{Name: "db", Size: resource.MustParse("10Gi"), Persistent: true},
+				{Name: "scratch", Size: resource.MustParse("5Gi")},
+			},
+		}
+		instance = clv1alpha2.Instance{
+			ObjectMeta: metav1.ObjectMeta{Name: instanceName, Namespace: instanceNamespace, UID: "instance-uid"},
+			Status:     clv1alpha2.InstanceStatus{Environments: []clv1alpha2.InstanceStatusEnv{{Name: environmentName}}},
+		}
+		dv = cdiv1beta1.DataVolume{}
+		objects = nil
+	})
+
+	JustBeforeEach(func() {
+		reconciler = instctrl.InstanceReconciler{
+			Client:         fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build(),
+			Scheme:         scheme.Scheme,
+			EventsRecorder: record.NewFakeRecorder(10),
+		}
+
+		ctx, _ = clctx.InstanceInto(ctx, &instance)
+		ctx, _ = clctx.EnvironmentInto(ctx, &environment)
+		ctx = clctx.EnvironmentIndexInto(ctx, 0)
+
+		err = reconciler.EnforceAdditionalDisks(ctx)
+	})
+
+	It("Should not return an error", func() { Expect(err).ToNot(HaveOccurred()) })
+
+	It("Should create the DataVolume of the persistent disk", func() {
+		name := forge.AdditionalDiskDataVolumeName(&instance, &environment, &environment.AdditionalDisks[0])
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: instanceNamespace, Name: name}, &dv)).To(Succeed())
+		Expect(dv.Spec).To(Equal(forge.AdditionalDiskDataVolumeSpec(&environment, &environment.AdditionalDisks[0])))
+		Expect(dv.GetOwnerReferences()).To(ContainElement(MatchFields(IgnoreExtras, Fields{"UID": Equal(instance.UID)})))
+	})
+
+	It("Should not create any DataVolume for the ephemeral disk", func() {
+		name := forge.AdditionalDiskDataVolumeName(&instance, &environment, &environment.AdditionalDisks[1])
+		Expect(kerrors.IsNotFound(reconciler.Get(ctx, types.NamespacedName{Namespace: instanceNamespace, Name: name}, &dv))).To(BeTrue())
+	})
+
+	When("the template requests a larger persistent disk", func() {
+		BeforeEach(func() {
+			existing := corev1.PersistentVolumeClaim{
+				ObjectMeta: forge.ObjectMetaWithSuffix(&instance, environmentName+"-db"),
+				Spec:       *forge.AdditionalDiskDataVolumeSpec(&environment, &environment.AdditionalDisks[0]).PVC,
+				Status: corev1.PersistentVolumeClaimStatus{
+					Phase:    corev1.ClaimBound,
+					Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			}
+			objects = append(objects, &existing)
+			environment.AdditionalDisks[0].Size = resource.MustParse("20Gi")
+		})
+
+		It("Should expand the PVC of the disk", func() {
+			var pvc corev1.PersistentVolumeClaim
+			Expect(reconciler.Get(ctx, forge.NamespacedNameWithSuffix(&instance, environmentName+"-db"), &pvc)).To(Succeed())
+			Expect(pvc.Spec.Resources.Requests.Storage()).To(PointTo(Equal(resource.MustParse("20Gi"))))
+		})
+
+		It("Should report the expansion in progress", func() {
+			Expect(instance.Status.Environments[0].AdditionalDisks).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Name":        Equal("db"),
+				"Requested":   Equal(resource.MustParse("20Gi")),
+				"ResizePhase": Equal(clv1alpha2.DiskResizePhaseResizing),
+			})))
+			Expect(instance.Status.Environments[0].Disk).To(BeNil())
+		})
+	})
+})
@@ -17,6 +17,7 @@ package instctrl
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	virtv1 "kubevirt.io/api/core/v1"
@@ -48,6 +49,12 @@ func (r *InstanceReconciler) EnforceVMEnvironment(ctx context.Context) error {
 		return err
 	}
 
+	// Enforce the DataVolumes backing the persistent additional disks, if any.
+	if err := r.EnforceAdditionalDisks(ctx); err != nil {
+		log.Error(err, "failed to enforce the additional disks")
+		return err
+	}
+
 	// Create a VirtualMachine if the environment is persistent.
 	if environment.Persistent {
 		return r.enforceVirtualMachine(ctx)
@@ -75,6 +82,7 @@ func (r *InstanceReconciler) enforceVirtualMachine(ctx context.Context) error {
 		log.Error(err, "failed to forge datavolume", "datavolume", klog.KObj(&dv))
 		return err
 	}
+	forgedDV.PVC.Resources.Requests[corev1.ResourceStorage] = forge.InstanceDiskSize(instance, environment)
 
 	// CreateOrUpdate the DataVolume, setting the Spec only if the DataVolume is being created for the first time.
 	resDV, errDV := ctrl.CreateOrUpdate(ctx, r.Client, &dv, func() error {
@@ -92,6 +100,11 @@ func (r *InstanceReconciler) enforceVirtualMachine(ctx context.Context) error {
 		return errDV
 	}
 	log.V(utils.FromResult(resDV)).Info("datavolume enforced", "datavolume", klog.KObj(&dv), "result", resDV)
+
+	// Expand the disk, in case a larger size is requested.
+	if err := r.enforceDiskSize(ctx); err != nil {
+		return err
+	}
 	// =========================================================================
 
 	vm := virtv1.VirtualMachine{ObjectMeta: forge.ObjectMetaWithSuffix(instance, environment.Name)}
@@ -191,3 +204,39 @@ func (r *InstanceReconciler) enforceVirtualMachineInstance(ctx context.Context)
 
 	return nil
 }
+
+// EnforceAdditionalDisks enforces the presence of the DataVolumes backing the persistent additional disks of the environment,
+// expanding them in case a larger size is requested. Ephemeral additional disks, instead, are directly rendered into the VMI specification.
+func (r *InstanceReconciler) EnforceAdditionalDisks(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx)
+	instance := clctx.InstanceFrom(ctx)
+	environment := clctx.EnvironmentFrom(ctx)
+
+	for i := range environment.AdditionalDisks {
+		disk := &environment.AdditionalDisks[i]
+		if !disk.Persistent {
+			continue
+		}
+
+		dv := cdiv1beta1.DataVolume{ObjectMeta: forge.ObjectMetaWithSuffix(instance, environment.Name+forge.StringSeparator+disk.Name)}
+		res, err := ctrl.CreateOrUpdate(ctx, r.Client, &dv, func() error {
+			// The DataVolume specifications are forged only at creation time, as changing them later may be either rejected by the webhook or cause data loss.
+			if dv.CreationTimestamp.IsZero() {
+				dv.Spec = forge.AdditionalDiskDataVolumeSpec(environment, disk)
+			}
+			return ctrl.SetControllerReference(instance, &dv, r.Scheme)
+		})
+		if err != nil {
+			log.Error(err, "failed to enforce additional disk datavolume", "datavolume", klog.KObj(&dv))
+			return err
+		}
+		log.V(utils.FromResult(res)).Info("additional disk datavolume enforced", "datavolume", klog.KObj(&dv), "result", res)
+
+		// Expand the disk, in case a larger size is requested by the template.
+		if err := r.enforceAdditionalDiskSize(ctx, disk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
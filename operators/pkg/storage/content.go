@@ -0,0 +1,78 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
+)
+
+// RunPVCContentSync enforces a job populating the passed PVC with the content retrieved from the given source,
+// optionally overwriting the files already present. Returns the revision of the content and true once the job
+// has completed successfully (false while it is still running), and deletes the job, so that the next
+// invocation triggers a new synchronization.
+func RunPVCContentSync(ctx context.Context, log logr.Logger, c client.Client, pvc *corev1.PersistentVolumeClaim, owner metav1.Object,
+	source *clv1alpha2.ContentSource, overwrite bool, opts *forge.ContainerEnvOpts) (revision string, done bool, err error) {
+	log = log.WithName("content-sync-job")
+
+	syncJob := batchv1.Job{
+		ObjectMeta: forge.ObjectMetaWithSuffix(pvc, "sync"),
+	}
+
+	syncJobOpRes, err := ctrl.CreateOrUpdate(ctx, c, &syncJob, func() error {
+		if syncJob.CreationTimestamp.IsZero() {
+			syncJob.Spec = forge.PVCContentSyncJobSpec(pvc, source, overwrite, opts)
+		}
+		return ctrl.SetControllerReference(owner, &syncJob, c.Scheme())
+	})
+	if err != nil {
+		log.Error(err, "Unable to create or update Job")
+		return "", false, err
+	}
+	log.V(utils.LogDebugLevel).Info("Job enforced", "result", syncJobOpRes)
+
+	switch {
+	case syncJob.Status.Succeeded > 0:
+		if revision, err = terminationMessageFromJobPods(ctx, c, &syncJob); err != nil {
+			log.Error(err, "Unable to retrieve the revision from the Job")
+		}
+		revision = strings.TrimSpace(revision)
+	case syncJob.Status.Failed > forge.ContentSyncJobMaxRetries:
+		err = fmt.Errorf("content sync job %s failed", syncJob.Name)
+		log.Error(err, "Failed")
+	default:
+		return "", false, nil
+	}
+
+	// The job is deleted in any case, to start from scratch at the next synchronization.
+	if err2 := c.Delete(ctx, &syncJob, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err2) != nil {
+		log.Error(err2, "Unable to delete Job")
+		return "", false, err2
+	}
+
+	return revision, err == nil, err
+}
@@ -0,0 +1,127 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/forge"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/utils"
+)
+
+// VolumeUsage represents the space used and available on a volume, in bytes.
+type VolumeUsage struct {
+	Used      int64
+	Available int64
+}
+
+// RunPVCUsageCollection enforces a job measuring the usage of the passed PVC.
+// Returns the usage once the job has completed successfully (nil while it is still running),
+// and deletes the job, so that the next invocation triggers a new measurement.
+func RunPVCUsageCollection(ctx context.Context, log logr.Logger, c client.Client, pvc *corev1.PersistentVolumeClaim, owner metav1.Object) (*VolumeUsage, error) {
+	log = log.WithName("usage-job")
+
+	usageJob := batchv1.Job{
+		ObjectMeta: forge.ObjectMetaWithSuffix(pvc, "usage"),
+	}
+
+	usageJobOpRes, err := ctrl.CreateOrUpdate(ctx, c, &usageJob, func() error {
+		if usageJob.CreationTimestamp.IsZero() {
+			usageJob.Spec = forge.PVCUsageJobSpec(pvc)
+		}
+		return ctrl.SetControllerReference(owner, &usageJob, c.Scheme())
+	})
+	if err != nil {
+		log.Error(err, "Unable to create or update Job")
+		return nil, err
+	}
+	log.V(utils.LogDebugLevel).Info("Job enforced", "result", usageJobOpRes)
+
+	var usage *VolumeUsage
+	switch {
+	case usageJob.Status.Succeeded > 0:
+		var output string
+		if output, err = terminationMessageFromJobPods(ctx, c, &usageJob); err == nil {
+			usage, err = ParseDFOutput(output)
+		}
+		if err != nil {
+			log.Error(err, "Unable to retrieve the usage from the Job")
+		}
+	case usageJob.Status.Failed > forge.UsageJobMaxRetries:
+		err = fmt.Errorf("usage job %s failed", usageJob.Name)
+		log.Error(err, "Failed")
+	default:
+		return nil, nil
+	}
+
+	// The job is deleted in any case, to start from scratch at the next measurement.
+	if err2 := c.Delete(ctx, &usageJob, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err2) != nil {
+		log.Error(err2, "Unable to delete Job")
+		return nil, err2
+	}
+
+	return usage, err
+}
+
+// terminationMessageFromJobPods retrieves the termination message reported by the succeeded pod of the given job.
+func terminationMessageFromJobPods(ctx context.Context, c client.Client, job *batchv1.Job) (string, error) {
+	var pods corev1.PodList
+	if err := c.List(ctx, &pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name}); err != nil {
+		return "", err
+	}
+
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		for _, status := range pods.Items[i].Status.ContainerStatuses {
+			if status.State.Terminated != nil && status.State.Terminated.Message != "" {
+				return status.State.Terminated.Message, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no termination message reported by the pods of job %s", job.Name)
+}
+
+// ParseDFOutput parses the line printed by "df -Pk" for a given volume, returning the corresponding usage.
+func ParseDFOutput(output string) (*VolumeUsage, error) {
+	// Filesystem 1024-blocks Used Available Capacity Mounted-on
+	fields := strings.Fields(output)
+	if len(fields) < 6 {
+		return nil, fmt.Errorf("unexpected df output %q", output)
+	}
+
+	used, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid used blocks in df output %q: %w", output, err)
+	}
+	available, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid available blocks in df output %q: %w", output, err)
+	}
+
+	return &VolumeUsage{Used: used * 1024, Available: available * 1024}, nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage_test
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/storage"
+)
+
+var _ = Describe("Volume usage collection", func() {
+	Describe("The storage.ParseDFOutput function", func() {
+		It("Should parse a valid df output", func() {
+			usage, err := storage.ParseDFOutput("nfs-server:/export/shvol 1048576 262144 786432 25% /volume\n")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(usage).To(Equal(&storage.VolumeUsage{Used: 262144 * 1024, Available: 786432 * 1024}))
+		})
+
+		It("Should fail with a truncated output", func() {
+			_, err := storage.ParseDFOutput("nfs-server:/export/shvol 1048576")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should fail with non-numeric values", func() {
+			_, err := storage.ParseDFOutput("nfs-server:/export/shvol 1048576 used 786432 25% /volume")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("The storage.RunPVCUsageCollection function", func() {
+		const (
+			namespace = "workspace-test"
+			jobName   = "shvol-test-usage"
+		)
+
+		var (
+			ctx   context.Context
+			c     client.Client
+			owner corev1.ConfigMap
+			pvc   corev1.PersistentVolumeClaim
+			usage *storage.VolumeUsage
+			err   error
+		)
+
+		getJob := func() (*batchv1.Job, error) {
+			var job batchv1.Job
+			return &job, c.Get(ctx, types.NamespacedName{Name: jobName, Namespace: namespace}, &job)
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			owner = corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: namespace, UID: "owner-uid"}}
+			pvc = corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "shvol-test", Namespace: namespace}}
+			c = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&owner, &pvc).Build()
+		})
+
+		JustBeforeEach(func() {
+			usage, err = storage.RunPVCUsageCollection(ctx, logr.Discard(), c, &pvc, &owner)
+		})
+
+		When("The job does not exist yet", func() {
+			It("Should create the job and return no usage", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(usage).To(BeNil())
+
+				job, err := getJob()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(pvc.Name))
+				Expect(metav1.IsControlledBy(job, &owner)).To(BeTrue())
+			})
+		})
+
+		When("The job has succeeded", func() {
+			BeforeEach(func() {
+				job := batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: jobName, Namespace: namespace}}
+				job.Status.Succeeded = 1
+				pod := corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: jobName + "-abcde", Namespace: namespace,
+						Labels: map[string]string{batchv1.JobNameLabel: jobName}},
+					Status: corev1.PodStatus{
+						Phase: corev1.PodSucceeded,
+						ContainerStatuses: []corev1.ContainerStatus{{
+							State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+								Message: "nfs-server:/export/shvol 2048 1024 1024 50% /volume",
+							}},
+						}},
+					},
+				}
+				Expect(c.Create(ctx, &job)).To(Succeed())
+				Expect(c.Status().Update(ctx, &job)).To(Succeed())
+				Expect(c.Create(ctx, &pod)).To(Succeed())
+			})
+
+			It("Should return the usage and delete the job", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(usage).To(Equal(&storage.VolumeUsage{Used: 1024 * 1024, Available: 1024 * 1024}))
+
+				_, err := getJob()
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		When("The job has failed", func() {
+			BeforeEach(func() {
+				job := batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: jobName, Namespace: namespace}}
+				job.Status.Failed = 2
+				Expect(c.Create(ctx, &job)).To(Succeed())
+				Expect(c.Status().Update(ctx, &job)).To(Succeed())
+			})
+
+			It("Should return an error and delete the job", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(usage).To(BeNil())
+
+				_, err := getJob()
+				Expect(kerrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+	})
+})
@@ -0,0 +1,264 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenantimport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/mail"
+	"slices"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/controller/tenant/webhook"
+)
+
+// Operation is the operation performed on a Tenant by the import.
+type Operation string
+
+const (
+	// OperationCreate -> the Tenant does not exist and is created.
+	OperationCreate Operation = "create"
+	// OperationUpdate -> the Tenant exists and its workspaces are updated.
+	OperationUpdate Operation = "update"
+	// OperationNone -> the Tenant already matches the roster.
+	OperationNone Operation = "unchanged"
+)
+
+// Change is the change planned for a single Tenant.
+type Change struct {
+	Operation Operation
+	// Tenant is the desired state of the Tenant.
+	Tenant *clv1alpha2.Tenant
+	// Details describes the differences with respect to the current state.
+	Details []string
+	// Warnings describes the differences which are not applied.
+	Warnings []string
+
+	current *clv1alpha2.Tenant
+}
+
+// Plan is the outcome of the validation of a roster against the existing Tenants and Workspaces.
+type Plan struct {
+	Changes []Change
+	Errors  []error
+}
+
+// Importer validates the rosters against the existing Tenants and Workspaces, and applies them idempotently.
+// The workspaces listed in the roster are added to the Tenants (or their role is updated), while the other
+// workspaces and the personal details of the existing Tenants are never modified.
+type Importer struct {
+	Client client.Client
+	// BaseWorkspaces are added to the new Tenants, as done by the tenant defaulter webhook.
+	BaseWorkspaces []string
+	// Manager is the Tenant performing the import (if any): the changes the tenant validator webhook would deny
+	// to it (e.g., to the workspaces it does not manage) are reported in advance as errors.
+	Manager string
+	// ServerDryRun submits the changes in dry-run mode, to have them validated by the API server (and the
+	// webhooks) without persisting them.
+	ServerDryRun bool
+}
+
+// Plan validates the roster and computes the changes required to apply it.
+func (i *Importer) Plan(ctx context.Context, entries []Entry) (*Plan, error) {
+	var workspaces clv1alpha1.WorkspaceList
+	if err := i.Client.List(ctx, &workspaces); err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	existingWorkspaces := make(map[string]bool, len(workspaces.Items))
+	for j := range workspaces.Items {
+		existingWorkspaces[workspaces.Items[j].Name] = true
+	}
+
+	var manager *clv1alpha2.Tenant
+	if i.Manager != "" {
+		manager = &clv1alpha2.Tenant{}
+		if err := i.Client.Get(ctx, client.ObjectKey{Name: i.Manager}, manager); err != nil {
+			return nil, fmt.Errorf("failed to get manager tenant %s: %w", i.Manager, err)
+		}
+	}
+
+	plan := &Plan{}
+	for j := range entries {
+		if errs := validateEntry(&entries[j], existingWorkspaces); len(errs) > 0 {
+			plan.Errors = append(plan.Errors, errs...)
+			continue
+		}
+
+		change, err := i.planEntry(ctx, &entries[j])
+		if err != nil {
+			return nil, err
+		}
+
+		if manager != nil && change.Operation != OperationNone {
+			if err := i.checkManagerAllowed(ctx, change, manager); err != nil {
+				plan.Errors = append(plan.Errors, fmt.Errorf("tenant %s: %w", entries[j].ID, err))
+				continue
+			}
+		}
+		plan.Changes = append(plan.Changes, *change)
+	}
+
+	return plan, nil
+}
+
+// planEntry computes the change required to apply a single (valid) entry.
+func (i *Importer) planEntry(ctx context.Context, entry *Entry) (*Change, error) {
+	var current clv1alpha2.Tenant
+	if err := i.Client.Get(ctx, client.ObjectKey{Name: entry.ID}, &current); client.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to get tenant %s: %w", entry.ID, err)
+	} else if err != nil {
+		desired := &clv1alpha2.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: entry.ID},
+			Spec: clv1alpha2.TenantSpec{
+				FirstName:  entry.FirstName,
+				LastName:   entry.LastName,
+				Email:      entry.Email,
+				Workspaces: slices.Clone(entry.Workspaces),
+			},
+		}
+		(&webhook.TenantDefaulter{BaseWorkspaces: i.BaseWorkspaces}).EnforceTenantBaseWorkspaces(ctx, desired)
+
+		change := &Change{Operation: OperationCreate, Tenant: desired}
+		for _, ws := range desired.Spec.Workspaces {
+			change.Details = append(change.Details, fmt.Sprintf("+%s=%s", ws.Name, ws.Role))
+		}
+		return change, nil
+	}
+
+	change := &Change{Operation: OperationNone, Tenant: current.DeepCopy(), current: &current}
+	for _, ws := range entry.Workspaces {
+		idx := slices.IndexFunc(change.Tenant.Spec.Workspaces, func(e clv1alpha2.TenantWorkspaceEntry) bool { return e.Name == ws.Name })
+		switch {
+		case idx < 0:
+			change.Tenant.Spec.Workspaces = append(change.Tenant.Spec.Workspaces, ws)
+			change.Details = append(change.Details, fmt.Sprintf("+%s=%s", ws.Name, ws.Role))
+		case change.Tenant.Spec.Workspaces[idx].Role != ws.Role:
+			change.Details = append(change.Details, fmt.Sprintf("~%s=%s->%s", ws.Name, change.Tenant.Spec.Workspaces[idx].Role, ws.Role))
+			change.Tenant.Spec.Workspaces[idx].Role = ws.Role
+		}
+	}
+	if len(change.Details) > 0 {
+		change.Operation = OperationUpdate
+	}
+
+	// the personal details can be changed only by the tenant itself (or the administrators)
+	if (entry.FirstName != "" && entry.FirstName != current.Spec.FirstName) ||
+		(entry.LastName != "" && entry.LastName != current.Spec.LastName) {
+		change.Warnings = append(change.Warnings, "name differs from the roster, not updated")
+	}
+	if entry.Email != current.Spec.Email {
+		change.Warnings = append(change.Warnings, "email differs from the roster, not updated")
+	}
+
+	return change, nil
+}
+
+// checkManagerAllowed verifies the change would be admitted by the tenant validator webhook for the given manager.
+func (i *Importer) checkManagerAllowed(ctx context.Context, change *Change, manager *clv1alpha2.Tenant) error {
+	validator := webhook.TenantValidator{TenantWebhook: webhook.TenantWebhook{Client: i.Client}}
+	operation, current := admissionv1.Update, change.current
+	if change.Operation == OperationCreate {
+		operation, current = admissionv1.Create, &clv1alpha2.Tenant{}
+	}
+
+	// the validator clears the workspaces of the given tenants, hence copies are provided
+	_, err := validator.HandleWorkspaceEdit(ctx, change.Tenant.DeepCopy(), current.DeepCopy(), manager, operation)
+	return err
+}
+
+// validateEntry checks the entry is well formed and refers to existing workspaces.
+func validateEntry(entry *Entry, workspaces map[string]bool) []error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("tenant %s: %s", entry.ID, fmt.Sprintf(format, args...)))
+	}
+
+	if msgs := validation.IsDNS1123Subdomain(entry.ID); len(msgs) > 0 {
+		invalid("invalid id: %s", strings.Join(msgs, ", "))
+	}
+	if address, err := mail.ParseAddress(entry.Email); err != nil || address.Address != entry.Email {
+		invalid("invalid email %q", entry.Email)
+	}
+	for _, ws := range entry.Workspaces {
+		switch {
+		case !workspaces[ws.Name]:
+			invalid("workspace %s does not exist", ws.Name)
+		case ws.Role != clv1alpha2.User && ws.Role != clv1alpha2.Manager:
+			// the candidate role can only be requested by the tenant itself
+			invalid("invalid role %q for workspace %s", ws.Role, ws.Name)
+		}
+	}
+
+	return errs
+}
+
+// Apply creates or updates the Tenants according to the plan, continuing in case of errors.
+func (i *Importer) Apply(ctx context.Context, plan *Plan) error {
+	var errs []error
+	for j := range plan.Changes {
+		change := &plan.Changes[j]
+		switch change.Operation {
+		case OperationCreate:
+			var opts []client.CreateOption
+			if i.ServerDryRun {
+				opts = append(opts, client.DryRunAll)
+			}
+			if err := i.Client.Create(ctx, change.Tenant.DeepCopy(), opts...); err != nil {
+				errs = append(errs, fmt.Errorf("failed to create tenant %s: %w", change.Tenant.Name, err))
+			}
+		case OperationUpdate:
+			var opts []client.PatchOption
+			if i.ServerDryRun {
+				opts = append(opts, client.DryRunAll)
+			}
+			patch := client.MergeFromWithOptions(change.current, client.MergeFromWithOptimisticLock{})
+			if err := i.Client.Patch(ctx, change.Tenant.DeepCopy(), patch, opts...); err != nil {
+				errs = append(errs, fmt.Errorf("failed to update tenant %s: %w", change.Tenant.Name, err))
+			}
+		case OperationNone:
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// Print writes a human readable description of the plan.
+func (p *Plan) Print(w io.Writer) {
+	counts := map[Operation]int{}
+	for j := range p.Changes {
+		change := &p.Changes[j]
+		counts[change.Operation]++
+		if change.Operation != OperationNone {
+			fmt.Fprintf(w, "%-9s %s: %s\n", change.Operation, change.Tenant.Name, strings.Join(change.Details, " "))
+		}
+		for _, warning := range change.Warnings {
+			fmt.Fprintf(w, "%-9s %s: %s\n", "warning", change.Tenant.Name, warning)
+		}
+	}
+	for _, err := range p.Errors {
+		fmt.Fprintf(w, "%-9s %s\n", "error", err)
+	}
+
+	fmt.Fprintf(w, "%d to create, %d to update, %d unchanged, %d errors\n",
+		counts[OperationCreate], counts[OperationUpdate], counts[OperationNone], len(p.Errors))
+}
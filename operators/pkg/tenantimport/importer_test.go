@@ -0,0 +1,171 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenantimport_test
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clv1alpha1 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha1"
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/tenantimport"
+)
+
+var _ = Describe("Importer", func() {
+	var (
+		ctx      context.Context
+		cl       client.Client
+		importer *tenantimport.Importer
+		entries  []tenantimport.Entry
+		plan     *tenantimport.Plan
+	)
+
+	workspace := func(name string) *clv1alpha1.Workspace {
+		return &clv1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(clv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(clv1alpha2.AddToScheme(scheme)).To(Succeed())
+
+		cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			workspace("course-a"), workspace("course-b"), workspace("utilities"),
+			&clv1alpha2.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: "existing"},
+				Spec: clv1alpha2.TenantSpec{
+					FirstName: "Anna", LastName: "Bianchi", Email: "anna@example.com",
+					Workspaces: []clv1alpha2.TenantWorkspaceEntry{
+						{Name: "course-a", Role: clv1alpha2.User},
+						{Name: "utilities", Role: clv1alpha2.User},
+					},
+				},
+			},
+			&clv1alpha2.Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: "teacher"},
+				Spec: clv1alpha2.TenantSpec{
+					Email:      "teacher@example.com",
+					Workspaces: []clv1alpha2.TenantWorkspaceEntry{{Name: "course-a", Role: clv1alpha2.Manager}},
+				},
+			},
+		).Build()
+
+		importer = &tenantimport.Importer{Client: cl, BaseWorkspaces: []string{"utilities"}}
+		entries = []tenantimport.Entry{
+			{
+				ID: "newcomer", FirstName: "Mario", LastName: "Rossi", Email: "mario@example.com",
+				Workspaces: []clv1alpha2.TenantWorkspaceEntry{{Name: "course-a", Role: clv1alpha2.User}},
+			},
+			{
+				ID: "existing", FirstName: "Anna", LastName: "Bianchi", Email: "anna@example.com",
+				Workspaces: []clv1alpha2.TenantWorkspaceEntry{{Name: "course-a", Role: clv1alpha2.Manager}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		plan, err = importer.Plan(ctx, entries)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should plan the creation and the update of the tenants", func() {
+		Expect(plan.Errors).To(BeEmpty())
+		Expect(plan.Changes).To(HaveLen(2))
+
+		Expect(plan.Changes[0].Operation).To(Equal(tenantimport.OperationCreate))
+		Expect(plan.Changes[0].Tenant.Spec.Workspaces).To(ConsistOf(
+			clv1alpha2.TenantWorkspaceEntry{Name: "course-a", Role: clv1alpha2.User},
+			clv1alpha2.TenantWorkspaceEntry{Name: "utilities", Role: clv1alpha2.User},
+		))
+
+		Expect(plan.Changes[1].Operation).To(Equal(tenantimport.OperationUpdate))
+		Expect(plan.Changes[1].Details).To(ConsistOf("~course-a=user->manager"))
+
+		var out bytes.Buffer
+		plan.Print(&out)
+		Expect(out.String()).To(ContainSubstring("1 to create, 1 to update, 0 unchanged, 0 errors"))
+	})
+
+	It("Should apply the changes idempotently", func() {
+		Expect(importer.Apply(ctx, plan)).To(Succeed())
+
+		var tenant clv1alpha2.Tenant
+		Expect(cl.Get(ctx, client.ObjectKey{Name: "newcomer"}, &tenant)).To(Succeed())
+		Expect(tenant.Spec.Email).To(Equal("mario@example.com"))
+		Expect(cl.Get(ctx, client.ObjectKey{Name: "existing"}, &tenant)).To(Succeed())
+		Expect(tenant.Spec.Workspaces).To(ContainElement(clv1alpha2.TenantWorkspaceEntry{Name: "course-a", Role: clv1alpha2.Manager}))
+		Expect(tenant.Spec.Workspaces).To(ContainElement(clv1alpha2.TenantWorkspaceEntry{Name: "utilities", Role: clv1alpha2.User}))
+
+		replan, err := importer.Plan(ctx, entries)
+		Expect(err).NotTo(HaveOccurred())
+		for _, change := range replan.Changes {
+			Expect(change.Operation).To(Equal(tenantimport.OperationNone))
+		}
+	})
+
+	When("the roster contains invalid entries", func() {
+		BeforeEach(func() {
+			entries = append(entries,
+				tenantimport.Entry{ID: "Invalid_ID", Email: "invalid@example.com"},
+				tenantimport.Entry{ID: "bad-email", Email: "not an email"},
+				tenantimport.Entry{ID: "missing-ws", Email: "x@example.com",
+					Workspaces: []clv1alpha2.TenantWorkspaceEntry{{Name: "course-z", Role: clv1alpha2.User}}},
+				tenantimport.Entry{ID: "candidate", Email: "y@example.com",
+					Workspaces: []clv1alpha2.TenantWorkspaceEntry{{Name: "course-a", Role: clv1alpha2.Candidate}}},
+			)
+		})
+
+		It("Should report an error for each of them", func() {
+			Expect(plan.Errors).To(HaveLen(4))
+			Expect(plan.Changes).To(HaveLen(2))
+		})
+	})
+
+	When("the personal details of an existing tenant differ", func() {
+		BeforeEach(func() {
+			entries[1].Email = "anna.bianchi@example.com"
+		})
+
+		It("Should warn without updating them", func() {
+			Expect(plan.Changes[1].Warnings).To(ConsistOf(ContainSubstring("email")))
+			Expect(plan.Changes[1].Tenant.Spec.Email).To(Equal("anna@example.com"))
+		})
+	})
+
+	When("the import is performed by a manager", func() {
+		BeforeEach(func() {
+			importer.Manager = "teacher"
+			importer.BaseWorkspaces = nil
+			entries = append(entries, tenantimport.Entry{
+				ID: "other", Email: "other@example.com",
+				Workspaces: []clv1alpha2.TenantWorkspaceEntry{{Name: "course-b", Role: clv1alpha2.User}},
+			})
+		})
+
+		It("Should reject the changes to the workspaces not managed", func() {
+			Expect(plan.Changes).To(HaveLen(2))
+			Expect(plan.Errors).To(ConsistOf(MatchError(ContainSubstring("course-b"))))
+		})
+	})
+})
@@ -0,0 +1,224 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tenantimport implements the bulk import of Tenants from roster files.
+package tenantimport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+)
+
+// Format is the format of a roster file.
+type Format string
+
+const (
+	// FormatAuto -> the format is inferred from the file extension.
+	FormatAuto Format = "auto"
+	// FormatCSV -> the roster is a CSV file, with a header row.
+	FormatCSV Format = "csv"
+	// FormatJSON -> the roster is a JSON array of entries.
+	FormatJSON Format = "json"
+)
+
+const (
+	columnID         = "id"
+	columnFirstName  = "firstName"
+	columnLastName   = "lastName"
+	columnEmail      = "email"
+	columnWorkspaces = "workspaces"
+
+	// workspacesSeparator separates the workspaces in the CSV column.
+	workspacesSeparator = ";"
+	// roleSeparator separates the name of the workspace from the role in the CSV column.
+	roleSeparator = ":"
+)
+
+// Entry is a single Tenant described by a roster.
+type Entry struct {
+	ID         string                            `json:"id"`
+	FirstName  string                            `json:"firstName"`
+	LastName   string                            `json:"lastName"`
+	Email      string                            `json:"email"`
+	Workspaces []clv1alpha2.TenantWorkspaceEntry `json:"workspaces,omitempty"`
+}
+
+// LoadRoster reads the roster from the given file ("-" for the standard input).
+func LoadRoster(path string, format Format) ([]Entry, error) {
+	if format == FormatAuto {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = FormatCSV
+		case ".json":
+			format = FormatJSON
+		default:
+			return nil, fmt.Errorf("cannot infer the format of roster %q, please specify it explicitly", path)
+		}
+	}
+
+	reader := io.Reader(os.Stdin)
+	if path != "-" {
+		file, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open roster: %w", err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	switch format {
+	case FormatCSV:
+		return ParseCSV(reader)
+	case FormatJSON:
+		return ParseJSON(reader)
+	default:
+		return nil, fmt.Errorf("unsupported roster format %q", format)
+	}
+}
+
+// ParseCSV parses a CSV roster. The header row identifies the id, firstName, lastName, email and workspaces
+// columns (in any order); the workspaces are separated by semicolons, each optionally followed by a colon and
+// the role (e.g., "course-a:user;course-b:manager"), which defaults to user. Multiple rows for the same id are merged.
+func ParseCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read roster header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{columnID, columnEmail} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("roster header is missing the %q column", required)
+		}
+	}
+
+	var entries []Entry
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read roster: %w", err)
+		}
+
+		field := func(column string) string {
+			if idx, ok := columns[column]; ok && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+
+		entry := Entry{
+			ID:        field(columnID),
+			FirstName: field(columnFirstName),
+			LastName:  field(columnLastName),
+			Email:     field(columnEmail),
+		}
+		if entry.Workspaces, err = parseWorkspaces(field(columnWorkspaces)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if entries, err = mergeEntry(entries, &entry); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	return entries, nil
+}
+
+// ParseJSON parses a JSON roster, consisting of an array of entries. Multiple entries for the same id are merged.
+func ParseJSON(r io.Reader) ([]Entry, error) {
+	var raw []Entry
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode roster: %w", err)
+	}
+
+	var entries []Entry
+	for i := range raw {
+		for j := range raw[i].Workspaces {
+			if raw[i].Workspaces[j].Role == "" {
+				raw[i].Workspaces[j].Role = clv1alpha2.User
+			}
+		}
+
+		var err error
+		if entries, err = mergeEntry(entries, &raw[i]); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+	}
+
+	return entries, nil
+}
+
+// parseWorkspaces parses the workspaces column of a CSV roster.
+func parseWorkspaces(value string) ([]clv1alpha2.TenantWorkspaceEntry, error) {
+	var workspaces []clv1alpha2.TenantWorkspaceEntry
+	for _, item := range strings.Split(value, workspacesSeparator) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, role, found := strings.Cut(item, roleSeparator)
+		if !found {
+			role = string(clv1alpha2.User)
+		}
+		name, role = strings.TrimSpace(name), strings.TrimSpace(role)
+		if name == "" {
+			return nil, fmt.Errorf("invalid workspace %q", item)
+		}
+		workspaces = append(workspaces, clv1alpha2.TenantWorkspaceEntry{Name: name, Role: clv1alpha2.WorkspaceUserRole(role)})
+	}
+	return workspaces, nil
+}
+
+// mergeEntry appends the entry to the list, merging the workspaces with a previous entry for the same id.
+func mergeEntry(entries []Entry, entry *Entry) ([]Entry, error) {
+	if entry.ID == "" {
+		return nil, errors.New("missing id")
+	}
+
+	idx := slices.IndexFunc(entries, func(e Entry) bool { return e.ID == entry.ID })
+	if idx < 0 {
+		return append(entries, *entry), nil
+	}
+
+	existing := &entries[idx]
+	if existing.Email != entry.Email || existing.FirstName != entry.FirstName || existing.LastName != entry.LastName {
+		return nil, fmt.Errorf("conflicting details for tenant %q", entry.ID)
+	}
+	for _, ws := range entry.Workspaces {
+		switch i := slices.IndexFunc(existing.Workspaces, func(e clv1alpha2.TenantWorkspaceEntry) bool { return e.Name == ws.Name }); {
+		case i < 0:
+			existing.Workspaces = append(existing.Workspaces, ws)
+		case existing.Workspaces[i].Role != ws.Role:
+			return nil, fmt.Errorf("conflicting roles for tenant %q in workspace %q", entry.ID, ws.Name)
+		}
+	}
+	return entries, nil
+}
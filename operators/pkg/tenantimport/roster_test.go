@@ -0,0 +1,92 @@
+// Copyright 2020-2026 Politecnico di Torino
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenantimport_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	clv1alpha2 "github.com/netgroup-polito/CrownLabs/operators/api/v1alpha2"
+	"github.com/netgroup-polito/CrownLabs/operators/pkg/tenantimport"
+)
+
+var _ = Describe("Roster parsing", func() {
+	Describe("The ParseCSV function", func() {
+		It("Should parse the entries, merging the rows of the same tenant", func() {
+			entries, err := tenantimport.ParseCSV(strings.NewReader(
+				"email,id,firstName,lastName,workspaces\n" +
+					"mario@example.com,s123,Mario,Rossi,course-a;course-b:manager\n" +
+					"anna@example.com,s456,Anna,Bianchi,\n" +
+					"mario@example.com,s123,Mario,Rossi,course-c:user\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+
+			Expect(entries[0].ID).To(Equal("s123"))
+			Expect(entries[0].Email).To(Equal("mario@example.com"))
+			Expect(entries[0].Workspaces).To(Equal([]clv1alpha2.TenantWorkspaceEntry{
+				{Name: "course-a", Role: clv1alpha2.User},
+				{Name: "course-b", Role: clv1alpha2.Manager},
+				{Name: "course-c", Role: clv1alpha2.User},
+			}))
+			Expect(entries[1].Workspaces).To(BeEmpty())
+		})
+
+		It("Should fail if the header misses the required columns", func() {
+			_, err := tenantimport.ParseCSV(strings.NewReader("id,firstName\ns123,Mario\n"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should fail if the rows of the same tenant are conflicting", func() {
+			_, err := tenantimport.ParseCSV(strings.NewReader(
+				"id,email,workspaces\ns123,mario@example.com,course-a:user\ns123,mario@example.com,course-a:manager\n"))
+			Expect(err).To(MatchError(ContainSubstring("conflicting roles")))
+		})
+	})
+
+	Describe("The ParseJSON function", func() {
+		It("Should parse the entries, defaulting the role to user", func() {
+			entries, err := tenantimport.ParseJSON(strings.NewReader(
+				`[{"id": "s123", "email": "mario@example.com", "workspaces": [{"name": "course-a"}]}]`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(ConsistOf(tenantimport.Entry{
+				ID: "s123", Email: "mario@example.com",
+				Workspaces: []clv1alpha2.TenantWorkspaceEntry{{Name: "course-a", Role: clv1alpha2.User}},
+			}))
+		})
+
+		It("Should fail if an entry has no id", func() {
+			_, err := tenantimport.ParseJSON(strings.NewReader(`[{"email": "mario@example.com"}]`))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("The LoadRoster function", func() {
+		It("Should infer the format from the file extension", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "roster.json")
+			Expect(os.WriteFile(path, []byte(`[{"id": "s123", "email": "mario@example.com"}]`), 0o600)).To(Succeed())
+
+			entries, err := tenantimport.LoadRoster(path, tenantimport.FormatAuto)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+
+			_, err = tenantimport.LoadRoster(filepath.Join(filepath.Dir(path), "roster.txt"), tenantimport.FormatAuto)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
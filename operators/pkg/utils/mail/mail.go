@@ -22,6 +22,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"html"
 	"io"
 	"net/mail"
 	"net/smtp"
@@ -64,6 +65,21 @@ type Placeholders struct {
 	PrettyName    string `name:"prettyName"`
 	InstanceName  string `name:"instanceName"`
 	RemainingTime string `name:"remainingTime"`
+	// DeferralNote explains why an automated action has been postponed (empty if not deferred),
+	// while DeferralNoteHTML contains the same explanation as HTML paragraph.
+	DeferralNote     string `name:"deferralNote"`
+	DeferralNoteHTML string `name:"deferralNoteHtml"`
+	// DigestPlaintext and DigestHTML contain the list of notifications aggregated in a digest email.
+	DigestPlaintext string `name:"digestPlaintext"`
+	DigestHTML      string `name:"digestHtml"`
+	DigestCount     string `name:"digestCount"`
+	// CandidateName, WorkspaceName, EnrollmentOutcome and Reason describe the requests of enrollment in a workspace.
+	CandidateName     string `name:"candidateName"`
+	WorkspaceName     string `name:"workspaceName"`
+	EnrollmentOutcome string `name:"enrollmentOutcome"`
+	Reason            string `name:"reason"`
+	// VolumeUsage describes the space used on a volume (e.g., the MyDrive of the tenant).
+	VolumeUsage string `name:"volumeUsage"`
 }
 
 // NewMailClientFromFilesystem creates a new Client instance that reads configs and templates from filesystem paths.
@@ -155,6 +171,15 @@ func getPlaceholderMap(placeholders *Placeholders) map[string]string {
 	return placeholdersMap
 }
 
+// HTMLParagraph returns the given text (escaped) as HTML paragraph, or an empty string if the text is empty,
+// to be used for the optional placeholders of the HTML content not to render empty paragraphs.
+func HTMLParagraph(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "<p>" + html.EscapeString(text) + "</p>"
+}
+
 // replaceTemplateVars replaces template variables in content using a map of replacements.
 func replacePlaceholders(content string, emailValues map[string]string) (string, error) {
 	if content == "" {
@@ -216,24 +241,25 @@ func (m *Client) processEmailContentTemplate(templatePath string, ph *Placeholde
 	}
 
 	// Parse content template YAML to extract content fields
-	var contentYAML map[string]string
-	if err := yaml.Unmarshal(emailContentTemplate, &contentYAML); err != nil {
+	var emailValues map[string]string
+	if err := yaml.Unmarshal(emailContentTemplate, &emailValues); err != nil {
 		return nil, fmt.Errorf("failed to parse email content template: %w", err)
 	}
 
 	// Convert placeholders struct to a map
 	phMap := getPlaceholderMap(ph)
 
-	// Substitute placeholders in the email content template
-	formattedContent, err := replacePlaceholders(string(emailContentTemplate), phMap)
-	if err != nil {
-		return nil, fmt.Errorf("failed to format email content template: %w", err)
-	}
-
-	// Parse the formatted content YAML to extract email fields
-	var emailValues map[string]string
-	if err := yaml.Unmarshal([]byte(formattedContent), &emailValues); err != nil {
-		return nil, fmt.Errorf("failed to parse email content template: %w", err)
+	// Substitute placeholders field by field, so that multi-line values (e.g., digests)
+	// do not break the indentation of the YAML template.
+	for key, value := range emailValues {
+		if value == "" {
+			continue
+		}
+		formattedValue, err := replacePlaceholders(value, phMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format email content template field %q: %w", key, err)
+		}
+		emailValues[key] = formattedValue
 	}
 
 	return emailValues, nil
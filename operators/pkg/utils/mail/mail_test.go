@@ -162,6 +162,11 @@ smtpFrom: "sender@example.com"
 			Expect(email).To(ContainSubstring("--\nCrownLabs Team"))
 		})
 
+		It("renders the optional HTML paragraphs only if not empty", func() {
+			Expect(mail.HTMLParagraph("")).To(BeEmpty())
+			Expect(mail.HTMLParagraph("Deferred <holiday>")).To(Equal("<p>Deferred &lt;holiday&gt;</p>"))
+		})
+
 		It("replaces all placeholders correctly in the email", func() {
 			contentMap := map[string]string{
 				"tenantName":   placeholders.TenantName,